@@ -0,0 +1,163 @@
+/**
+ * Blob Transfers
+ *
+ * Streams files dropped on elements to the server, and turns the blobs the
+ * server sends into object URLs for the elements referencing them with
+ * data-blob-src or data-blob-href.
+ */
+
+import { BlobOp, Capability } from './codec.js';
+
+/**
+ * Must match pkg/protocol/blob.go
+ */
+const CHUNK_SIZE = 32 * 1024;
+const WINDOW = 256 * 1024;
+
+export class BlobManager {
+    constructor(client) {
+        this.client = client;
+        this.nextId = 1;           // Client blobs have odd IDs
+        this.sending = new Map();  // id -> { credit, wake, aborted }
+        this.receiving = new Map(); // id -> { name, mimeType, chunks }
+        this.urls = new Map();     // id -> { url, name }
+    }
+
+    /**
+     * Whether the server negotiated blob transfers
+     */
+    supported() {
+        return (this.client.wsManager.capabilities & Capability.BINARY_BLOBS) !== 0;
+    }
+
+    /**
+     * Stream a file to the server for the element with the given HID
+     */
+    async sendFile(hid, file) {
+        const id = this.nextId;
+        this.nextId += 2;
+
+        const state = { credit: WINDOW, wake: null, aborted: false };
+        this.sending.set(id, state);
+        this._send({ op: BlobOp.OPEN, id, hid, name: file.name, mimeType: file.type, size: file.size });
+
+        try {
+            let offset = 0;
+            while (offset < file.size) {
+                while (state.credit <= 0 && !state.aborted) {
+                    await new Promise((resolve) => { state.wake = resolve; });
+                }
+                if (state.aborted) return;
+
+                const size = Math.min(CHUNK_SIZE, state.credit, file.size - offset);
+                const data = new Uint8Array(await file.slice(offset, offset + size).arrayBuffer());
+                this._send({ op: BlobOp.CHUNK, id, data });
+                state.credit -= size;
+                offset += size;
+            }
+            this._send({ op: BlobOp.END, id });
+        } catch (err) {
+            this._send({ op: BlobOp.ABORT, id, reason: String(err) });
+        } finally {
+            this.sending.delete(id);
+        }
+    }
+
+    /**
+     * Handle a blob frame from the server
+     */
+    handleMessage(buffer) {
+        const msg = this.client.codec.decodeBlob(buffer);
+
+        switch (msg.op) {
+            case BlobOp.OPEN:
+                this.receiving.set(msg.id, { name: msg.name, mimeType: msg.mimeType, chunks: [] });
+                break;
+
+            case BlobOp.CHUNK: {
+                const blob = this.receiving.get(msg.id);
+                if (!blob) return;
+                blob.chunks.push(msg.data);
+                this._send({ op: BlobOp.CREDIT, id: msg.id, credit: msg.data.length });
+                break;
+            }
+
+            case BlobOp.END: {
+                const blob = this.receiving.get(msg.id);
+                if (!blob) return;
+                this.receiving.delete(msg.id);
+                const url = URL.createObjectURL(new Blob(blob.chunks, { type: blob.mimeType }));
+                this.urls.set(String(msg.id), { url, name: blob.name });
+                this.applyTo(document);
+                break;
+            }
+
+            case BlobOp.CREDIT: {
+                const state = this.sending.get(msg.id);
+                if (!state) return;
+                state.credit += msg.credit;
+                this._wake(state);
+                break;
+            }
+
+            case BlobOp.ABORT: {
+                this.receiving.delete(msg.id);
+                const state = this.sending.get(msg.id);
+                if (state) {
+                    state.aborted = true;
+                    this._wake(state);
+                }
+                if (this.client.options.debug) {
+                    console.warn('[Vango] Blob aborted:', msg.id, msg.reason);
+                }
+                break;
+            }
+        }
+    }
+
+    /**
+     * Point the elements referencing received blobs at their object URLs
+     */
+    applyTo(root) {
+        root.querySelectorAll('[data-blob-src],[data-blob-href]').forEach((el) => {
+            const src = this.urls.get(el.dataset.blobSrc);
+            if (src && el.getAttribute('src') !== src.url) {
+                el.setAttribute('src', src.url);
+            }
+            const href = this.urls.get(el.dataset.blobHref);
+            if (href && el.getAttribute('href') !== href.url) {
+                el.setAttribute('href', href.url);
+                if (el.hasAttribute('download') && !el.getAttribute('download')) {
+                    el.setAttribute('download', href.name);
+                }
+            }
+        });
+    }
+
+    /**
+     * Abort transfers and release object URLs
+     */
+    destroy() {
+        for (const state of this.sending.values()) {
+            state.aborted = true;
+            this._wake(state);
+        }
+        for (const { url } of this.urls.values()) {
+            URL.revokeObjectURL(url);
+        }
+        this.urls.clear();
+        this.receiving.clear();
+    }
+
+    _wake(state) {
+        if (state.wake) {
+            const wake = state.wake;
+            state.wake = null;
+            wake();
+        }
+    }
+
+    _send(msg) {
+        this.client.sendBlobMessage(this.client.codec.encodeBlob(msg));
+    }
+}
@@ -0,0 +1,77 @@
+/**
+ * Client Calls
+ *
+ * Runs the functions the server calls with Ctx.Call, and sends their
+ * results back. Only registered functions can be called: the server names
+ * a function, it never sends code.
+ */
+
+import { EventType } from './codec.js';
+
+export class CallManager {
+    constructor(client) {
+        this.client = client;
+        this.functions = new Map(); // name -> fn(el, ...args)
+
+        // Built-in functions
+        this.register('selection', (el) => ({
+            start: el.selectionStart,
+            end: el.selectionEnd,
+            direction: el.selectionDirection,
+        }));
+        this.register('rect', (el) => el.getBoundingClientRect().toJSON());
+        this.register('copy', (el, text) => navigator.clipboard.writeText(
+            text ?? el?.value ?? el?.textContent ?? ''
+        ));
+        this.register('share', (el, data) => navigator.share(data));
+        this.register('invoke', (el, method, ...args) => this._invoke(el, method, args));
+    }
+
+    /**
+     * Register a function the server can call.
+     * It receives the element of the call (or null) and the call arguments,
+     * and may return a promise. Its result must be JSON-serializable.
+     */
+    register(name, fn) {
+        this.functions.set(name, fn);
+    }
+
+    /**
+     * Handle a Call patch: run the function and send its result
+     */
+    async handle(patch) {
+        const fn = this.functions.get(patch.name);
+        const el = patch.hid ? this.client.getNode(patch.hid) || null : null;
+
+        let result;
+        try {
+            if (!fn) {
+                throw new Error(`unknown function "${patch.name}"`);
+            }
+            if (patch.hid && !el) {
+                throw new Error(`node ${patch.hid} not found`);
+            }
+            const args = patch.args ? JSON.parse(patch.args) : [];
+            const value = await fn(el, ...args);
+            result = { id: patch.id, ok: true, value: JSON.stringify(value ?? null) };
+        } catch (err) {
+            const message = err?.message || String(err);
+            result = { id: patch.id, ok: false, value: err?.name && err.name !== 'Error' ? `${err.name}: ${message}` : message };
+        }
+
+        this.client.sendEvent(EventType.CALL_RESULT, '', result);
+    }
+
+    /**
+     * Call a method of the hook attached to the element, e.g. a
+     * third-party widget, or else of the element itself
+     */
+    _invoke(el, method, args) {
+        const hook = el ? this.client.hooks.instances.get(el.dataset.hid) : null;
+        const target = typeof hook?.instance?.[method] === 'function' ? hook.instance : el;
+        if (typeof target?.[method] !== 'function') {
+            throw new Error(`no method "${method}"`);
+        }
+        return target[method](...args);
+    }
+}
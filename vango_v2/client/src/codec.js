@@ -5,7 +5,7 @@
  * that matches the Go server implementation in pkg/protocol/.
  */
 
-import { hidToInt, intToHid, concat } from './utils.js';
+import { hidToInt, intToHid, concat, mediaPreferences } from './utils.js';
 
 /**
  * Event type constants - must match pkg/protocol/event.go
@@ -32,9 +32,10 @@ export const EventType = {
     KEYUP: 0x21,
     KEYPRESS: 0x22,
 
-    // Scroll/Resize events (0x30-0x31)
+    // Scroll/Resize events (0x30-0x32)
     SCROLL: 0x30,
     RESIZE: 0x31,
+    MEDIA: 0x32,
 
     // Touch events (0x40-0x42)
     TOUCHSTART: 0x40,
@@ -48,6 +49,7 @@ export const EventType = {
 
     // Special events (0x60+)
     HOOK: 0x60,
+    CALL_RESULT: 0x61,
     NAVIGATE: 0x70,
     CUSTOM: 0xFF,
 };
@@ -76,10 +78,46 @@ export const PatchType = {
     REMOVE_STYLE: 0x14,
     SET_DATA: 0x15,
     DISPATCH: 0x20,
+    CALL: 0x22,
     // NOTE: EVAL (0x21) has been REMOVED for security. Server never sends it.
     // URL operations (Phase 12: URLParam 2.0)
     URL_PUSH: 0x30,
     URL_REPLACE: 0x31,
+    // Document head operations
+    SET_TITLE: 0x40,
+    SET_HEAD: 0x41,
+    REMOVE_HEAD: 0x42,
+};
+
+/**
+ * Protocol version spoken by this client - must match pkg/protocol/handshake.go
+ */
+export const ProtocolVersion = { major: 2, minor: 1 };
+
+/**
+ * Capability flags announced in ClientHello - must match pkg/protocol/version.go
+ */
+export const Capability = {
+    HEAD_PATCHES: 0x01,
+    COMPRESSED_FRAMES: 0x02,
+    BINARY_BLOBS: 0x04,
+    CLIENT_CALLS: 0x08,
+};
+
+/**
+ * Capabilities implemented by this client
+ */
+const CLIENT_CAPABILITIES = Capability.HEAD_PATCHES | Capability.BINARY_BLOBS | Capability.CLIENT_CALLS;
+
+/**
+ * Blob message ops - must match pkg/protocol/blob.go
+ */
+export const BlobOp = {
+    OPEN: 0x01,
+    CHUNK: 0x02,
+    END: 0x03,
+    CREDIT: 0x04,
+    ABORT: 0x05,
 };
 
 /**
@@ -172,6 +210,10 @@ export class BinaryCodec {
                 this.encodeResizeEvent(parts, data);
                 break;
 
+            case EventType.MEDIA:
+                this.encodeMediaEvent(parts, data);
+                break;
+
             case EventType.TOUCHSTART:
             case EventType.TOUCHMOVE:
             case EventType.TOUCHEND:
@@ -188,6 +230,12 @@ export class BinaryCodec {
                 this.encodeHookEvent(parts, data);
                 break;
 
+            case EventType.CALL_RESULT:
+                parts.push(this.encodeUvarint(data?.id || 0));
+                parts.push(new Uint8Array([data?.ok ? 1 : 0]));
+                parts.push(this.encodeString(data?.value || ''));
+                break;
+
             case EventType.NAVIGATE:
                 parts.push(this.encodeString(data?.path || ''));
                 parts.push(new Uint8Array([data?.replace ? 1 : 0]));
@@ -256,7 +304,8 @@ export class BinaryCodec {
         // Payload (depends on patch type)
         switch (patch.type) {
             case PatchType.SET_TEXT:
-            case PatchType.SET_VALUE: {
+            case PatchType.SET_VALUE:
+            case PatchType.SET_TITLE: {
                 const { value, bytesRead } = this.decodeString(buffer, offset);
                 patch.value = value;
                 offset += bytesRead;
@@ -276,7 +325,8 @@ export class BinaryCodec {
             }
 
             case PatchType.REMOVE_ATTR:
-            case PatchType.REMOVE_STYLE: {
+            case PatchType.REMOVE_STYLE:
+            case PatchType.REMOVE_HEAD: {
                 const { value, bytesRead } = this.decodeString(buffer, offset);
                 patch.key = value;
                 offset += bytesRead;
@@ -326,6 +376,16 @@ export class BinaryCodec {
                 break;
             }
 
+            case PatchType.SET_HEAD: {
+                const { value: key, bytesRead: keyBytes } = this.decodeString(buffer, offset);
+                offset += keyBytes;
+                const { vnode, bytesRead: vnodeBytes } = this.decodeVNode(buffer, offset);
+                offset += vnodeBytes;
+                patch.key = key;
+                patch.vnode = vnode;
+                break;
+            }
+
             case PatchType.SET_CHECKED:
             case PatchType.SET_SELECTED: {
                 patch.value = buffer[offset++] === 1;
@@ -358,6 +418,19 @@ export class BinaryCodec {
                 break;
             }
 
+            case PatchType.CALL: {
+                const { value: id, bytesRead: idBytes } = this.decodeUvarint(buffer, offset);
+                offset += idBytes;
+                const { value: name, bytesRead: nameBytes } = this.decodeString(buffer, offset);
+                offset += nameBytes;
+                const { value: args, bytesRead: argsBytes } = this.decodeString(buffer, offset);
+                offset += argsBytes;
+                patch.id = id;
+                patch.name = name;
+                patch.args = args;
+                break;
+            }
+
             // NOTE: PatchType.EVAL (0x21) is intentionally not handled.
             // The server never sends it and we should not execute arbitrary code.
 
@@ -628,6 +701,14 @@ export class BinaryCodec {
         parts.push(this.encodeSvarint(data?.height || 0));
     }
 
+    /**
+     * Encode media query change event
+     */
+    encodeMediaEvent(parts, data) {
+        parts.push(new Uint8Array([data?.colorScheme || 0, data?.reducedMotion ? 1 : 0]));
+        parts.push(this.encodeUint16(data?.pixelRatio || 0));
+    }
+
     /**
      * Encode touch event
      */
@@ -722,15 +803,86 @@ export class BinaryCodec {
         return new Uint8Array(buffer);
     }
 
+    /**
+     * Encode a blob message
+     * Format: [op:1][id:varint] followed by
+     *   Open: [hid:string][name:string][mimeType:string][size:varint]
+     *   Chunk: [data:len-prefixed]
+     *   Credit: [bytes:varint]
+     *   Abort: [reason:string]
+     */
+    encodeBlob(msg) {
+        const parts = [new Uint8Array([msg.op]), this.encodeUvarint(msg.id)];
+
+        switch (msg.op) {
+            case BlobOp.OPEN:
+                parts.push(this.encodeString(msg.hid || ''));
+                parts.push(this.encodeString(msg.name || ''));
+                parts.push(this.encodeString(msg.mimeType || ''));
+                parts.push(this.encodeUvarint(msg.size || 0));
+                break;
+            case BlobOp.CHUNK:
+                parts.push(this.encodeUvarint(msg.data.length));
+                parts.push(msg.data);
+                break;
+            case BlobOp.CREDIT:
+                parts.push(this.encodeUvarint(msg.credit));
+                break;
+            case BlobOp.ABORT:
+                parts.push(this.encodeString(msg.reason || ''));
+                break;
+        }
+
+        return concat(parts);
+    }
+
+    /**
+     * Decode a blob message
+     */
+    decodeBlob(buffer) {
+        let offset = 0;
+        const op = buffer[offset++];
+        const { value: id, bytesRead: idBytes } = this.decodeUvarint(buffer, offset);
+        offset += idBytes;
+        const msg = { op, id };
+
+        switch (op) {
+            case BlobOp.OPEN: {
+                for (const field of ['hid', 'name', 'mimeType']) {
+                    const { value, bytesRead } = this.decodeString(buffer, offset);
+                    msg[field] = value;
+                    offset += bytesRead;
+                }
+                msg.size = this.decodeUvarint(buffer, offset).value;
+                break;
+            }
+            case BlobOp.CHUNK: {
+                const { value: length, bytesRead } = this.decodeUvarint(buffer, offset);
+                offset += bytesRead;
+                msg.data = buffer.slice(offset, offset + length);
+                break;
+            }
+            case BlobOp.CREDIT:
+                msg.credit = this.decodeUvarint(buffer, offset).value;
+                break;
+            case BlobOp.ABORT:
+                msg.reason = this.decodeString(buffer, offset).value;
+                break;
+        }
+
+        return msg;
+    }
+
     /**
      * Encode ClientHello for handshake
      * Format: [major:1][minor:1][csrf:string][sessionID:string][lastSeq:4][viewportW:2][viewportH:2][tzOffset:2]
+     * followed, since 2.1, by [capabilities:4][timeZone:string][locale:string][colorScheme:1][reducedMotion:1][pixelRatio:2]
      */
     encodeClientHello(options = {}) {
         const parts = [];
 
-        // Protocol version (2.0)
-        parts.push(new Uint8Array([0x02, 0x00]));
+        // Protocol version
+        parts.push(new Uint8Array([ProtocolVersion.major, ProtocolVersion.minor]));
 
         // CSRF token
         parts.push(this.encodeString(options.csrf || ''));
@@ -749,16 +901,33 @@ export class BinaryCodec {
         const tzOffset = new Date().getTimezoneOffset();
         parts.push(this.encodeInt16(-tzOffset)); // Negate because JS gives opposite sign
 
+        // Capabilities (uint32 little-endian, since 2.1)
+        parts.push(this.encodeUint32(CLIENT_CAPABILITIES));
+
+        // Environment: time zone, locale and media preferences
+        let timeZone = '';
+        try {
+            timeZone = Intl.DateTimeFormat().resolvedOptions().timeZone || '';
+        } catch (e) {
+            // Intl unavailable
+        }
+        const media = mediaPreferences();
+        parts.push(this.encodeString(timeZone));
+        parts.push(this.encodeString(navigator.language || ''));
+        parts.push(new Uint8Array([media.colorScheme, media.reducedMotion ? 1 : 0]));
+        parts.push(this.encodeUint16(media.pixelRatio));
+
         return concat(parts);
     }
 
     /**
      * Decode ServerHello from handshake response
-     * Response is wrapped in Frame: [type:1][payload...]
+     * Response is wrapped in Frame: [type:1][flags:1][length:2][payload...]
      * ServerHello payload: [status:1][sessionID:string][nextSeq:4][serverTime:8][flags:2]
+     * followed, since 2.1, by [major:1][minor:1][capabilities:4]
      */
     decodeServerHello(buffer) {
-        if (buffer.length < 2) {
+        if (buffer.length < 5) {
             return { error: 'Buffer too short' };
         }
 
@@ -768,7 +937,7 @@ export class BinaryCodec {
             return { error: `Unexpected frame type: ${frameType}` };
         }
 
-        let offset = 1;
+        let offset = 4;
 
         // Status byte
         const status = buffer[offset++];
@@ -787,6 +956,15 @@ export class BinaryCodec {
 
         // Flags (uint16 little-endian)
         const flags = this.decodeUint16(buffer, offset);
+        offset += 2;
+
+        // Negotiated version and capabilities (absent from 2.0 servers)
+        let version = { major: 2, minor: 0 };
+        let capabilities = 0;
+        if (offset + 6 <= buffer.length) {
+            version = { major: buffer[offset], minor: buffer[offset + 1] };
+            capabilities = this.decodeUint32(buffer, offset + 2);
+        }
 
         return {
             status,
@@ -794,6 +972,8 @@ export class BinaryCodec {
             nextSeq,
             serverTime,
             flags,
+            version,
+            capabilities,
             ok: status === 0,
         };
     }
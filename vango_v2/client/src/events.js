@@ -6,6 +6,7 @@
  */
 
 import { EventType } from './codec.js';
+import { debounce, mediaPreferences } from './utils.js';
 
 export class EventCapture {
     constructor(client) {
@@ -45,9 +46,17 @@ export class EventCapture {
         // Scroll events (throttled)
         this._on('scroll', this._handleScroll.bind(this), true);
 
+        // Drop events (files are streamed as blobs)
+        this._on('dragover', this._handleDragOver.bind(this));
+        this._on('drop', this._handleDrop.bind(this));
+
         // Navigation
         this._on('click', this._handleLinkClick.bind(this));
         window.addEventListener('popstate', this._handlePopState.bind(this));
+        this._updateActiveLinks();
+
+        // Window size and media preferences keep the server's ClientInfo current
+        this._watchEnvironment();
     }
 
     /**
@@ -288,6 +297,43 @@ export class EventCapture {
         this.client.sendEvent(EventType.MOUSELEAVE, el.dataset.hid);
     }
 
+    /**
+     * Allow dropping on elements with a drop handler
+     */
+    _handleDragOver(event) {
+        if (this._findHidElementWithHandler(event.target, 'data-on-drop')) {
+            event.preventDefault();
+        }
+    }
+
+    /**
+     * Handle drop event. Dropped files are streamed to the server as
+     * blobs when it supports them; other drops send their position.
+     */
+    _handleDrop(event) {
+        const el = this._findHidElementWithHandler(event.target, 'data-on-drop');
+        if (!el) return;
+
+        event.preventDefault();
+
+        const files = event.dataTransfer?.files;
+        if (files && files.length > 0 && this.client.blobs.supported()) {
+            for (const file of files) {
+                this.client.blobs.sendFile(el.dataset.hid, file);
+            }
+            return;
+        }
+
+        this.client.sendEvent(EventType.DROP, el.dataset.hid, {
+            clientX: event.clientX,
+            clientY: event.clientY,
+            ctrlKey: event.ctrlKey,
+            shiftKey: event.shiftKey,
+            altKey: event.altKey,
+            metaKey: event.metaKey,
+        });
+    }
+
     /**
      * Handle scroll event (throttled)
      */
@@ -298,21 +344,32 @@ export class EventCapture {
         const hid = el.dataset.hid;
         const throttleMs = parseInt(el.dataset.throttle || '100', 10);
 
-        // Simple throttle
+        // Throttle, sending the final position once scrolling stops
         if (this.scrollThrottled.has(hid)) {
             return;
         }
 
         this.scrollThrottled.add(hid);
 
+        const sent = this._sendScroll(el, hid);
         setTimeout(() => {
             this.scrollThrottled.delete(hid);
+            if (el.scrollTop !== sent.scrollTop || el.scrollLeft !== sent.scrollLeft) {
+                this._sendScroll(el, hid);
+            }
         }, throttleMs);
+    }
 
-        this.client.sendEvent(EventType.SCROLL, hid, {
+    /**
+     * Send the scroll position of an element
+     */
+    _sendScroll(el, hid) {
+        const position = {
             scrollTop: el.scrollTop,
             scrollLeft: el.scrollLeft,
-        });
+        };
+        this.client.sendEvent(EventType.SCROLL, hid, position);
+        return position;
     }
 
     /**
@@ -337,6 +394,7 @@ export class EventCapture {
 
         // Update browser URL
         history.pushState(null, '', href);
+        this._updateActiveLinks();
 
         // Send navigate event to server
         this.client.sendEvent(EventType.NAVIGATE, 'nav', { path: href });
@@ -346,9 +404,63 @@ export class EventCapture {
      * Handle browser back/forward
      */
     _handlePopState(event) {
+        this._updateActiveLinks();
         this.client.sendEvent(EventType.NAVIGATE, 'nav', { path: location.pathname });
     }
 
+    /**
+     * Report window resizes and media query changes to the server.
+     * They are sent without a HID: they concern the window, not an element.
+     */
+    _watchEnvironment() {
+        window.addEventListener('resize', debounce(() => {
+            this.client.sendEvent(EventType.RESIZE, '', {
+                width: window.innerWidth,
+                height: window.innerHeight,
+            });
+        }, 200));
+
+        if (!window.matchMedia) return;
+
+        const sendMedia = () => this.client.sendEvent(EventType.MEDIA, '', mediaPreferences());
+        for (const query of ['(prefers-color-scheme: dark)', '(prefers-reduced-motion: reduce)']) {
+            const mql = window.matchMedia(query);
+            if (mql.addEventListener) {
+                mql.addEventListener('change', sendMedia);
+            }
+        }
+
+        // The pixel ratio changes when zooming or moving to another screen
+        const watchPixelRatio = () => {
+            const mql = window.matchMedia(`(resolution: ${window.devicePixelRatio}dppx)`);
+            if (!mql.addEventListener) return;
+            mql.addEventListener('change', () => {
+                sendMedia();
+                watchPixelRatio();
+            }, { once: true });
+        };
+        watchPixelRatio();
+    }
+
+    /**
+     * Toggle the active class of links with data-active-class (ActiveLink,
+     * NavLink) for the current path. Persistent layouts keep their links
+     * across navigations, so they are updated here without a re-render.
+     */
+    _updateActiveLinks() {
+        const path = location.pathname;
+        document.querySelectorAll('a[data-active-class]').forEach((link) => {
+            const className = link.dataset.activeClass;
+            if (!className) return;
+
+            const href = new URL(link.getAttribute('href'), location.href).pathname;
+            const active = link.hasAttribute('data-active-exact')
+                ? path === href
+                : path === href || path.startsWith(href.endsWith('/') ? href : href + '/');
+            link.classList.toggle(className, active);
+        });
+    }
+
     /**
      * Check if event matches key filter
      * Format: "Enter" or "Ctrl+s" or "Meta+Enter"
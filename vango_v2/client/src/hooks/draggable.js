@@ -83,6 +83,8 @@ export class DraggableHook {
 
     _startDrag(x, y) {
         this.dragging = true;
+        this.dragStartX = this.currentX;
+        this.dragStartY = this.currentY;
         this.startX = x - this.currentX;
         this.startY = y - this.currentY;
 
@@ -170,4 +172,14 @@ export class DraggableHook {
             y: this.currentY,
         });
     }
+
+    /**
+     * Move the element back to where the last drag started, when the
+     * server rejects the position
+     */
+    revert() {
+        this.currentX = this.dragStartX || 0;
+        this.currentY = this.dragStartY || 0;
+        this.el.style.transform = `translate(${this.currentX}px, ${this.currentY}px)`;
+    }
 }
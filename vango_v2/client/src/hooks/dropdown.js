@@ -11,7 +11,8 @@ export class DropdownHook {
         this.pushEvent = pushEvent;
 
         this.closeOnEscape = config.closeOnEscape !== false;
-        this.closeOnClickOutside = config.closeOnClickOutside !== false;
+        // closeOnClick is the deprecated name of closeOnClickOutside
+        this.closeOnClickOutside = (config.closeOnClickOutside ?? config.closeOnClick) !== false;
 
         this._bindEvents();
     }
@@ -11,6 +11,7 @@ import { TooltipHook } from './tooltip.js';
 import { DropdownHook } from './dropdown.js';
 import { FocusTrapHook } from './focustrap.js';
 import { PortalHook } from './portal.js';
+import { VirtualListHook } from './virtuallist.js';
 
 export class HookManager {
     constructor(client) {
@@ -25,6 +26,7 @@ export class HookManager {
             'Dropdown': DropdownHook,
             'FocusTrap': FocusTrapHook,
             'Portal': PortalHook,
+            'VirtualList': VirtualListHook,
         };
     }
 
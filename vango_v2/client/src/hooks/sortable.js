@@ -233,9 +233,28 @@ export class SortableHook {
             });
         }
 
+        // Remember the move, so the server can revert it
+        this.lastMove = {
+            item: this.dragging,
+            container: this.initialContainer,
+            index: this.startIndex,
+        };
+
         this.dragging = null;
         this.ghost = null;
         this.activeContainer = null;
         this.initialContainer = null;
     }
+
+    /**
+     * Move the last dropped item back, when the server rejects the reorder
+     */
+    revert() {
+        const move = this.lastMove;
+        if (!move) return;
+        this.lastMove = null;
+
+        move.item.remove();
+        move.container.insertBefore(move.item, move.container.children[move.index] || null);
+    }
 }
@@ -0,0 +1,138 @@
+/**
+ * VirtualList Hook - Windowed List Support
+ *
+ * Attached to the rows container of a virtual.VirtualList. The viewport
+ * (its parent) sends scroll events; the server renders the rows in view.
+ * This hook:
+ *   - saves the scroll position and restores it on reload (config.id)
+ *   - reports the scroll position again after a reconnect
+ *   - measures rows and reports their heights (config.measure)
+ */
+
+const STORAGE_PREFIX = 'vango:virtual:';
+
+export class VirtualListHook {
+    mounted(el, config, pushEvent) {
+        this.el = el;
+        this.config = config;
+        this.pushEvent = pushEvent;
+        this.storageKey = config.id ? STORAGE_PREFIX + config.id : null;
+        this.reported = new Map(); // key -> height sent to the server
+        this.pending = new Map();  // key -> height not sent yet
+        this.frame = null;
+
+        this._onScroll = this._handleScroll.bind(this);
+        this._onConnection = this._handleConnection.bind(this);
+
+        // Lists inserted by a patch are mounted before they are attached
+        if (el.parentElement) {
+            this._attach();
+        } else {
+            queueMicrotask(() => this._attach());
+        }
+    }
+
+    updated(el, config, pushEvent) {
+        this.pushEvent = pushEvent;
+    }
+
+    destroyed(el) {
+        this.isDestroyed = true;
+        if (this.viewport) this.viewport.removeEventListener('scroll', this._onScroll);
+        document.removeEventListener('vango:connection', this._onConnection);
+        if (this.resizeObserver) this.resizeObserver.disconnect();
+        if (this.mutationObserver) this.mutationObserver.disconnect();
+        if (this.frame) cancelAnimationFrame(this.frame);
+    }
+
+    /**
+     * Start following the viewport, the parent of the rows
+     */
+    _attach() {
+        this.viewport = this.el.parentElement;
+        if (!this.viewport || this.isDestroyed) return;
+
+        this.viewport.addEventListener('scroll', this._onScroll, { passive: true });
+        document.addEventListener('vango:connection', this._onConnection);
+
+        this._restore();
+
+        if (this.config.measure && typeof ResizeObserver !== 'undefined') {
+            this.resizeObserver = new ResizeObserver(entries => this._handleResize(entries));
+            this.mutationObserver = new MutationObserver(() => this._observeRows());
+            this.mutationObserver.observe(this.el, { childList: true });
+            this._observeRows();
+        }
+    }
+
+    /**
+     * Restore the saved scroll position; the scroll event it fires
+     * moves the server's window there
+     */
+    _restore() {
+        if (!this.storageKey || this.viewport.scrollTop !== 0) return;
+        try {
+            const saved = parseInt(sessionStorage.getItem(this.storageKey), 10);
+            if (saved > 0) {
+                this.viewport.scrollTop = saved;
+            }
+        } catch (e) {
+            // Storage unavailable
+        }
+    }
+
+    _handleScroll() {
+        if (!this.storageKey) return;
+        try {
+            sessionStorage.setItem(this.storageKey, String(this.viewport.scrollTop));
+        } catch (e) {
+            // Storage unavailable
+        }
+    }
+
+    /**
+     * Scrolls during a disconnect were not sent: report the current
+     * position once connected again
+     */
+    _handleConnection(event) {
+        const { state, previousState } = event.detail || {};
+        if (state === 'connected' && previousState && previousState !== 'connected') {
+            this.viewport.dispatchEvent(new Event('scroll'));
+        }
+    }
+
+    _observeRows() {
+        for (const row of this.el.children) {
+            if (row.dataset.key !== undefined) {
+                this.resizeObserver.observe(row);
+            }
+        }
+    }
+
+    _handleResize(entries) {
+        for (const entry of entries) {
+            const row = entry.target;
+            if (!row.isConnected) {
+                this.resizeObserver.unobserve(row);
+                continue;
+            }
+            const height = Math.round(row.getBoundingClientRect().height);
+            if (height > 0 && this.reported.get(row.dataset.key) !== height) {
+                this.pending.set(row.dataset.key, height);
+            }
+        }
+
+        // Batch the heights of a frame into one event
+        if (this.pending.size > 0 && !this.frame) {
+            this.frame = requestAnimationFrame(() => {
+                this.frame = null;
+                const heights = Object.fromEntries(this.pending);
+                for (const [key, height] of this.pending) {
+                    this.reported.set(key, height);
+                }
+                this.pending.clear();
+                this.pushEvent('measure', { heights });
+            });
+        }
+    }
+}
@@ -17,6 +17,10 @@ import { ensurePortalRoot } from './hooks/portal.js';
 import { ConnectionManager, injectDefaultStyles } from './connection.js';
 import { URLManager } from './url.js';
 import { PrefManager, MergeStrategy } from './prefs.js';
+import { BlobManager } from './blobs.js';
+import { CallManager } from './calls.js';
+import { TransitionManager } from './transitions.js';
+import { PortalManager } from './portals.js';
 
 /**
  * Frame type constants for wire protocol
@@ -29,6 +33,7 @@ const FrameType = {
     CONTROL: 0x03,
     ACK: 0x04,
     ERROR: 0x05,
+    BLOB: 0x06,
 };
 
 /**
@@ -78,6 +83,10 @@ export class VangoClient {
         });
         this.urlManager = new URLManager(this, { debug: options.debug });
         this.prefs = new PrefManager(this, { debug: options.debug });
+        this.blobs = new BlobManager(this);
+        this.calls = new CallManager(this);
+        this.transitions = new TransitionManager(this);
+        this.portals = new PortalManager(this);
 
         // Callbacks
         this.onConnect = options.onConnect || (() => { });
@@ -92,6 +101,7 @@ export class VangoClient {
 
         // Initialize
         this._buildNodeMap();
+        this.portals.initializeFromDOM();
         this.wsManager.connect(this.options.wsUrl);
         this.eventCapture.attach();
         this.hooks.initializeFromDOM();
@@ -116,7 +126,21 @@ export class VangoClient {
      */
     _defaultWsUrl() {
         const protocol = location.protocol === 'https:' ? 'wss:' : 'ws:';
-        return `${protocol}//${location.host}/_vango/live`;
+        const params = new URLSearchParams();
+
+        // The page the session starts on, for server-side routing
+        params.set('path', location.pathname + location.search);
+
+        // Hand the route data loaded during SSR over to the session
+        if (window.__VANGO_LOAD__) {
+            params.set('load', window.__VANGO_LOAD__);
+        }
+
+        // Hand the resource data resolved during SSR over to the session
+        if (window.__VANGO_RESOURCES__) {
+            params.set('resources', window.__VANGO_RESOURCES__);
+        }
+        return `${protocol}//${location.host}/_vango/live?${params}`;
     }
 
     /**
@@ -165,6 +189,9 @@ export class VangoClient {
             case FrameType.ERROR:
                 this._handleServerError(payload);
                 break;
+            case FrameType.BLOB:
+                this.blobs.handleMessage(payload);
+                break;
             default:
                 if (this.options.debug) {
                     console.warn('[Vango] Unknown frame type:', frameType);
@@ -198,6 +225,9 @@ export class VangoClient {
 
         // Re-initialize hooks on new elements
         this.hooks.updateFromDOM();
+
+        // New elements may reference blobs already received
+        this.blobs.applyTo(document);
     }
 
     /**
@@ -294,6 +324,13 @@ export class VangoClient {
         }
     }
 
+    /**
+     * Send a blob message to the server
+     */
+    sendBlobMessage(payload) {
+        this.wsManager.send(this._encodeFrame(FrameType.BLOB, payload));
+    }
+
     /**
      * Encode a frame with proper header
      * Format: [type:1][flags:1][length:2 big-endian][payload]
@@ -344,6 +381,15 @@ export class VangoClient {
         this.hooks.register(name, hookClass);
     }
 
+    /**
+     * Register a function the server can call with ctx.Call
+     * @param {string} name - Function name
+     * @param {Function} fn - Called with the element and the arguments
+     */
+    registerFunction(name, fn) {
+        this.calls.register(name, fn);
+    }
+
     /**
      * Register a preference
      * @param {string} key - Unique preference key
@@ -362,6 +408,7 @@ export class VangoClient {
         this.eventCapture.detach();
         this.hooks.destroyAll();
         this.prefs.destroy();
+        this.blobs.destroy();
         this.wsManager.close();
     }
 }
@@ -15,15 +15,67 @@ export class PatchApplier {
      * Apply array of patches
      */
     apply(patches) {
+        // Positions of the children of the lists that change, to slide the
+        // ones with a move transition to their new positions
+        const moving = this.client.transitions.capture(this._changedParents(patches));
+
         for (const patch of patches) {
             this.applyPatch(patch);
         }
+
+        this.client.transitions.play(moving);
+    }
+
+    /**
+     * Elements whose children are inserted, removed or moved by patches
+     */
+    _changedParents(patches) {
+        const parents = new Set();
+        for (const patch of patches) {
+            let parentEl = null;
+            switch (patch.type) {
+                case PatchType.INSERT_NODE:
+                case PatchType.MOVE_NODE:
+                    parentEl = this._parent(patch.parentID);
+                    break;
+                case PatchType.REMOVE_NODE:
+                    parentEl = this.client.getNode(patch.hid)?.parentElement;
+                    break;
+            }
+            if (parentEl) parents.add(parentEl);
+        }
+        return parents;
+    }
+
+    /**
+     * The element holding the children of the node hid; the content
+     * element of portals
+     */
+    _parent(hid) {
+        return this.client.portals.container(this.client.getNode(hid));
     }
 
     /**
      * Apply single patch
      */
     applyPatch(patch) {
+        // Head patches target the document head, not a hydrated node
+        switch (patch.type) {
+            case PatchType.SET_TITLE:
+                document.title = patch.value;
+                return;
+            case PatchType.SET_HEAD:
+                this._setHead(patch.key, patch.vnode);
+                return;
+            case PatchType.REMOVE_HEAD:
+                this._removeHead(patch.key);
+                return;
+            case PatchType.CALL:
+                // Calls may not target an element; results are sent async
+                this.client.calls.handle(patch);
+                return;
+        }
+
         const el = this.client.getNode(patch.hid);
 
         // Some patches don't require the target element to exist
@@ -141,7 +193,7 @@ export class PatchApplier {
      * Set text content
      */
     _setText(el, text) {
-        el.textContent = text;
+        this.client.portals.container(el).textContent = text;
     }
 
     /**
@@ -241,7 +293,7 @@ export class PatchApplier {
      * Insert node at index in parent
      */
     _insertNode(parentHid, index, vnode) {
-        const parentEl = this.client.getNode(parentHid);
+        const parentEl = this._parent(parentHid);
         if (!parentEl) {
             if (this.client.options.debug) {
                 console.warn('[Vango] Parent node not found:', parentHid);
@@ -251,11 +303,9 @@ export class PatchApplier {
 
         const newEl = this._createNode(vnode);
 
-        if (index >= parentEl.children.length) {
-            parentEl.appendChild(newEl);
-        } else {
-            parentEl.insertBefore(newEl, parentEl.children[index]);
-        }
+        // Elements still leaving don't count: the server has removed them
+        parentEl.insertBefore(newEl, this.client.transitions.childAt(parentEl, index));
+        this.client.transitions.enter(newEl);
     }
 
     /**
@@ -269,20 +319,20 @@ export class PatchApplier {
         this.client.unregisterNode(hid);
 
         // Unregister all children with HIDs
-        el.querySelectorAll('[data-hid]').forEach(child => {
-            this.client.hooks.destroyForNode(child);
-            this.client.unregisterNode(child.dataset.hid);
-        });
+        el.querySelectorAll('[data-hid]').forEach(child => this._release(child));
+
+        // Portals take their content, kept in their targets, with them
+        this.client.portals.unmount(el, child => this._release(child));
 
-        // Remove from DOM
-        el.remove();
+        // Remove from DOM, once its leave transition ends
+        this.client.transitions.leave(el, () => el.remove());
     }
 
     /**
      * Move node to new position
      */
     _moveNode(el, parentHid, index) {
-        const parentEl = this.client.getNode(parentHid);
+        const parentEl = this._parent(parentHid);
         if (!parentEl) {
             if (this.client.options.debug) {
                 console.warn('[Vango] Parent node not found:', parentHid);
@@ -290,10 +340,9 @@ export class PatchApplier {
             return;
         }
 
-        if (index >= parentEl.children.length) {
-            parentEl.appendChild(el);
-        } else {
-            parentEl.insertBefore(el, parentEl.children[index]);
+        const ref = this.client.transitions.childAt(parentEl, index);
+        if (ref !== el) {
+            parentEl.insertBefore(el, ref);
         }
     }
 
@@ -304,14 +353,27 @@ export class PatchApplier {
         // Cleanup old
         this.client.hooks.destroyForNode(el);
         this.client.unregisterNode(hid);
-        el.querySelectorAll('[data-hid]').forEach(child => {
-            this.client.hooks.destroyForNode(child);
-            this.client.unregisterNode(child.dataset.hid);
-        });
+        el.querySelectorAll('[data-hid]').forEach(child => this._release(child));
+        this.client.portals.unmount(el, child => this._release(child));
 
-        // Create and insert new
+        // Create and insert new; the old element stays in place until its
+        // leave transition ends
         const newEl = this._createNode(vnode);
-        el.replaceWith(newEl);
+        if (this.client.transitions.has(el)) {
+            el.before(newEl);
+            this.client.transitions.leave(el, () => el.remove());
+        } else {
+            el.replaceWith(newEl);
+        }
+        this.client.transitions.enter(newEl);
+    }
+
+    /**
+     * Destroy the hooks of a removed element and unregister it
+     */
+    _release(el) {
+        this.client.hooks.destroyForNode(el);
+        this.client.unregisterNode(el.dataset.hid);
     }
 
     /**
@@ -355,6 +417,11 @@ export class PatchApplier {
             el.appendChild(this._createNode(child));
         }
 
+        // Portal children go to the portal's target
+        if (el.localName === 'vango-portal') {
+            this.client.portals.mount(el);
+        }
+
         // Initialize hooks
         this.client.hooks.initializeForNode(el);
 
@@ -372,6 +439,61 @@ export class PatchApplier {
         return frag;
     }
 
+    /**
+     * Find a managed head element by key
+     */
+    _findHead(key) {
+        for (const el of document.head.querySelectorAll('[data-vango-head]')) {
+            if (el.getAttribute('data-vango-head') === key) {
+                return el;
+            }
+        }
+        return null;
+    }
+
+    /**
+     * Add or replace a managed head element
+     */
+    _setHead(key, vnode) {
+        if (!vnode || vnode.type !== 'element') return;
+
+        // SECURITY: Only metadata elements may be placed in the head.
+        // Scripts are limited to JSON-LD, which browsers never execute.
+        const tag = vnode.tag.toLowerCase();
+        const attrs = vnode.attrs || {};
+        if (tag !== 'meta' && tag !== 'link' &&
+            !(tag === 'script' && attrs.type === 'application/ld+json')) {
+            console.warn('[Vango] Blocked head element:', tag);
+            return;
+        }
+
+        const el = document.createElement(tag);
+        for (const [name, value] of Object.entries(attrs)) {
+            this._setAttr(el, name, value);
+        }
+        el.setAttribute('data-vango-head', key);
+        if (tag === 'script') {
+            el.textContent = (vnode.children || []).map(child => child.text || '').join('');
+        }
+
+        const existing = this._findHead(key);
+        if (existing) {
+            existing.replaceWith(el);
+        } else {
+            document.head.appendChild(el);
+        }
+    }
+
+    /**
+     * Remove a managed head element
+     */
+    _removeHead(key) {
+        const el = this._findHead(key);
+        if (el) {
+            el.remove();
+        }
+    }
+
     /**
      * Dispatch custom event
      */
@@ -0,0 +1,116 @@
+/**
+ * Portals
+ *
+ * Keeps the children of vdom.Portal nodes in their target container. The
+ * portal element (<vango-portal>) stays where the server's tree has it;
+ * its children live in a <vango-portal-content> element appended to
+ * [data-portal-target="<name>"], or to the body without one. Patches
+ * addressing the portal as a parent are applied to the content element.
+ */
+
+const PORTAL = 'vango-portal';
+const CONTENT = 'vango-portal-content';
+
+export class PortalManager {
+    constructor(client) {
+        this.client = client;
+        this.contents = new Map(); // portal element -> content element
+    }
+
+    /**
+     * Link the portals rendered by the server to their content, and move
+     * the children of the ones rendered after their target
+     */
+    initializeFromDOM() {
+        for (const content of document.querySelectorAll(`${CONTENT}[data-portal-for]`)) {
+            const portal = this.client.getNode(content.getAttribute('data-portal-for'));
+            if (!portal) continue;
+            this.contents.set(portal, content);
+
+            // Content held for a target the server did not render
+            const target = this._target(portal);
+            if (!target.contains(content)) {
+                target.appendChild(content);
+            }
+        }
+
+        for (const portal of document.querySelectorAll(PORTAL)) {
+            this.mount(portal);
+        }
+    }
+
+    /**
+     * Move the children of a portal into a new content element in its
+     * target. The portal may not be attached yet.
+     */
+    mount(portal) {
+        if (this.contents.has(portal)) return;
+
+        const content = document.createElement(CONTENT);
+        content.style.display = 'contents';
+        if (portal.dataset.hid) {
+            content.setAttribute('data-portal-for', portal.dataset.hid);
+        }
+        content.append(...portal.childNodes);
+        this._target(portal).appendChild(content);
+        this.contents.set(portal, content);
+    }
+
+    /**
+     * The element holding the children of el: its content element if el
+     * is a portal, el otherwise
+     */
+    container(el) {
+        return (el && this.contents.get(el)) || el;
+    }
+
+    /**
+     * Remove the content of the portals in the subtree of el, including
+     * portals nested in that content. release is called with each element
+     * being removed, before it is removed.
+     */
+    unmount(el, release) {
+        const portals = el.localName === PORTAL ? [el] : [];
+        portals.push(...el.querySelectorAll(PORTAL));
+
+        for (const portal of portals) {
+            const content = this.contents.get(portal);
+            if (!content) continue;
+            this.contents.delete(portal);
+
+            content.querySelectorAll('[data-hid]').forEach(release);
+            this.unmount(content, release);
+            this._leave(content);
+        }
+    }
+
+    /**
+     * Remove content once the leave transitions of its children end
+     */
+    _leave(content) {
+        const children = [...content.children];
+        let remaining = children.length;
+        if (remaining === 0) {
+            content.remove();
+            return;
+        }
+        for (const child of children) {
+            this.client.transitions.leave(child, () => {
+                if (--remaining === 0) content.remove();
+            });
+        }
+    }
+
+    /**
+     * The target container of a portal, or the body
+     */
+    _target(portal) {
+        const name = portal.getAttribute('data-portal');
+        if (name) {
+            for (const el of document.querySelectorAll('[data-portal-target]')) {
+                if (el.getAttribute('data-portal-target') === name) return el;
+            }
+        }
+        return document.body;
+    }
+}
@@ -0,0 +1,236 @@
+/**
+ * Transitions
+ *
+ * Animates elements declared with vdom.Transition when patches insert,
+ * remove or move them. The DOM only lags behind the server's tree:
+ * removed elements are unregistered at once and kept in the DOM, inert,
+ * until their leave transition ends. Patches skip them when indexing
+ * children, so later inserts and moves land where the server expects.
+ */
+
+const LEAVING = 'data-vango-leaving';
+
+export class TransitionManager {
+    constructor(client) {
+        this.client = client;
+    }
+
+    /**
+     * Whether the element declares a transition
+     */
+    has(el) {
+        if (!el || el.nodeType !== 1) return false;
+        for (const key in el.dataset) {
+            if (key.startsWith('transition')) return true;
+        }
+        return false;
+    }
+
+    /**
+     * Whether the element is kept in the DOM for its leave transition
+     */
+    isLeaving(el) {
+        return el.hasAttribute(LEAVING);
+    }
+
+    /**
+     * The child at index among the children not leaving, or null past
+     * the end
+     */
+    childAt(parentEl, index) {
+        let i = 0;
+        for (const child of parentEl.children) {
+            if (this.isLeaving(child)) continue;
+            if (i === index) return child;
+            i++;
+        }
+        return null;
+    }
+
+    /**
+     * Run the enter transition of an inserted element
+     */
+    enter(el) {
+        if (!this.has(el) || this._reducedMotion()) return;
+
+        const { active, from, to } = this._classes(el, 'enter');
+        this._add(el, active, from);
+        this._nextFrame(() => {
+            this._remove(el, from);
+            this._add(el, to);
+            this._whenDone(el, () => this._remove(el, active, to));
+        });
+    }
+
+    /**
+     * Run the leave transition of a removed element, then call done.
+     * The element must already be unregistered.
+     */
+    leave(el, done) {
+        if (!this.has(el) || this._reducedMotion()) {
+            done();
+            return;
+        }
+
+        // Out of the way of hooks, events and patches while it leaves
+        el.setAttribute(LEAVING, '');
+        el.inert = true;
+        el.removeAttribute('data-hid');
+        el.querySelectorAll('[data-hid]').forEach(child => child.removeAttribute('data-hid'));
+
+        const { active, from, to } = this._classes(el, 'leave');
+        this._add(el, active, from);
+        this._nextFrame(() => {
+            this._remove(el, from);
+            this._add(el, to);
+            this._whenDone(el, done);
+        });
+    }
+
+    /**
+     * Record the positions of the children with a move transition of
+     * parents about to change (FLIP: first)
+     */
+    capture(parents) {
+        const first = new Map();
+        for (const parentEl of parents) {
+            for (const child of parentEl.children) {
+                if (!this.isLeaving(child) && this._moveClass(child)) {
+                    first.set(child, child.getBoundingClientRect());
+                }
+            }
+        }
+        return first;
+    }
+
+    /**
+     * Slide the captured children from their old positions to their new
+     * ones (FLIP: last, invert, play)
+     */
+    play(first) {
+        if (first.size === 0 || this._reducedMotion()) return;
+
+        const moved = [];
+        for (const [el, rect] of first) {
+            if (!el.isConnected || this.isLeaving(el)) continue;
+            const last = el.getBoundingClientRect();
+            const dx = rect.left - last.left;
+            const dy = rect.top - last.top;
+            if (dx === 0 && dy === 0) continue;
+
+            el.style.transition = 'none';
+            el.style.transform = `translate(${dx}px, ${dy}px)`;
+            moved.push(el);
+        }
+        if (moved.length === 0) return;
+
+        // Apply the inverted positions before animating to the new ones
+        document.body.offsetHeight;
+
+        for (const el of moved) {
+            const move = this._moveClass(el);
+            this._add(el, move);
+            el.style.transition = '';
+            el.style.transform = '';
+            this._whenDone(el, () => this._remove(el, move));
+        }
+    }
+
+    /**
+     * The active, from and to classes of a phase of the transition
+     */
+    _classes(el, phase) {
+        return {
+            active: this._class(el, phase, `${phase}-active`),
+            from: this._class(el, `${phase}-from`, `${phase}-from`),
+            to: this._class(el, `${phase}-to`, `${phase}-to`),
+        };
+    }
+
+    _moveClass(el) {
+        return this._class(el, 'move', 'move');
+    }
+
+    /**
+     * The class of data-transition-<key>, or <name>-<suffix> if the
+     * transition is named
+     */
+    _class(el, key, suffix) {
+        const explicit = el.getAttribute(`data-transition-${key}`);
+        if (explicit) return explicit;
+        const name = el.dataset.transition;
+        return name ? `${name}-${suffix}` : '';
+    }
+
+    _add(el, ...classes) {
+        for (const c of classes) {
+            if (c) el.classList.add(...c.split(/\s+/).filter(Boolean));
+        }
+    }
+
+    _remove(el, ...classes) {
+        for (const c of classes) {
+            if (c) el.classList.remove(...c.split(/\s+/).filter(Boolean));
+        }
+    }
+
+    _nextFrame(fn) {
+        requestAnimationFrame(() => requestAnimationFrame(fn));
+    }
+
+    /**
+     * Call fn once the transition of el ends, or its duration elapsed
+     */
+    _whenDone(el, fn) {
+        let called = false;
+        const finish = () => {
+            if (called) return;
+            called = true;
+            el.removeEventListener('transitionend', onEnd);
+            el.removeEventListener('animationend', onEnd);
+            clearTimeout(timer);
+            fn();
+        };
+        const onEnd = (e) => {
+            if (e.target === el) finish();
+        };
+
+        el.addEventListener('transitionend', onEnd);
+        el.addEventListener('animationend', onEnd);
+        const timer = setTimeout(finish, this._duration(el) + 50);
+    }
+
+    /**
+     * The declared duration in ms, or the longest computed transition
+     * or animation
+     */
+    _duration(el) {
+        const declared = parseInt(el.dataset.transitionDuration, 10);
+        if (declared >= 0) return declared;
+
+        const style = getComputedStyle(el);
+        const longest = (durations, delays) => {
+            const d = durations.split(',').map(toMs);
+            const w = delays.split(',').map(toMs);
+            return Math.max(0, ...d.map((v, i) => v + (w[i % w.length] || 0)));
+        };
+        return Math.max(
+            longest(style.transitionDuration, style.transitionDelay),
+            longest(style.animationDuration, style.animationDelay)
+        );
+    }
+
+    _reducedMotion() {
+        return typeof matchMedia === 'function' &&
+            matchMedia('(prefers-reduced-motion: reduce)').matches;
+    }
+}
+
+/**
+ * Parse a CSS time ("0.2s", "150ms") to milliseconds
+ */
+function toMs(value) {
+    const v = value.trim();
+    if (v.endsWith('ms')) return parseFloat(v) || 0;
+    return (parseFloat(v) || 0) * 1000;
+}
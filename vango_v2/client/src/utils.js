@@ -77,3 +77,24 @@ export function throttle(fn, limit) {
         }
     };
 }
+
+/**
+ * Media query preferences of the browser, as sent to the server
+ * @returns {{colorScheme: number, reducedMotion: boolean, pixelRatio: number}}
+ */
+export function mediaPreferences() {
+    const matches = (query) => !!(window.matchMedia && window.matchMedia(query).matches);
+
+    let colorScheme = 0; // No preference
+    if (matches('(prefers-color-scheme: dark)')) {
+        colorScheme = 2;
+    } else if (matches('(prefers-color-scheme: light)')) {
+        colorScheme = 1;
+    }
+
+    return {
+        colorScheme,
+        reducedMotion: matches('(prefers-reduced-motion: reduce)'),
+        pixelRatio: Math.round((window.devicePixelRatio || 1) * 100),
+    };
+}
@@ -116,6 +116,12 @@ export class WebSocketManager {
                 return;
             }
 
+            // The server no longer speaks our protocol: reload to get a
+            // current bundle, once, in case the cache still serves this one
+            if (hello.status === 0x05 && this._reloadForUpgrade()) {
+                return;
+            }
+
             if (!hello.ok) {
                 const errorMessages = {
                     0x01: 'Version mismatch',
@@ -136,6 +142,8 @@ export class WebSocketManager {
             this.handshakeComplete = true;
             this.connected = true;
             this.sessionId = hello.sessionId;
+            this.protocolVersion = hello.version;
+            this.capabilities = hello.capabilities;
             this.client._onConnected();
 
             // Send queued messages
@@ -151,6 +159,31 @@ export class WebSocketManager {
         this.client._handleBinaryMessage(buffer);
     }
 
+    /**
+     * Hard-reload the page after HandshakeUpgradeRequired.
+     * Returns false if the page was already reloaded for an upgrade recently.
+     */
+    _reloadForUpgrade() {
+        const key = '__vango_upgrade_reload';
+        try {
+            const last = Number(sessionStorage.getItem(key) || 0);
+            if (Date.now() - last < 60000) {
+                return false;
+            }
+            sessionStorage.setItem(key, String(Date.now()));
+        } catch (e) {
+            // Storage unavailable: reload anyway
+        }
+
+        if (this.client.options.debug) {
+            console.log('[Vango] Protocol upgrade required, reloading');
+        }
+        this.options.reconnect = false;
+        this.ws.close();
+        window.location.reload();
+        return true;
+    }
+
     /**
      * Handle WebSocket close
      */
@@ -171,6 +171,30 @@ describe('BinaryCodec', () => {
             expect(patches[0].value).toBe('hello');
         });
 
+        test('decodes SET_TITLE patch', () => {
+            const parts = [
+                codec.encodeUvarint(2), // seq
+                codec.encodeUvarint(1), // count
+                new Uint8Array([PatchType.SET_TITLE]),
+                codec.encodeString(''),
+                codec.encodeString('Profile | Acme'),
+            ];
+
+            let totalLength = 0;
+            for (const p of parts) totalLength += p.length;
+            const buffer = new Uint8Array(totalLength);
+            let offset = 0;
+            for (const p of parts) {
+                buffer.set(p, offset);
+                offset += p.length;
+            }
+
+            const { patches } = codec.decodePatches(buffer);
+
+            expect(patches[0].type).toBe(PatchType.SET_TITLE);
+            expect(patches[0].value).toBe('Profile | Acme');
+        });
+
         test('decodes SET_ATTR patch', () => {
             const parts = [
                 codec.encodeUvarint(1), // seq
@@ -358,4 +382,10 @@ describe('PatchType constants', () => {
         expect(PatchType.ADD_CLASS).toBe(0x10);
         expect(PatchType.REMOVE_CLASS).toBe(0x11);
     });
+
+    test('head operations have correct values', () => {
+        expect(PatchType.SET_TITLE).toBe(0x40);
+        expect(PatchType.SET_HEAD).toBe(0x41);
+        expect(PatchType.REMOVE_HEAD).toBe(0x42);
+    });
 });
@@ -0,0 +1,336 @@
+/**
+ * Minimal DOM for patch tests
+ *
+ * Jest runs the client tests in the node environment. This implements the
+ * part of the DOM that patches, transitions and portals use: the element
+ * tree, attributes, dataset, classList, listeners and simple selectors
+ * (tag, [attr] and [attr="value"]). Animation frames are queued and run
+ * with flushFrames().
+ */
+
+import { PatchApplier } from '../src/patches.js';
+import { TransitionManager } from '../src/transitions.js';
+import { PortalManager } from '../src/portals.js';
+
+class Node {
+    constructor(nodeType) {
+        this.nodeType = nodeType;
+        this.parentNode = null;
+        this.childNodes = [];
+    }
+
+    get parentElement() {
+        return this.parentNode && this.parentNode.nodeType === 1 ? this.parentNode : null;
+    }
+
+    get children() {
+        return this.childNodes.filter(n => n.nodeType === 1);
+    }
+
+    get isConnected() {
+        let n = this;
+        while (n.parentNode) n = n.parentNode;
+        return n === document.documentElement;
+    }
+
+    get textContent() {
+        return this.childNodes.map(n => n.textContent).join('');
+    }
+
+    set textContent(text) {
+        for (const child of this.childNodes) child.parentNode = null;
+        this.childNodes = [];
+        if (text !== '') this.appendChild(new Text(text));
+    }
+
+    appendChild(node) {
+        return this.insertBefore(node, null);
+    }
+
+    append(...nodes) {
+        for (const node of nodes) this.appendChild(node);
+    }
+
+    insertBefore(node, ref) {
+        if (node.nodeType === 11) {
+            for (const child of [...node.childNodes]) this.insertBefore(child, ref);
+            return node;
+        }
+        if (node.parentNode) node.parentNode.removeChild(node);
+        const i = ref ? this.childNodes.indexOf(ref) : -1;
+        if (i < 0) {
+            this.childNodes.push(node);
+        } else {
+            this.childNodes.splice(i, 0, node);
+        }
+        node.parentNode = this;
+        return node;
+    }
+
+    removeChild(node) {
+        this.childNodes.splice(this.childNodes.indexOf(node), 1);
+        node.parentNode = null;
+        return node;
+    }
+
+    remove() {
+        if (this.parentNode) this.parentNode.removeChild(this);
+    }
+
+    before(node) {
+        this.parentNode.insertBefore(node, this);
+    }
+
+    replaceWith(node) {
+        const parent = this.parentNode;
+        parent.insertBefore(node, this);
+        parent.removeChild(this);
+    }
+
+    contains(node) {
+        for (let n = node; n; n = n.parentNode) {
+            if (n === this) return true;
+        }
+        return false;
+    }
+
+    querySelectorAll(selector) {
+        const match = parseSelector(selector);
+        const found = [];
+        const walk = (node) => {
+            for (const child of node.children) {
+                if (match(child)) found.push(child);
+                walk(child);
+            }
+        };
+        walk(this);
+        return found;
+    }
+}
+
+class Text extends Node {
+    constructor(text) {
+        super(3);
+        this.data = text;
+    }
+
+    get textContent() {
+        return this.data;
+    }
+
+    set textContent(text) {
+        this.data = text;
+    }
+}
+
+class Fragment extends Node {
+    constructor() {
+        super(11);
+    }
+}
+
+class Element extends Node {
+    constructor(tag) {
+        super(1);
+        this.localName = tag.toLowerCase();
+        this.tagName = tag.toUpperCase();
+        this.attributes = new Map();
+        this.style = {};
+        this.inert = false;
+        this.listeners = {};
+        this.dataset = datasetOf(this);
+        this.classList = classListOf(this);
+    }
+
+    get className() {
+        return this.getAttribute('class') || '';
+    }
+
+    set className(value) {
+        this.setAttribute('class', value);
+    }
+
+    getAttribute(name) {
+        return this.attributes.has(name) ? this.attributes.get(name) : null;
+    }
+
+    setAttribute(name, value) {
+        this.attributes.set(name, String(value));
+    }
+
+    hasAttribute(name) {
+        return this.attributes.has(name);
+    }
+
+    removeAttribute(name) {
+        this.attributes.delete(name);
+    }
+
+    addEventListener(type, fn) {
+        (this.listeners[type] ||= []).push(fn);
+    }
+
+    removeEventListener(type, fn) {
+        this.listeners[type] = (this.listeners[type] || []).filter(l => l !== fn);
+    }
+
+    dispatchEvent(event) {
+        event.target ||= this;
+        for (const fn of [...(this.listeners[event.type] || [])]) fn(event);
+        return true;
+    }
+
+    getBoundingClientRect() {
+        const i = this.parentNode ? this.parentNode.children.indexOf(this) : 0;
+        return { left: 0, top: i * 10, width: 100, height: 10 };
+    }
+}
+
+/**
+ * The data-* attributes of el as a dataset object
+ */
+function datasetOf(el) {
+    const attr = key => 'data-' + key.replace(/[A-Z]/g, c => '-' + c.toLowerCase());
+    const keys = () => [...el.attributes.keys()]
+        .filter(name => name.startsWith('data-'))
+        .map(name => name.slice(5).replace(/-([a-z])/g, (_, c) => c.toUpperCase()));
+
+    return new Proxy({}, {
+        get: (_, key) => el.getAttribute(attr(key)) ?? undefined,
+        set: (_, key, value) => {
+            el.setAttribute(attr(key), value);
+            return true;
+        },
+        deleteProperty: (_, key) => {
+            el.removeAttribute(attr(key));
+            return true;
+        },
+        has: (_, key) => el.hasAttribute(attr(key)),
+        ownKeys: () => keys(),
+        getOwnPropertyDescriptor: (_, key) => el.hasAttribute(attr(key))
+            ? { value: el.getAttribute(attr(key)), enumerable: true, configurable: true }
+            : undefined,
+    });
+}
+
+/**
+ * The class attribute of el as a classList object
+ */
+function classListOf(el) {
+    const list = () => el.className.split(/\s+/).filter(Boolean);
+    const set = classes => { el.className = [...new Set(classes)].join(' '); };
+    return {
+        add: (...classes) => set([...list(), ...classes]),
+        remove: (...classes) => set(list().filter(c => !classes.includes(c))),
+        toggle: c => (list().includes(c) ? set(list().filter(x => x !== c)) : set([...list(), c])),
+        contains: c => list().includes(c),
+    };
+}
+
+/**
+ * A matcher for selectors of the form tag, [attr], [attr="value"] or a
+ * tag followed by one attribute
+ */
+function parseSelector(selector) {
+    const m = /^([a-z-]*)(?:\[([a-z-]+)(?:="([^"]*)")?\])?$/.exec(selector);
+    if (!m) throw new Error(`unsupported selector: ${selector}`);
+    const [, tag, name, value] = m;
+    return el =>
+        (!tag || el.localName === tag) &&
+        (!name || (el.hasAttribute(name) && (value === undefined || el.getAttribute(name) === value)));
+}
+
+let frames = [];
+
+/**
+ * Install a fresh document and the globals used by the client
+ */
+export function installDOM() {
+    const documentElement = new Element('html');
+    const head = new Element('head');
+    const body = new Element('body');
+    documentElement.append(head, body);
+
+    globalThis.document = {
+        documentElement,
+        head,
+        body,
+        title: '',
+        createElement: tag => new Element(tag),
+        createTextNode: text => new Text(text),
+        createDocumentFragment: () => new Fragment(),
+        querySelectorAll: selector => documentElement.querySelectorAll(selector),
+    };
+    globalThis.requestAnimationFrame = fn => frames.push(fn);
+    globalThis.getComputedStyle = () => ({
+        transitionDuration: '0s',
+        transitionDelay: '0s',
+        animationDuration: '0s',
+        animationDelay: '0s',
+    });
+    frames = [];
+    return globalThis.document;
+}
+
+/**
+ * Run queued animation frames, including frames they request
+ */
+export function flushFrames() {
+    while (frames.length > 0) {
+        const queued = frames;
+        frames = [];
+        for (const fn of queued) fn();
+    }
+}
+
+/**
+ * Build an element: h('li', { 'data-hid': 'h2' }, 'text', child)
+ */
+export function h(tag, attrs = {}, ...children) {
+    const el = new Element(tag);
+    for (const [name, value] of Object.entries(attrs)) el.setAttribute(name, value);
+    for (const child of children) {
+        el.appendChild(typeof child === 'string' ? new Text(child) : child);
+    }
+    return el;
+}
+
+/**
+ * A client with the patch, transition and portal managers, hydrated from
+ * the document body
+ */
+export function createClient() {
+    const client = {
+        options: { debug: false },
+        nodeMap: new Map(),
+        getNode(hid) {
+            return this.nodeMap.get(hid);
+        },
+        registerNode(hid, node) {
+            this.nodeMap.set(hid, node);
+        },
+        unregisterNode(hid) {
+            this.nodeMap.delete(hid);
+        },
+        hooks: {
+            initializeForNode() {},
+            destroyForNode() {},
+        },
+    };
+    client.transitions = new TransitionManager(client);
+    client.portals = new PortalManager(client);
+    client.patchApplier = new PatchApplier(client);
+
+    for (const el of document.body.querySelectorAll('[data-hid]')) {
+        client.registerNode(el.dataset.hid, el);
+    }
+    client.portals.initializeFromDOM();
+    return client;
+}
+
+/**
+ * The hids of the children of el, skipping children without one
+ */
+export function hids(el) {
+    return el.children.map(child => child.dataset.hid).filter(Boolean);
+}
@@ -0,0 +1,119 @@
+/**
+ * Portal Tests
+ *
+ * Nodes inside a portal live in its target container but are patched by
+ * hid like any other node; the portal itself stays in the server's tree.
+ */
+
+import { describe, test, expect, beforeEach } from '@jest/globals';
+import { PatchType } from '../src/codec.js';
+import { installDOM, h, createClient, hids } from './dom.js';
+
+describe('Patches inside portals', () => {
+    let client;
+    let target;
+    let portal;
+
+    beforeEach(() => {
+        const document = installDOM();
+        portal = h('vango-portal', { 'data-hid': 'h2', 'data-portal': 'modal' },
+            h('p', { 'data-hid': 'h3' }, 'hello'),
+        );
+        target = h('div', { 'data-portal-target': 'modal' });
+        document.body.append(
+            h('main', { 'data-hid': 'h1' }, portal),
+            target,
+        );
+        client = createClient();
+    });
+
+    /**
+     * The content element of the portal in its target
+     */
+    function content() {
+        return target.querySelectorAll('vango-portal-content')[0];
+    }
+
+    test('children are moved to the target', () => {
+        expect(portal.childNodes.length).toBe(0);
+        expect(content().getAttribute('data-portal-for')).toBe('h2');
+        expect(hids(content())).toEqual(['h3']);
+        expect(target.contains(client.getNode('h3'))).toBe(true);
+    });
+
+    test('set-text on a node inside the portal target', () => {
+        client.patchApplier.apply([{ type: PatchType.SET_TEXT, hid: 'h3', value: 'bye' }]);
+        expect(client.getNode('h3').textContent).toBe('bye');
+        expect(target.textContent).toBe('bye');
+    });
+
+    test('set-text on the portal sets the text of its content', () => {
+        client.patchApplier.apply([{ type: PatchType.SET_TEXT, hid: 'h2', value: 'plain' }]);
+        expect(content().textContent).toBe('plain');
+        expect(portal.childNodes.length).toBe(0);
+    });
+
+    test('set-attr on a node inside the portal target', () => {
+        client.patchApplier.apply([
+            { type: PatchType.SET_ATTR, hid: 'h3', key: 'class', value: 'title' },
+            { type: PatchType.SET_ATTR, hid: 'h3', key: 'aria-live', value: 'polite' },
+        ]);
+        const p = client.getNode('h3');
+        expect(p.className).toBe('title');
+        expect(p.getAttribute('aria-live')).toBe('polite');
+    });
+
+    test('insert into the portal lands in the target', () => {
+        client.patchApplier.apply([{
+            type: PatchType.INSERT_NODE,
+            parentID: 'h2',
+            index: 1,
+            vnode: { type: 'element', tag: 'button', hid: 'h4', attrs: {}, children: [{ type: 'text', text: 'ok' }] },
+        }]);
+        expect(hids(content())).toEqual(['h3', 'h4']);
+        expect(target.contains(client.getNode('h4'))).toBe(true);
+
+        client.patchApplier.apply([{
+            type: PatchType.INSERT_NODE,
+            parentID: 'h2',
+            index: 0,
+            vnode: { type: 'element', tag: 'h2', hid: 'h5', attrs: {}, children: [] },
+        }]);
+        expect(hids(content())).toEqual(['h5', 'h3', 'h4']);
+    });
+
+    test('remove a node inside the portal target', () => {
+        const p = client.getNode('h3');
+        client.patchApplier.apply([{ type: PatchType.REMOVE_NODE, hid: 'h3' }]);
+        expect(p.parentNode).toBeNull();
+        expect(client.getNode('h3')).toBeUndefined();
+        expect(content().childNodes.length).toBe(0);
+
+        // The portal keeps its content element for later inserts
+        client.patchApplier.apply([{
+            type: PatchType.INSERT_NODE,
+            parentID: 'h2',
+            index: 0,
+            vnode: { type: 'element', tag: 'p', hid: 'h6', attrs: {}, children: [] },
+        }]);
+        expect(hids(content())).toEqual(['h6']);
+    });
+
+    test('removing the portal removes its content from the target', () => {
+        client.patchApplier.apply([{ type: PatchType.REMOVE_NODE, hid: 'h2' }]);
+        expect(portal.parentNode).toBeNull();
+        expect(target.childNodes.length).toBe(0);
+        expect(client.getNode('h2')).toBeUndefined();
+        expect(client.getNode('h3')).toBeUndefined();
+
+        // Later patches for its children are dropped
+        client.patchApplier.apply([{ type: PatchType.SET_TEXT, hid: 'h3', value: 'stale' }]);
+        expect(target.textContent).toBe('');
+    });
+
+    test('removing an ancestor of the portal removes its content', () => {
+        client.patchApplier.apply([{ type: PatchType.REMOVE_NODE, hid: 'h1' }]);
+        expect(target.childNodes.length).toBe(0);
+        expect(client.getNode('h3')).toBeUndefined();
+    });
+});
@@ -0,0 +1,123 @@
+/**
+ * Transition Tests
+ *
+ * Patches that arrive while a removed element is still leaving must land
+ * where the server expects, as if the element were already gone.
+ */
+
+import { describe, test, expect, beforeEach } from '@jest/globals';
+import { PatchType } from '../src/codec.js';
+import { installDOM, flushFrames, h, createClient, hids } from './dom.js';
+
+/**
+ * End the leave transition of el
+ */
+function finishLeave(el) {
+    flushFrames();
+    el.dispatchEvent({ type: 'transitionend', target: el });
+}
+
+/**
+ * The hids of the children of el that are not leaving
+ */
+function visible(client, el) {
+    return el.children
+        .filter(child => !client.transitions.isLeaving(child))
+        .map(child => child.dataset.hid);
+}
+
+describe('Patches during leave transitions', () => {
+    let client;
+    let list;
+    let leaving;
+
+    beforeEach(() => {
+        const document = installDOM();
+        leaving = h('li', { 'data-hid': 'h2', 'data-transition': 'fade', 'data-transition-duration': '0' }, 'a');
+        list = h('ul', { 'data-hid': 'h1' },
+            leaving,
+            h('li', { 'data-hid': 'h3' }, 'b'),
+            h('li', { 'data-hid': 'h4' }, 'c'),
+        );
+        document.body.appendChild(list);
+        client = createClient();
+    });
+
+    test('a removed element stays until its leave transition ends', () => {
+        client.patchApplier.apply([{ type: PatchType.REMOVE_NODE, hid: 'h2' }]);
+
+        expect(leaving.parentNode).toBe(list);
+        expect(client.transitions.isLeaving(leaving)).toBe(true);
+        expect(leaving.hasAttribute('data-hid')).toBe(false);
+        expect(client.getNode('h2')).toBeUndefined();
+
+        finishLeave(leaving);
+        expect(leaving.parentNode).toBeNull();
+        expect(hids(list)).toEqual(['h3', 'h4']);
+    });
+
+    test('a remove followed by a patch aimed at a sibling', () => {
+        client.patchApplier.apply([
+            { type: PatchType.REMOVE_NODE, hid: 'h2' },
+            { type: PatchType.SET_TEXT, hid: 'h3', value: 'b2' },
+            { type: PatchType.SET_ATTR, hid: 'h4', key: 'class', value: 'last' },
+        ]);
+
+        expect(client.getNode('h3').textContent).toBe('b2');
+        expect(client.getNode('h4').className).toBe('last');
+        expect(leaving.textContent).toBe('a');
+
+        // A late patch for the removed element is dropped
+        client.patchApplier.apply([{ type: PatchType.SET_TEXT, hid: 'h2', value: 'stale' }]);
+        expect(leaving.textContent).toBe('a');
+
+        finishLeave(leaving);
+        expect(hids(list)).toEqual(['h3', 'h4']);
+        expect(list.textContent).toBe('b2c');
+    });
+
+    test('a remove followed by an insert at the same index', () => {
+        client.patchApplier.apply([
+            { type: PatchType.REMOVE_NODE, hid: 'h2' },
+            {
+                type: PatchType.INSERT_NODE,
+                parentID: 'h1',
+                index: 0,
+                vnode: { type: 'element', tag: 'li', hid: 'h5', attrs: {}, children: [{ type: 'text', text: 'new' }] },
+            },
+        ]);
+
+        // The new element takes the index of the removed one among the
+        // elements that stay
+        expect(visible(client, list)).toEqual(['h5', 'h3', 'h4']);
+        expect(client.getNode('h5').textContent).toBe('new');
+
+        // A second insert counts from the server's tree, not the DOM
+        client.patchApplier.apply([{
+            type: PatchType.INSERT_NODE,
+            parentID: 'h1',
+            index: 1,
+            vnode: { type: 'element', tag: 'li', hid: 'h6', attrs: {}, children: [] },
+        }]);
+        expect(visible(client, list)).toEqual(['h5', 'h6', 'h3', 'h4']);
+
+        finishLeave(leaving);
+        expect(hids(list)).toEqual(['h5', 'h6', 'h3', 'h4']);
+    });
+
+    test('a keyed move while a leave is still running', () => {
+        client.patchApplier.apply([{ type: PatchType.REMOVE_NODE, hid: 'h2' }]);
+
+        // The server's list is now [h3, h4]; it moves h4 to the front
+        client.patchApplier.apply([{ type: PatchType.MOVE_NODE, hid: 'h4', parentID: 'h1', index: 0 }]);
+        expect(visible(client, list)).toEqual(['h4', 'h3']);
+        expect(client.transitions.isLeaving(leaving)).toBe(true);
+
+        // And back: the diff moves h3 to the front
+        client.patchApplier.apply([{ type: PatchType.MOVE_NODE, hid: 'h3', parentID: 'h1', index: 0 }]);
+        expect(visible(client, list)).toEqual(['h3', 'h4']);
+
+        finishLeave(leaving);
+        expect(hids(list)).toEqual(['h3', 'h4']);
+    });
+});
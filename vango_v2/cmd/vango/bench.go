@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vango-dev/vango/v2/internal/config"
+	"github.com/vango-dev/vango/v2/pkg/bench"
+)
+
+func benchCmd() *cobra.Command {
+	var (
+		url        string
+		clients    int
+		rampUp     time.Duration
+		duration   time.Duration
+		iterations int
+		timeout    time.Duration
+		headers    []string
+		output     string
+		jsonOut    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "bench <flow>",
+		Short: "Load test a running app over the live protocol",
+		Long: `Run virtual clients against a running app.
+
+Each client opens a session like the browser (page request, handshake)
+and repeats a flow of events, waiting for the patches of each event. The
+flow is a JSON script or a session recording (.vrec):
+
+  {
+    "path": "/todos",
+    "steps": [
+      {"event": "input", "hid": "h3", "value": "Buy milk"},
+      {"event": "submit", "hid": "h2", "fields": {"title": "Buy milk"}},
+      {"wait": "500ms"},
+      {"event": "click", "hid": "h7"}
+    ]
+  }
+
+The HIDs of elements are shown by the session inspector (/_vango/debug).
+
+Results include event→patch latency percentiles, bytes per event, error
+and reconnect rates, and the memory per session when the app serves the
+session inspector to the requests of the test (see --header).
+
+Examples:
+  vango bench flow.json --clients=1000 --ramp=30s --duration=2m
+  vango bench recordings/4f9c2e.vrec --clients=200 --iterations=5
+  vango bench flow.json --output=bench.json --header="Authorization: Bearer $TOKEN"`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			header := http.Header{}
+			for _, h := range headers {
+				name, value, ok := strings.Cut(h, ":")
+				if !ok {
+					return fmt.Errorf("invalid header %q, want \"Name: value\"", h)
+				}
+				header.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+			}
+			return runBench(args[0], url, bench.Options{
+				Clients:    clients,
+				RampUp:     rampUp,
+				Duration:   duration,
+				Iterations: iterations,
+				Timeout:    timeout,
+				Header:     header,
+			}, output, jsonOut)
+		},
+	}
+
+	cmd.Flags().StringVar(&url, "url", "", "Base URL of the app (default: the dev server URL)")
+	cmd.Flags().IntVarP(&clients, "clients", "c", bench.DefaultClients, "Number of virtual clients")
+	cmd.Flags().DurationVar(&rampUp, "ramp", 0, "Time over which the clients are started")
+	cmd.Flags().DurationVarP(&duration, "duration", "d", 0, "Stop after this time")
+	cmd.Flags().IntVarP(&iterations, "iterations", "n", 0, "Flow runs per client (default: 1, or unlimited with --duration)")
+	cmd.Flags().DurationVar(&timeout, "timeout", bench.DefaultTimeout, "How long to wait for the patches of an event")
+	cmd.Flags().StringArrayVarP(&headers, "header", "H", nil, "Header sent with every request (repeatable)")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Write the JSON report to this file")
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "Print the JSON report instead of the summary")
+
+	return cmd
+}
+
+func runBench(file, url string, opts bench.Options, output string, jsonOut bool) error {
+	flow, err := bench.LoadFlow(file)
+	if err != nil {
+		return err
+	}
+
+	if url == "" {
+		url = "http://localhost:3000"
+		if cfg, err := config.LoadFromWorkingDir(); err == nil {
+			url = cfg.DevURL()
+		}
+	}
+	opts.URL = url
+
+	// Handle signals: stop the test and report what was measured
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+
+	go func() {
+		<-sigCh
+		cancel()
+	}()
+
+	if !jsonOut {
+		info("Running %d clients against %s%s...", max(opts.Clients, 1), url, flow.Path)
+		fmt.Println()
+	}
+
+	report, err := bench.Run(ctx, flow, opts)
+	if err != nil {
+		return err
+	}
+
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		err = report.WriteJSON(f)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return fmt.Errorf("writing report: %w", err)
+		}
+	}
+
+	if jsonOut {
+		return report.WriteJSON(os.Stdout)
+	}
+	report.WriteText(os.Stdout)
+	if output != "" {
+		fmt.Println()
+		success("Report written to %s", output)
+	}
+	return nil
+}
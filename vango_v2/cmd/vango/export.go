@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vango-dev/vango/v2/internal/build"
+	"github.com/vango-dev/vango/v2/internal/config"
+	"github.com/vango-dev/vango/v2/pkg/export"
+	"github.com/vango-dev/vango/v2/pkg/router"
+)
+
+func exportCmd() *cobra.Command {
+	var (
+		output    string
+		minify    bool
+		strictCSP bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export pages as a static site",
+		Long: `Render every page to static HTML for hosting without a Go server.
+
+This command:
+  • Builds the thin client, styles and hashed assets
+  • Scans app/routes/ for page routes
+  • Expands dynamic routes with the StaticParams function of their file
+  • Renders each page to <output>/<path>/index.html
+
+The output directory is replaced. It must not contain the project, and if
+it is not empty it must hold a previous export.
+
+Pages whose route file contains the //vango:static directive are exported
+without the client bootstrap. Other pages still need a Vango server for
+interactivity.
+
+Examples:
+  vango export
+  vango export --output=public_html`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runExport(output, minify, strictCSP)
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "dist", "Output directory")
+	cmd.Flags().BoolVar(&minify, "minify", true, "Minify output")
+	cmd.Flags().BoolVar(&strictCSP, "strict-csp", false, "Refuse raw HTML containing scripts")
+
+	return cmd
+}
+
+func runExport(output string, minify, strictCSP bool) error {
+	start := time.Now()
+
+	cfg, err := config.LoadFromWorkingDir()
+	if err != nil {
+		return err
+	}
+
+	if !filepath.IsAbs(output) {
+		output = filepath.Join(cfg.Dir(), output)
+	}
+
+	// Handle signals
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+
+	go func() {
+		<-sigCh
+		cancel()
+	}()
+
+	fmt.Println("  Exporting static site...")
+	fmt.Println()
+
+	// Scan routes
+	routesDir := cfg.RoutesPath()
+	info("Scanning %s...", routesDir)
+	routes, err := router.NewScanner(routesDir).Scan()
+	if err != nil {
+		return err
+	}
+
+	for _, route := range routes {
+		if route.HasPage && len(route.Params) > 0 && !route.HasStaticParams {
+			warn("%s has no StaticParams and will be skipped", route.Path)
+		}
+	}
+
+	// Regenerate routes_gen.go, which registers the StaticParams functions
+	// and static directives
+	if err := writeRoutesGen(cfg, routes, filepath.Join(routesDir, "routes_gen.go")); err != nil {
+		return err
+	}
+
+	modulePath, err := getModulePath(cfg.Dir())
+	if err != nil {
+		return fmt.Errorf("determining module path: %w", err)
+	}
+	routesImport, err := router.RoutesPackagePath(modulePath, cfg.Dir(), routesDir)
+	if err != nil {
+		return err
+	}
+
+	// Build assets into the output directory
+	if err := export.CleanOutDir(output, cfg.Dir()); err != nil {
+		return err
+	}
+	builder := build.New(cfg, build.Options{
+		Minify: minify,
+		OnProgress: func(step string) {
+			info(step)
+		},
+	})
+	if _, err := builder.BuildAssets(ctx, output); err != nil {
+		return err
+	}
+
+	// Generate and run the export program
+	info("Rendering pages...")
+	code, err := export.GenerateMain(routesImport)
+	if err != nil {
+		return err
+	}
+
+	programDir := filepath.Join(cfg.Dir(), ".vango", "export")
+	if err := os.MkdirAll(programDir, 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(programDir, "main.go"), code, 0644); err != nil {
+		return err
+	}
+
+	args := []string{"run", "./.vango/export",
+		"-out", output,
+		"-manifest", filepath.Join(output, export.ManifestFile),
+	}
+	if strictCSP {
+		args = append(args, "-strict-csp")
+	}
+
+	run := exec.CommandContext(ctx, "go", args...)
+	run.Dir = cfg.Dir()
+	run.Stdout = os.Stdout
+	run.Stderr = os.Stderr
+	if err := run.Run(); err != nil {
+		return fmt.Errorf("export failed: %w", err)
+	}
+
+	fmt.Println()
+	success("Export complete in %s", time.Since(start).Round(time.Millisecond))
+	fmt.Println()
+	fmt.Println("  Output:")
+	fmt.Printf("    %s/\n", output)
+	fmt.Println()
+
+	return nil
+}
@@ -1,15 +1,18 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"unicode"
 
 	"github.com/spf13/cobra"
 	"github.com/vango-dev/vango/v2/internal/config"
 	"github.com/vango-dev/vango/v2/internal/errors"
+	"github.com/vango-dev/vango/v2/pkg/i18n"
 	"github.com/vango-dev/vango/v2/pkg/router"
 )
 
@@ -27,16 +30,21 @@ Types:
   store       Generate a new store file
   middleware  Generate a new middleware file
   openapi     Generate OpenAPI 3.0 specification from API routes
+  client      Generate typed Go and TypeScript API clients
+  i18n        Extract translation keys into app/locales/ catalogs
 
 Examples:
   vango gen routes                    # Regenerate routes_gen.go
+  vango gen routes --check            # Report hrefs that match no route
   vango gen route users/[id]          # Generate app/routes/users/[id].go
   vango gen api products              # Generate app/routes/api/products.go
   vango gen component Card            # Generate app/components/card.go
   vango gen component shared/Button   # Generate app/components/shared/button.go
   vango gen store cart                # Generate app/store/cart.go
   vango gen middleware rate-limit     # Generate app/middleware/rate_limit.go
-  vango gen openapi                   # Generate openapi.json`,
+  vango gen openapi                   # Generate openapi.json
+  vango gen client                    # Generate apiclient/client.go and client.ts
+  vango gen i18n                      # Update app/locales/*.json`,
 	}
 
 	cmd.AddCommand(
@@ -47,6 +55,8 @@ Examples:
 		genStoreCmd(),
 		genMiddlewareCmd(),
 		genOpenAPICmd(),
+		genClientCmd(),
+		genI18nCmd(),
 	)
 
 	return cmd
@@ -57,7 +67,10 @@ Examples:
 // =============================================================================
 
 func genRoutesCmd() *cobra.Command {
-	var output string
+	var (
+		output string
+		check  bool
+	)
 
 	cmd := &cobra.Command{
 		Use:   "routes",
@@ -66,16 +79,27 @@ func genRoutesCmd() *cobra.Command {
 
 This command scans app/routes/ for Go files with Page, Layout, Middleware,
 and HTTP method functions (GET, POST, etc.) and generates the route
-registration glue code.
+registration glue code, route constants and a URL builder function for
+each page:
+
+  routes.UsersIDPostsSlugURL(42, "hello") // "/users/42/posts/hello"
 
 The output is deterministic - running it multiple times produces identical
-output unless the routes change.`,
+output unless the routes change.
+
+With --check, nothing is written. Instead the hrefs hard-coded in Href,
+Link, NavLink and Navigate calls are checked against the routes, and the
+command fails if any matches neither a route nor a public file.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if check {
+				return runCheckRoutes()
+			}
 			return runGenRoutes(output)
 		},
 	}
 
 	cmd.Flags().StringVarP(&output, "output", "o", "", "Output file (default: app/routes/routes_gen.go)")
+	cmd.Flags().BoolVar(&check, "check", false, "Check hard-coded hrefs against the routes instead of generating")
 
 	return cmd
 }
@@ -102,6 +126,21 @@ func runGenRoutes(output string) error {
 
 	info("Found %d routes", len(routes))
 
+	if err := reportRouteConflicts(routes); err != nil {
+		return err
+	}
+
+	if err := writeRoutesGen(cfg, routes, output); err != nil {
+		return err
+	}
+
+	success("Generated %s", output)
+	return nil
+}
+
+// writeRoutesGen generates the Register function of the scanned routes
+// into output.
+func writeRoutesGen(cfg *config.Config, routes []router.ScannedRoute, output string) error {
 	// Get module path from go.mod
 	modulePath, err := getModulePath(cfg.Dir())
 	if err != nil {
@@ -111,17 +150,75 @@ func runGenRoutes(output string) error {
 
 	// Generate code using the router package generator
 	gen := router.NewGenerator(routes, modulePath)
+	if pkg, err := router.RoutesPackagePath(modulePath, cfg.Dir(), cfg.RoutesPath()); err == nil {
+		gen.SetRoutesPackage(pkg)
+	}
 	code, err := gen.Generate()
 	if err != nil {
 		return err
 	}
 
-	// Write file
-	if err := os.WriteFile(output, code, 0644); err != nil {
+	return os.WriteFile(output, code, 0644)
+}
+
+// reportRouteConflicts prints the URLs matched by several route files and
+// the parameter constraints the router does not know.
+func reportRouteConflicts(routes []router.ScannedRoute) error {
+	for _, route := range routes {
+		for _, p := range route.Params {
+			if p.Constraint != "" && !router.KnownConstraint(p.Constraint) {
+				warn("%s: unknown constraint %q accepts any value unless registered with router.RegisterConstraint",
+					route.FilePath, p.Constraint)
+			}
+		}
+	}
+
+	conflicts := router.FindConflicts(routes)
+	for _, c := range conflicts {
+		fmt.Fprintf(os.Stderr, "  %s\n", c)
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("%d route conflicts", len(conflicts))
+	}
+	return nil
+}
+
+func runCheckRoutes() error {
+	cfg, err := config.LoadFromWorkingDir()
+	if err != nil {
 		return err
 	}
 
-	success("Generated %s", output)
+	routesDir := cfg.RoutesPath()
+	info("Scanning %s...", routesDir)
+
+	routes, err := router.NewScanner(routesDir).Scan()
+	if err != nil {
+		return err
+	}
+
+	checker := router.NewLinkChecker(routes)
+	publicDir := cfg.PublicPath()
+	checker.Ignore = func(href string) bool {
+		if i := strings.IndexAny(href, "?#"); i >= 0 {
+			href = href[:i]
+		}
+		fi, err := os.Stat(filepath.Join(publicDir, filepath.FromSlash(href)))
+		return err == nil && !fi.IsDir()
+	}
+
+	broken, err := checker.CheckDir(cfg.Dir())
+	if err != nil {
+		return err
+	}
+	if len(broken) > 0 {
+		for _, link := range broken {
+			fmt.Fprintf(os.Stderr, "  %s\n", link)
+		}
+		return fmt.Errorf("%d hrefs match no route", len(broken))
+	}
+
+	success("All hrefs match a route")
 	return nil
 }
 
@@ -643,6 +740,258 @@ func runGenOpenAPI(output, title, description, version string) error {
 	return nil
 }
 
+// =============================================================================
+// vango gen client
+// =============================================================================
+
+func genClientCmd() *cobra.Command {
+	var (
+		output      string
+		packageName string
+		lang        string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "client",
+		Short: "Generate typed API clients",
+		Long: `Generate typed API clients from your API routes.
+
+This scans app/routes/api/ like 'vango gen openapi' and writes a Go
+client package and a TypeScript client with a method per endpoint,
+typed path parameters, and request and response types.
+
+Non-2xx responses are returned as typed errors: *Error values matching
+ErrNotFound, ErrValidation, etc. with errors.Is in Go, and ApiError
+with a kind such as 'not_found' in TypeScript.
+
+Examples:
+  vango gen client                           # apiclient/client.go + client.ts
+  vango gen client -o pkg/api                # Custom output directory
+  vango gen client --lang ts                 # TypeScript only
+  vango gen client --package myapi           # Custom Go package name`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runGenClient(output, packageName, lang)
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "apiclient", "Output directory")
+	cmd.Flags().StringVar(&packageName, "package", "", "Go package name (default: output directory name)")
+	cmd.Flags().StringVar(&lang, "lang", "go,ts", "Languages to generate (go, ts)")
+
+	return cmd
+}
+
+func runGenClient(output, packageName, lang string) error {
+	cfg, err := config.LoadFromWorkingDir()
+	if err != nil {
+		return err
+	}
+
+	if !filepath.IsAbs(output) {
+		output = filepath.Join(cfg.Dir(), output)
+	}
+	if packageName == "" {
+		packageName = strings.ReplaceAll(strings.ToLower(filepath.Base(output)), "-", "_")
+		if !isValidIdentifier(packageName) {
+			packageName = "apiclient"
+		}
+	}
+
+	routesDir := cfg.RoutesPath()
+	info("Scanning %s/api/...", routesDir)
+
+	gen, err := router.NewClientGenerator(router.NewOpenAPIGenerator(routesDir, "", router.OpenAPIInfo{}), packageName)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(output, 0755); err != nil {
+		return err
+	}
+
+	for _, l := range strings.Split(lang, ",") {
+		var (
+			code []byte
+			file string
+		)
+		switch strings.TrimSpace(l) {
+		case "go":
+			code, err = gen.GenerateGo()
+			file = filepath.Join(output, "client.go")
+		case "ts":
+			code, err = gen.GenerateTS()
+			file = filepath.Join(output, "client.ts")
+		default:
+			return fmt.Errorf("unknown client language %q (want go or ts)", l)
+		}
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(file, code, 0644); err != nil {
+			return err
+		}
+		success("Generated %s", file)
+	}
+
+	return nil
+}
+
+// =============================================================================
+// vango gen i18n
+// =============================================================================
+
+func genI18nCmd() *cobra.Command {
+	var (
+		locale string
+		output string
+		prune  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "i18n",
+		Short: "Extract translation keys into locale catalogs",
+		Long: `Scan the project for translation calls and update the locale catalogs.
+
+Every call named T with a string literal key is collected:
+
+  i18n.T(ctx, "nav.home")
+  l.T("cart.items", "count", n)
+
+Missing keys are added to the default locale catalog with the key as its
+message, and to every other existing catalog as an empty (untranslated)
+message. Keys that are no longer used are reported, and removed with --prune.
+
+Catalogs are written as flat, sorted JSON objects.
+
+Examples:
+  vango gen i18n                    # Update app/locales/*.json
+  vango gen i18n --locale fr        # Use fr as the default locale
+  vango gen i18n --prune            # Also remove unused keys`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runGenI18n(locale, output, prune)
+		},
+	}
+
+	cmd.Flags().StringVarP(&locale, "locale", "l", "en", "Default locale")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Catalog directory (default: app/locales)")
+	cmd.Flags().BoolVar(&prune, "prune", false, "Remove keys that are no longer used")
+
+	return cmd
+}
+
+func runGenI18n(locale, output string, prune bool) error {
+	cfg, err := config.LoadFromWorkingDir()
+	if err != nil {
+		return err
+	}
+
+	localesDir := cfg.LocalesPath()
+	if output != "" {
+		localesDir = output
+		if !filepath.IsAbs(localesDir) {
+			localesDir = filepath.Join(cfg.Dir(), localesDir)
+		}
+	}
+
+	info("Scanning %s...", cfg.Dir())
+
+	keys, err := i18n.ExtractKeys(cfg.Dir())
+	if err != nil {
+		return err
+	}
+
+	info("Found %d translation keys", len(keys))
+
+	if err := os.MkdirAll(localesDir, 0755); err != nil {
+		return err
+	}
+
+	// The default locale always gets a catalog
+	locale = i18n.CanonicalLocale(locale)
+	if locale == "" {
+		return fmt.Errorf("invalid locale")
+	}
+	catalogs := []string{locale}
+	entries, err := os.ReadDir(localesDir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".json")
+		if entry.IsDir() || name == entry.Name() || name == locale {
+			continue
+		}
+		catalogs = append(catalogs, name)
+	}
+
+	used := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		used[k.Key] = true
+	}
+
+	for _, name := range catalogs {
+		path := filepath.Join(localesDir, name+".json")
+
+		messages := make(map[string]string)
+		if data, err := os.ReadFile(path); err == nil {
+			messages, err = i18n.ParseCatalog(data)
+			if err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+
+		added, removed := 0, 0
+		for _, k := range keys {
+			if _, ok := messages[k.Key]; ok {
+				continue
+			}
+			if name == locale {
+				messages[k.Key] = k.Key
+			} else {
+				messages[k.Key] = ""
+			}
+			added++
+		}
+
+		var unused []string
+		for key := range messages {
+			if !used[key] {
+				unused = append(unused, key)
+			}
+		}
+		sort.Strings(unused)
+		for _, key := range unused {
+			if prune {
+				delete(messages, key)
+				removed++
+			} else {
+				warn("%s.json: unused key %q", name, key)
+			}
+		}
+
+		if added == 0 && removed == 0 {
+			if _, err := os.Stat(path); err == nil {
+				continue
+			}
+		}
+
+		// encoding/json sorts map keys, keeping output deterministic
+		data, err := json.MarshalIndent(messages, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+			return err
+		}
+
+		success("Updated %s (%d added, %d removed)", path, added, removed)
+	}
+
+	return nil
+}
+
 // =============================================================================
 // Helper Functions
 // =============================================================================
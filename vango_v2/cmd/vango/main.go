@@ -44,9 +44,12 @@ and a thin JavaScript client. Features include:
 		createCmd(),
 		devCmd(),
 		buildCmd(),
+		exportCmd(),
 		testCmd(),
 		genCmd(),
 		addCmd(),
+		replayCmd(),
+		benchCmd(),
 		versionCmd(),
 	)
 
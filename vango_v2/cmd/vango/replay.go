@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vango-dev/vango/v2/internal/config"
+	"github.com/vango-dev/vango/v2/pkg/replay"
+)
+
+func replayCmd() *cobra.Command {
+	var (
+		url      string
+		realtime bool
+		timeout  time.Duration
+		report   string
+		open     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "replay <file>",
+		Short: "Replay a recorded session against the app",
+		Long: `Re-drive the events of a session recording against a running app.
+
+Sessions are recorded with ServerConfig.RecordSession (for example
+server.RecordToDir). This command:
+  • Opens a new session at the URL of the recorded one
+  • Sends the recorded events in order
+  • Compares the patches the app sends with the recorded ones
+  • Reports the frames that differ
+
+Start the app first (vango dev, or your built binary). The command exits
+with an error if any frame differs.
+
+Examples:
+  vango replay recordings/4f9c2e.vrec
+  vango replay bug.vrec --url=http://localhost:8080 --realtime
+  vango replay bug.vrec --open`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runReplay(args[0], url, realtime, timeout, report, open)
+		},
+	}
+
+	cmd.Flags().StringVar(&url, "url", "", "Base URL of the app (default: the dev server URL)")
+	cmd.Flags().BoolVar(&realtime, "realtime", false, "Wait between events as in the recording")
+	cmd.Flags().DurationVar(&timeout, "timeout", replay.DefaultTimeout, "How long to wait for each frame")
+	cmd.Flags().StringVar(&report, "report", "", "Write an HTML report to this file")
+	cmd.Flags().BoolVarP(&open, "open", "o", false, "Open the HTML report in the browser")
+
+	return cmd
+}
+
+func runReplay(file, url string, realtime bool, timeout time.Duration, report string, open bool) error {
+	rec, err := replay.LoadFile(file)
+	if err != nil {
+		return fmt.Errorf("loading %s: %w", file, err)
+	}
+
+	if url == "" {
+		url = "http://localhost:3000"
+		if cfg, err := config.LoadFromWorkingDir(); err == nil {
+			url = cfg.DevURL()
+		}
+	}
+
+	// Handle signals
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+
+	go func() {
+		<-sigCh
+		cancel()
+	}()
+
+	info("Replaying %d events of session %s against %s...", rec.Events(), rec.Header.SessionID, url)
+	fmt.Println()
+
+	result, err := replay.Run(ctx, rec, replay.Options{
+		URL:      url,
+		Realtime: realtime,
+		Timeout:  timeout,
+	})
+	if err != nil {
+		return err
+	}
+
+	if open && report == "" {
+		report = strings.TrimSuffix(file, filepath.Ext(file)) + ".html"
+	}
+	if report != "" {
+		f, err := os.Create(report)
+		if err != nil {
+			return err
+		}
+		err = result.WriteHTML(f)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return fmt.Errorf("writing report: %w", err)
+		}
+		info("Report: %s", report)
+		if open {
+			abs, _ := filepath.Abs(report)
+			openURL("file://" + filepath.ToSlash(abs))
+		}
+	}
+
+	if result.Diverged() {
+		result.WriteText(os.Stdout)
+		return fmt.Errorf("%d frames differ from the recording", len(result.Divergences))
+	}
+
+	success("All %d frames match the recording", len(result.Steps)-result.Events)
+	return nil
+}
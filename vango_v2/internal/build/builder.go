@@ -124,11 +124,48 @@ func (b *Builder) Build(ctx context.Context) (*Result, error) {
 	}
 	result.Binary = binaryPath
 
+	// Bundle client, styles and static assets
+	if err := b.buildAssets(ctx, outputDir, publicDir, result); err != nil {
+		return nil, err
+	}
+
+	result.Duration = time.Since(start)
+	result.Public = publicDir
+
+	return result, nil
+}
+
+// BuildAssets builds the thin client, the Tailwind CSS (if enabled) and the
+// hashed static assets into dir, without compiling the Go binary. The
+// manifest is written to dir/manifest.json. Used by `vango export`.
+func (b *Builder) BuildAssets(ctx context.Context, dir string) (*Result, error) {
+	start := time.Now()
+	result := &Result{
+		Manifest: make(map[string]string),
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, errors.New("E142").Wrap(err)
+	}
+
+	if err := b.buildAssets(ctx, dir, dir, result); err != nil {
+		return nil, err
+	}
+
+	result.Duration = time.Since(start)
+	result.Public = dir
+
+	return result, nil
+}
+
+// buildAssets bundles the client, compiles styles and copies static assets
+// into publicDir, then writes the manifest to outputDir.
+func (b *Builder) buildAssets(ctx context.Context, outputDir, publicDir string, result *Result) error {
 	// Bundle thin client
 	b.progress("Bundling thin client...")
 	clientPath, size, err := b.bundleClient(ctx, publicDir)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	result.ClientSize = size
 	result.Manifest["vango.min.js"] = filepath.Base(clientPath)
@@ -138,7 +175,7 @@ func (b *Builder) Build(ctx context.Context) (*Result, error) {
 		b.progress("Compiling Tailwind CSS...")
 		cssPath, size, err := b.compileTailwind(ctx, publicDir)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		result.CSSSize = size
 		result.Manifest["styles.css"] = filepath.Base(cssPath)
@@ -147,19 +184,16 @@ func (b *Builder) Build(ctx context.Context) (*Result, error) {
 	// Copy static assets
 	b.progress("Copying static assets...")
 	if err := b.copyAssets(publicDir, result.Manifest); err != nil {
-		return nil, err
+		return err
 	}
 
 	// Write manifest
 	b.progress("Writing manifest...")
 	if err := b.writeManifest(outputDir, result.Manifest); err != nil {
-		return nil, err
+		return err
 	}
 
-	result.Duration = time.Since(start)
-	result.Public = publicDir
-
-	return result, nil
+	return nil
 }
 
 // buildGo compiles the Go binary.
@@ -90,6 +90,9 @@ type PathsConfig struct {
 
 	// Middleware is the path to the middleware directory.
 	Middleware string `json:"middleware,omitempty"`
+
+	// Locales is the path to the i18n message catalogs.
+	Locales string `json:"locales,omitempty"`
 }
 
 // StaticConfig contains static file serving configuration.
@@ -202,6 +205,7 @@ func New() *Config {
 			UI:         "app/components/ui",
 			Store:      "app/store",
 			Middleware: "app/middleware",
+			Locales:    "app/locales",
 		},
 		Static: StaticConfig{
 			Dir:    "public",
@@ -359,6 +363,9 @@ func (c *Config) applyDefaults() {
 	if c.Paths.Middleware == "" {
 		c.Paths.Middleware = "app/middleware"
 	}
+	if c.Paths.Locales == "" {
+		c.Paths.Locales = "app/locales"
+	}
 
 	// Static
 	if c.Static.Dir == "" {
@@ -507,6 +514,18 @@ func (c *Config) MiddlewarePath() string {
 	return filepath.Join(c.Dir(), path)
 }
 
+// LocalesPath returns the absolute path to the i18n catalogs directory.
+func (c *Config) LocalesPath() string {
+	path := c.Paths.Locales
+	if path == "" {
+		path = "app/locales"
+	}
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(c.Dir(), path)
+}
+
 // StaticPrefix returns the URL prefix for static files.
 func (c *Config) StaticPrefix() string {
 	if c.Static.Prefix == "" {
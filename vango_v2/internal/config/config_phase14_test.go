@@ -36,6 +36,9 @@ func TestPhase14ConfigSchema(t *testing.T) {
 		if cfg.Paths.Middleware != "app/middleware" {
 			t.Errorf("expected Paths.Middleware 'app/middleware', got %q", cfg.Paths.Middleware)
 		}
+		if cfg.Paths.Locales != "app/locales" {
+			t.Errorf("expected Paths.Locales 'app/locales', got %q", cfg.Paths.Locales)
+		}
 
 		// Check Static config
 		if cfg.Static.Dir != "public" {
@@ -188,6 +191,16 @@ func TestPathHelpers(t *testing.T) {
 		}
 	})
 
+	t.Run("LocalesPath returns absolute path", func(t *testing.T) {
+		path := cfg.LocalesPath()
+		if !filepath.IsAbs(path) {
+			t.Errorf("expected absolute path, got %q", path)
+		}
+		if !strings.HasSuffix(path, "app/locales") {
+			t.Errorf("expected path to end with 'app/locales', got %q", path)
+		}
+	})
+
 	t.Run("StaticPrefix returns correct prefix", func(t *testing.T) {
 		prefix := cfg.StaticPrefix()
 		if prefix != "/" {
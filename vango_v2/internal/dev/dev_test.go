@@ -223,6 +223,9 @@ func TestDevClientScript(t *testing.T) {
 	if !containsString(DevClientScript, "location.reload") {
 		t.Error("DevClientScript should contain reload logic")
 	}
+	if !containsString(DevClientScript, "vango:a11y") {
+		t.Error("DevClientScript should show accessibility warnings")
+	}
 }
 
 func containsString(s, substr string) bool {
@@ -89,8 +89,7 @@ func (r *ErrorRecovery) recoverFromUndefinedSymbols(buildOutput string) Recovery
 	}
 
 	// Regenerate routes_gen.go with current symbols
-	gen := router.NewGenerator(routes, r.modulePath)
-	code, err := gen.Generate()
+	code, err := r.generator(routes).Generate()
 	if err != nil {
 		return RecoveryResult{
 			Recovered: false,
@@ -155,8 +154,7 @@ func (r *ErrorRecovery) recoverFromMissingImports(buildOutput string) RecoveryRe
 	}
 
 	// Regenerate routes_gen.go with current packages
-	gen := router.NewGenerator(routes, r.modulePath)
-	code, err := gen.Generate()
+	code, err := r.generator(routes).Generate()
 	if err != nil {
 		return RecoveryResult{
 			Recovered: false,
@@ -180,6 +178,15 @@ func (r *ErrorRecovery) recoverFromMissingImports(buildOutput string) RecoveryRe
 	}
 }
 
+// generator returns the generator of routes_gen.go for the scanned routes.
+func (r *ErrorRecovery) generator(routes []router.ScannedRoute) *router.Generator {
+	gen := router.NewGenerator(routes, r.modulePath)
+	if pkg, err := router.RoutesPackagePath(r.modulePath, r.projectDir, r.routesDir); err == nil {
+		gen.SetRoutesPackage(pkg)
+	}
+	return gen
+}
+
 // IsRecoverableError checks if a build error might be recoverable.
 func IsRecoverableError(buildOutput string) bool {
 	// Check for undefined symbols in routes_gen.go
@@ -244,6 +244,49 @@ const DevClientScript = `
         }
     }
 
+    // Accessibility violations reported by the live session
+    function showA11yWarnings(warnings) {
+        var panel = document.getElementById('vango-a11y-overlay');
+        if (panel) {
+            panel.remove();
+        }
+        if (!warnings || warnings.length === 0) {
+            return;
+        }
+
+        panel = document.createElement('div');
+        panel.id = 'vango-a11y-overlay';
+        panel.style.cssText = 'position:fixed;right:12px;bottom:12px;max-width:560px;max-height:40vh;overflow:auto;background:rgba(0,0,0,0.9);color:#fff;font-family:monospace;font-size:12px;padding:12px 16px;border-radius:8px;border:1px solid #b58900;z-index:999998;';
+
+        var title = document.createElement('div');
+        title.style.cssText = 'color:#f0c674;font-weight:bold;margin-bottom:8px;';
+        title.textContent = 'Accessibility (' + warnings.length + ')';
+
+        var close = document.createElement('button');
+        close.textContent = '\u00d7';
+        close.setAttribute('aria-label', 'Dismiss');
+        close.style.cssText = 'float:right;background:none;border:none;color:#888;font-size:16px;cursor:pointer;';
+        close.onclick = function() { panel.remove(); };
+        title.appendChild(close);
+
+        var list = document.createElement('ul');
+        list.style.cssText = 'margin:0;padding-left:16px;';
+        warnings.forEach(function(warning) {
+            var item = document.createElement('li');
+            item.style.cssText = 'margin:4px 0;white-space:pre-wrap;';
+            item.textContent = warning;
+            list.appendChild(item);
+        });
+
+        panel.appendChild(title);
+        panel.appendChild(list);
+        document.body.appendChild(panel);
+    }
+
+    document.addEventListener('vango:a11y', function(e) {
+        showA11yWarnings(e.detail);
+    });
+
     // Connect on load
     if (document.readyState === 'loading') {
         document.addEventListener('DOMContentLoaded', connect);
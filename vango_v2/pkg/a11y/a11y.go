@@ -0,0 +1,322 @@
+package a11y
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"github.com/vango-dev/vango/v2/pkg/vdom"
+)
+
+// Rule identifies an accessibility check.
+type Rule string
+
+// Rules checked by Check.
+const (
+	// RuleImageAlt: img elements need alt text. Decorative images are
+	// hidden with aria-hidden or role "presentation" instead.
+	RuleImageAlt Rule = "image-alt"
+
+	// RuleLabel: form controls need a label, either a label element
+	// referencing their ID with For, a label element around them, or
+	// aria-label / aria-labelledby.
+	RuleLabel Rule = "label"
+
+	// RuleClickable: elements that are not interactive by nature, such as
+	// div, need a role and a tabindex when they have a click handler, so
+	// keyboard and screen reader users can reach them.
+	RuleClickable Rule = "clickable"
+
+	// RuleDuplicateID: element IDs must be unique in the page.
+	RuleDuplicateID Rule = "duplicate-id"
+)
+
+// Violation is an accessibility problem found in a tree.
+type Violation struct {
+	Rule Rule
+
+	// Path is the component path of the element, from the outermost
+	// component, e.g. "App > TodoList".
+	Path string
+
+	// Element describes the element, e.g. `img.avatar` or `input#email`.
+	Element string
+
+	// HID is the hydration ID of the element, if it has one.
+	HID string
+
+	Message string
+}
+
+// String formats the violation as "path: element: message (rule)".
+func (v Violation) String() string {
+	var b strings.Builder
+	if v.Path != "" {
+		b.WriteString(v.Path)
+		b.WriteString(": ")
+	}
+	fmt.Fprintf(&b, "%s: %s (%s)", v.Element, v.Message, v.Rule)
+	return b.String()
+}
+
+// Checker walks VNode trees and reports accessibility violations.
+type Checker struct {
+	// Root is the name of the component rendering the tree, the first
+	// element of violation paths. Empty for trees not rendered by a
+	// component.
+	Root string
+
+	// Resolve returns the tree rendered by a component node. If nil,
+	// components are rendered by calling their Render method, as during
+	// server-side rendering.
+	Resolve func(node *vdom.VNode) *vdom.VNode
+}
+
+// Check reports the accessibility violations in the tree of node, in
+// document order. Components in the tree are rendered.
+func Check(node *vdom.VNode) []Violation {
+	return Checker{}.Check(node)
+}
+
+// Check reports the accessibility violations in the tree of node, in
+// document order.
+func (c Checker) Check(node *vdom.VNode) []Violation {
+	w := &walker{
+		checker: c,
+		labeled: make(map[string]bool),
+		ids:     make(map[string]bool),
+	}
+	if c.Root != "" {
+		w.path = []string{c.Root}
+	}
+
+	// Labels may come after the controls they name
+	w.collectLabels(node)
+	w.walk(node, false)
+	return w.violations
+}
+
+// walker holds the state of a Check.
+type walker struct {
+	checker    Checker
+	path       []string        // Component path of the current node
+	labeled    map[string]bool // IDs named by label For
+	ids        map[string]bool // IDs seen so far
+	violations []Violation
+	resolved   map[*vdom.VNode]*vdom.VNode
+}
+
+// resolve returns the tree of a component node, rendering it at most once
+// per Check.
+func (w *walker) resolve(node *vdom.VNode) *vdom.VNode {
+	if tree, ok := w.resolved[node]; ok {
+		return tree
+	}
+	var tree *vdom.VNode
+	if w.checker.Resolve != nil {
+		tree = w.checker.Resolve(node)
+	} else if node.Comp != nil {
+		tree = node.Comp.Render()
+	}
+	if w.resolved == nil {
+		w.resolved = make(map[*vdom.VNode]*vdom.VNode)
+	}
+	w.resolved[node] = tree
+	return tree
+}
+
+// collectLabels records the IDs named by label elements.
+func (w *walker) collectLabels(node *vdom.VNode) {
+	if node == nil {
+		return
+	}
+	if node.Kind == vdom.KindComponent {
+		w.collectLabels(w.resolve(node))
+		return
+	}
+	if node.Kind == vdom.KindElement && node.Tag == "label" {
+		if id := prop(node, "for"); id != "" {
+			w.labeled[id] = true
+		} else if id := prop(node, "htmlFor"); id != "" {
+			w.labeled[id] = true
+		}
+	}
+	for _, child := range node.Children {
+		w.collectLabels(child)
+	}
+}
+
+// walk checks node and its descendants. inLabel is true inside a label
+// element.
+func (w *walker) walk(node *vdom.VNode, inLabel bool) {
+	if node == nil {
+		return
+	}
+
+	switch node.Kind {
+	case vdom.KindComponent:
+		w.path = append(w.path, ComponentName(node.Comp))
+		w.walk(w.resolve(node), inLabel)
+		w.path = w.path[:len(w.path)-1]
+		return
+	case vdom.KindElement:
+		w.check(node, inLabel)
+		if node.Tag == "label" {
+			inLabel = true
+		}
+	}
+
+	for _, child := range node.Children {
+		w.walk(child, inLabel)
+	}
+}
+
+// check applies the rules to an element.
+func (w *walker) check(node *vdom.VNode, inLabel bool) {
+	if id := prop(node, "id"); id != "" {
+		if w.ids[id] {
+			w.report(RuleDuplicateID, node, fmt.Sprintf("id %q is already used", id))
+		}
+		w.ids[id] = true
+	}
+
+	if hidden(node) {
+		return
+	}
+
+	switch node.Tag {
+	case "img":
+		if prop(node, "alt") == "" && !presentational(node) {
+			w.report(RuleImageAlt, node, "image has no alt text")
+		}
+	case "input", "select", "textarea":
+		if isControl(node) && !inLabel && !w.labeled[prop(node, "id")] && !ariaLabeled(node) {
+			w.report(RuleLabel, node, "form control has no label")
+		}
+	}
+
+	if _, ok := node.Props["onclick"]; ok && !interactive(node) {
+		var missing []string
+		if prop(node, "role") == "" {
+			missing = append(missing, "role")
+		}
+		if _, ok := node.Props["tabindex"]; !ok {
+			missing = append(missing, "tabindex")
+		}
+		if len(missing) > 0 {
+			w.report(RuleClickable, node, "element with a click handler has no "+strings.Join(missing, " or "))
+		}
+	}
+}
+
+// report records a violation at node.
+func (w *walker) report(rule Rule, node *vdom.VNode, message string) {
+	w.violations = append(w.violations, Violation{
+		Rule:    rule,
+		Path:    strings.Join(w.path, " > "),
+		Element: describe(node),
+		HID:     node.HID,
+		Message: message,
+	})
+}
+
+// isControl reports whether an input, select or textarea needs a label.
+func isControl(node *vdom.VNode) bool {
+	if node.Tag != "input" {
+		return true
+	}
+	switch strings.ToLower(prop(node, "type")) {
+	case "hidden", "submit", "reset", "button", "image":
+		return false
+	}
+	return true
+}
+
+// interactive reports whether an element is focusable and operable with
+// a keyboard by nature.
+func interactive(node *vdom.VNode) bool {
+	switch node.Tag {
+	case "button", "input", "select", "textarea", "summary", "option", "label":
+		return true
+	case "a", "area":
+		_, ok := node.Props["href"]
+		return ok
+	}
+	return false
+}
+
+// ariaLabeled reports whether an element is named by ARIA attributes.
+func ariaLabeled(node *vdom.VNode) bool {
+	return prop(node, "aria-label") != "" || prop(node, "aria-labelledby") != "" || prop(node, "title") != ""
+}
+
+// presentational reports whether an element is marked as decoration.
+func presentational(node *vdom.VNode) bool {
+	role := prop(node, "role")
+	return role == "presentation" || role == "none"
+}
+
+// hidden reports whether an element is hidden from assistive technology.
+func hidden(node *vdom.VNode) bool {
+	return prop(node, "aria-hidden") == "true"
+}
+
+// prop returns a prop of node as a string.
+func prop(node *vdom.VNode, key string) string {
+	switch v := node.Props[key].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// describe returns a short selector-like description of an element.
+func describe(node *vdom.VNode) string {
+	var b strings.Builder
+	b.WriteString(node.Tag)
+	if id := prop(node, "id"); id != "" {
+		b.WriteString("#" + id)
+	}
+	for _, class := range strings.Fields(prop(node, "class")) {
+		b.WriteString("." + class)
+	}
+	return b.String()
+}
+
+// ComponentName returns the name of a component's type, or of its
+// function for function components, as used in violation paths.
+func ComponentName(comp vdom.Component) string {
+	if comp == nil {
+		return "?"
+	}
+	v := reflect.ValueOf(comp)
+	if v.Kind() == reflect.Func {
+		if fn := runtime.FuncForPC(v.Pointer()); fn != nil {
+			return shortName(fn.Name())
+		}
+	}
+	t := v.Type()
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Name() != "" {
+		return t.Name()
+	}
+	return t.String()
+}
+
+// shortName strips the package path of a function name:
+// "example.com/app/ui.Counter.func1" becomes "Counter.func1".
+func shortName(name string) string {
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.Index(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
@@ -0,0 +1,99 @@
+package a11y
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/vango-dev/vango/v2/pkg/vdom"
+)
+
+func TestCheck(t *testing.T) {
+	click := vdom.OnClick(func() {})
+
+	tests := []struct {
+		name string
+		node *vdom.VNode
+		want []Rule
+	}{
+		{"img with alt", vdom.Img(vdom.Src("a.png"), vdom.Alt("A")), nil},
+		{"img without alt", vdom.Img(vdom.Src("a.png")), []Rule{RuleImageAlt}},
+		{"img with empty alt", vdom.Img(vdom.Alt("")), []Rule{RuleImageAlt}},
+		{"hidden img", vdom.Img(vdom.AriaHidden(true)), nil},
+		{"presentational img", vdom.Img(vdom.Role("presentation")), nil},
+
+		{"input without label", vdom.Input(vdom.Type("text")), []Rule{RuleLabel}},
+		{"input with label for", vdom.Div(vdom.Label(vdom.For("email")), vdom.Input(vdom.ID("email"))), nil},
+		{"label for after input", vdom.Div(vdom.Input(vdom.ID("email")), vdom.Label(vdom.For("email"))), nil},
+		{"input in label", vdom.Label(vdom.Text("Email"), vdom.Input()), nil},
+		{"input with aria-label", vdom.Input(vdom.AriaLabel("Search")), nil},
+		{"hidden input", vdom.Input(vdom.Type("hidden")), nil},
+		{"submit input", vdom.Input(vdom.Type("submit")), nil},
+		{"select and textarea", vdom.Div(vdom.Select(), vdom.Textarea()), []Rule{RuleLabel, RuleLabel}},
+
+		{"clickable div", vdom.Div(click), []Rule{RuleClickable}},
+		{"clickable div with role only", vdom.Div(click, vdom.Role("button")), []Rule{RuleClickable}},
+		{"clickable div with role and tabindex", vdom.Div(click, vdom.Role("button"), vdom.TabIndex(0)), nil},
+		{"clickable button", vdom.Button(click), nil},
+		{"clickable link", vdom.A(click, vdom.Href("/")), nil},
+
+		{"unique ids", vdom.Div(vdom.Span(vdom.ID("a")), vdom.Span(vdom.ID("b"))), nil},
+		{"duplicate ids", vdom.Div(vdom.Span(vdom.ID("a")), vdom.Span(vdom.ID("a"))), []Rule{RuleDuplicateID}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []Rule
+			for _, v := range Check(tt.node) {
+				got = append(got, v.Rule)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("rules = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// profile renders an avatar without alt text.
+type profile struct{}
+
+func (profile) Render() *vdom.VNode {
+	return vdom.Div(vdom.Img(vdom.Class("avatar"), vdom.Src("a.png")))
+}
+
+func TestCheckComponentPath(t *testing.T) {
+	page := vdom.Div(vdom.Section(profile{}))
+
+	got := Checker{Root: "App"}.Check(page)
+	if len(got) != 1 {
+		t.Fatalf("Check() = %v, want one violation", got)
+	}
+	v := got[0]
+	if v.Path != "App > profile" || v.Element != "img.avatar" {
+		t.Errorf("violation at %q %q, want App > profile img.avatar", v.Path, v.Element)
+	}
+	if s := v.String(); s != "App > profile: img.avatar: image has no alt text (image-alt)" {
+		t.Errorf("String() = %q", s)
+	}
+}
+
+func TestCheckerResolve(t *testing.T) {
+	comp := &vdom.VNode{Kind: vdom.KindComponent, Comp: profile{}}
+	checker := Checker{Resolve: func(node *vdom.VNode) *vdom.VNode {
+		if node != comp {
+			t.Errorf("Resolve() called with %v", node)
+		}
+		return vdom.Img(vdom.Alt("Ada"))
+	}}
+	if got := checker.Check(vdom.Div(comp)); len(got) != 0 {
+		t.Errorf("Check() = %v, want the resolved tree to be checked", got)
+	}
+}
+
+func TestComponentName(t *testing.T) {
+	if got := ComponentName(profile{}); got != "profile" {
+		t.Errorf("ComponentName(profile{}) = %q", got)
+	}
+	if got := ComponentName(&profile{}); got != "profile" {
+		t.Errorf("ComponentName(&profile{}) = %q", got)
+	}
+}
@@ -0,0 +1,23 @@
+// Package a11y checks rendered VNode trees for common accessibility
+// problems.
+//
+// Check walks a tree, components included, and reports:
+//
+//   - img elements without alt text (RuleImageAlt)
+//   - form controls without a label (RuleLabel)
+//   - elements such as div with a click handler but no role or tabindex
+//     (RuleClickable)
+//   - IDs used by more than one element (RuleDuplicateID)
+//
+// Each Violation names the path of components rendering the element:
+//
+//	for _, v := range a11y.Check(page) {
+//	    log.Println(v) // App > Profile: img.avatar: image has no alt text (image-alt)
+//	}
+//
+// # Development
+//
+// In dev mode (ServerConfig.DevMode), live sessions check their tree after
+// every render, log new violations and show them in the browser's error
+// overlay. Tests assert a tree is accessible with vtest.ExpectAccessible.
+package a11y
@@ -0,0 +1,470 @@
+package bench
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/vango-dev/vango/v2/pkg/protocol"
+	"github.com/vango-dev/vango/v2/pkg/server"
+)
+
+// Defaults for Options.
+const (
+	DefaultClients = 10
+	DefaultTimeout = 5 * time.Second
+)
+
+// Options configure a load test.
+type Options struct {
+	// URL is the base URL of the app, e.g. "http://localhost:3000".
+	URL string
+
+	// Clients is the number of virtual clients. Default: DefaultClients
+	Clients int
+
+	// RampUp is the time over which the clients are started.
+	RampUp time.Duration
+
+	// Duration stops the test after this time. If zero, the test stops
+	// once every client ran the flow Iterations times.
+	Duration time.Duration
+
+	// Iterations is how many times each client runs the flow; zero repeats
+	// it until Duration. Default: 1 when Duration is zero
+	Iterations int
+
+	// Timeout is how long a client waits for the patches of an event and
+	// for the handshake. Default: DefaultTimeout
+	Timeout time.Duration
+
+	// Header is sent with every request, e.g. authentication cookies.
+	Header http.Header
+
+	// Dialer connects the WebSockets. Default: websocket.DefaultDialer
+	Dialer *websocket.Dialer
+
+	// HTTPClient loads pages and the session inspector. Default: http.DefaultClient
+	HTTPClient *http.Client
+}
+
+// Run runs the flow with opts.Clients virtual clients against the app at
+// opts.URL and returns the measurements. Each client opens a session like
+// the thin client (page request, handshake), sends the events of the flow
+// and waits for the resulting patches before the next step. A client whose
+// connection drops opens a new session and starts the flow over.
+//
+// Memory per session is read from the session inspector (ServerConfig.Debug)
+// if the app serves it to the requests of the test.
+func Run(ctx context.Context, flow *Flow, opts Options) (*Report, error) {
+	if flow == nil || flow.Events() == 0 {
+		return nil, errors.New("bench: flow has no events")
+	}
+	base, err := url.Parse(opts.URL)
+	if err != nil || base.Host == "" {
+		return nil, fmt.Errorf("bench: invalid URL %q", opts.URL)
+	}
+	if opts.Clients <= 0 {
+		opts.Clients = DefaultClients
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.Duration <= 0 && opts.Iterations <= 0 {
+		opts.Iterations = 1
+	}
+	if opts.Dialer == nil {
+		opts.Dialer = websocket.DefaultDialer
+	}
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = http.DefaultClient
+	}
+
+	if opts.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Duration)
+		defer cancel()
+	}
+
+	stats := &stats{}
+	clients := make([]*client, opts.Clients)
+	start := time.Now()
+
+	// Sample the memory of the sessions while the clients run
+	sampleCtx, stopSampling := context.WithCancel(ctx)
+	memory := make(chan int64, 1)
+	go func() {
+		memory <- sampleMemory(sampleCtx, base, opts)
+	}()
+
+	var wg sync.WaitGroup
+	for i := range clients {
+		c := &client{flow: flow, base: base, opts: opts, stats: stats}
+		clients[i] = c
+
+		delay := time.Duration(0)
+		if opts.Clients > 1 {
+			delay = opts.RampUp * time.Duration(i) / time.Duration(opts.Clients-1)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return
+			}
+			c.run(ctx)
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+	stopSampling()
+
+	var latencies []time.Duration
+	for _, c := range clients {
+		latencies = append(latencies, c.latencies...)
+	}
+	return newReport(opts, flow, stats, latencies, <-memory, elapsed), nil
+}
+
+// stats are the counters shared by the clients.
+type stats struct {
+	attempts    atomic.Int64
+	connections atomic.Int64
+	reconnects  atomic.Int64
+	events      atomic.Int64
+	bytesSent   atomic.Int64
+	bytesRecv   atomic.Int64
+	errors      atomic.Int64
+	timeouts    atomic.Int64
+}
+
+// client is a virtual client.
+type client struct {
+	flow  *Flow
+	base  *url.URL
+	opts  Options
+	stats *stats
+
+	conn      *websocket.Conn
+	frames    chan *protocol.Frame
+	seq       uint64
+	latencies []time.Duration
+}
+
+// run runs the flow until ctx is done or the iterations are complete.
+func (c *client) run(ctx context.Context) {
+	defer c.disconnect()
+
+	connected := false
+	for i := 0; c.opts.Iterations == 0 || i < c.opts.Iterations; {
+		if ctx.Err() != nil {
+			return
+		}
+
+		if c.conn == nil {
+			if err := c.connect(ctx); err != nil {
+				c.stats.errors.Add(1)
+				if !sleep(ctx, c.opts.Timeout/10) {
+					return
+				}
+				continue
+			}
+			if connected {
+				c.stats.reconnects.Add(1)
+			}
+			connected = true
+		}
+
+		if c.runFlow(ctx) {
+			i++
+		}
+	}
+}
+
+// runFlow runs the steps of the flow once. It returns false if the
+// connection dropped.
+func (c *client) runFlow(ctx context.Context) bool {
+	for _, step := range c.flow.Steps {
+		if step.Wait > 0 && !sleep(ctx, step.Wait) {
+			return false
+		}
+		if step.Event == nil {
+			continue
+		}
+
+		if !c.drain() {
+			c.dropped()
+			return false
+		}
+
+		c.seq++
+		event := *step.Event
+		event.Seq = c.seq
+		data := protocol.NewFrame(protocol.FrameEvent, protocol.EncodeEvent(&event)).Encode()
+
+		sent := time.Now()
+		if err := c.conn.WriteMessage(websocket.BinaryMessage, data); err != nil {
+			c.dropped()
+			return false
+		}
+		c.stats.events.Add(1)
+		c.stats.bytesSent.Add(int64(len(data)))
+
+		if step.NoPatches {
+			continue
+		}
+		switch c.awaitPatches(ctx) {
+		case awaitOK:
+			c.latencies = append(c.latencies, time.Since(sent))
+		case awaitTimeout:
+			c.stats.timeouts.Add(1)
+		case awaitClosed:
+			c.dropped()
+			return false
+		case awaitCanceled:
+			return false
+		}
+	}
+	return true
+}
+
+type awaitResult int
+
+const (
+	awaitOK awaitResult = iota
+	awaitTimeout
+	awaitClosed
+	awaitCanceled
+)
+
+// awaitPatches waits for the next patches frame.
+func (c *client) awaitPatches(ctx context.Context) awaitResult {
+	timer := time.NewTimer(c.opts.Timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case f, ok := <-c.frames:
+			if !ok {
+				return awaitClosed
+			}
+			switch f.Type {
+			case protocol.FramePatches:
+				return awaitOK
+			case protocol.FrameError:
+				c.stats.errors.Add(1)
+			}
+		case <-timer.C:
+			return awaitTimeout
+		case <-ctx.Done():
+			return awaitCanceled
+		}
+	}
+}
+
+// drain discards the frames received since the last step, so that the
+// latency of an event is measured to its own patches. It returns false if
+// the connection closed.
+func (c *client) drain() bool {
+	for {
+		select {
+		case _, ok := <-c.frames:
+			if !ok {
+				return false
+			}
+		default:
+			return true
+		}
+	}
+}
+
+// dropped records a lost connection.
+func (c *client) dropped() {
+	c.stats.errors.Add(1)
+	c.disconnect()
+}
+
+// connect opens a session at the page of the flow.
+func (c *client) connect(ctx context.Context) error {
+	c.stats.attempts.Add(1)
+
+	header := c.opts.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	hello := protocol.NewClientHello("")
+
+	// The page sets the CSRF cookie the handshake must echo
+	if token := c.csrfToken(ctx); token != "" {
+		hello.CSRFToken = token
+		header.Add("Cookie", (&http.Cookie{Name: server.CSRFCookieName, Value: token}).String())
+	}
+	header.Set("Origin", c.base.Scheme+"://"+c.base.Host)
+
+	wsURL := *c.base
+	wsURL.Scheme = "ws"
+	if c.base.Scheme == "https" {
+		wsURL.Scheme = "wss"
+	}
+	wsURL.Path = "/_vango/live"
+	wsURL.RawQuery = url.Values{"path": {c.flow.Path}}.Encode()
+
+	dialCtx, cancel := context.WithTimeout(ctx, c.opts.Timeout)
+	defer cancel()
+	conn, _, err := c.opts.Dialer.DialContext(dialCtx, wsURL.String(), header)
+	if err != nil {
+		return err
+	}
+
+	conn.SetReadDeadline(time.Now().Add(c.opts.Timeout))
+	if err := conn.WriteMessage(websocket.BinaryMessage, protocol.EncodeClientHello(hello)); err != nil {
+		conn.Close()
+		return err
+	}
+	_, msg, err := conn.ReadMessage()
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	conn.SetReadDeadline(time.Time{})
+
+	frame, err := protocol.DecodeFrame(msg)
+	if err != nil || frame.Type != protocol.FrameHandshake {
+		conn.Close()
+		return errors.New("bench: unexpected handshake response")
+	}
+	sh, err := protocol.DecodeServerHello(frame.Payload)
+	if err != nil || sh.Status != protocol.HandshakeOK {
+		conn.Close()
+		return errors.New("bench: handshake rejected")
+	}
+
+	c.stats.connections.Add(1)
+	c.conn = conn
+	c.frames = make(chan *protocol.Frame, 16)
+	go c.read(conn, c.frames)
+	return nil
+}
+
+// read forwards the frames of conn until it closes.
+func (c *client) read(conn *websocket.Conn, frames chan<- *protocol.Frame) {
+	defer close(frames)
+	for {
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		c.stats.bytesRecv.Add(int64(len(msg)))
+
+		frame, err := protocol.DecodeFrame(msg)
+		if err != nil || frame.Type == protocol.FrameControl || frame.Type == protocol.FrameAck {
+			continue
+		}
+		select {
+		case frames <- frame:
+		default:
+			// Nobody waits for frames that arrive between steps
+		}
+	}
+}
+
+// disconnect closes the connection.
+func (c *client) disconnect() {
+	if c.conn != nil {
+		c.conn.Close()
+		c.conn = nil
+	}
+}
+
+// csrfToken loads the page of the flow and returns its CSRF cookie.
+func (c *client) csrfToken(ctx context.Context) string {
+	page := c.base.ResolveReference(&url.URL{Path: c.flow.Path})
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, page.String(), nil)
+	if err != nil {
+		return ""
+	}
+	req.Header = c.opts.Header.Clone()
+	if req.Header == nil {
+		req.Header = http.Header{}
+	}
+	resp, err := c.opts.HTTPClient.Do(req)
+	if err != nil {
+		return ""
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	for _, cookie := range resp.Cookies() {
+		if cookie.Name == server.CSRFCookieName {
+			return cookie.Value
+		}
+	}
+	return ""
+}
+
+// sampleMemory polls the session inspector until ctx is done and returns
+// the mean memory per session of the sample with the most sessions, or 0
+// if the inspector is not available.
+func sampleMemory(ctx context.Context, base *url.URL, opts Options) int64 {
+	endpoint := base.ResolveReference(&url.URL{Path: server.DebugPath + "/api/sessions"})
+
+	var best, mean int64
+	for {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+		if err != nil {
+			return mean
+		}
+		req.Header = opts.Header.Clone()
+		if req.Header == nil {
+			req.Header = http.Header{}
+		}
+
+		resp, err := opts.HTTPClient.Do(req)
+		if err != nil {
+			return mean
+		}
+		var sessions []server.SessionSummary
+		err = json.NewDecoder(resp.Body).Decode(&sessions)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || err != nil {
+			return mean
+		}
+
+		var total, n int64
+		for _, s := range sessions {
+			if !s.Busy {
+				total += s.Memory
+				n++
+			}
+		}
+		if n > best {
+			best, mean = n, total/n
+		}
+
+		if !sleep(ctx, time.Second) {
+			return mean
+		}
+	}
+}
+
+// sleep waits for d and reports whether ctx is still running.
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,234 @@
+package bench
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/vango-dev/vango/v2/pkg/protocol"
+	"github.com/vango-dev/vango/v2/pkg/replay"
+	"github.com/vango-dev/vango/v2/pkg/server"
+	"github.com/vango-dev/vango/v2/pkg/vango"
+	"github.com/vango-dev/vango/v2/pkg/vdom"
+)
+
+// counter renders a count and a button incrementing it.
+func counter(count *vango.Signal[int]) *vdom.VNode {
+	return vdom.Div(
+		vdom.Span(vdom.Text(fmt.Sprintf("%d", count.Get()))),
+		vdom.Button(vdom.OnClick(func() { count.Set(count.Get() + 1) }), vdom.Text("+")),
+	)
+}
+
+// counterApp serves a counter with the session inspector.
+func counterApp(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	config := server.DefaultServerConfig()
+	config.Debug = &server.DebugConfig{
+		Authorize: func(r *http.Request) bool { return r.Header.Get("X-Bench") == "yes" },
+	}
+	srv := server.New(config)
+	srv.SetRootComponent(func() server.Component {
+		count := vango.NewSignal(0)
+		return server.FuncComponent(func() *vdom.VNode {
+			return counter(count)
+		})
+	})
+
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// clickFlow clicks the counter's button.
+func clickFlow(wait string) *Flow {
+	tree := counter(vango.NewSignal(0))
+	vdom.AssignHIDs(tree, vdom.NewHIDGenerator())
+
+	flow, err := ParseFlow([]byte(fmt.Sprintf(`{"steps": [
+		{"event": "click", "hid": %q},
+		{"wait": %q},
+		{"event": "click", "hid": %q}
+	]}`, tree.Children[1].HID, wait, tree.Children[1].HID)))
+	if err != nil {
+		panic(err)
+	}
+	return flow
+}
+
+func TestRunIterations(t *testing.T) {
+	ts := counterApp(t)
+
+	report, err := Run(context.Background(), clickFlow("0s"), Options{
+		URL:        ts.URL,
+		Clients:    5,
+		RampUp:     50 * time.Millisecond,
+		Iterations: 3,
+	})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+
+	if report.Connections != 5 || report.Reconnects != 0 || report.Errors != 0 || report.Timeouts != 0 {
+		t.Errorf("report = %+v, want 5 sessions without errors", report)
+	}
+	if report.Events != 30 || report.Latency.Samples != 30 {
+		t.Errorf("events = %d with %d latencies, want 30", report.Events, report.Latency.Samples)
+	}
+	if report.Latency.P50 <= 0 || report.Latency.P99 < report.Latency.P50 || report.Latency.Max < report.Latency.P99 {
+		t.Errorf("latency = %+v", report.Latency)
+	}
+	if report.BytesSentPerEvent <= 0 || report.BytesReceivedPerEvent <= 0 {
+		t.Errorf("bytes per event = %v sent, %v received", report.BytesSentPerEvent, report.BytesReceivedPerEvent)
+	}
+	if report.MemoryPerSession != 0 {
+		t.Errorf("MemoryPerSession = %d without access to the inspector", report.MemoryPerSession)
+	}
+
+	var out bytes.Buffer
+	if err := report.WriteJSON(&out); err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("WriteJSON() output: %v", err)
+	}
+	if _, ok := decoded["latency_ms"].(map[string]any)["p99"]; !ok {
+		t.Errorf("JSON = %s, want latency_ms.p99", out.String())
+	}
+}
+
+func TestRunDuration(t *testing.T) {
+	ts := counterApp(t)
+
+	report, err := Run(context.Background(), clickFlow("50ms"), Options{
+		URL:      ts.URL,
+		Clients:  3,
+		Duration: 1200 * time.Millisecond,
+		Header:   http.Header{"X-Bench": {"yes"}},
+	})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if report.Connections != 3 || report.Events < 6 || report.Errors != 0 {
+		t.Errorf("report = %+v", report)
+	}
+	if report.MemoryPerSession <= 0 {
+		t.Errorf("MemoryPerSession = %d, want the inspector's value", report.MemoryPerSession)
+	}
+}
+
+func TestRunConnectErrors(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	defer ts.Close()
+
+	report, err := Run(context.Background(), clickFlow("0s"), Options{
+		URL:      ts.URL,
+		Clients:  2,
+		Duration: 300 * time.Millisecond,
+		Timeout:  time.Second,
+	})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if report.Connections != 0 || report.Errors == 0 || report.ErrorRate != 1 {
+		t.Errorf("report = %+v, want only failed connections", report)
+	}
+}
+
+func TestParseFlow(t *testing.T) {
+	flow, err := ParseFlow([]byte(`{"path": "/todos", "steps": [
+		{"event": "input", "hid": "h3", "value": "Buy milk"},
+		{"event": "KeyDown", "hid": "h3", "key": "Enter", "noPatches": true},
+		{"wait": "250ms", "event": "submit", "hid": "h2", "fields": {"title": "Buy milk"}},
+		{"event": "navigate", "path": "/done"}
+	]}`))
+	if err != nil {
+		t.Fatalf("ParseFlow() error: %v", err)
+	}
+	if flow.Path != "/todos" || flow.Events() != 4 {
+		t.Fatalf("flow = %+v", flow)
+	}
+	if flow.Steps[0].Event.Type != protocol.EventInput || flow.Steps[0].Event.Payload != "Buy milk" {
+		t.Errorf("input step = %+v", flow.Steps[0].Event)
+	}
+	if kb, ok := flow.Steps[1].Event.Payload.(*protocol.KeyboardEventData); !ok || kb.Key != "Enter" || !flow.Steps[1].NoPatches {
+		t.Errorf("keydown step = %+v", flow.Steps[1])
+	}
+	if flow.Steps[2].Wait != 250*time.Millisecond || flow.Steps[2].Event.Payload.(*protocol.SubmitEventData).Fields["title"] != "Buy milk" {
+		t.Errorf("submit step = %+v", flow.Steps[2])
+	}
+	if nav, ok := flow.Steps[3].Event.Payload.(*protocol.NavigateEventData); !ok || nav.Path != "/done" {
+		t.Errorf("navigate step = %+v", flow.Steps[3].Event)
+	}
+
+	invalid := []string{
+		`{"steps": []}`,
+		`{"steps": [{"event": "explode", "hid": "h1"}]}`,
+		`{"steps": [{"event": "click"}]}`,
+		`{"steps": [{"wait": "soon"}]}`,
+		`not json`,
+	}
+	for _, src := range invalid {
+		if _, err := ParseFlow([]byte(src)); err == nil {
+			t.Errorf("ParseFlow(%s) should fail", src)
+		}
+	}
+}
+
+func TestFlowFromRecording(t *testing.T) {
+	start := time.Now()
+	var buf bytes.Buffer
+	w, _ := protocol.NewRecordingWriter(&buf, protocol.RecordingHeader{Path: "/_vango/live?path=%2Fcart", Start: start})
+
+	event := func(seq uint64, hid string) []byte {
+		e := &protocol.Event{Seq: seq, Type: protocol.EventClick, HID: hid}
+		return protocol.NewFrame(protocol.FrameEvent, protocol.EncodeEvent(e)).Encode()
+	}
+	patches := protocol.NewFrame(protocol.FramePatches, protocol.EncodePatches(&protocol.PatchesFrame{Seq: 1})).Encode()
+
+	w.RecordAt(protocol.RecordIn, start, protocol.EncodeClientHello(protocol.NewClientHello("")))
+	w.RecordAt(protocol.RecordIn, start.Add(time.Second), event(1, "h1"))
+	w.RecordAt(protocol.RecordOut, start.Add(time.Second), patches)
+	w.RecordAt(protocol.RecordIn, start.Add(3*time.Second), event(2, "h2"))
+
+	rec, err := replay.Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	flow, err := FlowFromRecording(rec)
+	if err != nil {
+		t.Fatalf("FlowFromRecording() error: %v", err)
+	}
+	if flow.Path != "/cart" || len(flow.Steps) != 2 {
+		t.Fatalf("flow = %+v", flow)
+	}
+	if s := flow.Steps[0]; s.Event.HID != "h1" || s.Wait != 0 || s.NoPatches {
+		t.Errorf("first step = %+v", s)
+	}
+	if s := flow.Steps[1]; s.Event.HID != "h2" || s.Wait != 2*time.Second || !s.NoPatches {
+		t.Errorf("second step = %+v", s)
+	}
+}
+
+func TestLatencyPercentiles(t *testing.T) {
+	var samples []time.Duration
+	for i := 100; i >= 1; i-- {
+		samples = append(samples, time.Duration(i)*time.Millisecond)
+	}
+
+	l := latencyPercentiles(samples)
+	want := Latency{Samples: 100, Mean: 50.5, P50: 50, P90: 90, P95: 95, P99: 99, Max: 100}
+	if l != want {
+		t.Errorf("latencyPercentiles() = %+v, want %+v", l, want)
+	}
+	if l := latencyPercentiles(nil); l != (Latency{}) {
+		t.Errorf("latencyPercentiles(nil) = %+v", l)
+	}
+}
@@ -0,0 +1,42 @@
+// Package bench load tests a running app over the live protocol.
+//
+// It backs the `vango bench` command. Unlike the browser benchmarks in
+// benchmark/, it speaks the handshake and binary event protocol of
+// package protocol directly, so a single machine can run thousands of
+// virtual clients.
+//
+// # Flows
+//
+// Each virtual client opens a session at the page of a Flow and repeats its
+// steps: it sends an event, waits for the patches it causes and moves on.
+// Flows are JSON scripts (ParseFlow) or session recordings
+// (FlowFromRecording), which keep the recorded pauses between events:
+//
+//	{
+//	  "path": "/todos",
+//	  "steps": [
+//	    {"event": "input", "hid": "h3", "value": "Buy milk"},
+//	    {"event": "submit", "hid": "h2", "fields": {"title": "Buy milk"}},
+//	    {"wait": "500ms"},
+//	    {"event": "click", "hid": "h7"}
+//	  ]
+//	}
+//
+// Mark steps that do not change the page with "noPatches": true, or they
+// are reported as timeouts.
+//
+// # Results
+//
+// Run returns a Report with event→patch latency percentiles, bytes per
+// event, error and reconnect rates and, if the app serves the session
+// inspector (server.DebugConfig) to the test's requests, the memory per
+// session. Reports are written as JSON for comparison in CI:
+//
+//	report, err := bench.Run(ctx, flow, bench.Options{
+//	    URL:      "http://localhost:3000",
+//	    Clients:  1000,
+//	    RampUp:   30 * time.Second,
+//	    Duration: 2 * time.Minute,
+//	})
+//	report.WriteJSON(os.Stdout)
+package bench
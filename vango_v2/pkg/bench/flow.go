@@ -0,0 +1,216 @@
+package bench
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/vango-dev/vango/v2/pkg/protocol"
+	"github.com/vango-dev/vango/v2/pkg/replay"
+	"github.com/vango-dev/vango/v2/pkg/server"
+)
+
+// Flow is the interaction each virtual client repeats.
+type Flow struct {
+	// Path is the page the session starts on
+	Path string
+
+	// Steps are the events to send and the pauses between them
+	Steps []Step
+}
+
+// Step is an event or a pause of a flow.
+type Step struct {
+	// Event is the event to send; nil for a pause
+	Event *protocol.Event
+
+	// Wait is the pause before the event
+	Wait time.Duration
+
+	// NoPatches is set for events that do not change the page. Their
+	// latency is not measured.
+	NoPatches bool
+}
+
+// Events returns the number of events of the flow.
+func (f *Flow) Events() int {
+	n := 0
+	for _, s := range f.Steps {
+		if s.Event != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// LoadFlow reads a flow from a script (.json) or a session recording
+// (.vrec).
+func LoadFlow(path string) (*Flow, error) {
+	if filepath.Ext(path) == server.RecordingExt {
+		rec, err := replay.LoadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return FlowFromRecording(rec)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseFlow(data)
+}
+
+// script is the JSON form of a flow:
+//
+//	{
+//	  "path": "/todos",
+//	  "steps": [
+//	    {"event": "input", "hid": "h3", "value": "Buy milk"},
+//	    {"event": "submit", "hid": "h2", "fields": {"title": "Buy milk"}},
+//	    {"wait": "500ms"},
+//	    {"event": "click", "hid": "h7"}
+//	  ]
+//	}
+type script struct {
+	Path  string       `json:"path"`
+	Steps []scriptStep `json:"steps"`
+}
+
+type scriptStep struct {
+	Event     string            `json:"event"`
+	HID       string            `json:"hid"`
+	Value     string            `json:"value"`
+	Key       string            `json:"key"`
+	Fields    map[string]string `json:"fields"`
+	Path      string            `json:"path"`
+	Wait      string            `json:"wait"`
+	NoPatches bool              `json:"noPatches"`
+}
+
+// ParseFlow parses a JSON flow script.
+func ParseFlow(data []byte) (*Flow, error) {
+	var s script
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("bench: invalid flow: %w", err)
+	}
+
+	flow := &Flow{Path: s.Path}
+	if flow.Path == "" {
+		flow.Path = "/"
+	}
+	for i, st := range s.Steps {
+		step := Step{NoPatches: st.NoPatches}
+		if st.Wait != "" {
+			d, err := time.ParseDuration(st.Wait)
+			if err != nil {
+				return nil, fmt.Errorf("bench: step %d: invalid wait %q", i+1, st.Wait)
+			}
+			step.Wait = d
+		}
+		if st.Event != "" {
+			event, err := scriptEvent(st)
+			if err != nil {
+				return nil, fmt.Errorf("bench: step %d: %w", i+1, err)
+			}
+			step.Event = event
+		}
+		flow.Steps = append(flow.Steps, step)
+	}
+
+	if flow.Events() == 0 {
+		return nil, errors.New("bench: flow has no events")
+	}
+	return flow, nil
+}
+
+// scriptEvent returns the event of a script step.
+func scriptEvent(st scriptStep) (*protocol.Event, error) {
+	et, ok := eventTypes[strings.ToLower(st.Event)]
+	if !ok {
+		return nil, fmt.Errorf("unknown event %q", st.Event)
+	}
+
+	event := &protocol.Event{Type: et, HID: st.HID}
+	switch et {
+	case protocol.EventInput, protocol.EventChange:
+		event.Payload = st.Value
+	case protocol.EventSubmit:
+		event.Payload = &protocol.SubmitEventData{Fields: st.Fields}
+	case protocol.EventKeyDown, protocol.EventKeyUp, protocol.EventKeyPress:
+		event.Payload = &protocol.KeyboardEventData{Key: st.Key}
+	case protocol.EventNavigate:
+		event.Payload = &protocol.NavigateEventData{Path: st.Path}
+	}
+	if event.HID == "" && et != protocol.EventNavigate {
+		return nil, fmt.Errorf("%s event without hid", st.Event)
+	}
+	return event, nil
+}
+
+// eventTypes maps lowercase event names to types.
+var eventTypes = func() map[string]protocol.EventType {
+	types := make(map[string]protocol.EventType)
+	for i := 0; i < 256; i++ {
+		et := protocol.EventType(i)
+		if name := et.String(); name != "Unknown" {
+			types[strings.ToLower(name)] = et
+		}
+	}
+	return types
+}()
+
+// FlowFromRecording returns the flow of a recorded session: its events,
+// with the recorded pauses between them.
+func FlowFromRecording(rec *replay.Recording) (*Flow, error) {
+	flow := &Flow{Path: rec.PagePath()}
+
+	var last time.Duration
+	for i, m := range rec.Messages {
+		if i == 0 || m.Dir != protocol.RecordIn {
+			continue
+		}
+		frame, err := protocol.DecodeFrame(m.Data)
+		if err != nil || frame.Type != protocol.FrameEvent {
+			continue
+		}
+		event, err := protocol.DecodeEvent(frame.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("bench: recorded event: %w", err)
+		}
+
+		step := Step{Event: event, NoPatches: !patchesFollow(rec.Messages[i+1:])}
+		if len(flow.Steps) > 0 {
+			step.Wait = m.Offset - last
+		}
+		last = m.Offset
+		flow.Steps = append(flow.Steps, step)
+	}
+
+	if len(flow.Steps) == 0 {
+		return nil, errors.New("bench: recording has no events")
+	}
+	return flow, nil
+}
+
+// patchesFollow reports whether the server sent patches before the next
+// recorded event.
+func patchesFollow(messages []protocol.RecordedMessage) bool {
+	for _, m := range messages {
+		frame, err := protocol.DecodeFrame(m.Data)
+		if err != nil {
+			continue
+		}
+		if m.Dir == protocol.RecordIn && frame.Type == protocol.FrameEvent {
+			return false
+		}
+		if m.Dir == protocol.RecordOut && frame.Type == protocol.FramePatches {
+			return true
+		}
+	}
+	return false
+}
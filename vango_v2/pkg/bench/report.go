@@ -0,0 +1,153 @@
+package bench
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"time"
+)
+
+// Report is the result of a load test. It is written as JSON for CI
+// comparison.
+type Report struct {
+	URL        string  `json:"url"`
+	Path       string  `json:"path"`
+	Clients    int     `json:"clients"`
+	DurationMs float64 `json:"duration_ms"`
+
+	// Connections is the number of sessions opened, Reconnects those
+	// opened after a client lost its connection
+	Connections int64 `json:"connections"`
+	Reconnects  int64 `json:"reconnects"`
+
+	// Events is the number of events sent
+	Events          int64   `json:"events"`
+	EventsPerSecond float64 `json:"events_per_second"`
+
+	// Latency is the time from sending an event to receiving its patches
+	Latency Latency `json:"latency_ms"`
+
+	BytesSentPerEvent     float64 `json:"bytes_sent_per_event"`
+	BytesReceivedPerEvent float64 `json:"bytes_received_per_event"`
+
+	// MemoryPerSession is the mean memory of a session in bytes, read from
+	// the session inspector; 0 if it is not available
+	MemoryPerSession int64 `json:"memory_per_session,omitempty"`
+
+	// Errors counts failed connection attempts, dropped connections and
+	// error frames; Timeouts counts events whose patches did not arrive
+	// within Options.Timeout
+	Errors   int64 `json:"errors"`
+	Timeouts int64 `json:"timeouts"`
+
+	// ErrorRate is Errors per connection attempt and event
+	ErrorRate float64 `json:"error_rate"`
+
+	// ReconnectRate is Reconnects per connection
+	ReconnectRate float64 `json:"reconnect_rate"`
+}
+
+// Latency are percentiles of the event→patch latency in milliseconds.
+type Latency struct {
+	Samples int     `json:"samples"`
+	Mean    float64 `json:"mean"`
+	P50     float64 `json:"p50"`
+	P90     float64 `json:"p90"`
+	P95     float64 `json:"p95"`
+	P99     float64 `json:"p99"`
+	Max     float64 `json:"max"`
+}
+
+// newReport computes the report of a test.
+func newReport(opts Options, flow *Flow, s *stats, latencies []time.Duration, memory int64, elapsed time.Duration) *Report {
+	r := &Report{
+		URL:              opts.URL,
+		Path:             flow.Path,
+		Clients:          opts.Clients,
+		DurationMs:       ms(elapsed),
+		Connections:      s.connections.Load(),
+		Reconnects:       s.reconnects.Load(),
+		Events:           s.events.Load(),
+		Latency:          latencyPercentiles(latencies),
+		MemoryPerSession: memory,
+		Errors:           s.errors.Load(),
+		Timeouts:         s.timeouts.Load(),
+	}
+
+	if elapsed > 0 {
+		r.EventsPerSecond = round(float64(r.Events) / elapsed.Seconds())
+	}
+	if r.Events > 0 {
+		r.BytesSentPerEvent = round(float64(s.bytesSent.Load()) / float64(r.Events))
+		r.BytesReceivedPerEvent = round(float64(s.bytesRecv.Load()) / float64(r.Events))
+	}
+	if ops := s.attempts.Load() + r.Events; ops > 0 {
+		r.ErrorRate = float64(r.Errors) / float64(ops)
+	}
+	if r.Connections > 0 {
+		r.ReconnectRate = float64(r.Reconnects) / float64(r.Connections)
+	}
+	return r
+}
+
+// latencyPercentiles returns the percentiles of latencies.
+func latencyPercentiles(latencies []time.Duration) Latency {
+	if len(latencies) == 0 {
+		return Latency{}
+	}
+	sorted := append([]time.Duration(nil), latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var total time.Duration
+	for _, l := range sorted {
+		total += l
+	}
+
+	// Nearest-rank percentile
+	percentile := func(p float64) float64 {
+		rank := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+		return ms(sorted[max(rank, 0)])
+	}
+	return Latency{
+		Samples: len(sorted),
+		Mean:    ms(total / time.Duration(len(sorted))),
+		P50:     percentile(50),
+		P90:     percentile(90),
+		P95:     percentile(95),
+		P99:     percentile(99),
+		Max:     ms(sorted[len(sorted)-1]),
+	}
+}
+
+// ms returns d in milliseconds, rounded to microseconds.
+func ms(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// round rounds v to two decimals.
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteText writes a summary of the report.
+func (r *Report) WriteText(w io.Writer) {
+	fmt.Fprintf(w, "%d clients on %s for %s\n", r.Clients, r.Path, time.Duration(r.DurationMs*float64(time.Millisecond)).Round(time.Millisecond))
+	fmt.Fprintf(w, "  Sessions:    %d (%d reconnects, %.2f%%)\n", r.Connections, r.Reconnects, r.ReconnectRate*100)
+	fmt.Fprintf(w, "  Events:      %d (%.1f/s)\n", r.Events, r.EventsPerSecond)
+	fmt.Fprintf(w, "  Latency:     p50 %.2fms  p90 %.2fms  p95 %.2fms  p99 %.2fms  max %.2fms\n",
+		r.Latency.P50, r.Latency.P90, r.Latency.P95, r.Latency.P99, r.Latency.Max)
+	fmt.Fprintf(w, "  Bytes/event: %.1f sent, %.1f received\n", r.BytesSentPerEvent, r.BytesReceivedPerEvent)
+	if r.MemoryPerSession > 0 {
+		fmt.Fprintf(w, "  Memory:      %d bytes per session\n", r.MemoryPerSession)
+	}
+	fmt.Fprintf(w, "  Errors:      %d (%.2f%%), %d timeouts\n", r.Errors, r.ErrorRate*100, r.Timeouts)
+}
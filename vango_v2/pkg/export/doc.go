@@ -0,0 +1,38 @@
+// Package export renders the pages of a router to static HTML files.
+//
+// It backs the `vango export` command, which builds the assets of the
+// project, generates a small program that registers its routes and runs
+// Main. Pages are written to <out>/<path>/index.html, so the output can be
+// served by any static file host.
+//
+// # Dynamic Routes
+//
+// A dynamic route is exported once for every parameter set returned by
+// the StaticParams function of its route file:
+//
+//	// app/routes/blog/[slug].go
+//	func StaticParams() []map[string]string {
+//	    return []map[string]string{
+//	        {"slug": "hello-world"},
+//	        {"slug": "release-notes"},
+//	    }
+//	}
+//
+// Dynamic routes without StaticParams are skipped and reported.
+//
+// # Static Pages
+//
+// Pages without interactivity can omit the thin client and the WebSocket
+// connection entirely. Mark the route file with the static directive:
+//
+//	//vango:static
+//	package routes
+//
+// Other pages keep the client bootstrap and need a running Vango server
+// to become interactive.
+//
+// # Programmatic Use
+//
+//	exp := export.New(r, export.Options{OutDir: "dist"})
+//	result, err := exp.Export(ctx)
+package export
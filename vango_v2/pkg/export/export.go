@@ -0,0 +1,220 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/vango-dev/vango/v2/pkg/render"
+	"github.com/vango-dev/vango/v2/pkg/router"
+	"github.com/vango-dev/vango/v2/pkg/server"
+	"github.com/vango-dev/vango/v2/pkg/vdom"
+)
+
+// Manifest keys of the assets the exporter links from every page.
+const (
+	ClientAsset     = "vango.min.js"
+	StylesheetAsset = "styles.css"
+)
+
+// Options configures an Exporter.
+type Options struct {
+	// OutDir is the directory pages are written to.
+	// Default: "dist"
+	OutDir string
+
+	// Renderer configures the HTML renderer.
+	Renderer render.RendererConfig
+
+	// Assets is the asset manifest written by build.Builder, mapping
+	// original names to hashed file names relative to OutDir. The hashed
+	// thin client and stylesheet are linked from the exported pages.
+	Assets map[string]string
+
+	// Lang is the language attribute of the html element.
+	Lang string
+
+	// OnPage is called after each page is written.
+	OnPage func(page Page)
+}
+
+// Page is an exported page.
+type Page struct {
+	// Path is the URL path of the page (e.g., "/blog/hello-world").
+	Path string
+
+	// Route is the route pattern the page was rendered from.
+	Route string
+
+	// File is the path of the written HTML file.
+	File string
+
+	// Static reports whether the page omits the client bootstrap.
+	Static bool
+}
+
+// Result is the outcome of an export.
+type Result struct {
+	// Pages are the written pages, in route order.
+	Pages []Page
+
+	// Skipped are dynamic route patterns without StaticParams.
+	Skipped []string
+}
+
+// Exporter renders the pages of a router to static HTML files.
+type Exporter struct {
+	router   *router.Router
+	options  Options
+	renderer *render.Renderer
+}
+
+// New creates an exporter for the pages registered on r.
+func New(r *router.Router, options Options) *Exporter {
+	if options.OutDir == "" {
+		options.OutDir = "dist"
+	}
+	return &Exporter{
+		router:   r,
+		options:  options,
+		renderer: render.NewRenderer(options.Renderer),
+	}
+}
+
+// Export renders every page route. Dynamic routes are expanded with their
+// StaticParams; those without are skipped and listed in the result.
+func (e *Exporter) Export(ctx context.Context) (*Result, error) {
+	result := &Result{}
+
+	for _, route := range e.router.Pages() {
+		paths, err := expand(route)
+		if err != nil {
+			return result, err
+		}
+		if paths == nil {
+			result.Skipped = append(result.Skipped, route.Path)
+			continue
+		}
+
+		for _, p := range paths {
+			if err := ctx.Err(); err != nil {
+				return result, err
+			}
+
+			page, err := e.exportPage(ctx, route.Path, p)
+			if err != nil {
+				return result, fmt.Errorf("export %s: %w", p, err)
+			}
+			result.Pages = append(result.Pages, page)
+			if e.options.OnPage != nil {
+				e.options.OnPage(page)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// expand returns the URL paths of route, or nil if it is dynamic and has
+// no StaticParams.
+func expand(route router.PageRoute) ([]string, error) {
+	if len(route.Params) == 0 {
+		return []string{route.Path}, nil
+	}
+	if route.StaticParams == nil {
+		return nil, nil
+	}
+
+	var paths []string
+	seen := make(map[string]bool)
+	for _, params := range route.StaticParams() {
+		p, err := router.BuildPath(route.Path, params)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[p] {
+			seen[p] = true
+			paths = append(paths, p)
+		}
+	}
+	return paths, nil
+}
+
+// exportPage renders the page at urlPath and writes it to its file.
+func (e *Exporter) exportPage(ctx context.Context, pattern, urlPath string) (Page, error) {
+	match, ok := e.router.Match(http.MethodGet, urlPath)
+	if !ok || match.PageHandler == nil {
+		return Page{}, fmt.Errorf("no page matches %s", urlPath)
+	}
+
+	file, err := e.outputFile(urlPath)
+	if err != nil {
+		return Page{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, urlPath, nil)
+	if err != nil {
+		return Page{}, err
+	}
+	c := server.NewRequestContext(httptest.NewRecorder(), req, match.Params)
+
+	// A failing loader fails the export rather than publishing an error page
+	if _, err := match.Load(c); err != nil {
+		return Page{}, err
+	}
+
+	body := &vdom.VNode{Kind: vdom.KindComponent, Comp: match.PageHandler(c, match.Params)}
+	for i := len(match.Layouts) - 1; i >= 0; i-- {
+		body = match.Layouts[i](c, body)
+	}
+
+	page := render.PageData{
+		Body:   body,
+		Lang:   e.options.Lang,
+		Static: match.Static,
+	}
+	if h := match.Head(c); h != nil {
+		page.Head = []*vdom.VNode{h}
+	}
+	if client, ok := e.options.Assets[ClientAsset]; ok {
+		page.ClientScript = "/" + client
+	}
+	if styles, ok := e.options.Assets[StylesheetAsset]; ok {
+		page.StyleSheets = append(page.StyleSheets, "/"+styles)
+	}
+
+	var buf bytes.Buffer
+	if err := e.renderer.RenderPage(&buf, page); err != nil {
+		return Page{}, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return Page{}, err
+	}
+	if err := os.WriteFile(file, buf.Bytes(), 0644); err != nil {
+		return Page{}, err
+	}
+
+	return Page{
+		Path:   urlPath,
+		Route:  pattern,
+		File:   file,
+		Static: match.Static,
+	}, nil
+}
+
+// outputFile returns the HTML file for urlPath: <OutDir>/<path>/index.html.
+func (e *Exporter) outputFile(urlPath string) (string, error) {
+	// SECURITY: Params come from user code; never write outside OutDir
+	clean := path.Clean("/" + urlPath)
+	if clean != strings.TrimSuffix(urlPath, "/") && clean != urlPath {
+		return "", fmt.Errorf("invalid page path %q", urlPath)
+	}
+	return filepath.Join(e.options.OutDir, filepath.FromSlash(clean), "index.html"), nil
+}
@@ -0,0 +1,316 @@
+package export
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vango-dev/vango/v2/pkg/head"
+	"github.com/vango-dev/vango/v2/pkg/router"
+	"github.com/vango-dev/vango/v2/pkg/server"
+	"github.com/vango-dev/vango/v2/pkg/vdom"
+)
+
+func testRouter() *router.Router {
+	r := router.NewRouter()
+
+	r.AddLayout("/", func(ctx server.Ctx, children router.Slot) *vdom.VNode {
+		return vdom.Div(vdom.Class("layout"), head.Title("Site"), children)
+	})
+	r.AddPage("/", func(ctx server.Ctx, params any) vdom.Component {
+		return vdom.Func(func() *vdom.VNode {
+			return vdom.H1(vdom.Text("Home"))
+		})
+	})
+	r.AddPage("/about", func(ctx server.Ctx, params any) vdom.Component {
+		return vdom.Func(func() *vdom.VNode {
+			return vdom.P(head.Title("About"), vdom.Text("About us"))
+		})
+	})
+	r.SetStatic("/about")
+	r.AddPage("/blog/:slug", func(ctx server.Ctx, params any) vdom.Component {
+		return vdom.Func(func() *vdom.VNode {
+			return vdom.H1(vdom.Text("Post " + ctx.Param("slug")))
+		})
+	})
+	r.AddStaticParams("/blog/:slug", func() []map[string]string {
+		return []map[string]string{{"slug": "hello"}, {"slug": "world"}, {"slug": "hello"}}
+	})
+	r.AddPage("/users/:id", func(ctx server.Ctx, params any) vdom.Component {
+		return nil
+	})
+
+	return r
+}
+
+func TestExport(t *testing.T) {
+	dir := t.TempDir()
+
+	exp := New(testRouter(), Options{
+		OutDir: dir,
+		Assets: map[string]string{
+			ClientAsset:     "vango.1234abcd.min.js",
+			StylesheetAsset: "styles.5678efab.css",
+		},
+	})
+	result, err := exp.Export(context.Background())
+	if err != nil {
+		t.Fatalf("Export() error: %v", err)
+	}
+
+	var paths []string
+	for _, page := range result.Pages {
+		paths = append(paths, page.Path)
+	}
+	want := []string{"/", "/about", "/blog/hello", "/blog/world"}
+	if strings.Join(paths, " ") != strings.Join(want, " ") {
+		t.Errorf("pages = %v, want %v", paths, want)
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0] != "/users/:id" {
+		t.Errorf("Skipped = %v, want [/users/:id]", result.Skipped)
+	}
+
+	read := func(rel string) string {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(dir, rel))
+		if err != nil {
+			t.Fatalf("read %s: %v", rel, err)
+		}
+		return string(data)
+	}
+
+	home := read("index.html")
+	for _, want := range []string{
+		`<div class="layout"`,
+		"<h1",
+		"<title>Site</title>",
+		`src="/vango.1234abcd.min.js"`,
+		`href="/styles.5678efab.css"`,
+	} {
+		if !strings.Contains(home, want) {
+			t.Errorf("index.html should contain %s, got %q", want, home)
+		}
+	}
+
+	about := read("about/index.html")
+	if strings.Contains(about, "<script") {
+		t.Errorf("static page should not load the client, got %q", about)
+	}
+	if !strings.Contains(about, "<title>About</title>") {
+		t.Errorf("page title should override the layout, got %q", about)
+	}
+
+	if post := read("blog/world/index.html"); !strings.Contains(post, "Post world") {
+		t.Errorf("blog/world should render its params, got %q", post)
+	}
+}
+
+func TestExportRejectsEscapingPaths(t *testing.T) {
+	r := router.NewRouter()
+	r.AddPage("/blog/:slug", func(ctx server.Ctx, params any) vdom.Component {
+		return vdom.Func(func() *vdom.VNode { return vdom.Div() })
+	})
+	r.AddStaticParams("/blog/:slug", func() []map[string]string {
+		return []map[string]string{{"slug": ".."}}
+	})
+
+	_, err := New(r, Options{OutDir: t.TempDir()}).Export(context.Background())
+	if err == nil {
+		t.Fatal("expected an error for a path outside the output directory")
+	}
+}
+
+func TestGenerateMain(t *testing.T) {
+	code, err := GenerateMain("example.com/site/app/routes")
+	if err != nil {
+		t.Fatalf("GenerateMain() error: %v", err)
+	}
+	src := string(code)
+
+	for _, want := range []string{
+		`routes "example.com/site/app/routes"`,
+		"routes.Register(r)",
+		"export.Main(r)",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("generated code should contain %s, got:\n%s", want, src)
+		}
+	}
+}
+
+func TestCleanOutDir(t *testing.T) {
+	project := t.TempDir()
+	write := func(name string) {
+		t.Helper()
+		path := filepath.Join(project, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("go.mod")
+	write("app/routes/index.go")
+	write("dist/manifest.json")
+	write("dist/index.html")
+
+	for _, out := range []string{project, filepath.Dir(project), filepath.Join(project, "app")} {
+		if err := CleanOutDir(out, project); err == nil {
+			t.Errorf("CleanOutDir(%s) should refuse", out)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(project, "app", "routes", "index.go")); err != nil {
+		t.Fatalf("project files were removed: %v", err)
+	}
+
+	if err := CleanOutDir(filepath.Join(project, "missing"), project); err != nil {
+		t.Errorf("CleanOutDir() of a missing directory error: %v", err)
+	}
+	if err := CleanOutDir(filepath.Join(project, "dist"), project); err != nil {
+		t.Fatalf("CleanOutDir() error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(project, "dist")); !os.IsNotExist(err) {
+		t.Error("the previous export should be removed")
+	}
+}
+
+// exportFixture is an app with a root layout, an interactive page and a
+// static one.
+var exportFixture = map[string]string{
+	"app/routes/index.go": `package routes
+
+import (
+	"github.com/vango-dev/vango/v2/pkg/router"
+	"github.com/vango-dev/vango/v2/pkg/server"
+	"github.com/vango-dev/vango/v2/pkg/vdom"
+)
+
+func Layout(ctx server.Ctx, children router.Slot) *vdom.VNode {
+	return vdom.Main(vdom.Class("layout"), children)
+}
+
+func IndexPage(ctx server.Ctx, params any) vdom.Component {
+	return vdom.Func(func() *vdom.VNode { return vdom.H1(vdom.Text("Home")) })
+}
+`,
+	"app/routes/about.go": `//vango:static
+
+package routes
+
+import (
+	"github.com/vango-dev/vango/v2/pkg/server"
+	"github.com/vango-dev/vango/v2/pkg/vdom"
+)
+
+func AboutPage(ctx server.Ctx, params any) vdom.Component {
+	return vdom.Func(func() *vdom.VNode { return vdom.P(vdom.Text("About us")) })
+}
+`,
+}
+
+// TestExportProject exports a fixture app the way vango export does:
+// generating its routes and the export program, and running it.
+func TestExportProject(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs a module")
+	}
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go tool not found")
+	}
+	framework, err := filepath.Abs("../..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum, err := os.ReadFile(filepath.Join(framework, "go.sum"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	project := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/site\n\ngo 1.23\n\n" +
+			"require github.com/vango-dev/vango/v2 v2.0.0\n\n" +
+			"replace github.com/vango-dev/vango/v2 => " + framework + "\n",
+		"go.sum":              string(sum),
+		"dist/manifest.json":  "{}",
+		"dist/old/index.html": "stale",
+	}
+	for name, content := range exportFixture {
+		files[name] = content
+	}
+	for name, content := range files {
+		path := filepath.Join(project, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Generate the routes
+	routesDir := filepath.Join(project, "app", "routes")
+	routes, err := router.NewScanner(routesDir).Scan()
+	if err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	routesImport, err := router.RoutesPackagePath("example.com/site", project, routesDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gen := router.NewGenerator(routes, "example.com/site")
+	gen.SetRoutesPackage(routesImport)
+	code, err := gen.Generate()
+	if err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(routesDir, "routes_gen.go"), code, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Replace the previous export, then run the export program
+	out := filepath.Join(project, "dist")
+	if err := CleanOutDir(out, project); err != nil {
+		t.Fatalf("CleanOutDir() error: %v", err)
+	}
+	program, err := GenerateMain(routesImport)
+	if err != nil {
+		t.Fatalf("GenerateMain() error: %v", err)
+	}
+	programDir := filepath.Join(project, ".vango", "export")
+	if err := os.MkdirAll(programDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(programDir, "main.go"), program, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(goTool, "run", "./.vango/export", "-out", out)
+	cmd.Dir = project
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("export failed: %v\n%s\nroutes:\n%s", err, output, code)
+	}
+
+	for file, want := range map[string]string{
+		"index.html":       "<h1",
+		"about/index.html": "About us",
+	} {
+		data, err := os.ReadFile(filepath.Join(out, file))
+		if err != nil {
+			t.Errorf("missing %s: %v", file, err)
+			continue
+		}
+		if html := string(data); !strings.Contains(html, want) || !strings.Contains(html, `class="layout"`) {
+			t.Errorf("%s should contain %s in the layout, got:\n%s", file, want, html)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(out, "old")); !os.IsNotExist(err) {
+		t.Error("the previous export should be removed")
+	}
+}
@@ -0,0 +1,123 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/vango-dev/vango/v2/pkg/render"
+	"github.com/vango-dev/vango/v2/pkg/router"
+)
+
+// Main runs an export from the command line. It is called by the program
+// `vango export` generates and accepts these flags:
+//
+//	-out        output directory (default "dist")
+//	-manifest   asset manifest written by the build
+//	-strict-csp refuse raw HTML containing scripts
+func Main(r *router.Router) {
+	out := flag.String("out", "dist", "output directory")
+	manifest := flag.String("manifest", "", "asset manifest")
+	strictCSP := flag.Bool("strict-csp", false, "refuse raw HTML containing scripts")
+	flag.Parse()
+
+	options := Options{
+		OutDir:   *out,
+		Renderer: render.RendererConfig{StrictCSP: *strictCSP},
+		OnPage: func(page Page) {
+			suffix := ""
+			if page.Static {
+				suffix = " (static)"
+			}
+			fmt.Printf("  %s → %s%s\n", page.Path, page.File, suffix)
+		},
+	}
+
+	if *manifest != "" {
+		data, err := os.ReadFile(*manifest)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "export: reading manifest: %v\n", err)
+			os.Exit(1)
+		}
+		if err := json.Unmarshal(data, &options.Assets); err != nil {
+			fmt.Fprintf(os.Stderr, "export: parsing manifest: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	result, err := New(r, options).Export(context.Background())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "export: %v\n", err)
+		os.Exit(1)
+	}
+	for _, pattern := range result.Skipped {
+		fmt.Fprintf(os.Stderr, "  skipped %s: dynamic route without StaticParams\n", pattern)
+	}
+}
+
+// GenerateMain returns the source of the export program for a project.
+//
+// The program registers the project's routes, with their StaticParams
+// functions and static directives, through the generated routes.Register
+// function and calls Main. routesImport is the import path of the routes
+// package.
+func GenerateMain(routesImport string) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by vango export. DO NOT EDIT.\n\n")
+	buf.WriteString("package main\n\n")
+	buf.WriteString("import (\n")
+	buf.WriteString("\t\"github.com/vango-dev/vango/v2/pkg/export\"\n")
+	buf.WriteString("\t\"github.com/vango-dev/vango/v2/pkg/router\"\n\n")
+	fmt.Fprintf(&buf, "\troutes %q\n", routesImport)
+	buf.WriteString(")\n\n")
+	buf.WriteString("func main() {\n")
+	buf.WriteString("\tr := router.NewRouter()\n")
+	buf.WriteString("\troutes.Register(r)\n")
+	buf.WriteString("\texport.Main(r)\n")
+	buf.WriteString("}\n")
+
+	return format.Source(buf.Bytes())
+}
+
+// ManifestFile is the asset manifest the build writes to the output
+// directory of an export.
+const ManifestFile = "manifest.json"
+
+// CleanOutDir removes the output of a previous export from outDir. It
+// refuses to remove projectDir or a directory containing it, and a
+// non-empty directory without the asset manifest of an export.
+func CleanOutDir(outDir, projectDir string) error {
+	out, err := filepath.Abs(outDir)
+	if err != nil {
+		return err
+	}
+	project, err := filepath.Abs(projectDir)
+	if err != nil {
+		return err
+	}
+	if rel, err := filepath.Rel(out, project); err == nil &&
+		rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("refusing to remove %s: it contains the project", outDir)
+	}
+
+	entries, err := os.ReadDir(out)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	if _, err := os.Stat(filepath.Join(out, ManifestFile)); err != nil {
+		return fmt.Errorf("refusing to remove %s: it is not the output of an export", outDir)
+	}
+	return os.RemoveAll(out)
+}
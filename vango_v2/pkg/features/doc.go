@@ -15,6 +15,7 @@
 //   - shared: Session-scoped and global shared state
 //   - optimistic: Instant visual feedback for interactions
 //   - islands: Third-party JavaScript library integration
+//   - virtual: Windowed rendering of long lists
 //
 // # Usage
 //
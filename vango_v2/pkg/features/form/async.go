@@ -0,0 +1,281 @@
+package form
+
+import (
+	"context"
+	"reflect"
+	"time"
+)
+
+// asyncField tracks the async validation state of a single field.
+// All fields are protected by Form.asyncMu.
+type asyncField struct {
+	// seq identifies the latest run; results from older runs are discarded.
+	seq uint64
+
+	// value is the field value the latest run checks.
+	value any
+
+	// timer fires the debounced run.
+	timer *time.Timer
+
+	// cancel cancels the context of the latest run.
+	cancel context.CancelFunc
+
+	// done is closed when the latest run finishes or is superseded.
+	done chan struct{}
+
+	// settled reports whether errs holds the result for value.
+	settled bool
+
+	// errs are the error messages from the latest completed run.
+	errs []string
+}
+
+// stop cancels the pending or in-flight run, if any.
+func (a *asyncField) stop() {
+	if a.cancel != nil {
+		a.cancel()
+		a.cancel = nil
+	}
+	if a.timer != nil && a.timer.Stop() {
+		// The run never started, so nobody else will close done
+		close(a.done)
+	}
+	a.timer = nil
+	a.done = nil
+}
+
+// asyncState returns the state for field, creating it if needed.
+// Must be called with asyncMu held.
+func (f *Form[T]) asyncState(field string) *asyncField {
+	st, ok := f.async[field]
+	if !ok {
+		st = &asyncField{}
+		f.async[field] = st
+	}
+	return st
+}
+
+// scheduleAsync starts a debounced async validation of field.
+// If a result for the same value is already known, it is returned with
+// settled == true and nothing is scheduled.
+func (f *Form[T]) scheduleAsync(field string, value any, validators []*AsyncValidator) (errs []string, settled bool) {
+	f.asyncMu.Lock()
+	st := f.asyncState(field)
+	if st.settled && reflect.DeepEqual(st.value, value) {
+		errs = st.errs
+		f.asyncMu.Unlock()
+		return errs, true
+	}
+	if st.done != nil && !st.settled && reflect.DeepEqual(st.value, value) {
+		// Already checking this exact value
+		f.asyncMu.Unlock()
+		return nil, false
+	}
+
+	var delay time.Duration
+	for _, v := range validators {
+		if v.debounce > delay {
+			delay = v.debounce
+		}
+	}
+	f.launchAsyncLocked(field, st, value, validators, delay)
+	f.asyncMu.Unlock()
+
+	f.setValidating(field, true)
+	return nil, false
+}
+
+// launchAsyncLocked supersedes any previous run of field and schedules a new
+// one after delay. Must be called with asyncMu held.
+func (f *Form[T]) launchAsyncLocked(field string, st *asyncField, value any, validators []*AsyncValidator, delay time.Duration) {
+	st.stop()
+	st.seq++
+	st.value = value
+	st.settled = false
+	st.errs = nil
+
+	seq := st.seq
+	done := make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	st.done = done
+	st.cancel = cancel
+	st.timer = time.AfterFunc(delay, func() {
+		f.runAsync(ctx, field, seq, value, validators, done)
+	})
+}
+
+// runAsync executes the async validators of field in the timer goroutine.
+func (f *Form[T]) runAsync(ctx context.Context, field string, seq uint64, value any, validators []*AsyncValidator, done chan struct{}) {
+	var errs []string
+	for _, v := range validators {
+		if ctx.Err() != nil {
+			break
+		}
+		if err := v.run(ctx, value); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	f.asyncMu.Lock()
+	st := f.asyncState(field)
+	current := st.seq == seq && ctx.Err() == nil
+	var cancel context.CancelFunc
+	if current {
+		st.settled = true
+		st.errs = errs
+		st.timer = nil
+		cancel, st.cancel = st.cancel, nil
+	}
+	f.asyncMu.Unlock()
+	close(done)
+
+	// Release the context of the settled run
+	if cancel != nil {
+		cancel()
+	}
+
+	if !current {
+		// Superseded; the newer run owns the field's state
+		return
+	}
+
+	f.dispatch.Dispatch(func() {
+		f.applyAsync(field, seq)
+	})
+}
+
+// applyAsync publishes the result of run seq to the field's errors.
+// It runs on the session event loop.
+func (f *Form[T]) applyAsync(field string, seq uint64) {
+	f.asyncMu.Lock()
+	st := f.asyncState(field)
+	if st.seq != seq || !st.settled {
+		f.asyncMu.Unlock()
+		return
+	}
+	errs := st.errs
+	f.asyncMu.Unlock()
+
+	f.setFieldErrors(field, errs)
+	f.setValidating(field, false)
+}
+
+// asyncWaiter identifies the run of a field that validation waits for.
+type asyncWaiter struct {
+	seq  uint64
+	done chan struct{}
+}
+
+// startAsync makes sure async validation of every field in checks is running
+// without debounce. Results for values that were already validated are
+// returned keyed by field; the other fields are returned with the run to
+// wait for. It reads the form values, so it runs on the caller's goroutine.
+func (f *Form[T]) startAsync(checks map[string][]*AsyncValidator) (results map[string][]string, waiting map[string]asyncWaiter) {
+	results = make(map[string][]string)
+	waiting = make(map[string]asyncWaiter)
+
+	f.asyncMu.Lock()
+	defer f.asyncMu.Unlock()
+	for field, validators := range checks {
+		value := f.Get(field)
+		st := f.asyncState(field)
+
+		switch {
+		case st.settled && reflect.DeepEqual(st.value, value):
+			if len(st.errs) > 0 {
+				results[field] = st.errs
+			}
+			continue
+		case st.done == nil || !reflect.DeepEqual(st.value, value):
+			f.launchAsyncLocked(field, st, value, validators, 0)
+		case st.timer != nil && st.timer.Stop():
+			// Still debouncing: run the same check right away, releasing
+			// the context of the timer that will not fire
+			st.timer = nil
+			st.cancel()
+			seq, done := st.seq, st.done
+			runCtx, cancel := context.WithCancel(context.Background())
+			st.cancel = cancel
+			go f.runAsync(runCtx, field, seq, value, validators, done)
+		}
+		waiting[field] = asyncWaiter{seq: st.seq, done: st.done}
+	}
+	return results, waiting
+}
+
+// waitAsync waits for the runs started by startAsync until ctx is done and
+// returns their errors keyed by field. It may run on any goroutine.
+func (f *Form[T]) waitAsync(ctx context.Context, waiting map[string]asyncWaiter) map[string][]string {
+	results := make(map[string][]string)
+	for field, w := range waiting {
+		select {
+		case <-w.done:
+		case <-ctx.Done():
+			results[field] = []string{"Validation did not complete"}
+			continue
+		}
+
+		f.asyncMu.Lock()
+		st := f.asyncState(field)
+		if st.seq == w.seq && st.settled && len(st.errs) > 0 {
+			results[field] = st.errs
+		}
+		f.asyncMu.Unlock()
+	}
+	return results
+}
+
+// cancelAsync stops all pending and in-flight async validation.
+func (f *Form[T]) cancelAsync() {
+	f.asyncMu.Lock()
+	for _, st := range f.async {
+		st.stop()
+		st.seq++
+		st.settled = false
+		st.errs = nil
+	}
+	f.asyncMu.Unlock()
+}
+
+// cancelAsyncField stops async validation of a single field.
+func (f *Form[T]) cancelAsyncField(field string) {
+	f.asyncMu.Lock()
+	if st, ok := f.async[field]; ok {
+		st.stop()
+		st.seq++
+		st.settled = false
+		st.errs = nil
+	}
+	f.asyncMu.Unlock()
+	f.setValidating(field, false)
+}
+
+// setValidating updates the validating flag of a field.
+func (f *Form[T]) setValidating(field string, validating bool) {
+	if f.validating.Peek()[field] == validating {
+		return
+	}
+	f.validating.Update(func(m map[string]bool) map[string]bool {
+		newMap := make(map[string]bool, len(m)+1)
+		for k, v := range m {
+			newMap[k] = v
+		}
+		if validating {
+			newMap[field] = true
+		} else {
+			delete(newMap, field)
+		}
+		return newMap
+	})
+}
+
+// IsValidating returns true if any field has an async validation in progress.
+func (f *Form[T]) IsValidating() bool {
+	return len(f.validating.Get()) > 0
+}
+
+// FieldValidating returns true if the field has an async validation in progress.
+func (f *Form[T]) FieldValidating(field string) bool {
+	return f.validating.Get()[field]
+}
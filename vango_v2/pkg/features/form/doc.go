@@ -45,6 +45,35 @@
 //   - Pattern: Regular expression matching
 //   - Min/Max: Numeric range constraints
 //   - Custom: User-defined validation logic
+//   - Async: Background checks such as "username taken" lookups
+//
+// # Async Validation
+//
+// Async validators run off the session event loop with a context that is
+// canceled when a newer value supersedes the check. They are debounced per
+// field, and their results are delivered back through the event loop:
+//
+//	form.AddValidators("username", form.Async(func(ctx context.Context, v any) error {
+//	    if taken, _ := users.Exists(ctx, v.(string)); taken {
+//	        return form.ValidationError{Message: "Username is already taken"}
+//	    }
+//	    return nil
+//	}))
+//
+// ValidateField schedules the check and FieldValidating reports it as
+// pending until the result arrives. Validate never blocks the event loop:
+// it returns false while checks are pending. Submit handlers use
+// ValidateAsync, which reports the final result on the event loop:
+//
+//	form.ValidateAsync(func(valid bool) {
+//	    if valid {
+//	        save(form.Values())
+//	    }
+//	})
+//
+// Async validators take a context and return only an error. Earlier
+// versions took func(value any) (error, bool) and reported completion
+// themselves; drop the bool and honor ctx instead.
 //
 // # Form Arrays
 //
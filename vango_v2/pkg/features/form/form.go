@@ -1,6 +1,7 @@
 package form
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"strings"
@@ -20,10 +21,17 @@ type Form[T any] struct {
 	touched    *vango.Signal[map[string]bool]
 	dirty      *vango.Signal[map[string]bool]
 	submitting *vango.Signal[bool]
+	validating *vango.Signal[map[string]bool]
 	validators map[string][]Validator
 	fieldMeta  map[string]fieldMeta
 
 	mu sync.RWMutex
+
+	// Async validation state, keyed by field path
+	async    map[string]*asyncField
+	asyncMu  sync.Mutex
+	dispatch vango.Dispatcher
+	loop     bool // Whether an event loop owns the form, so it must not block
 }
 
 // fieldMeta stores metadata extracted from struct tags.
@@ -44,8 +52,12 @@ func UseForm[T any](initial T) *Form[T] {
 		touched:    vango.NewSignal(make(map[string]bool)),
 		dirty:      vango.NewSignal(make(map[string]bool)),
 		submitting: vango.NewSignal(false),
+		validating: vango.NewSignal(make(map[string]bool)),
 		validators: make(map[string][]Validator),
 		fieldMeta:  make(map[string]fieldMeta),
+		async:      make(map[string]*asyncField),
+		dispatch:   vango.GetDispatcher(),
+		loop:       vango.HasDispatcher(),
 	}
 
 	// Parse struct tags to extract field metadata and validators
@@ -207,7 +219,10 @@ func (f *Form[T]) SetValues(values T) {
 }
 
 // Reset restores the form to its initial values and clears errors.
+// Pending async validations are canceled.
 func (f *Form[T]) Reset() {
+	f.cancelAsync()
+	f.validating.Set(make(map[string]bool))
 	f.values.Set(f.initial)
 	f.errors.Set(make(map[string][]string))
 	f.touched.Set(make(map[string]bool))
@@ -217,29 +232,142 @@ func (f *Form[T]) Reset() {
 
 // Validate runs all validators and returns true if the form is valid.
 // Validation errors are stored and can be accessed via Errors() or FieldErrors().
+//
+// Async validators of fields that pass their synchronous checks run without
+// debounce, reusing results already computed for the current values. On a
+// session event loop, Validate does not wait for them: the fields report as
+// validating, and their errors are applied when they finish.
+//
+// On an event loop, false therefore means "not known to be valid", not
+// "invalid": Validate returns false while any check is pending, even for
+// values that will pass. Do not show a generic error on false; render
+// Errors, which only holds actual failures, and IsValidating, or use
+// ValidateAsync to act on the final result, e.g. to submit. Elsewhere,
+// Validate waits at most DefaultAsyncTimeout; use ValidateContext to
+// control the deadline.
 func (f *Form[T]) Validate() bool {
-	allErrors := make(map[string][]string)
+	if !f.loop {
+		ctx, cancel := context.WithTimeout(context.Background(), DefaultAsyncTimeout)
+		defer cancel()
+		return f.ValidateContext(ctx)
+	}
 
-	for field, validators := range f.validators {
-		value := f.Get(field)
-		var fieldErrors []string
+	// done runs before ValidateAsync returns unless a check is pending
+	valid := false
+	f.ValidateAsync(func(v bool) { valid = v })
+	return valid
+}
 
-		for _, v := range validators {
-			if err := v.Validate(value); err != nil {
-				fieldErrors = append(fieldErrors, err.Error())
-			}
+// ValidateContext is like Validate but waits for async validators until ctx
+// is done, even on a session event loop, which it blocks meanwhile. Fields
+// whose async checks do not finish in time are reported as invalid.
+func (f *Form[T]) ValidateContext(ctx context.Context) bool {
+	allErrors, checks := f.validateSync()
+	results, waiting := f.startAsync(checks)
+	for field, errs := range results {
+		allErrors[field] = errs
+	}
+	for field, errs := range f.waitAsync(ctx, waiting) {
+		allErrors[field] = errs
+	}
+	return f.finishValidate(allErrors, checks)
+}
+
+// ValidateAsync validates the form like Validate without blocking, and calls
+// done with the result once every async validator has finished. done runs on
+// the session event loop, before ValidateAsync returns if no async check is
+// pending. Checks still running after DefaultAsyncTimeout fail.
+//
+// Example:
+//
+//	submit := func() {
+//	    form.ValidateAsync(func(valid bool) {
+//	        if valid {
+//	            save(form.Values())
+//	        }
+//	    })
+//	}
+func (f *Form[T]) ValidateAsync(done func(valid bool)) {
+	allErrors, checks := f.validateSync()
+	results, waiting := f.startAsync(checks)
+	for field, errs := range results {
+		allErrors[field] = errs
+	}
+	if len(waiting) == 0 {
+		valid := f.finishValidate(allErrors, checks)
+		if done != nil {
+			done(valid)
 		}
+		return
+	}
+
+	// Show the errors known so far while the checks run
+	for field := range waiting {
+		f.setValidating(field, true)
+	}
+	f.errors.Set(copyErrors(allErrors))
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), DefaultAsyncTimeout)
+		defer cancel()
+		asyncErrors := f.waitAsync(ctx, waiting)
+
+		f.dispatch.Dispatch(func() {
+			for field, errs := range asyncErrors {
+				allErrors[field] = errs
+			}
+			valid := f.finishValidate(allErrors, checks)
+			if done != nil {
+				done(valid)
+			}
+		})
+	}()
+}
+
+// validateSync runs the synchronous validators of every field. It returns
+// their errors, and the async validators of the fields that passed.
+func (f *Form[T]) validateSync() (allErrors map[string][]string, checks map[string][]*AsyncValidator) {
+	allErrors = make(map[string][]string)
+	checks = make(map[string][]*AsyncValidator)
+
+	for field, validators := range f.validators {
+		syncs, asyncs := splitValidators(validators)
+		fieldErrors := runValidators(syncs, f.Get(field))
 
 		if len(fieldErrors) > 0 {
 			allErrors[field] = fieldErrors
+		} else if len(asyncs) > 0 {
+			checks[field] = asyncs
 		}
 	}
+	return allErrors, checks
+}
 
+// finishValidate stores the errors of a validation and ends the validating
+// state of the fields it checked asynchronously.
+func (f *Form[T]) finishValidate(allErrors map[string][]string, checks map[string][]*AsyncValidator) bool {
+	for field := range checks {
+		f.setValidating(field, false)
+	}
 	f.errors.Set(allErrors)
 	return len(allErrors) == 0
 }
 
+// copyErrors returns a shallow copy of an errors map.
+func copyErrors(m map[string][]string) map[string][]string {
+	c := make(map[string][]string, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
 // ValidateField validates a single field and returns true if valid.
+//
+// If the field has async validators and passes its synchronous checks, the
+// async validators are scheduled in the background and the field reports as
+// validating until they finish. ValidateField then returns true unless a
+// result for the current value is already known.
 func (f *Form[T]) ValidateField(field string) bool {
 	validators, ok := f.validators[field]
 	if !ok {
@@ -247,15 +375,46 @@ func (f *Form[T]) ValidateField(field string) bool {
 	}
 
 	value := f.Get(field)
-	var fieldErrors []string
+	syncs, asyncs := splitValidators(validators)
+	fieldErrors := runValidators(syncs, value)
+
+	if len(asyncs) > 0 {
+		if len(fieldErrors) > 0 {
+			f.cancelAsyncField(field)
+		} else if errs, settled := f.scheduleAsync(field, value, asyncs); settled {
+			fieldErrors = errs
+		}
+	}
 
+	// Update errors map
+	f.setFieldErrors(field, fieldErrors)
+
+	// Mark as touched
+	f.touched.Update(func(m map[string]bool) map[string]bool {
+		newMap := make(map[string]bool, len(m)+1)
+		for k, v := range m {
+			newMap[k] = v
+		}
+		newMap[field] = true
+		return newMap
+	})
+
+	return len(fieldErrors) == 0
+}
+
+// runValidators runs synchronous validators and collects their messages.
+func runValidators(validators []Validator, value any) []string {
+	var fieldErrors []string
 	for _, v := range validators {
 		if err := v.Validate(value); err != nil {
 			fieldErrors = append(fieldErrors, err.Error())
 		}
 	}
+	return fieldErrors
+}
 
-	// Update errors map
+// setFieldErrors replaces the errors of a single field.
+func (f *Form[T]) setFieldErrors(field string, fieldErrors []string) {
 	f.errors.Update(func(m map[string][]string) map[string][]string {
 		newMap := make(map[string][]string, len(m))
 		for k, v := range m {
@@ -268,18 +427,6 @@ func (f *Form[T]) ValidateField(field string) bool {
 		}
 		return newMap
 	})
-
-	// Mark as touched
-	f.touched.Update(func(m map[string]bool) map[string]bool {
-		newMap := make(map[string]bool, len(m)+1)
-		for k, v := range m {
-			newMap[k] = v
-		}
-		newMap[field] = true
-		return newMap
-	})
-
-	return len(fieldErrors) == 0
 }
 
 // Errors returns all validation errors keyed by field name.
@@ -402,8 +549,13 @@ func (f *Form[T]) Field(name string, input *vdom.VNode, validators ...Validator)
 		))
 	}
 
+	wrapperClass := "field"
+	if f.FieldValidating(name) {
+		wrapperClass = "field field-validating"
+	}
+
 	return vdom.Div(
-		vdom.Class("field"),
+		vdom.Class(wrapperClass),
 		children,
 	)
 }
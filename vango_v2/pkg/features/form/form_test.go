@@ -1,8 +1,12 @@
 package form
 
 import (
+	"context"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/vango-dev/vango/v2/pkg/vango"
 	"github.com/vango-dev/vango/v2/pkg/vdom"
 )
 
@@ -875,3 +879,196 @@ func TestFormGetStringNonString(t *testing.T) {
 		t.Errorf("GetString(count) = %s, want '42'", str)
 	}
 }
+
+// TestSignup is a form with an async-validated field.
+type TestSignup struct {
+	Username string `form:"username" validate:"required"`
+}
+
+func TestFormAsyncValidateFieldDebouncesAndSupersedes(t *testing.T) {
+	form := UseForm(TestSignup{})
+
+	var mu sync.Mutex
+	var checked []string
+	taken := Async(func(ctx context.Context, value any) error {
+		mu.Lock()
+		checked = append(checked, value.(string))
+		mu.Unlock()
+		if value == "admin" {
+			return ValidationError{Message: "Username is already taken"}
+		}
+		return nil
+	}).Debounce(20 * time.Millisecond)
+	form.AddValidators("username", taken)
+
+	form.Set("username", "adm")
+	form.ValidateField("username")
+	form.Set("username", "admin")
+	if !form.ValidateField("username") {
+		t.Error("ValidateField should not fail before the async result is known")
+	}
+	if !form.FieldValidating("username") {
+		t.Error("Expected username to be validating")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for form.FieldValidating("username") && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if form.FieldValidating("username") {
+		t.Fatal("Async validation did not finish")
+	}
+	if !form.HasError("username") {
+		t.Error("Expected async error on username")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(checked) != 1 || checked[0] != "admin" {
+		t.Errorf("Expected only the latest value to be checked, got %v", checked)
+	}
+}
+
+func TestFormAsyncValidateAwaits(t *testing.T) {
+	form := UseForm(TestSignup{Username: "admin"})
+
+	form.AddValidators("username", Async(func(ctx context.Context, value any) error {
+		select {
+		case <-time.After(10 * time.Millisecond):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if value == "admin" {
+			return ValidationError{Message: "Username is already taken"}
+		}
+		return nil
+	}).Debounce(time.Hour))
+
+	// The debounced check must be flushed rather than waited out
+	form.ValidateField("username")
+	if form.Validate() {
+		t.Error("Expected Validate to fail with async error")
+	}
+	if errs := form.FieldErrors("username"); len(errs) != 1 || errs[0] != "Username is already taken" {
+		t.Errorf("Unexpected errors: %v", errs)
+	}
+	if form.IsValidating() {
+		t.Error("Expected no validation in progress after Validate")
+	}
+
+	form.Set("username", "gopher")
+	if !form.Validate() {
+		t.Errorf("Expected valid form, got errors %v", form.Errors())
+	}
+}
+
+func TestFormAsyncSkippedWhenSyncFails(t *testing.T) {
+	form := UseForm(TestSignup{})
+
+	called := false
+	form.AddValidators("username", Async(func(ctx context.Context, value any) error {
+		called = true
+		return nil
+	}))
+
+	if form.Validate() {
+		t.Error("Expected required error")
+	}
+	if called {
+		t.Error("Async validator should not run when sync validators fail")
+	}
+}
+
+func TestFormAsyncTimeout(t *testing.T) {
+	form := UseForm(TestSignup{Username: "slow"})
+
+	form.AddValidators("username", Async(func(ctx context.Context, value any) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}).Timeout(10*time.Millisecond))
+
+	if form.Validate() {
+		t.Error("Expected timed out validation to fail")
+	}
+	if errs := form.FieldErrors("username"); len(errs) != 1 || errs[0] != "Validation timed out" {
+		t.Errorf("Unexpected errors: %v", errs)
+	}
+}
+
+func TestFormValidateOnEventLoop(t *testing.T) {
+	// A dispatcher standing in for the session event loop
+	queue := make(chan func(), 10)
+	owner := vango.NewOwner(nil)
+	vango.SetDispatcher(owner, vango.DispatcherFunc(func(fn func()) { queue <- fn }))
+
+	var form *Form[TestSignup]
+	vango.WithOwner(owner, func() {
+		form = UseForm(TestSignup{Username: "admin"})
+	})
+
+	release := make(chan struct{})
+	form.AddValidators("username", Async(func(ctx context.Context, value any) error {
+		<-release
+		return ValidationError{Message: "Username is already taken"}
+	}))
+
+	returned := make(chan bool, 1)
+	go func() { returned <- form.Validate() }()
+	select {
+	case valid := <-returned:
+		if valid {
+			t.Error("Validate should not report valid while a check is pending")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Validate blocked on the event loop")
+	}
+	if !form.FieldValidating("username") {
+		t.Error("Expected username to be validating")
+	}
+
+	result := make(chan bool, 1)
+	form.ValidateAsync(func(valid bool) { result <- valid })
+	close(release)
+
+	// Run dispatched functions until ValidateAsync reports
+	for {
+		select {
+		case fn := <-queue:
+			fn()
+			continue
+		case valid := <-result:
+			if valid {
+				t.Error("Expected ValidateAsync to report invalid")
+			}
+		case <-time.After(time.Second):
+			t.Fatal("ValidateAsync did not report")
+		}
+		break
+	}
+	if errs := form.FieldErrors("username"); len(errs) != 1 || errs[0] != "Username is already taken" {
+		t.Errorf("Unexpected errors: %v", errs)
+	}
+	if form.FieldValidating("username") {
+		t.Error("Expected no validation in progress after ValidateAsync")
+	}
+}
+
+func TestFormAsyncReleasesContext(t *testing.T) {
+	form := UseForm(TestSignup{Username: "gopher"})
+
+	var runCtx context.Context
+	form.AddValidators("username", Async(func(ctx context.Context, value any) error {
+		runCtx = ctx
+		return nil
+	}).Timeout(0))
+
+	// Flush a debounced check, then let it settle
+	form.ValidateField("username")
+	if !form.Validate() {
+		t.Fatalf("Expected valid form, got errors %v", form.Errors())
+	}
+	if runCtx == nil || runCtx.Err() == nil {
+		t.Error("Expected the context of the settled run to be released")
+	}
+}
@@ -1,12 +1,15 @@
 package form
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/url"
 	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 	"unicode"
 )
@@ -487,36 +490,119 @@ func Custom(fn func(value any) error) Validator {
 	return ValidatorFunc(fn)
 }
 
-// AsyncValidator wraps an async validation function.
-// The function returns (error, isComplete).
+// Default settings for async validators.
+const (
+	// DefaultAsyncDebounce is how long a field must be idle before an async
+	// validator runs.
+	DefaultAsyncDebounce = 300 * time.Millisecond
+
+	// DefaultAsyncTimeout bounds a single async validator run.
+	DefaultAsyncTimeout = 10 * time.Second
+)
+
+// AsyncValidator validates a field value off the session event loop.
+//
+// When attached to a Form, the validator runs in a background goroutine after
+// the field has been idle for the debounce interval. Starting a new check for
+// the same field cancels the context of the previous one, and only the latest
+// result is applied. Results are delivered back through the session event loop,
+// so the field's errors and validating state re-render like any other change.
+//
+// Example:
+//
+//	usernameTaken := form.Async(func(ctx context.Context, value any) error {
+//	    taken, err := db.UsernameExists(ctx, value.(string))
+//	    if err != nil {
+//	        return err
+//	    }
+//	    if taken {
+//	        return form.ValidationError{Message: "Username is already taken"}
+//	    }
+//	    return nil
+//	}).Debounce(500 * time.Millisecond)
+//
+//	f.AddValidators("username", usernameTaken)
 type AsyncValidator struct {
-	fn       func(value any) (error, bool)
-	loading  bool
-	lastErr  error
-	complete bool
+	fn       func(ctx context.Context, value any) error
+	debounce time.Duration
+	timeout  time.Duration
+
+	inflight atomic.Int32
+	complete atomic.Bool
 }
 
 // Async creates an async validator for server-side checks.
-func Async(fn func(value any) (error, bool)) *AsyncValidator {
-	return &AsyncValidator{fn: fn}
+// The function should honor ctx, which is canceled when the check is superseded
+// by a newer value or exceeds the validator's timeout.
+func Async(fn func(ctx context.Context, value any) error) *AsyncValidator {
+	return &AsyncValidator{
+		fn:       fn,
+		debounce: DefaultAsyncDebounce,
+		timeout:  DefaultAsyncTimeout,
+	}
 }
 
+// Debounce sets how long the field must be idle before the validator runs.
+func (a *AsyncValidator) Debounce(d time.Duration) *AsyncValidator {
+	a.debounce = d
+	return a
+}
+
+// Timeout sets the maximum duration of a single validation run.
+// Zero disables the timeout.
+func (a *AsyncValidator) Timeout(d time.Duration) *AsyncValidator {
+	a.timeout = d
+	return a
+}
+
+// Validate runs the check synchronously on the calling goroutine.
+// It lets an AsyncValidator be used anywhere a Validator is expected;
+// Form runs async validators in the background instead.
 func (a *AsyncValidator) Validate(value any) error {
-	err, complete := a.fn(value)
-	a.complete = complete
-	a.loading = !complete
-	a.lastErr = err
+	return a.run(context.Background(), value)
+}
+
+// run executes the validation function with the configured timeout.
+func (a *AsyncValidator) run(ctx context.Context, value any) error {
+	a.inflight.Add(1)
+	defer a.inflight.Add(-1)
+
+	if a.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, a.timeout)
+		defer cancel()
+	}
+
+	err := a.fn(ctx, value)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = ValidationError{Message: "Validation timed out"}
+	}
+	a.complete.Store(true)
 	return err
 }
 
-// IsLoading returns true if the async validation is in progress.
+// IsLoading returns true if a validation run is in progress.
 func (a *AsyncValidator) IsLoading() bool {
-	return a.loading
+	return a.inflight.Load() > 0
 }
 
-// IsComplete returns true if the async validation has completed.
+// IsComplete returns true if at least one run has finished and none is in progress.
 func (a *AsyncValidator) IsComplete() bool {
-	return a.complete
+	return a.complete.Load() && a.inflight.Load() == 0
+}
+
+// splitValidators separates synchronous validators from async ones.
+func splitValidators(validators []Validator) ([]Validator, []*AsyncValidator) {
+	var syncs []Validator
+	var asyncs []*AsyncValidator
+	for _, v := range validators {
+		if av, ok := v.(*AsyncValidator); ok {
+			asyncs = append(asyncs, av)
+		} else {
+			syncs = append(syncs, v)
+		}
+	}
+	return syncs, asyncs
 }
 
 // ----------------------------------------------------------------------------
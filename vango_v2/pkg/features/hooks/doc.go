@@ -6,7 +6,30 @@
 // Usage:
 //
 //	Div(
-//	    Hook("Sortable", map[string]any{"group": "tasks"}),
-//	    OnEvent("reorder", func(e HookEvent) { ... }),
+//	    standard.Sortable(standard.SortableConfig{Group: "tasks"}),
+//	    standard.OnReorder(func(r standard.ReorderEvent, e hooks.HookEvent) {
+//	        if err := tasks.Move(r.ID, r.ToIndex); err != nil {
+//	            e.Revert()
+//	        }
+//	    }),
 //	)
+//
+// # Definitions
+//
+// Each hook is defined in Go with the type of its config, and each of its
+// events with the type of its payload, so hook names and config keys are
+// checked by the compiler:
+//
+//	var Chart = hooks.Define[ChartConfig]("Chart")
+//	var ChartSelect = hooks.DefineEvent[ChartSelection](Chart, "select")
+//
+// Hook accepts any name and config map; in the sessions of a server in dev
+// mode (see SetStrict), it panics at render time on hooks not defined and
+// on config keys their config type does not have.
+//
+// # Revert
+//
+// Hooks change the DOM before the server hears of it. HookEvent.Revert asks
+// the hook instance to undo the change of an event the server rejects: the
+// thin client calls its revert method with the name and data of the event.
 package hooks
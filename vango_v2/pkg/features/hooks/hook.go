@@ -10,7 +10,13 @@ import (
 
 // Hook creates a hook attribute for element.
 // The config map is serialized to JSON and sent to the client.
+// Prefer the typed Attr of hooks defined with Define; in strict scopes
+// (see SetStrict), Hook panics on hooks not defined.
 func Hook(name string, config any) vdom.Attr {
+	if isStrict() {
+		check(name, config)
+	}
+
 	// We serialize the config immediately to ensure it's valid JSON.
 	// In a real implementation, this might be handled by the renderer,
 	// but here we pack it into the attribute value.
@@ -37,6 +43,14 @@ func OnEvent(name string, handler func(HookEvent)) vdom.EventHandler {
 type HookEvent struct {
 	Name string
 	Data map[string]any
+
+	revert func() // Sends the revert instruction to the hook instance
+}
+
+// NewHookEvent returns the event name of a hook with its data. revert
+// undoes the change of the event on the client; it may be nil.
+func NewHookEvent(name string, data map[string]any, revert func()) HookEvent {
+	return HookEvent{Name: name, Data: data, revert: revert}
 }
 
 // Accessors
@@ -111,11 +125,12 @@ func (e HookEvent) Raw(key string) any {
 	return e.Data[key]
 }
 
-// Revert requests the client to revert the optimistic change.
-// This is typically handled by sending a command back to the client.
+// Revert requests the client to revert the change the hook made before
+// sending the event, e.g. when the server rejects a reorder. It calls the
+// revert method of the hook instance on the element, with the data of the
+// event. Revert does nothing for events not received from a live session.
 func (e HookEvent) Revert() {
-	// In the server-first model, reverting means invalidating the optimistic state
-	// or sending a specific command.
-	// For this implementation, we can't easily signal back without context.
-	// We'll leave it as a placeholder or dependent on how handlers are invoked.
+	if e.revert != nil {
+		e.revert()
+	}
 }
@@ -2,8 +2,11 @@ package hooks
 
 import (
 	"encoding/json"
+	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/vango-dev/vango/v2/pkg/vango"
 )
 
 func TestHook(t *testing.T) {
@@ -285,9 +288,14 @@ func TestHookEventRaw(t *testing.T) {
 func TestHookEventRevert(t *testing.T) {
 	e := HookEvent{Name: "test", Data: map[string]any{}}
 
-	// Revert should not panic (it's a placeholder)
+	// Revert of an event not received from a session does nothing
 	e.Revert()
-	// If we get here without panic, the test passes
+
+	reverted := false
+	NewHookEvent("test", nil, func() { reverted = true }).Revert()
+	if !reverted {
+		t.Error("Revert should send the revert instruction")
+	}
 }
 
 func TestHookEventName(t *testing.T) {
@@ -321,3 +329,95 @@ func TestHookEventNilData(t *testing.T) {
 		t.Error("Raw should return nil for nil data")
 	}
 }
+
+type chartConfig struct {
+	Type   string `json:"type"`
+	Legend bool   `json:"legend,omitempty"`
+	Theme  string
+	secret string
+}
+
+type chartSelection struct {
+	Series string `json:"series"`
+	Index  int    `json:"index"`
+}
+
+var (
+	testChart       = Define[chartConfig]("TestChart")
+	testChartSelect = DefineEvent[chartSelection](testChart, "select")
+)
+
+func TestDefine(t *testing.T) {
+	d, ok := Lookup("TestChart")
+	if !ok || d != testChart.Definition() {
+		t.Fatal("Lookup should return the definition of a defined hook")
+	}
+	if d.Config != reflect.TypeFor[chartConfig]() || d.Events["select"] != reflect.TypeFor[chartSelection]() {
+		t.Errorf("definition = %+v", d)
+	}
+	if _, ok := Lookup("TestMissing"); ok {
+		t.Error("Lookup of an undefined hook should fail")
+	}
+
+	attr := testChart.Attr(chartConfig{Type: "line"})
+	if attr.Value != `TestChart:{"type":"line","Theme":""}` {
+		t.Errorf("Attr() = %v", attr.Value)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Define should panic on a hook defined twice")
+		}
+	}()
+	Define[chartConfig]("TestChart")
+}
+
+func TestEventDefHandle(t *testing.T) {
+	var got []chartSelection
+	h := testChartSelect.Handle(func(s chartSelection, e HookEvent) {
+		got = append(got, s)
+	})
+	if h.Event != "onhook-select" {
+		t.Errorf("Event = %q, want onhook-select", h.Event)
+	}
+
+	fn := h.Handler.(func(HookEvent))
+	fn(HookEvent{Name: "select", Data: map[string]any{"series": "sales", "index": int64(3)}})
+	fn(HookEvent{Name: "hover", Data: map[string]any{"series": "costs"}})
+	fn(HookEvent{Name: "select", Data: map[string]any{"index": "three"}})
+
+	if len(got) != 1 || got[0] != (chartSelection{Series: "sales", Index: 3}) {
+		t.Errorf("handled %+v, want only the decoded select event", got)
+	}
+}
+
+func TestStrict(t *testing.T) {
+	owner := vango.NewOwner(nil)
+	SetStrict(owner, true)
+
+	tests := []struct {
+		name   string
+		hook   string
+		config any
+		panics bool
+	}{
+		{"defined", "TestChart", chartConfig{Type: "bar"}, false},
+		{"known keys", "TestChart", map[string]any{"type": "bar", "legend": true, "Theme": "dark"}, false},
+		{"unknown hook", "TestChrat", nil, true},
+		{"unknown key", "TestChart", map[string]any{"legnd": true}, true},
+		{"unexported field", "TestChart", map[string]any{"secret": "x"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); (r != nil) != tt.panics {
+					t.Errorf("Hook(%q) panic = %v, want panic %v", tt.hook, r, tt.panics)
+				}
+			}()
+			vango.WithOwner(owner, func() { Hook(tt.hook, tt.config) })
+		})
+	}
+
+	// Outside strict scopes, any hook is accepted
+	Hook("TestChrat", map[string]any{"legnd": true})
+}
@@ -0,0 +1,189 @@
+package hooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/vango-dev/vango/v2/pkg/vango"
+	"github.com/vango-dev/vango/v2/pkg/vdom"
+)
+
+// strictKey is the Owner value key of the strict setting.
+type strictKey struct{}
+
+// SetStrict makes Hook, when rendering under owner or its descendants,
+// panic on hooks not registered with Define and on map configs with keys
+// the config type of the hook does not have, so typos fail at render time
+// instead of in the browser. The server sets it on the sessions of servers
+// in dev mode (ServerConfig.DevMode).
+func SetStrict(owner *vango.Owner, strict bool) {
+	if owner == nil {
+		return
+	}
+	owner.SetValue(strictKey{}, strict)
+}
+
+// isStrict reports whether the current owner scope is strict.
+func isStrict() bool {
+	strict, _ := vango.GetContext(strictKey{}).(bool)
+	return strict
+}
+
+// Definition describes a client hook: its name, the type of its config and
+// the payload types of its events.
+type Definition struct {
+	Name   string
+	Config reflect.Type
+	Events map[string]reflect.Type
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]*Definition)
+)
+
+// Lookup returns the definition of the hook name.
+func Lookup(name string) (*Definition, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	d, ok := registry[name]
+	return d, ok
+}
+
+// Def is a hook defined with the config type C.
+type Def[C any] struct {
+	def *Definition
+}
+
+// Define registers the client hook name, implemented in JavaScript and
+// registered in the thin client, with its config type C. The JSON names of
+// the fields of C are the config keys of the hook:
+//
+//	var Chart = hooks.Define[ChartConfig]("Chart")
+//	var ChartSelect = hooks.DefineEvent[ChartSelection](Chart, "select")
+//
+//	Div(Chart.Attr(ChartConfig{Type: "line"}), ChartSelect.Handle(onSelect))
+//
+// Define panics if name is already defined.
+func Define[C any](name string) Def[C] {
+	d := &Definition{
+		Name:   name,
+		Config: reflect.TypeFor[C](),
+		Events: make(map[string]reflect.Type),
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, exists := registry[name]; exists {
+		panic(fmt.Sprintf("hooks: hook %q defined twice", name))
+	}
+	registry[name] = d
+	return Def[C]{def: d}
+}
+
+// Name returns the name of the hook.
+func (d Def[C]) Name() string {
+	return d.def.Name
+}
+
+// Definition returns the definition of the hook.
+func (d Def[C]) Definition() *Definition {
+	return d.def
+}
+
+// Attr returns the attribute attaching the hook to an element with config.
+func (d Def[C]) Attr(config C) vdom.Attr {
+	return Hook(d.def.Name, config)
+}
+
+// EventDef is an event of a hook with the payload T.
+type EventDef[T any] struct {
+	hook string
+	name string
+}
+
+// DefineEvent declares the event name of the hook d, whose data decodes
+// into T.
+func DefineEvent[T any, C any](d Def[C], name string) EventDef[T] {
+	registryMu.Lock()
+	d.def.Events[name] = reflect.TypeFor[T]()
+	registryMu.Unlock()
+	return EventDef[T]{hook: d.def.Name, name: name}
+}
+
+// Name returns the name of the event.
+func (e EventDef[T]) Name() string {
+	return e.name
+}
+
+// Handle returns the handler of the event, called with its decoded
+// payload and the raw event, e.g. to Revert it. Other events of the hook,
+// and events whose data does not decode into T, are ignored.
+func (e EventDef[T]) Handle(fn func(T, HookEvent)) vdom.EventHandler {
+	return vdom.EventHandler{
+		Event: "onhook-" + e.name,
+		Handler: func(he HookEvent) {
+			if he.Name != e.name {
+				return
+			}
+			var payload T
+			if err := he.Decode(&payload); err != nil {
+				return
+			}
+			fn(payload, he)
+		},
+	}
+}
+
+// check panics if name is not a defined hook, or config has keys its
+// config type does not have. Only called in strict scopes.
+func check(name string, config any) {
+	d, ok := Lookup(name)
+	if !ok {
+		panic(fmt.Sprintf("hooks: unknown hook %q (define it with hooks.Define)", name))
+	}
+
+	m, ok := config.(map[string]any)
+	if !ok || d.Config == nil || d.Config.Kind() != reflect.Struct {
+		return
+	}
+	keys := configKeys(d.Config)
+	for key := range m {
+		if !keys[key] {
+			panic(fmt.Sprintf("hooks: %s has no config key %q", name, key))
+		}
+	}
+}
+
+// configKeys returns the JSON names of the fields of the struct type t.
+func configKeys(t reflect.Type) map[string]bool {
+	keys := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = f.Name
+		}
+		keys[name] = true
+	}
+	return keys
+}
+
+// Decode decodes the data of the event into v, a pointer to the payload
+// type of the event.
+func (e HookEvent) Decode(v any) error {
+	data, err := json.Marshal(e.Data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
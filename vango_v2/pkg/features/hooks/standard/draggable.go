@@ -7,17 +7,32 @@ import (
 
 // DraggableConfig configures the Draggable hook.
 type DraggableConfig struct {
-	Axis   string `json:"axis,omitempty"` // "x", "y", or "both"
-	Handle string `json:"handle,omitempty"`
-	Revert bool   `json:"revert,omitempty"`
+	Axis   string `json:"axis"` // "x", "y", or "both"
+	Handle string `json:"handle"`
+	Revert bool   `json:"revert"`
+	Bounds string `json:"bounds,omitempty"` // "parent", "window", or none
 }
 
+// PositionEvent is sent by the Draggable hook when a drag ends, with the
+// offset of the element from its original position.
+type PositionEvent struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// DraggableHook is the definition of the Draggable hook.
+var DraggableHook = hooks.Define[DraggableConfig]("Draggable")
+
+// DraggablePosition is the position event of the Draggable hook.
+var DraggablePosition = hooks.DefineEvent[PositionEvent](DraggableHook, "position")
+
 // Draggable creates a Draggable hook attribute.
 func Draggable(config DraggableConfig) vdom.Attr {
-	m := map[string]any{
-		"axis":   config.Axis,
-		"handle": config.Handle,
-		"revert": config.Revert,
-	}
-	return hooks.Hook("Draggable", m)
+	return DraggableHook.Attr(config)
+}
+
+// OnPosition handles the position event of a Draggable element. Call
+// Revert on the event to move the element back to where the drag started.
+func OnPosition(fn func(PositionEvent, hooks.HookEvent)) vdom.EventHandler {
+	return DraggablePosition.Handle(fn)
 }
@@ -7,15 +7,31 @@ import (
 
 // DropdownConfig configures the Dropdown hook.
 type DropdownConfig struct {
-	CloseOnEscape bool `json:"closeOnEscape,omitempty"`
-	CloseOnClick  bool `json:"closeOnClick,omitempty"`
+	CloseOnEscape bool `json:"closeOnEscape"`
+	CloseOnClick  bool `json:"closeOnClickOutside"`
+
+	// Deprecated: the thin client reads closeOnClickOutside, set with
+	// CloseOnClick. Dropdown also sends CloseOnClick under the old
+	// closeOnClick key for clients that read it.
+	LegacyCloseOnClick bool `json:"closeOnClick"`
 }
 
+// CloseEvent is sent by the Dropdown hook on Escape or a click outside.
+type CloseEvent struct{}
+
+// DropdownHook is the definition of the Dropdown hook.
+var DropdownHook = hooks.Define[DropdownConfig]("Dropdown")
+
+// DropdownClose is the close event of the Dropdown hook.
+var DropdownClose = hooks.DefineEvent[CloseEvent](DropdownHook, "close")
+
 // Dropdown creates a Dropdown hook attribute.
 func Dropdown(config DropdownConfig) vdom.Attr {
-	m := map[string]any{
-		"closeOnEscape": config.CloseOnEscape,
-		"closeOnClick":  config.CloseOnClick,
-	}
-	return hooks.Hook("Dropdown", m)
+	config.LegacyCloseOnClick = config.CloseOnClick
+	return DropdownHook.Attr(config)
+}
+
+// OnClose handles the close event of a Dropdown.
+func OnClose(fn func(CloseEvent, hooks.HookEvent)) vdom.EventHandler {
+	return DropdownClose.Handle(fn)
 }
@@ -0,0 +1,19 @@
+package standard
+
+import (
+	"github.com/vango-dev/vango/v2/pkg/features/hooks"
+	"github.com/vango-dev/vango/v2/pkg/vdom"
+)
+
+// FocusTrapConfig configures the FocusTrap hook.
+type FocusTrapConfig struct {
+	Active bool `json:"active"` // Constrain Tab navigation to the element
+}
+
+// FocusTrapHook is the definition of the FocusTrap hook.
+var FocusTrapHook = hooks.Define[FocusTrapConfig]("FocusTrap")
+
+// FocusTrap creates a FocusTrap hook attribute, for dialogs and modals.
+func FocusTrap(config FocusTrapConfig) vdom.Attr {
+	return FocusTrapHook.Attr(config)
+}
@@ -0,0 +1,20 @@
+package standard
+
+import (
+	"github.com/vango-dev/vango/v2/pkg/features/hooks"
+	"github.com/vango-dev/vango/v2/pkg/vdom"
+)
+
+// PortalConfig configures the Portal hook.
+type PortalConfig struct {
+	Target string `json:"target,omitempty"` // Default: "body"
+}
+
+// PortalHook is the definition of the Portal hook.
+var PortalHook = hooks.Define[PortalConfig]("Portal")
+
+// Portal creates a Portal hook attribute, which moves the element to the
+// end of the document to escape overflow and stacking contexts.
+func Portal(config PortalConfig) vdom.Attr {
+	return PortalHook.Attr(config)
+}
@@ -10,13 +10,40 @@ type SortableConfig struct {
 	Group      string `json:"group,omitempty"`
 	Animation  int    `json:"animation,omitempty"`
 	GhostClass string `json:"ghostClass,omitempty"`
+	DragClass  string `json:"dragClass,omitempty"`
 	Handle     string `json:"handle,omitempty"`
 	Disabled   bool   `json:"disabled,omitempty"`
 }
 
-// Sortable creates a Sortable hook attribute.
+// ReorderEvent is sent by the Sortable hook when an item is dropped at a
+// new position, possibly in another list of the same group.
+type ReorderEvent struct {
+	ID            string `json:"id"`            // data-id of the item, or its HID
+	FromContainer string `json:"fromContainer"` // data-id of the list, or its HID
+	ToContainer   string `json:"toContainer"`
+	FromIndex     int    `json:"fromIndex"`
+	ToIndex       int    `json:"toIndex"`
+}
+
+// SortableHook is the definition of the Sortable hook.
+var SortableHook = hooks.Define[SortableConfig]("Sortable")
+
+// SortableReorder is the reorder event of the Sortable hook.
+var SortableReorder = hooks.DefineEvent[ReorderEvent](SortableHook, "reorder")
+
+// Sortable creates a Sortable hook attribute from a SortableConfig.
+//
+// Deprecated config maps (map[string]any) are still accepted and sent as
+// is; in strict scopes their keys are checked against SortableConfig.
 func Sortable(config any) vdom.Attr {
-	// We pass the config directly, relying on hooks.Hook to marshal it.
-	// This supports both structs and maps.
-	return hooks.Hook("Sortable", config)
+	if c, ok := config.(SortableConfig); ok {
+		return SortableHook.Attr(c)
+	}
+	return hooks.Hook(SortableHook.Name(), config)
+}
+
+// OnReorder handles the reorder event of a Sortable list. Call Revert on
+// the event to move the item back if the new order is rejected.
+func OnReorder(fn func(ReorderEvent, hooks.HookEvent)) vdom.EventHandler {
+	return SortableReorder.Handle(fn)
 }
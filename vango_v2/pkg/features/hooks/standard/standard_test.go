@@ -3,6 +3,8 @@ package standard
 import (
 	"strings"
 	"testing"
+
+	"github.com/vango-dev/vango/v2/pkg/features/hooks"
 )
 
 func TestSortable(t *testing.T) {
@@ -255,3 +257,48 @@ func TestAllHooksReturnValidAttr(t *testing.T) {
 		})
 	}
 }
+
+func TestDropdownConfigKeys(t *testing.T) {
+	val := Dropdown(DropdownConfig{CloseOnClick: true}).Value.(string)
+	want := `Dropdown:{"closeOnEscape":false,"closeOnClickOutside":true,"closeOnClick":true}`
+	if val != want {
+		t.Errorf("Dropdown() = %s, want %s", val, want)
+	}
+}
+
+func TestSortableMapConfig(t *testing.T) {
+	val := Sortable(map[string]any{"group": "tasks"}).Value.(string)
+	if want := `Sortable:{"group":"tasks"}`; val != want {
+		t.Errorf("Sortable() = %s, want %s", val, want)
+	}
+}
+
+func TestStandardHooksDefined(t *testing.T) {
+	for _, name := range []string{"Sortable", "Draggable", "Tooltip", "Dropdown", "FocusTrap", "Portal"} {
+		if _, ok := hooks.Lookup(name); !ok {
+			t.Errorf("hook %s is not defined", name)
+		}
+	}
+}
+
+func TestOnReorder(t *testing.T) {
+	var got ReorderEvent
+	reverted := false
+	h := OnReorder(func(r ReorderEvent, e hooks.HookEvent) {
+		got = r
+		e.Revert()
+	})
+
+	h.Handler.(func(hooks.HookEvent))(hooks.NewHookEvent("reorder", map[string]any{
+		"id":            "task-7",
+		"fromContainer": "todo",
+		"toContainer":   "done",
+		"fromIndex":     int64(2),
+		"toIndex":       int64(0),
+	}, func() { reverted = true }))
+
+	want := ReorderEvent{ID: "task-7", FromContainer: "todo", ToContainer: "done", FromIndex: 2}
+	if got != want || !reverted {
+		t.Errorf("got %+v (reverted %v), want %+v", got, reverted, want)
+	}
+}
@@ -8,18 +8,15 @@ import (
 // TooltipConfig configures the Tooltip hook.
 type TooltipConfig struct {
 	Content   string `json:"content"`
-	Placement string `json:"placement,omitempty"` // top, bottom, left, right
-	Delay     int    `json:"delay,omitempty"`     // ms
-	Trigger   string `json:"trigger,omitempty"`   // hover, click, focus
+	Placement string `json:"placement"` // top, bottom, left, right
+	Delay     int    `json:"delay"`     // ms
+	Trigger   string `json:"trigger"`   // hover, click, focus
 }
 
+// TooltipHook is the definition of the Tooltip hook.
+var TooltipHook = hooks.Define[TooltipConfig]("Tooltip")
+
 // Tooltip creates a Tooltip hook attribute.
 func Tooltip(config TooltipConfig) vdom.Attr {
-	m := map[string]any{
-		"content":   config.Content,
-		"placement": config.Placement,
-		"delay":     config.Delay,
-		"trigger":   config.Trigger,
-	}
-	return hooks.Hook("Tooltip", m)
+	return TooltipHook.Attr(config)
 }
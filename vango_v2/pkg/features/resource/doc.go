@@ -20,4 +20,18 @@
 //	    resource.OnError(func(err error) *vdom.VNode { return Error(err) }),
 //	    resource.OnReady(func(u *User) *vdom.VNode { return UserProfile(u) }),
 //	)
+//
+// Suspense:
+//
+// Reading a loading resource inside a vdom.Suspense boundary suspends the
+// boundary, which renders its fallback until the fetch settles. With
+// streaming SSR this lets the rest of the page flush before slow data loads:
+//
+//	return vdom.Suspense(Skeleton(), func() *vdom.VNode {
+//	    return UserProfile(user.Data())
+//	})
+//
+// A Handover carries the data resolved during SSR to the live session of
+// the page, whose resources then start ready instead of fetching again and
+// whose boundaries match the streamed content.
 package resource
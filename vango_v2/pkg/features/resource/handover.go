@@ -0,0 +1,275 @@
+package resource
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/vango-dev/vango/v2/pkg/server"
+	"github.com/vango-dev/vango/v2/pkg/vango"
+)
+
+// Handover carries the data of resources resolved during server-side
+// rendering to the live session of the page. Resources created in the
+// session start ready with that data instead of fetching again, so Suspense
+// boundaries whose content was streamed start resolved and the session
+// matches the streamed HTML.
+//
+// Resources are matched by the order they are created in and by type;
+// the page must create the same resources in SSR and in the session's
+// first render. A resource that was not ready when the handover was sealed,
+// or whose type differs, fetches as usual.
+//
+//	store := resource.NewHandoverStore(time.Minute)
+//
+//	// Page handler
+//	h := resource.NewHandover()
+//	owner := vango.NewOwner(nil)
+//	h.Attach(owner)
+//	vango.WithOwner(owner, func() { body = App() })
+//	store.Save(h)
+//	sr.RenderPage(render.PageData{Body: body, Resources: h})
+//
+//	// Server config
+//	config.OnSessionStart = func(httpCtx context.Context, s *server.Session) {
+//	    store.StartSession(httpCtx, s)
+//	}
+type Handover struct {
+	mu       sync.Mutex
+	entries  []handoverEntry
+	next     int
+	restored bool
+	sealed   bool
+	token    string
+}
+
+// handoverEntry is the data of one resource, in creation order.
+// Raw is nil for resources that were not ready.
+type handoverEntry struct {
+	Type     string             `json:"type"`
+	Raw      json.RawMessage    `json:"data,omitempty"`
+	snapshot func() (any, bool) // Reads the resource until sealed
+}
+
+// handoverKey is the owner value key of the Handover in scope.
+type handoverKey struct{}
+
+// NewHandover creates a handover that records the resources created under
+// the owners it is attached to.
+func NewHandover() *Handover {
+	return &Handover{}
+}
+
+// Attach makes h the handover of resources created under owner.
+func (h *Handover) Attach(owner *vango.Owner) {
+	owner.SetValue(handoverKey{}, h)
+}
+
+// Seal records the data of the ready resources and stops recording. The
+// streaming renderer seals the handover of a page once its suspended
+// content has been streamed. Seal is idempotent.
+func (h *Handover) Seal() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.sealed {
+		return
+	}
+	h.sealed = true
+
+	for i := range h.entries {
+		e := &h.entries[i]
+		if e.snapshot == nil {
+			continue
+		}
+		if v, ok := e.snapshot(); ok {
+			// Unencodable data is fetched again by the session
+			if b, err := json.Marshal(v); err == nil {
+				e.Raw = b
+			}
+		}
+		e.snapshot = nil
+	}
+}
+
+// Token returns the token h was saved under, or "" if it was not saved.
+func (h *Handover) Token() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.token
+}
+
+// MarshalJSON implements json.Marshaler. Only sealed data is encoded.
+func (h *Handover) MarshalJSON() ([]byte, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return json.Marshal(h.entries)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The result hands its data to
+// the resources created under the owner it is attached to.
+func (h *Handover) UnmarshalJSON(b []byte) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.restored = true
+	h.sealed = true
+	return json.Unmarshal(b, &h.entries)
+}
+
+// handOver records r in the handover in scope, or restores its data from
+// it. It reports whether r was restored and must not fetch.
+func handOver[T any](r *Resource[T]) bool {
+	h, _ := vango.GetContext(handoverKey{}).(*Handover)
+	if h == nil {
+		return false
+	}
+	typ := typeName(reflect.TypeOf((*T)(nil)).Elem())
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.restored {
+		if !h.sealed {
+			h.entries = append(h.entries, handoverEntry{
+				Type: typ,
+				snapshot: func() (any, bool) {
+					if r.state.Peek() != Ready {
+						return nil, false
+					}
+					return r.data.Peek(), true
+				},
+			})
+		}
+		return false
+	}
+
+	if h.next >= len(h.entries) {
+		return false
+	}
+	e := h.entries[h.next]
+	h.next++
+	if e.Type != typ || e.Raw == nil {
+		return false
+	}
+	var data T
+	if err := json.Unmarshal(e.Raw, &data); err != nil {
+		return false
+	}
+	r.data.Set(data)
+	r.state.Set(Ready)
+	r.lastFetch = time.Now()
+	return true
+}
+
+// typeName returns a stable name for t, used to match data across a JSON
+// round trip.
+func typeName(t reflect.Type) string {
+	if t == nil {
+		return ""
+	}
+	return t.String()
+}
+
+// =============================================================================
+// Handover to Live Sessions
+// =============================================================================
+
+// HandoverParam is the query parameter of the WebSocket URL that carries
+// the handover token of the page.
+const HandoverParam = "resources"
+
+// HandoverStore keeps the handovers of rendered pages until their live
+// session claims them. Entries not claimed within the TTL are dropped.
+type HandoverStore struct {
+	mu      sync.Mutex
+	entries map[string]storedHandover
+	ttl     time.Duration
+}
+
+// storedHandover is a handover awaiting its session.
+type storedHandover struct {
+	handover *Handover
+	expires  time.Time
+}
+
+// NewHandoverStore creates a store keeping handovers for ttl.
+// Default: 1 minute
+func NewHandoverStore(ttl time.Duration) *HandoverStore {
+	if ttl <= 0 {
+		ttl = time.Minute
+	}
+	return &HandoverStore{
+		entries: make(map[string]storedHandover),
+		ttl:     ttl,
+	}
+}
+
+// Save stores h and returns its token, also available as h.Token. The
+// data is read when h is sealed, so Save may be called before rendering.
+func (s *HandoverStore) Save(h *Handover) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(buf)
+
+	h.mu.Lock()
+	h.token = token
+	h.mu.Unlock()
+
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Drop expired entries
+	for k, e := range s.entries {
+		if now.After(e.expires) {
+			delete(s.entries, k)
+		}
+	}
+	s.entries[token] = storedHandover{handover: h, expires: now.Add(s.ttl)}
+	return token, nil
+}
+
+// Take returns the data of the handover saved under token and removes it.
+// A handover that was never sealed is sealed now.
+func (s *HandoverStore) Take(token string) (*Handover, bool) {
+	s.mu.Lock()
+	e, ok := s.entries[token]
+	delete(s.entries, token)
+	s.mu.Unlock()
+
+	if !ok || time.Now().After(e.expires) {
+		return nil, false
+	}
+
+	e.handover.Seal()
+	b, err := json.Marshal(e.handover)
+	if err != nil {
+		return nil, false
+	}
+	restored := &Handover{}
+	if err := json.Unmarshal(b, restored); err != nil {
+		return nil, false
+	}
+	return restored, true
+}
+
+// StartSession attaches the handover of the page that opened session to
+// the session's owner, so its first render starts from the SSR data. Call
+// it from OnSessionStart.
+func (s *HandoverStore) StartSession(httpCtx context.Context, session *server.Session) bool {
+	r := server.RequestFromContext(httpCtx)
+	if r == nil || session == nil {
+		return false
+	}
+	h, ok := s.Take(r.URL.Query().Get(HandoverParam))
+	if !ok {
+		return false
+	}
+	h.Attach(session.Owner())
+	return true
+}
@@ -0,0 +1,127 @@
+package resource
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vango-dev/vango/v2/pkg/server"
+	"github.com/vango-dev/vango/v2/pkg/vango"
+	"github.com/vango-dev/vango/v2/pkg/vdom"
+)
+
+// waitReady waits until r has loaded.
+func waitReady[T any](t *testing.T, r *Resource[T]) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for r.state.Peek() != Ready {
+		if time.Now().After(deadline) {
+			t.Fatal("resource did not load")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestHandoverStartsSessionResolved(t *testing.T) {
+	store := NewHandoverStore(time.Minute)
+
+	// Server-side render: one resource loads, one is still loading when
+	// the page is sealed
+	h := NewHandover()
+	ssr := vango.NewOwner(nil)
+	h.Attach(ssr)
+	block := make(chan struct{})
+	defer close(block)
+
+	var user *Resource[string]
+	vango.WithOwner(ssr, func() {
+		user = New(func() (string, error) { return "ada", nil })
+		New(func() (int, error) { <-block; return 1, nil })
+	})
+	waitReady(t, user)
+
+	token, err := store.Save(h)
+	if err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+	if h.Token() != token {
+		t.Errorf("Token() = %q, want %q", h.Token(), token)
+	}
+	h.Seal()
+
+	restored, ok := store.Take(token)
+	if !ok {
+		t.Fatal("Take() should return the saved handover")
+	}
+	if _, ok := store.Take(token); ok {
+		t.Error("a handover should only be taken once")
+	}
+
+	// Live session: the same resources in the same order
+	live := vango.NewOwner(nil)
+	restored.Attach(live)
+	var fetches atomic.Int32
+
+	var node *vdom.VNode
+	var count *Resource[int]
+	vango.WithOwner(live, func() {
+		user := New(func() (string, error) { fetches.Add(1); return "grace", nil })
+		count = New(func() (int, error) { fetches.Add(1); return 2, nil })
+		node = vdom.Suspense(vdom.Text("loading"), func() *vdom.VNode {
+			return vdom.Text(user.Data())
+		})
+	})
+
+	if b := vdom.SuspenseOf(node); b == nil || b.IsPending() {
+		t.Error("boundary over handed-over data should start resolved")
+	}
+	if got := node.Children[0].Children[0].Text; got != "ada" {
+		t.Errorf("boundary content = %q, want the SSR data", got)
+	}
+
+	// Only the resource that was not ready when sealed fetches
+	waitReady(t, count)
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("fetches = %d, want 1", n)
+	}
+}
+
+func TestHandoverTypeMismatchFetches(t *testing.T) {
+	h := NewHandover()
+	ssr := vango.NewOwner(nil)
+	h.Attach(ssr)
+	var r *Resource[string]
+	vango.WithOwner(ssr, func() {
+		r = New(func() (string, error) { return "ada", nil })
+	})
+	waitReady(t, r)
+
+	store := NewHandoverStore(time.Minute)
+	token, _ := store.Save(h)
+	restored, ok := store.Take(token) // Seals h
+	if !ok {
+		t.Fatal("Take() should return the saved handover")
+	}
+
+	live := vango.NewOwner(nil)
+	restored.Attach(live)
+	var fetched atomic.Bool
+	var n *Resource[int]
+	vango.WithOwner(live, func() {
+		n = New(func() (int, error) { fetched.Store(true); return 7, nil })
+	})
+	waitReady(t, n)
+	if !fetched.Load() || n.Data() != 7 {
+		t.Error("a resource of another type should fetch its own data")
+	}
+}
+
+func TestHandoverStoreStartSession(t *testing.T) {
+	store := NewHandoverStore(time.Minute)
+
+	// Without the upgrade request there is nothing to hand over
+	if store.StartSession(context.Background(), server.NewMockSession()) {
+		t.Error("StartSession() should report no handover")
+	}
+}
@@ -38,12 +38,14 @@ type Resource[T any] struct {
 
 	// Internal
 	lastFetch time.Time
-	fetchID   uint64 // For cancelling/ignoring outdated fetches
+	fetchID   uint64        // For cancelling/ignoring outdated fetches
+	ready     chan struct{} // Closed when the current fetch settles
 	mu        sync.Mutex
 }
 
 // New creates a new Resource with the given fetcher function.
-// The fetch is triggered immediately.
+// The fetch is triggered immediately, unless a Handover in scope provides
+// the data.
 func New[T any](fetcher func() (T, error)) *Resource[T] {
 	r := &Resource[T]{
 		fetcher: fetcher,
@@ -51,6 +53,9 @@ func New[T any](fetcher func() (T, error)) *Resource[T] {
 		data:    vango.NewSignal(*new(T)),
 		err:     vango.NewSignal[error](nil),
 	}
+	if handOver(r) {
+		return r
+	}
 	r.Fetch()
 	return r
 }
@@ -66,9 +71,15 @@ func NewWithKey[K comparable, T any](key func() K, fetcher func(K) (T, error)) *
 
 	r := New(wrappedFetcher)
 
-	// Setup effect to refetch when key changes
+	// Setup effect to refetch when key changes. New already fetched (or
+	// took handed-over data) for the first key.
+	first := true
 	vango.CreateEffect(func() vango.Cleanup {
 		key() // Track dependency
+		if first {
+			first = false
+			return nil
+		}
 		r.Fetch()
 		return nil
 	})
@@ -85,26 +96,41 @@ func NewWithKey[K comparable, T any](key func() K, fetcher func(K) (T, error)) *
 
 // State methods
 
+// State returns the current state. Reading a loading resource inside a
+// suspense boundary suspends the boundary until the fetch settles.
 func (r *Resource[T]) State() State {
-	return r.state.Get()
+	s := r.state.Get()
+	if s == Loading || s == Pending {
+		r.suspend()
+	}
+	return s
 }
 
 func (r *Resource[T]) IsLoading() bool {
-	s := r.state.Get()
+	s := r.State()
 	return s == Loading || s == Pending
 }
 
 func (r *Resource[T]) IsReady() bool {
-	return r.state.Get() == Ready
+	return r.State() == Ready
 }
 
 func (r *Resource[T]) IsError() bool {
-	return r.state.Get() == Error
+	return r.State() == Error
+}
+
+// suspend reports the in-flight fetch to the enclosing suspense boundary.
+func (r *Resource[T]) suspend() {
+	r.mu.Lock()
+	ready := r.ready
+	r.mu.Unlock()
+	vango.Suspend(ready)
 }
 
 // Data access methods
 
 func (r *Resource[T]) Data() T {
+	r.State()
 	return r.data.Get()
 }
 
@@ -138,6 +164,12 @@ func (r *Resource[T]) Refetch() {
 	r.mu.Lock()
 	r.fetchID++
 	currentID := r.fetchID
+	if r.ready != nil {
+		// Release waiters on the superseded fetch; they re-check the state
+		close(r.ready)
+	}
+	ready := make(chan struct{})
+	r.ready = ready
 	r.mu.Unlock()
 
 	r.state.Set(Loading)
@@ -191,6 +223,13 @@ func (r *Resource[T]) Refetch() {
 				r.onSuccess(result)
 			}
 		}
+
+		r.mu.Lock()
+		if r.fetchID == currentID {
+			close(ready)
+			r.ready = nil
+		}
+		r.mu.Unlock()
 	}()
 }
 
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/vango-dev/vango/v2/pkg/vango"
 	"github.com/vango-dev/vango/v2/pkg/vdom"
 )
 
@@ -555,3 +556,34 @@ func TestResourceOnErrorChaining(t *testing.T) {
 		t.Error("OnError should return the resource for chaining")
 	}
 }
+
+func TestResourceSuspendsWhileLoading(t *testing.T) {
+	release := make(chan struct{})
+	r := New(func() (string, error) {
+		<-release
+		return "loaded", nil
+	})
+
+	pending := vango.CollectSuspense(func() {
+		_ = r.Data()
+	})
+	if len(pending) != 1 {
+		t.Fatalf("loading resource should suspend, got %d pending", len(pending))
+	}
+
+	close(release)
+	select {
+	case <-pending[0]:
+	case <-time.After(time.Second):
+		t.Fatal("pending channel should close when the fetch settles")
+	}
+
+	pending = vango.CollectSuspense(func() {
+		if got := r.Data(); got != "loaded" {
+			t.Errorf("Data() = %q, want loaded", got)
+		}
+	})
+	if len(pending) != 0 {
+		t.Errorf("ready resource should not suspend, got %d pending", len(pending))
+	}
+}
@@ -0,0 +1,38 @@
+// Package virtual renders long lists as a window of rows.
+//
+// A list of 10,000 rows rendered in full keeps 10,000 VNodes in every
+// session. A VirtualList mounts only the rows in view plus a few rows of
+// overscan, and spacers as high as the rows around them, so the viewport
+// scrolls like the full list:
+//
+//	func Inbox(messages *vango.Signal[[]Message]) vango.Component {
+//	    list := virtual.New(
+//	        func(m Message) string { return m.ID },
+//	        func(m Message, i int) *vdom.VNode { return MessageRow(m) },
+//	    ).Height(600).RowHeight(48).Persist("inbox")
+//
+//	    return vango.Func(func() *vdom.VNode {
+//	        return list.Render(messages.Get(), Class("inbox"))
+//	    })
+//	}
+//
+// # Window
+//
+// The viewport sends scroll events; when the rows in view leave the
+// rendered window, the component renders the window at the new position.
+// Rows are keyed, so the patches insert the entering rows and remove the
+// leaving ones, and the rows that stay are not touched.
+//
+// # Row Heights
+//
+// With RowHeight, offsets are computed from the fixed height. Without it,
+// the VirtualList client hook measures rows as they are mounted and
+// resized, and reports their heights; rows not measured yet are assumed
+// to be Estimate pixels high.
+//
+// # Scroll Position
+//
+// Lists with Persist save their scroll position on the client and restore
+// it when the page is reloaded. After a reconnect the client reports the
+// current position again, including scrolls made while disconnected.
+package virtual
@@ -0,0 +1,274 @@
+package virtual
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/vango-dev/vango/v2/pkg/features/hooks"
+	"github.com/vango-dev/vango/v2/pkg/server"
+	"github.com/vango-dev/vango/v2/pkg/vango"
+	"github.com/vango-dev/vango/v2/pkg/vdom"
+)
+
+// Defaults of a VirtualList.
+const (
+	DefaultHeight             = 400 // Viewport height in pixels
+	DefaultEstimatedRowHeight = 40  // Height of rows not measured yet
+	DefaultOverscan           = 5   // Rows rendered beyond each edge
+)
+
+// hookConfig configures the VirtualList client hook.
+type hookConfig struct {
+	ID      string `json:"id,omitempty"` // Key of the saved scroll position
+	Measure bool   `json:"measure"`      // Report the heights of the rows
+}
+
+// Measurement is sent by the client hook with the heights of rows, in
+// pixels, by row key.
+type Measurement struct {
+	Heights map[string]int `json:"heights"`
+}
+
+// ListHook is the definition of the client hook of a virtual list. It
+// restores the scroll position, reports it again after a reconnect and
+// measures rows of lists without a fixed row height.
+var ListHook = hooks.Define[hookConfig]("VirtualList")
+
+// ListMeasure is the measure event of the VirtualList hook.
+var ListMeasure = hooks.DefineEvent[Measurement](ListHook, "measure")
+
+// view is the rendered window of a list: the rows from start to end and
+// the heights of the spacers standing in for the rows around them.
+type view struct {
+	start, end  int
+	top, bottom int
+}
+
+// VirtualList renders a window of a long list: only the rows in view,
+// plus the overscan, are mounted. The window follows the scroll position
+// of the viewport, reported by scroll events.
+type VirtualList[T any] struct {
+	key func(item T) string
+	row func(item T, index int) *vdom.VNode
+
+	height    int
+	rowHeight int
+	estimate  int
+	overscan  int
+	id        string
+
+	// offset is the scroll position of the viewport, set by scroll events
+	// and read by renders without tracking. Renders track version, bumped
+	// when the window or the row heights change.
+	offset  *vango.Signal[int]
+	version *vango.Signal[int]
+
+	// Layout and window of the last render
+	current view
+	count   int
+	offsets []int          // Offset of each row and the total, measured lists only
+	heights map[string]int // Measured heights by key
+}
+
+// New creates a virtual list of items identified by key and rendered by
+// row. Rows must be a single element each. Create it once per component,
+// like a signal, and render it with Render.
+func New[T any](key func(item T) string, row func(item T, index int) *vdom.VNode) *VirtualList[T] {
+	return &VirtualList[T]{
+		key:      key,
+		row:      row,
+		height:   DefaultHeight,
+		estimate: DefaultEstimatedRowHeight,
+		overscan: DefaultOverscan,
+		offset:   vango.NewSignal(0, vango.Transient()),
+		version:  vango.NewSignal(0, vango.Transient()),
+		heights:  make(map[string]int),
+	}
+}
+
+// Height sets the height of the viewport in pixels.
+func (l *VirtualList[T]) Height(px int) *VirtualList[T] {
+	l.height = px
+	return l
+}
+
+// RowHeight sets a fixed height of the rows in pixels. Without it, rows
+// are measured on the client and rows not measured yet are assumed to be
+// Estimate pixels high.
+func (l *VirtualList[T]) RowHeight(px int) *VirtualList[T] {
+	l.rowHeight = px
+	return l
+}
+
+// Estimate sets the height assumed for rows not measured yet.
+func (l *VirtualList[T]) Estimate(px int) *VirtualList[T] {
+	l.estimate = px
+	return l
+}
+
+// Overscan sets the number of rows rendered beyond each edge of the
+// viewport, so short scrolls don't show blank space before the next
+// window arrives.
+func (l *VirtualList[T]) Overscan(rows int) *VirtualList[T] {
+	l.overscan = rows
+	return l
+}
+
+// Persist keeps the scroll position of the list under id: the client
+// restores it on reload, and the offset is kept in a signal persisted with
+// the key "virtual.<id>".
+func (l *VirtualList[T]) Persist(id string) *VirtualList[T] {
+	l.id = id
+	l.offset = vango.NewSignal(l.offset.Peek(), vango.PersistKey("virtual."+id))
+	return l
+}
+
+// Range returns the indices of the first row rendered and one past the
+// last.
+func (l *VirtualList[T]) Range() (start, end int) {
+	return l.current.start, l.current.end
+}
+
+// ScrollTop returns the last scroll position of the viewport in pixels.
+func (l *VirtualList[T]) ScrollTop() int {
+	return l.offset.Peek()
+}
+
+// Render renders the window of items at the current scroll position.
+// attrs are added to the viewport element, e.g. a class.
+//
+// The viewport holds a spacer as high as the rows above the window, the
+// rows of the window and a spacer as high as the rows below it, so it
+// scrolls like the full list.
+func (l *VirtualList[T]) Render(items []T, attrs ...any) *vdom.VNode {
+	l.version.Get() // Re-render when the window moves
+	l.layout(items)
+	v := l.visible(l.offset.Peek())
+	l.current = v
+
+	rows := make([]*vdom.VNode, 0, v.end-v.start)
+	for i := v.start; i < v.end; i++ {
+		node := l.row(items[i], i)
+		if node == nil {
+			continue
+		}
+		key := l.key(items[i])
+		node.Key = key
+		if node.Props == nil {
+			node.Props = make(vdom.Props)
+		}
+		node.Props["data-key"] = key
+		rows = append(rows, node)
+	}
+
+	measure := l.rowHeight <= 0
+	var onMeasure any
+	if measure {
+		onMeasure = ListMeasure.Handle(l.onMeasure)
+	}
+
+	args := append([]any{}, attrs...)
+	args = append(args,
+		vdom.StyleAttr(fmt.Sprintf("height:%dpx;overflow-y:auto", l.height)),
+		vdom.OnScroll(l.onScroll),
+		spacer(v.top),
+		vdom.Div(
+			ListHook.Attr(hookConfig{ID: l.id, Measure: measure}),
+			onMeasure,
+			rows,
+		),
+		spacer(v.bottom),
+	)
+	return vdom.Div(args...)
+}
+
+// spacer renders an empty block of height px.
+func spacer(px int) *vdom.VNode {
+	return vdom.Div(vdom.StyleAttr(fmt.Sprintf("height:%dpx", px)))
+}
+
+// onScroll moves the window to the scroll position. Scrolls that keep the
+// rows in view within the rendered window, overscan included, don't
+// render.
+func (l *VirtualList[T]) onScroll(e server.ScrollEvent) {
+	l.offset.Set(e.ScrollTop)
+	first := l.indexAt(e.ScrollTop)
+	last := min(l.indexAt(e.ScrollTop+l.height-1)+1, l.count)
+	if first < l.current.start || last > l.current.end {
+		l.invalidate()
+	}
+}
+
+// onMeasure records the measured heights of rows.
+func (l *VirtualList[T]) onMeasure(m Measurement, _ hooks.HookEvent) {
+	changed := false
+	for key, h := range m.Heights {
+		if h > 0 && l.heights[key] != h {
+			l.heights[key] = h
+			changed = true
+		}
+	}
+	if changed {
+		l.invalidate() // Offsets are recomputed on the next render
+	}
+}
+
+// invalidate re-renders the list.
+func (l *VirtualList[T]) invalidate() {
+	l.version.Update(func(n int) int { return n + 1 })
+}
+
+// layout records the row offsets of items.
+func (l *VirtualList[T]) layout(items []T) {
+	l.count = len(items)
+	if l.rowHeight > 0 {
+		l.offsets = nil
+		return
+	}
+
+	if cap(l.offsets) < len(items)+1 {
+		l.offsets = make([]int, len(items)+1)
+	}
+	l.offsets = l.offsets[:len(items)+1]
+	for i, item := range items {
+		h, ok := l.heights[l.key(item)]
+		if !ok {
+			h = l.estimate
+		}
+		l.offsets[i+1] = l.offsets[i] + h
+	}
+}
+
+// offsetOf returns the offset of row i from the top of the list.
+func (l *VirtualList[T]) offsetOf(i int) int {
+	if l.rowHeight > 0 {
+		return i * l.rowHeight
+	}
+	return l.offsets[i]
+}
+
+// indexAt returns the row at offset y, or count past the end.
+func (l *VirtualList[T]) indexAt(y int) int {
+	if y < 0 {
+		return 0
+	}
+	if l.rowHeight > 0 {
+		return min(y/l.rowHeight, l.count)
+	}
+	return sort.Search(l.count, func(i int) bool { return l.offsets[i+1] > y })
+}
+
+// visible returns the window of rows in view at scroll position top.
+func (l *VirtualList[T]) visible(top int) view {
+	start := max(l.indexAt(top)-l.overscan, 0)
+	end := min(l.indexAt(top+l.height-1)+1+l.overscan, l.count)
+	if start > end {
+		start = end
+	}
+	return view{
+		start:  start,
+		end:    end,
+		top:    l.offsetOf(start),
+		bottom: l.offsetOf(l.count) - l.offsetOf(end),
+	}
+}
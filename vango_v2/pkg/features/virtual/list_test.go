@@ -0,0 +1,171 @@
+package virtual
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/vango-dev/vango/v2/pkg/features/hooks"
+	"github.com/vango-dev/vango/v2/pkg/server"
+	"github.com/vango-dev/vango/v2/pkg/vdom"
+)
+
+func testItems(n int) []int {
+	items := make([]int, n)
+	for i := range items {
+		items[i] = i
+	}
+	return items
+}
+
+func testList() *VirtualList[int] {
+	return New(strconv.Itoa, func(item, _ int) *vdom.VNode {
+		return vdom.Div(vdom.Text(strconv.Itoa(item)))
+	})
+}
+
+// parts returns the spacer heights and row keys of a rendered list.
+func parts(t *testing.T, node *vdom.VNode) (top string, keys []string, bottom string) {
+	t.Helper()
+	if len(node.Children) != 3 {
+		t.Fatalf("viewport has %d children, want 3", len(node.Children))
+	}
+	for _, row := range node.Children[1].Children {
+		keys = append(keys, row.Key)
+	}
+	return node.Children[0].Props["style"].(string), keys, node.Children[2].Props["style"].(string)
+}
+
+func TestRenderWindow(t *testing.T) {
+	l := testList().Height(100).RowHeight(20).Overscan(2)
+	items := testItems(10000)
+
+	top, keys, bottom := parts(t, l.Render(items))
+	if len(keys) != 7 || keys[0] != "0" || keys[6] != "6" {
+		t.Errorf("rows = %v, want 0-6", keys)
+	}
+	if top != "height:0px" || bottom != "height:199860px" {
+		t.Errorf("spacers = %q, %q", top, bottom)
+	}
+
+	l.onScroll(server.ScrollEvent{ScrollTop: 5000})
+	if start, end := l.Range(); start != 0 || end != 7 {
+		t.Errorf("Range() before render = %d, %d", start, end)
+	}
+	top, keys, bottom = parts(t, l.Render(items))
+	if len(keys) != 9 || keys[0] != "248" || keys[8] != "256" {
+		t.Errorf("rows = %v, want 248-256", keys)
+	}
+	if top != "height:4960px" || bottom != "height:194860px" {
+		t.Errorf("spacers = %q, %q", top, bottom)
+	}
+	if start, end := l.Range(); start != 248 || end != 257 {
+		t.Errorf("Range() = %d, %d, want 248, 257", start, end)
+	}
+
+	// Past the end, e.g. after the items shrank
+	l.onScroll(server.ScrollEvent{ScrollTop: 1 << 20})
+	_, keys, bottom = parts(t, l.Render(items))
+	if len(keys) != 2 || keys[1] != "9999" || bottom != "height:0px" {
+		t.Errorf("rows past the end = %v, bottom %q", keys, bottom)
+	}
+	_, keys, _ = parts(t, l.Render(nil))
+	if len(keys) != 0 {
+		t.Errorf("rows of an empty list = %v", keys)
+	}
+}
+
+func TestScrollWithinWindow(t *testing.T) {
+	l := testList().Height(100).RowHeight(20).Overscan(2)
+	l.Render(testItems(100))
+
+	version := l.version.Peek()
+	l.onScroll(server.ScrollEvent{ScrollTop: 0})
+	if l.version.Peek() != version {
+		t.Error("a scroll that keeps the window should not render")
+	}
+	l.onScroll(server.ScrollEvent{ScrollTop: 15})
+	if l.version.Peek() != version || l.ScrollTop() != 15 {
+		t.Error("a scroll within the overscan should not render")
+	}
+	l.onScroll(server.ScrollEvent{ScrollTop: 60})
+	if l.version.Peek() != version+1 {
+		t.Error("a scroll that moves the window should render")
+	}
+}
+
+func TestScrollDiff(t *testing.T) {
+	l := testList().Height(100).RowHeight(20).Overscan(2)
+	items := testItems(1000)
+	gen := vdom.NewHIDGenerator()
+
+	l.onScroll(server.ScrollEvent{ScrollTop: 200})
+	prev := l.Render(items)
+	vdom.AssignHIDs(prev, gen)
+
+	// One row down: row 8 leaves, row 17 enters, the rest stay
+	l.onScroll(server.ScrollEvent{ScrollTop: 220})
+	next := l.Render(items)
+	vdom.CopyHIDs(prev, next)
+	vdom.AssignHIDs(next, gen)
+
+	var ops []vdom.PatchOp
+	for _, p := range vdom.Diff(prev, next) {
+		if p.Op == vdom.PatchSetAttr {
+			continue // Spacer heights
+		}
+		ops = append(ops, p.Op)
+	}
+	if len(ops) != 2 || ops[0] != vdom.PatchRemoveNode || ops[1] != vdom.PatchInsertNode {
+		t.Errorf("row patches = %v, want one remove and one insert", ops)
+	}
+	if next.Children[1].Children[0].HID != prev.Children[1].Children[1].HID {
+		t.Error("rows that stay should keep their HIDs")
+	}
+}
+
+func TestMeasuredRows(t *testing.T) {
+	l := testList().Height(100).Estimate(50).Overscan(0)
+	items := testItems(100)
+
+	top, keys, bottom := parts(t, l.Render(items))
+	if len(keys) != 2 || top != "height:0px" || bottom != "height:4900px" {
+		t.Errorf("estimated: rows %v, spacers %q, %q", keys, top, bottom)
+	}
+
+	version := l.version.Peek()
+	l.onMeasure(Measurement{Heights: map[string]int{"0": 10, "1": 10, "2": 10}}, hooks.HookEvent{})
+	l.onMeasure(Measurement{Heights: map[string]int{"0": 10}}, hooks.HookEvent{})
+	if l.version.Peek() != version+1 {
+		t.Error("only new heights should render")
+	}
+
+	_, keys, bottom = parts(t, l.Render(items))
+	if len(keys) != 5 || keys[4] != "4" || bottom != "height:4750px" {
+		t.Errorf("measured: rows %v, bottom %q", keys, bottom)
+	}
+
+	l.onScroll(server.ScrollEvent{ScrollTop: 130})
+	top, keys, _ = parts(t, l.Render(items))
+	if keys[0] != "5" || top != "height:130px" {
+		t.Errorf("scrolled: rows %v, top %q", keys, top)
+	}
+}
+
+func TestRenderAttrs(t *testing.T) {
+	l := testList().RowHeight(20).Persist("inbox")
+	node := l.Render(testItems(3), vdom.Class("inbox"))
+
+	if node.Props["class"] != "inbox" || node.Props["onscroll"] == nil {
+		t.Errorf("viewport props = %v", node.Props)
+	}
+	rows := node.Children[1]
+	if rows.Props["v-hook"] != `VirtualList:{"id":"inbox","measure":false}` {
+		t.Errorf("hook = %v", rows.Props["v-hook"])
+	}
+	if rows.Children[1].Props["data-key"] != "1" {
+		t.Errorf("row props = %v", rows.Children[1].Props)
+	}
+	if l.offset.PersistKey() != "virtual.inbox" || l.offset.IsTransient() {
+		t.Error("Persist should persist the offset")
+	}
+}
@@ -0,0 +1,53 @@
+// Package head manages the document head: title, meta tags, links and
+// JSON-LD structured data.
+//
+// Components declare head entries inline, next to the content they
+// describe. Declarations render nothing in the body; the renderer collects
+// them into the head of the page, and live sessions keep the document head
+// in sync as components re-render.
+//
+// # Declaring Entries
+//
+//	func ProfilePage(user *User) *vdom.VNode {
+//	    return Div(
+//	        head.Title(user.Name + " | Acme"),
+//	        head.Meta("description", user.Bio),
+//	        head.Property("og:image", user.AvatarURL),
+//	        head.Canonical("https://acme.com/u/" + user.Handle),
+//	        head.JSONLD(map[string]any{
+//	            "@context": "https://schema.org",
+//	            "@type":    "Person",
+//	            "name":     user.Name,
+//	        }),
+//	        H1(Text(user.Name)),
+//	    )
+//	}
+//
+// # Merging
+//
+// Entries with the same key replace each other. The declaration nested
+// deepest in the tree wins, so a layout can set defaults that its pages
+// override:
+//
+//	func Layout(ctx server.Ctx, children router.Slot) *vdom.VNode {
+//	    return Div(
+//	        head.Title("Acme"),
+//	        head.Meta("description", "Acme helps you ship."),
+//	        Main(children),
+//	    )
+//	}
+//
+// Meta tags are keyed by name or property, JSON-LD documents by "@type",
+// links by rel and href, and the canonical link is unique. Route Meta
+// functions produce the same declarations through router.PageMeta.Head.
+//
+// # Live Updates
+//
+// After each render the session compares the merged head with the one the
+// client shows and sends dedicated patches: SetTitle updates
+// document.title, SetHead adds or replaces a managed element and RemoveHead
+// removes one. Managed elements carry the data-vango-head attribute.
+//
+// A title that is no longer declared is left as it was, so declare a
+// default title in the root layout.
+package head
@@ -0,0 +1,359 @@
+package head
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/vango-dev/vango/v2/pkg/protocol"
+	"github.com/vango-dev/vango/v2/pkg/vdom"
+)
+
+// KeyAttr is the attribute that marks head elements managed by Vango.
+// Its value is the entry key, which the client uses to find the element
+// to replace or remove.
+const KeyAttr = "data-vango-head"
+
+// TitleKey is the key of the document title entry.
+const TitleKey = "title"
+
+// entryProp is the internal prop holding the declared entry.
+const entryProp = "_head"
+
+// Entry is a single declaration for the document head.
+type Entry struct {
+	// Key identifies the entry when merging. Entries with the same key
+	// replace each other, e.g. "name:description" or "property:og:title".
+	Key string
+
+	// Tag is the element rendered for the entry: "title", "meta", "link"
+	// or "script" (JSON-LD).
+	Tag string
+
+	// Attrs are the element attributes.
+	Attrs map[string]string
+
+	// Text is the title text or the JSON-LD document.
+	Text string
+}
+
+// Equal reports whether e and other render the same element.
+func (e *Entry) Equal(other *Entry) bool {
+	if e == nil || other == nil {
+		return e == other
+	}
+	if e.Key != other.Key || e.Tag != other.Tag || e.Text != other.Text || len(e.Attrs) != len(other.Attrs) {
+		return false
+	}
+	for k, v := range e.Attrs {
+		if ov, ok := other.Attrs[k]; !ok || ov != v {
+			return false
+		}
+	}
+	return true
+}
+
+// AttrKeys returns the attribute names of e, sorted.
+func (e *Entry) AttrKeys() []string {
+	keys := make([]string, 0, len(e.Attrs))
+	for k := range e.Attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Node returns the head element for e, marked with KeyAttr.
+// The title entry has no element; it is applied through document.title.
+func (e *Entry) Node() *vdom.VNode {
+	props := make(vdom.Props, len(e.Attrs)+1)
+	for k, v := range e.Attrs {
+		props[k] = v
+	}
+	node := &vdom.VNode{Kind: vdom.KindElement, Tag: e.Tag, Props: props}
+	if e.Tag == "title" {
+		node.Children = []*vdom.VNode{vdom.Text(e.Text)}
+		return node
+	}
+	props[KeyAttr] = e.Key
+	if e.Text != "" {
+		node.Children = []*vdom.VNode{vdom.Text(e.Text)}
+	}
+	return node
+}
+
+// =============================================================================
+// Declarations
+// =============================================================================
+
+// declare returns the placeholder node for e. Head declarations are empty
+// fragments, so they render nothing where they appear in the body.
+func declare(e *Entry) *vdom.VNode {
+	return &vdom.VNode{
+		Kind:  vdom.KindFragment,
+		Props: vdom.Props{entryProp: e},
+	}
+}
+
+// EntryOf returns the entry declared by node, or nil if node is not a head
+// declaration.
+func EntryOf(node *vdom.VNode) *Entry {
+	if node == nil || node.Kind != vdom.KindFragment {
+		return nil
+	}
+	e, _ := node.Props[entryProp].(*Entry)
+	return e
+}
+
+// Title sets the document title.
+//
+//	head.Title("Profile | Acme")
+func Title(title string) *vdom.VNode {
+	return declare(&Entry{Key: TitleKey, Tag: "title", Text: title})
+}
+
+// Meta sets a named meta tag, such as "description" or "robots".
+//
+//	head.Meta("description", "Account settings")
+func Meta(name, content string) *vdom.VNode {
+	return declare(&Entry{
+		Key:   "name:" + name,
+		Tag:   "meta",
+		Attrs: map[string]string{"name": name, "content": content},
+	})
+}
+
+// Property sets a meta tag identified by its property attribute,
+// as used by OpenGraph.
+//
+//	head.Property("og:image", "https://example.com/card.png")
+func Property(property, content string) *vdom.VNode {
+	return declare(&Entry{
+		Key:   "property:" + property,
+		Tag:   "meta",
+		Attrs: map[string]string{"property": property, "content": content},
+	})
+}
+
+// Link adds a link tag. Links are identified by rel and href, so the same
+// link declared twice is rendered once.
+//
+//	head.Link("alternate", "/feed.xml", vdom.Attr{Key: "type", Value: "application/rss+xml"})
+func Link(rel, href string, attrs ...vdom.Attr) *vdom.VNode {
+	e := &Entry{
+		Key:   "link:" + rel + ":" + href,
+		Tag:   "link",
+		Attrs: map[string]string{"rel": rel, "href": href},
+	}
+	for _, attr := range attrs {
+		if v, ok := attr.Value.(string); ok && !attr.IsEmpty() {
+			e.Attrs[attr.Key] = v
+		}
+	}
+	return declare(e)
+}
+
+// Canonical sets the canonical URL of the page.
+// Unlike Link, a deeper declaration replaces an outer one.
+func Canonical(href string) *vdom.VNode {
+	return declare(&Entry{
+		Key:   "link:canonical",
+		Tag:   "link",
+		Attrs: map[string]string{"rel": "canonical", "href": href},
+	})
+}
+
+// JSONLD adds a JSON-LD structured data script. Documents are identified
+// by their "@type", so a page can replace a layout's WebSite or
+// Organization data. Values that cannot be encoded declare nothing.
+//
+//	head.JSONLD(map[string]any{
+//	    "@context": "https://schema.org",
+//	    "@type":    "Article",
+//	    "headline": post.Title,
+//	})
+func JSONLD(v any) *vdom.VNode {
+	// json.Marshal escapes <, > and &, so the document cannot close the
+	// script element it is rendered in.
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+
+	key := "jsonld:" + string(data)
+	var typed struct {
+		Type any `json:"@type"`
+	}
+	if json.Unmarshal(data, &typed) == nil {
+		if t, ok := typed.Type.(string); ok && t != "" {
+			key = "jsonld:" + t
+		}
+	}
+
+	return declare(&Entry{
+		Key:   key,
+		Tag:   "script",
+		Attrs: map[string]string{"type": "application/ld+json"},
+		Text:  string(data),
+	})
+}
+
+// =============================================================================
+// Head
+// =============================================================================
+
+// Head is the merged set of head entries of a document.
+//
+// Entries are merged by nesting depth: a declaration deeper in the tree
+// replaces one with the same key higher up, so a page overrides the defaults
+// of its layouts. At equal depth the later declaration wins.
+type Head struct {
+	entries  map[string]*ranked
+	seq      int
+	maxDepth int
+}
+
+// ranked is an entry with its merge position.
+type ranked struct {
+	entry *Entry
+	depth int
+	order int
+}
+
+// New creates an empty Head.
+func New() *Head {
+	return &Head{entries: make(map[string]*ranked)}
+}
+
+// Add adds e declared at depth.
+func (h *Head) Add(depth int, e *Entry) {
+	if e == nil {
+		return
+	}
+	if depth > h.maxDepth {
+		h.maxDepth = depth
+	}
+
+	if cur, ok := h.entries[e.Key]; ok {
+		if cur.depth > depth {
+			return
+		}
+		cur.entry = e
+		cur.depth = depth
+		return
+	}
+	h.seq++
+	h.entries[e.Key] = &ranked{entry: e, depth: depth, order: h.seq}
+}
+
+// Collect adds the declarations in the tree rooted at node, which sits at
+// depth. Component nodes are not rendered; callers that render components
+// collect their output separately.
+func (h *Head) Collect(node *vdom.VNode, depth int) {
+	if node == nil {
+		return
+	}
+	if e := EntryOf(node); e != nil {
+		h.Add(depth, e)
+		return
+	}
+	for _, child := range node.Children {
+		h.Collect(child, depth+1)
+	}
+}
+
+// Merge adds the entries of other as if other were nested inside h, so every
+// entry of other replaces an entry of h with the same key.
+func (h *Head) Merge(other *Head) {
+	if other == nil {
+		return
+	}
+	offset := h.maxDepth + 1
+	for _, r := range other.sorted() {
+		h.Add(r.depth+offset, r.entry)
+	}
+}
+
+// Title returns the merged document title, or "" if none was declared.
+func (h *Head) Title() string {
+	if h == nil {
+		return ""
+	}
+	if r, ok := h.entries[TitleKey]; ok {
+		return r.entry.Text
+	}
+	return ""
+}
+
+// Has reports whether an entry with key was declared.
+func (h *Head) Has(key string) bool {
+	if h == nil {
+		return false
+	}
+	_, ok := h.entries[key]
+	return ok
+}
+
+// Get returns the entry with key, or nil.
+func (h *Head) Get(key string) *Entry {
+	if h == nil {
+		return nil
+	}
+	if r, ok := h.entries[key]; ok {
+		return r.entry
+	}
+	return nil
+}
+
+// Entries returns the merged entries other than the title, in the order
+// they were first declared.
+func (h *Head) Entries() []*Entry {
+	if h == nil {
+		return nil
+	}
+	var entries []*Entry
+	for _, r := range h.sorted() {
+		if r.entry.Key != TitleKey {
+			entries = append(entries, r.entry)
+		}
+	}
+	return entries
+}
+
+// sorted returns the ranked entries in declaration order.
+func (h *Head) sorted() []*ranked {
+	list := make([]*ranked, 0, len(h.entries))
+	for _, r := range h.entries {
+		list = append(list, r)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].order < list[j].order
+	})
+	return list
+}
+
+// Diff returns the patches that update a document showing prev to next.
+//
+// A title that is no longer declared is left unchanged, so the document
+// keeps its last title rather than becoming untitled.
+func Diff(prev, next *Head) []protocol.Patch {
+	var patches []protocol.Patch
+
+	if title := next.Title(); title != "" && title != prev.Title() {
+		patches = append(patches, protocol.NewSetTitlePatch(title))
+	}
+
+	for _, e := range next.Entries() {
+		if old := prev.Get(e.Key); old != nil && old.Equal(e) {
+			continue
+		}
+		patches = append(patches, protocol.NewSetHeadPatch(e.Key, protocol.VNodeToWire(e.Node())))
+	}
+
+	for _, e := range prev.Entries() {
+		if !next.Has(e.Key) {
+			patches = append(patches, protocol.NewRemoveHeadPatch(e.Key))
+		}
+	}
+
+	return patches
+}
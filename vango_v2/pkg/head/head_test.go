@@ -0,0 +1,100 @@
+package head
+
+import (
+	"testing"
+
+	"github.com/vango-dev/vango/v2/pkg/protocol"
+	"github.com/vango-dev/vango/v2/pkg/vdom"
+)
+
+func TestCollectMergesByDepth(t *testing.T) {
+	tree := vdom.Div(
+		Title("Acme"),
+		Meta("description", "Layout"),
+		Canonical("/"),
+		vdom.Main(
+			Title("Profile"),
+			Canonical("/profile"),
+		),
+		Meta("description", "Later at the same depth"),
+	)
+
+	h := New()
+	h.Collect(tree, 0)
+
+	if got := h.Title(); got != "Profile" {
+		t.Errorf("Title() = %q, want the deeper title", got)
+	}
+	if got := h.Get("link:canonical").Attrs["href"]; got != "/profile" {
+		t.Errorf("canonical = %q, want %q", got, "/profile")
+	}
+	if got := h.Get("name:description").Attrs["content"]; got != "Later at the same depth" {
+		t.Errorf("description = %q", got)
+	}
+
+	entries := h.Entries()
+	if len(entries) != 2 || entries[0].Key != "name:description" || entries[1].Key != "link:canonical" {
+		t.Errorf("Entries() = %+v, want declaration order without the title", entries)
+	}
+}
+
+func TestMergeOverridesDefaults(t *testing.T) {
+	defaults := New()
+	defaults.Collect(vdom.Fragment(vdom.Fragment(vdom.Fragment(Title("Default")))), 0)
+
+	page := New()
+	page.Add(0, EntryOf(Title("Page")))
+
+	defaults.Merge(page)
+	if got := defaults.Title(); got != "Page" {
+		t.Errorf("Title() = %q, want merged head to win", got)
+	}
+}
+
+func TestJSONLDKey(t *testing.T) {
+	a := EntryOf(JSONLD(map[string]any{"@type": "Organization", "name": "Acme"}))
+	b := EntryOf(JSONLD(map[string]any{"@type": "Organization", "name": "Acme Inc"}))
+	if a.Key != b.Key || a.Key != "jsonld:Organization" {
+		t.Errorf("keys = %q, %q, want both keyed by @type", a.Key, b.Key)
+	}
+	if JSONLD(func() {}) != nil {
+		t.Error("unencodable value should declare nothing")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	prev := New()
+	prev.Collect(vdom.Div(
+		Title("Home"),
+		Meta("description", "Welcome"),
+		Property("og:image", "/home.png"),
+		Meta("robots", "index"),
+	), 0)
+
+	next := New()
+	next.Collect(vdom.Div(
+		Title("About"),
+		Meta("description", "About us"),
+		Meta("robots", "index"),
+	), 0)
+
+	patches := Diff(prev, next)
+	if len(patches) != 3 {
+		t.Fatalf("Diff() returned %d patches, want 3: %+v", len(patches), patches)
+	}
+	if patches[0].Op != protocol.PatchSetTitle || patches[0].Value != "About" {
+		t.Errorf("patch 0 = %+v, want SetTitle", patches[0])
+	}
+	if patches[1].Op != protocol.PatchSetHead || patches[1].Key != "name:description" ||
+		patches[1].Node.Attrs["content"] != "About us" || patches[1].Node.Attrs[KeyAttr] != "name:description" {
+		t.Errorf("patch 1 = %+v, want SetHead for the description", patches[1])
+	}
+	if patches[2].Op != protocol.PatchRemoveHead || patches[2].Key != "property:og:image" {
+		t.Errorf("patch 2 = %+v, want RemoveHead for og:image", patches[2])
+	}
+
+	// An undeclared title keeps the document's current one
+	if patches := Diff(next, New()); len(patches) != 2 {
+		t.Errorf("Diff() to empty head = %+v, want only removals", patches)
+	}
+}
@@ -0,0 +1,252 @@
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultCookieName is the cookie that stores a user's chosen locale.
+const DefaultCookieName = "vango_locale"
+
+// Bundle holds the message catalogs of an application.
+// A Bundle is safe for concurrent use and is typically created once at startup.
+type Bundle struct {
+	mu            sync.RWMutex
+	defaultLocale string
+	cookieName    string
+	catalogs      map[string]map[string]*Message
+}
+
+// Option configures a Bundle.
+type Option func(*Bundle)
+
+// WithCookie sets the cookie name used for locale negotiation.
+// Default: "vango_locale".
+func WithCookie(name string) Option {
+	return func(b *Bundle) {
+		b.cookieName = name
+	}
+}
+
+// NewBundle creates a Bundle whose fallback locale is defaultLocale.
+//
+// Example:
+//
+//	bundle := i18n.NewBundle("en")
+//	if err := bundle.LoadFS(locales, "locales"); err != nil {
+//	    log.Fatal(err)
+//	}
+func NewBundle(defaultLocale string, opts ...Option) *Bundle {
+	b := &Bundle{
+		defaultLocale: CanonicalLocale(defaultLocale),
+		cookieName:    DefaultCookieName,
+		catalogs:      make(map[string]map[string]*Message),
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	if b.defaultLocale == "" {
+		b.defaultLocale = "en"
+	}
+	return b
+}
+
+// DefaultLocale returns the fallback locale.
+func (b *Bundle) DefaultLocale() string {
+	return b.defaultLocale
+}
+
+// CookieName returns the cookie used for locale negotiation.
+func (b *Bundle) CookieName() string {
+	return b.cookieName
+}
+
+// AddMessages adds messages for locale. Each message is parsed as an ICU
+// MessageFormat pattern; the first invalid pattern aborts the call.
+// Empty messages are treated as untranslated and skipped.
+func (b *Bundle) AddMessages(locale string, messages map[string]string) error {
+	locale = CanonicalLocale(locale)
+	if locale == "" {
+		return fmt.Errorf("i18n: invalid locale")
+	}
+
+	parsed := make(map[string]*Message, len(messages))
+	for key, pattern := range messages {
+		if pattern == "" {
+			continue
+		}
+		msg, err := ParseMessage(pattern)
+		if err != nil {
+			return fmt.Errorf("i18n: %s: key %q: %w", locale, key, err)
+		}
+		parsed[key] = msg
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	catalog, ok := b.catalogs[locale]
+	if !ok {
+		catalog = make(map[string]*Message, len(parsed))
+		b.catalogs[locale] = catalog
+	}
+	for key, msg := range parsed {
+		catalog[key] = msg
+	}
+	return nil
+}
+
+// LoadJSON adds messages for locale from a JSON catalog.
+// See ParseCatalog for the format.
+func (b *Bundle) LoadJSON(locale string, data []byte) error {
+	messages, err := ParseCatalog(data)
+	if err != nil {
+		return fmt.Errorf("i18n: %s: %w", locale, err)
+	}
+	return b.AddMessages(locale, messages)
+}
+
+// ParseCatalog decodes a JSON catalog into flat message keys.
+// Nested objects are flattened with dots, so {"cart": {"title": "Cart"}}
+// defines the key "cart.title".
+func ParseCatalog(data []byte) (map[string]string, error) {
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	messages := make(map[string]string)
+	if err := flatten("", raw, messages); err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// LoadFS loads every <locale>.json file in dir of fsys.
+// It works with embed.FS and os.DirFS.
+//
+// Example:
+//
+//	//go:embed locales/*.json
+//	var locales embed.FS
+//
+//	bundle.LoadFS(locales, "locales")
+func (b *Bundle) LoadFS(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return fmt.Errorf("i18n: %w", err)
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || path.Ext(name) != ".json" {
+			continue
+		}
+		data, err := fs.ReadFile(fsys, path.Join(dir, name))
+		if err != nil {
+			return fmt.Errorf("i18n: %w", err)
+		}
+		if err := b.LoadJSON(strings.TrimSuffix(name, ".json"), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flatten converts nested JSON objects into dotted keys.
+func flatten(prefix string, raw map[string]any, out map[string]string) error {
+	for key, value := range raw {
+		full := key
+		if prefix != "" {
+			full = prefix + "." + key
+		}
+		switch v := value.(type) {
+		case string:
+			out[full] = v
+		case map[string]any:
+			if err := flatten(full, v, out); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("key %q: expected string or object, got %T", full, value)
+		}
+	}
+	return nil
+}
+
+// Locales returns the locales that have catalogs, sorted.
+func (b *Bundle) Locales() []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	locales := make([]string, 0, len(b.catalogs))
+	for locale := range b.catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// HasLocale reports whether locale has a catalog.
+func (b *Bundle) HasLocale(locale string) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	_, ok := b.catalogs[CanonicalLocale(locale)]
+	return ok
+}
+
+// Keys returns the message keys defined for locale, sorted.
+func (b *Bundle) Keys(locale string) []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	catalog := b.catalogs[CanonicalLocale(locale)]
+	keys := make([]string, 0, len(catalog))
+	for key := range catalog {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Lookup finds the message for key, falling back from the regional locale
+// to its base language and then to the default locale.
+func (b *Bundle) Lookup(locale, key string) (*Message, string, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, candidate := range b.fallbacks(CanonicalLocale(locale)) {
+		if msg, ok := b.catalogs[candidate][key]; ok {
+			return msg, candidate, true
+		}
+	}
+	return nil, "", false
+}
+
+// fallbacks returns the lookup chain for locale, e.g. fr-CA → fr → en.
+func (b *Bundle) fallbacks(locale string) []string {
+	chain := make([]string, 0, 3)
+	for locale != "" {
+		chain = append(chain, locale)
+		i := strings.LastIndexByte(locale, '-')
+		if i < 0 {
+			break
+		}
+		locale = locale[:i]
+	}
+	if len(chain) == 0 || chain[len(chain)-1] != b.defaultLocale {
+		chain = append(chain, b.defaultLocale)
+	}
+	return chain
+}
+
+// Translate formats the message for key in locale.
+// If no catalog defines key, the key itself is returned.
+func (b *Bundle) Translate(locale, key string, args map[string]any) string {
+	msg, found, ok := b.Lookup(locale, key)
+	if !ok {
+		return key
+	}
+	return msg.Format(found, args)
+}
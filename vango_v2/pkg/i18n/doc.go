@@ -0,0 +1,79 @@
+// Package i18n provides internationalization for Vango applications.
+//
+// Messages live in per-locale JSON catalogs and use ICU MessageFormat
+// syntax for placeholders, plurals and selects. Each session gets its own
+// Localizer whose locale is a reactive signal, so switching languages
+// re-renders the live UI without a page reload.
+//
+// # Catalogs
+//
+// A catalog is a JSON file named after its locale. Nested objects are
+// flattened into dotted keys:
+//
+//	// locales/en.json
+//	{
+//	    "nav": {"home": "Home"},
+//	    "greeting": "Hello, {name}!",
+//	    "cart.items": "{count, plural, =0 {Your cart is empty} one {# item} other {# items}}",
+//	    "reply": "{gender, select, female {She} male {He} other {They}} replied."
+//	}
+//
+// Load catalogs from disk or from an embedded file system:
+//
+//	//go:embed locales/*.json
+//	var locales embed.FS
+//
+//	bundle := i18n.NewBundle("en")
+//	if err := bundle.LoadFS(locales, "locales"); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// Patterns are parsed at load time, so syntax errors surface at startup.
+// Lookups fall back from a regional locale to its base language and then to
+// the default locale ("fr-CA" → "fr" → "en"). A key missing everywhere is
+// rendered as the key itself.
+//
+// # Locale Negotiation
+//
+// StartSession picks the session's locale from the locale cookie, then the
+// Accept-Language header, and stores a Localizer on the session:
+//
+//	config := server.DefaultServerConfig()
+//	config.OnSessionStart = func(httpCtx context.Context, session *server.Session) {
+//	    bundle.StartSession(httpCtx, session)
+//	}
+//
+// For server-side rendering, Bundle.Middleware negotiates the locale of each
+// HTTP request. Use the Localizer to set the document language:
+//
+//	l := i18n.FromContext(r.Context())
+//	renderer.RenderPage(w, render.PageData{
+//	    Body: body,
+//	    Lang: l.Locale(),
+//	    Dir:  l.Dir(),
+//	})
+//
+// # Translating
+//
+//	func Header(ctx server.Ctx) *vdom.VNode {
+//	    return Nav(
+//	        A(Href("/"), Text(i18n.T(ctx, "nav.home"))),
+//	        Span(Text(i18n.T(ctx, "cart.items", "count", cart.Len()))),
+//	    )
+//	}
+//
+// # Switching Languages
+//
+// Setting the locale updates the session's signal and re-renders every
+// component that translated text:
+//
+//	func SwitchLanguage(ctx server.Ctx, locale string) {
+//	    applied := i18n.Get(ctx).SetLocale(locale)
+//	    ctx.SetCookie(bundle.Cookie(applied))
+//	}
+//
+// # Extracting Keys
+//
+// `vango gen i18n` scans the project for T calls with literal keys and adds
+// missing keys to the catalogs in app/locales.
+package i18n
@@ -0,0 +1,123 @@
+package i18n
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ExtractedKey is a message key found in source code.
+type ExtractedKey struct {
+	// Key is the message key.
+	Key string
+
+	// Positions lists every "file:line" where the key is used.
+	Positions []string
+}
+
+// ExtractKeys scans the Go files under root for translation calls with a
+// string literal key and returns the keys sorted by name.
+//
+// Recognized calls are any method or function named T whose key is the
+// first argument, or the second argument after a ctx:
+//
+//	l.T("nav.home")
+//	i18n.T(ctx, "inbox.unread", "count", n)
+//
+// Test files, vendor, node_modules and hidden directories are skipped.
+func ExtractKeys(root string) ([]ExtractedKey, error) {
+	found := make(map[string]*ExtractedKey)
+	fset := token.NewFileSet()
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := d.Name()
+		if d.IsDir() {
+			if path != root && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") ||
+				name == "vendor" || name == "node_modules" || name == "testdata") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			return nil
+		}
+
+		file, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return err
+		}
+
+		rel, relErr := filepath.Rel(root, path)
+		if relErr != nil {
+			rel = path
+		}
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			key, pos, ok := translationKey(call)
+			if !ok {
+				return true
+			}
+			k, exists := found[key]
+			if !exists {
+				k = &ExtractedKey{Key: key}
+				found[key] = k
+			}
+			line := fset.Position(pos).Line
+			k.Positions = append(k.Positions, filepath.ToSlash(rel)+":"+strconv.Itoa(line))
+			return true
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]ExtractedKey, 0, len(found))
+	for _, k := range found {
+		keys = append(keys, *k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].Key < keys[j].Key
+	})
+	return keys, nil
+}
+
+// translationKey returns the literal key of a T call.
+func translationKey(call *ast.CallExpr) (string, token.Pos, bool) {
+	var name string
+	switch fn := call.Fun.(type) {
+	case *ast.SelectorExpr:
+		name = fn.Sel.Name
+	case *ast.Ident:
+		name = fn.Name
+	}
+	if name != "T" || len(call.Args) == 0 {
+		return "", 0, false
+	}
+
+	// The key is the first string literal among the first two arguments
+	for i := 0; i < len(call.Args) && i < 2; i++ {
+		lit, ok := call.Args[i].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			continue
+		}
+		key, err := strconv.Unquote(lit.Value)
+		if err != nil || key == "" {
+			return "", 0, false
+		}
+		return key, lit.Pos(), true
+	}
+	return "", 0, false
+}
@@ -0,0 +1,257 @@
+package i18n
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/vango-dev/vango/v2/pkg/server"
+	"github.com/vango-dev/vango/v2/pkg/vango"
+)
+
+func testBundle(t *testing.T) *Bundle {
+	t.Helper()
+	fsys := fstest.MapFS{
+		"locales/en.json": {Data: []byte(`{
+			"nav": {"home": "Home"},
+			"greeting": "Hello, {name}!",
+			"cart.items": "{count, plural, =0 {Empty} one {# item} other {# items}}"
+		}`)},
+		"locales/fr.json": {Data: []byte(`{
+			"nav": {"home": "Accueil"},
+			"greeting": "Bonjour, {name} !",
+			"cart.items": ""
+		}`)},
+		"locales/fr-CA.json": {Data: []byte(`{"nav": {"home": "Page d'accueil"}}`)},
+		"locales/README.md":  {Data: []byte("not a catalog")},
+	}
+
+	b := NewBundle("en")
+	if err := b.LoadFS(fsys, "locales"); err != nil {
+		t.Fatalf("LoadFS error: %v", err)
+	}
+	return b
+}
+
+func TestBundleTranslate(t *testing.T) {
+	b := testBundle(t)
+
+	if got := b.Locales(); !reflect.DeepEqual(got, []string{"en", "fr", "fr-CA"}) {
+		t.Errorf("Locales() = %v", got)
+	}
+
+	tests := []struct {
+		locale, key string
+		args        map[string]any
+		want        string
+	}{
+		{"en", "nav.home", nil, "Home"},
+		{"fr", "nav.home", nil, "Accueil"},
+		{"fr-CA", "nav.home", nil, "Page d'accueil"},
+		{"fr-CA", "greeting", map[string]any{"name": "Zoé"}, "Bonjour, Zoé !"},
+		{"fr", "cart.items", map[string]any{"count": 2}, "2 items"},
+		{"de", "nav.home", nil, "Home"},
+		{"en", "missing.key", nil, "missing.key"},
+	}
+	for _, tt := range tests {
+		if got := b.Translate(tt.locale, tt.key, tt.args); got != tt.want {
+			t.Errorf("Translate(%q, %q) = %q, want %q", tt.locale, tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestBundleLoadErrors(t *testing.T) {
+	b := NewBundle("en")
+	if err := b.LoadJSON("en", []byte(`{"bad": "{n, plural, one {x}}"}`)); err == nil {
+		t.Error("invalid pattern should fail to load")
+	}
+	if err := b.LoadJSON("en", []byte(`{"bad": 1}`)); err == nil {
+		t.Error("non-string value should fail to load")
+	}
+	if err := b.LoadJSON("en", []byte(`not json`)); err == nil {
+		t.Error("invalid JSON should fail to load")
+	}
+}
+
+func TestCanonicalLocale(t *testing.T) {
+	tests := map[string]string{
+		"en":         "en",
+		"EN_us":      "en-US",
+		"zh-hant-tw": "zh-Hant-TW",
+		"es-419":     "es-419",
+		"*":          "",
+		"en--US":     "",
+		"en;q=1":     "",
+	}
+	for in, want := range tests {
+		if got := CanonicalLocale(in); got != want {
+			t.Errorf("CanonicalLocale(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	got := ParseAcceptLanguage("en;q=0.8, fr-CH, fr;q=0.9, *;q=0.5, de;q=0")
+	want := []string{"fr-CH", "fr", "en"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseAcceptLanguage() = %v, want %v", got, want)
+	}
+}
+
+func TestBundleMatch(t *testing.T) {
+	b := testBundle(t)
+
+	tests := []struct {
+		prefs []string
+		want  string
+	}{
+		{[]string{"fr-CA"}, "fr-CA"},
+		{[]string{"fr-CH"}, "fr"},
+		{[]string{"de", "fr"}, "fr"},
+		{[]string{"de"}, "en"},
+		{nil, "en"},
+	}
+	for _, tt := range tests {
+		if got := b.Match(tt.prefs...); got != tt.want {
+			t.Errorf("Match(%v) = %q, want %q", tt.prefs, got, tt.want)
+		}
+	}
+}
+
+func TestBundleNegotiate(t *testing.T) {
+	b := testBundle(t)
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept-Language", "fr-FR,fr;q=0.9,en;q=0.8")
+	if got := b.Negotiate(r); got != "fr" {
+		t.Errorf("Negotiate(header) = %q, want %q", got, "fr")
+	}
+
+	r.AddCookie(&http.Cookie{Name: DefaultCookieName, Value: "en"})
+	if got := b.Negotiate(r); got != "en" {
+		t.Errorf("Negotiate(cookie) = %q, want cookie to win", got)
+	}
+
+	if got := b.Negotiate(nil); got != "en" {
+		t.Errorf("Negotiate(nil) = %q, want default", got)
+	}
+}
+
+func TestLocalizerReactive(t *testing.T) {
+	b := testBundle(t)
+	l := b.NewLocalizer("en")
+
+	owner := vango.NewOwner(nil)
+	defer owner.Dispose()
+
+	var rendered []string
+	vango.WithOwner(owner, func() {
+		vango.CreateEffect(func() vango.Cleanup {
+			rendered = append(rendered, l.T("nav.home"))
+			return nil
+		})
+	})
+
+	if applied := l.SetLocale("fr-BE"); applied != "fr" {
+		t.Errorf("SetLocale() = %q, want %q", applied, "fr")
+	}
+	owner.RunPendingEffects()
+
+	want := []string{"Home", "Accueil"}
+	if !reflect.DeepEqual(rendered, want) {
+		t.Errorf("rendered = %v, want %v", rendered, want)
+	}
+}
+
+func TestLocalizerArgs(t *testing.T) {
+	l := testBundle(t).NewLocalizer("en")
+
+	if got := l.T("greeting", "name", "Ada"); got != "Hello, Ada!" {
+		t.Errorf("T(pairs) = %q", got)
+	}
+	if got := l.T("cart.items", Args{"count": 1}); got != "1 item" {
+		t.Errorf("T(Args) = %q", got)
+	}
+
+	var nilLocalizer *Localizer
+	if got := nilLocalizer.T("nav.home"); got != "nav.home" {
+		t.Errorf("nil Localizer T() = %q, want key", got)
+	}
+}
+
+func TestStartSession(t *testing.T) {
+	b := testBundle(t)
+	session := server.NewMockSession()
+
+	r := httptest.NewRequest(http.MethodGet, "/_vango/live", nil)
+	r.Header.Set("Accept-Language", "fr-CA")
+	// Without the request only the default locale is available
+	if l := b.StartSession(context.Background(), session); l.Locale() != "en" {
+		t.Errorf("Locale() = %q, want default", l.Locale())
+	}
+
+	// Middleware-negotiated locale is reused
+	var reqCtx context.Context
+	b.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqCtx = r.Context()
+	})).ServeHTTP(httptest.NewRecorder(), r)
+
+	l := b.StartSession(reqCtx, session)
+	if l.Locale() != "fr-CA" {
+		t.Errorf("Locale() = %q, want %q", l.Locale(), "fr-CA")
+	}
+	if got := T(server.NewTestContext(session), "nav.home"); got != "Page d'accueil" {
+		t.Errorf("T(ctx) = %q", got)
+	}
+}
+
+func TestExtractKeys(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeFile("app/routes/index.go", `package routes
+
+func Page(ctx Ctx, l *Localizer) {
+	_ = i18n.T(ctx, "nav.home")
+	_ = l.T("cart.items", "count", 2)
+	_ = l.T(dynamicKey)
+}
+`)
+	writeFile("app/components/nav.go", `package components
+
+func Nav(l *Localizer) { _ = l.T("nav.home") }
+`)
+	writeFile("app/components/nav_test.go", `package components
+
+func x(l *Localizer) { _ = l.T("test.only") }
+`)
+	writeFile("vendor/lib/lib.go", `package lib
+
+func y(l *Localizer) { _ = l.T("vendored") }
+`)
+
+	keys, err := ExtractKeys(dir)
+	if err != nil {
+		t.Fatalf("ExtractKeys error: %v", err)
+	}
+
+	if len(keys) != 2 || keys[0].Key != "cart.items" || keys[1].Key != "nav.home" {
+		t.Fatalf("ExtractKeys() = %+v", keys)
+	}
+	if len(keys[1].Positions) != 2 {
+		t.Errorf("nav.home positions = %v, want 2", keys[1].Positions)
+	}
+}
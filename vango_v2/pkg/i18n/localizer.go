@@ -0,0 +1,185 @@
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/vango-dev/vango/v2/pkg/server"
+	"github.com/vango-dev/vango/v2/pkg/vango"
+)
+
+// SessionKey is the session key under which the Localizer is stored.
+const SessionKey = "vango_i18n_localizer"
+
+// Args holds named message arguments.
+type Args map[string]any
+
+// Localizer translates messages into the current locale of one session.
+//
+// The locale is a reactive signal: components that call T or Locale during
+// render re-render when SetLocale switches languages.
+//
+// A nil *Localizer is valid and returns message keys untranslated.
+type Localizer struct {
+	bundle *Bundle
+	locale *vango.Signal[string]
+}
+
+// NewLocalizer creates a Localizer for locale. The locale is matched against
+// the bundle's catalogs.
+func (b *Bundle) NewLocalizer(locale string) *Localizer {
+	return &Localizer{
+		bundle: b,
+		locale: vango.NewSignal(b.Match(locale)),
+	}
+}
+
+// Bundle returns the bundle the Localizer translates from.
+func (l *Localizer) Bundle() *Bundle {
+	if l == nil {
+		return nil
+	}
+	return l.bundle
+}
+
+// Locale returns the current locale. Reading it during render subscribes
+// the component to locale changes.
+func (l *Localizer) Locale() string {
+	if l == nil {
+		return ""
+	}
+	return l.locale.Get()
+}
+
+// SetLocale switches the current locale. The locale is matched against the
+// bundle's catalogs, so unsupported locales fall back to the default.
+// It returns the locale that was applied.
+func (l *Localizer) SetLocale(locale string) string {
+	if l == nil {
+		return ""
+	}
+	matched := l.bundle.Match(locale)
+	l.locale.Set(matched)
+	return matched
+}
+
+// Dir returns the text direction of the current locale, "ltr" or "rtl".
+func (l *Localizer) Dir() string {
+	return Direction(l.Locale())
+}
+
+// T translates key into the current locale.
+//
+// Arguments are either name/value pairs or a single Args map:
+//
+//	l.T("cart.items", "count", 3)
+//	l.T("greeting", i18n.Args{"name": user.Name})
+func (l *Localizer) T(key string, args ...any) string {
+	if l == nil {
+		return key
+	}
+	return l.bundle.Translate(l.Locale(), key, argsMap(args))
+}
+
+// argsMap converts T's variadic arguments into a map.
+func argsMap(args []any) map[string]any {
+	if len(args) == 0 {
+		return nil
+	}
+	if len(args) == 1 {
+		switch m := args[0].(type) {
+		case Args:
+			return m
+		case map[string]any:
+			return m
+		}
+	}
+
+	out := make(map[string]any, len(args)/2)
+	for i := 0; i+1 < len(args); i += 2 {
+		out[fmt.Sprint(args[i])] = args[i+1]
+	}
+	return out
+}
+
+// =============================================================================
+// Session Integration
+// =============================================================================
+
+// StartSession negotiates the locale for a new session from the upgrade
+// request and stores a Localizer on the session. Call it from OnSessionStart.
+//
+// Example:
+//
+//	OnSessionStart: func(httpCtx context.Context, session *vango.Session) {
+//	    bundle.StartSession(httpCtx, session)
+//	}
+func (b *Bundle) StartSession(httpCtx context.Context, session *server.Session) *Localizer {
+	locale := b.Negotiate(server.RequestFromContext(httpCtx))
+	if l := FromContext(httpCtx); l != nil && l.bundle == b {
+		locale = l.locale.Peek()
+	}
+	l := b.NewLocalizer(locale)
+	if session != nil {
+		session.Set(SessionKey, l)
+	}
+	return l
+}
+
+// FromSession returns the Localizer stored on session, or nil.
+func FromSession(session *server.Session) *Localizer {
+	if session == nil {
+		return nil
+	}
+	l, _ := session.Get(SessionKey).(*Localizer)
+	return l
+}
+
+// localizerContextKey is the context key for request-scoped Localizers.
+type localizerContextKey struct{}
+
+// Middleware negotiates the locale of each HTTP request and makes a
+// Localizer available to server-side rendering via FromContext and Get.
+func (b *Bundle) Middleware() server.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			l := b.NewLocalizer(b.Negotiate(r))
+			ctx := context.WithValue(r.Context(), localizerContextKey{}, l)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// FromContext returns the Localizer installed by Middleware, or nil.
+func FromContext(ctx context.Context) *Localizer {
+	if ctx == nil {
+		return nil
+	}
+	l, _ := ctx.Value(localizerContextKey{}).(*Localizer)
+	return l
+}
+
+// Get returns the Localizer for ctx: the session's during live updates and
+// the request's during server-side rendering. Returns nil if neither is set,
+// which translates keys to themselves.
+func Get(ctx server.Ctx) *Localizer {
+	if ctx == nil {
+		return nil
+	}
+	if l := FromSession(ctx.Session()); l != nil {
+		return l
+	}
+	if r := ctx.Request(); r != nil {
+		return FromContext(r.Context())
+	}
+	return nil
+}
+
+// T translates key using the Localizer for ctx.
+//
+//	i18n.T(ctx, "nav.home")
+//	i18n.T(ctx, "inbox.unread", "count", n)
+func T(ctx server.Ctx, key string, args ...any) string {
+	return Get(ctx).T(key, args...)
+}
@@ -0,0 +1,518 @@
+package i18n
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// maxMessageDepth bounds nesting of plural/select arguments.
+const maxMessageDepth = 16
+
+// Message is a parsed ICU MessageFormat pattern.
+//
+// Supported syntax:
+//
+//	Hello, {name}!
+//	{count, plural, =0 {No items} one {# item} other {# items}}
+//	{count, plural, offset:1 one {You and one other} other {You and # others}}
+//	{gender, select, female {She} male {He} other {They}} replied.
+//	{place, selectordinal, one {#st} two {#nd} few {#rd} other {#th}}
+//
+// Quoting follows ICU: a doubled apostrophe is a literal apostrophe, and
+// text wrapped in apostrophes such as '{name}' is not interpreted.
+type Message struct {
+	source string
+	parts  []part
+}
+
+// part is one piece of a parsed message.
+type part interface {
+	format(b *strings.Builder, f *formatter)
+}
+
+// textPart is literal text.
+type textPart string
+
+// argPart is a simple {name} or {name, number} placeholder.
+type argPart struct {
+	name string
+}
+
+// poundPart is the # placeholder inside a plural branch.
+type poundPart struct{}
+
+// pluralPart is a {name, plural|selectordinal, ...} argument.
+type pluralPart struct {
+	name    string
+	offset  float64
+	ordinal bool
+	exact   map[string][]part
+	cases   map[PluralCategory][]part
+}
+
+// selectPart is a {name, select, ...} argument.
+type selectPart struct {
+	name  string
+	cases map[string][]part
+}
+
+// formatter carries state while formatting a message.
+type formatter struct {
+	locale string
+	args   map[string]any
+
+	// pound is the value substituted for # in the innermost plural.
+	pound    string
+	hasPound bool
+}
+
+// ParseMessage parses an ICU MessageFormat pattern.
+func ParseMessage(pattern string) (*Message, error) {
+	p := &messageParser{src: []rune(pattern)}
+	parts, err := p.parseParts(0, false)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.src) {
+		return nil, p.errorf("unexpected '}'")
+	}
+	return &Message{source: pattern, parts: parts}, nil
+}
+
+// MustParseMessage is like ParseMessage but panics on error.
+func MustParseMessage(pattern string) *Message {
+	m, err := ParseMessage(pattern)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// String returns the original pattern.
+func (m *Message) String() string {
+	return m.source
+}
+
+// Format renders the message for locale with the given arguments.
+// Missing arguments render as their {name} placeholder.
+func (m *Message) Format(locale string, args map[string]any) string {
+	var b strings.Builder
+	f := &formatter{locale: locale, args: args}
+	formatParts(&b, f, m.parts)
+	return b.String()
+}
+
+func formatParts(b *strings.Builder, f *formatter, parts []part) {
+	for _, p := range parts {
+		p.format(b, f)
+	}
+}
+
+func (t textPart) format(b *strings.Builder, f *formatter) {
+	b.WriteString(string(t))
+}
+
+func (a argPart) format(b *strings.Builder, f *formatter) {
+	v, ok := f.args[a.name]
+	if !ok {
+		b.WriteString("{" + a.name + "}")
+		return
+	}
+	b.WriteString(formatValue(v))
+}
+
+func (poundPart) format(b *strings.Builder, f *formatter) {
+	if f.hasPound {
+		b.WriteString(f.pound)
+	} else {
+		b.WriteByte('#')
+	}
+}
+
+func (p *pluralPart) format(b *strings.Builder, f *formatter) {
+	n, ok := toFloat(f.args[p.name])
+	if !ok {
+		b.WriteString("{" + p.name + "}")
+		return
+	}
+
+	branch, found := p.exact[formatNumber(n)]
+	if !found {
+		var cat PluralCategory
+		if p.ordinal {
+			cat = OrdinalCategory(f.locale, n-p.offset)
+		} else {
+			cat = PluralCategoryOf(f.locale, n-p.offset)
+		}
+		branch, found = p.cases[cat]
+		if !found {
+			branch = p.cases[Other]
+		}
+	}
+
+	saved, savedHas := f.pound, f.hasPound
+	f.pound, f.hasPound = formatNumber(n-p.offset), true
+	formatParts(b, f, branch)
+	f.pound, f.hasPound = saved, savedHas
+}
+
+func (s *selectPart) format(b *strings.Builder, f *formatter) {
+	key := formatValue(f.args[s.name])
+	branch, ok := s.cases[key]
+	if !ok {
+		branch = s.cases["other"]
+	}
+	formatParts(b, f, branch)
+}
+
+// formatValue converts an argument to its display string.
+func formatValue(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case fmt.Stringer:
+		return x.String()
+	}
+	if n, ok := toFloat(v); ok {
+		return formatNumber(n)
+	}
+	return fmt.Sprint(v)
+}
+
+// formatNumber renders a number without exponent or trailing zeros.
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// toFloat converts numeric arguments to float64.
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// =============================================================================
+// Parser
+// =============================================================================
+
+// ParseError reports a syntax error in a message pattern.
+type ParseError struct {
+	Pattern string
+	Offset  int
+	Message string
+}
+
+// Error returns the error message with position.
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("i18n: %s at offset %d in %q", e.Message, e.Offset, e.Pattern)
+}
+
+type messageParser struct {
+	src []rune
+	pos int
+}
+
+func (p *messageParser) errorf(format string, args ...any) error {
+	return &ParseError{Pattern: string(p.src), Offset: p.pos, Message: fmt.Sprintf(format, args...)}
+}
+
+// parseParts parses message text until an unmatched '}' or end of input.
+func (p *messageParser) parseParts(depth int, inPlural bool) ([]part, error) {
+	if depth > maxMessageDepth {
+		return nil, p.errorf("message nested too deeply")
+	}
+
+	var parts []part
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			parts = append(parts, textPart(text.String()))
+			text.Reset()
+		}
+	}
+
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c == '\'':
+			p.parseQuoted(&text, inPlural)
+		case c == '{':
+			flush()
+			arg, err := p.parseArgument(depth)
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, arg)
+		case c == '}':
+			flush()
+			return parts, nil
+		case c == '#' && inPlural:
+			flush()
+			parts = append(parts, poundPart{})
+			p.pos++
+		default:
+			text.WriteRune(c)
+			p.pos++
+		}
+	}
+
+	flush()
+	return parts, nil
+}
+
+// parseQuoted handles ICU apostrophe quoting.
+func (p *messageParser) parseQuoted(text *strings.Builder, inPlural bool) {
+	p.pos++ // opening '
+	if p.pos < len(p.src) && p.src[p.pos] == '\'' {
+		text.WriteRune('\'')
+		p.pos++
+		return
+	}
+	if p.pos >= len(p.src) || !isQuotable(p.src[p.pos], inPlural) {
+		// A lone apostrophe is literal
+		text.WriteRune('\'')
+		return
+	}
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		p.pos++
+		if c == '\'' {
+			if p.pos < len(p.src) && p.src[p.pos] == '\'' {
+				text.WriteRune('\'')
+				p.pos++
+				continue
+			}
+			return
+		}
+		text.WriteRune(c)
+	}
+}
+
+func isQuotable(c rune, inPlural bool) bool {
+	return c == '{' || c == '}' || (c == '#' && inPlural)
+}
+
+// parseArgument parses {name}, {name, type} or {name, type, style}.
+func (p *messageParser) parseArgument(depth int) (part, error) {
+	p.pos++ // {
+	p.skipSpace()
+	name := p.parseIdent()
+	if name == "" {
+		return nil, p.errorf("expected argument name")
+	}
+	p.skipSpace()
+
+	if p.consume('}') {
+		return argPart{name: name}, nil
+	}
+	if !p.consume(',') {
+		return nil, p.errorf("expected ',' or '}' after argument %q", name)
+	}
+	p.skipSpace()
+	typ := p.parseIdent()
+	p.skipSpace()
+
+	switch typ {
+	case "plural", "selectordinal":
+		if !p.consume(',') {
+			return nil, p.errorf("expected ',' after %s", typ)
+		}
+		return p.parsePlural(name, typ == "selectordinal", depth)
+	case "select":
+		if !p.consume(',') {
+			return nil, p.errorf("expected ',' after select")
+		}
+		return p.parseSelect(name, depth)
+	case "number", "date", "time":
+		// Style is accepted but formatting is locale-neutral
+		if p.consume(',') {
+			for p.pos < len(p.src) && p.src[p.pos] != '}' {
+				p.pos++
+			}
+		}
+		if !p.consume('}') {
+			return nil, p.errorf("unterminated argument %q", name)
+		}
+		return argPart{name: name}, nil
+	default:
+		return nil, p.errorf("unknown argument type %q", typ)
+	}
+}
+
+// parsePlural parses the branches of a plural or selectordinal argument.
+func (p *messageParser) parsePlural(name string, ordinal bool, depth int) (part, error) {
+	pp := &pluralPart{
+		name:    name,
+		ordinal: ordinal,
+		exact:   make(map[string][]part),
+		cases:   make(map[PluralCategory][]part),
+	}
+
+	p.skipSpace()
+	if p.hasPrefix("offset:") {
+		p.pos += len("offset:")
+		start := p.pos
+		for p.pos < len(p.src) && (unicode.IsDigit(p.src[p.pos]) || p.src[p.pos] == '.') {
+			p.pos++
+		}
+		offset, err := strconv.ParseFloat(string(p.src[start:p.pos]), 64)
+		if err != nil {
+			return nil, p.errorf("invalid plural offset")
+		}
+		pp.offset = offset
+	}
+
+	for {
+		p.skipSpace()
+		if p.consume('}') {
+			break
+		}
+		if p.pos >= len(p.src) {
+			return nil, p.errorf("unterminated plural argument %q", name)
+		}
+
+		var selector string
+		if p.consume('=') {
+			selector = "=" + p.parseIdent()
+		} else {
+			selector = p.parseIdent()
+		}
+		if selector == "" || selector == "=" {
+			return nil, p.errorf("expected plural selector")
+		}
+
+		branch, err := p.parseBranch(depth, true)
+		if err != nil {
+			return nil, err
+		}
+
+		if strings.HasPrefix(selector, "=") {
+			n, err := strconv.ParseFloat(selector[1:], 64)
+			if err != nil {
+				return nil, p.errorf("invalid explicit value %q", selector)
+			}
+			pp.exact[formatNumber(n)] = branch
+			continue
+		}
+
+		cat, ok := parsePluralCategory(selector)
+		if !ok {
+			return nil, p.errorf("unknown plural category %q", selector)
+		}
+		pp.cases[cat] = branch
+	}
+
+	if _, ok := pp.cases[Other]; !ok {
+		return nil, p.errorf("plural argument %q requires an 'other' branch", name)
+	}
+	return pp, nil
+}
+
+// parseSelect parses the branches of a select argument.
+func (p *messageParser) parseSelect(name string, depth int) (part, error) {
+	sp := &selectPart{name: name, cases: make(map[string][]part)}
+
+	for {
+		p.skipSpace()
+		if p.consume('}') {
+			break
+		}
+		if p.pos >= len(p.src) {
+			return nil, p.errorf("unterminated select argument %q", name)
+		}
+
+		selector := p.parseIdent()
+		if selector == "" {
+			return nil, p.errorf("expected select key")
+		}
+		branch, err := p.parseBranch(depth, false)
+		if err != nil {
+			return nil, err
+		}
+		sp.cases[selector] = branch
+	}
+
+	if _, ok := sp.cases["other"]; !ok {
+		return nil, p.errorf("select argument %q requires an 'other' branch", name)
+	}
+	return sp, nil
+}
+
+// parseBranch parses a {submessage} branch.
+func (p *messageParser) parseBranch(depth int, inPlural bool) ([]part, error) {
+	p.skipSpace()
+	if !p.consume('{') {
+		return nil, p.errorf("expected '{' to open branch")
+	}
+	parts, err := p.parseParts(depth+1, inPlural)
+	if err != nil {
+		return nil, err
+	}
+	if !p.consume('}') {
+		return nil, p.errorf("unterminated branch")
+	}
+	return parts, nil
+}
+
+func (p *messageParser) parseIdent() string {
+	start := p.pos
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if unicode.IsLetter(c) || unicode.IsDigit(c) || c == '_' || c == '-' || c == '.' {
+			p.pos++
+			continue
+		}
+		break
+	}
+	return string(p.src[start:p.pos])
+}
+
+func (p *messageParser) skipSpace() {
+	for p.pos < len(p.src) && unicode.IsSpace(p.src[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *messageParser) consume(c rune) bool {
+	if p.pos < len(p.src) && p.src[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *messageParser) hasPrefix(s string) bool {
+	return strings.HasPrefix(string(p.src[p.pos:]), s)
+}
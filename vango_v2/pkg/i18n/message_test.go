@@ -0,0 +1,96 @@
+package i18n
+
+import "testing"
+
+func TestMessageFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		locale  string
+		pattern string
+		args    map[string]any
+		want    string
+	}{
+		{"plain", "en", "Hello", nil, "Hello"},
+		{"arg", "en", "Hello, {name}!", map[string]any{"name": "Ada"}, "Hello, Ada!"},
+		{"missing arg", "en", "Hello, {name}!", nil, "Hello, {name}!"},
+		{"number arg", "en", "{n, number} pts", map[string]any{"n": 2.5}, "2.5 pts"},
+		{"plural exact", "en", "{n, plural, =0 {none} one {# item} other {# items}}", map[string]any{"n": 0}, "none"},
+		{"plural one", "en", "{n, plural, =0 {none} one {# item} other {# items}}", map[string]any{"n": 1}, "1 item"},
+		{"plural other", "en", "{n, plural, =0 {none} one {# item} other {# items}}", map[string]any{"n": 5}, "5 items"},
+		{"plural fraction", "en", "{n, plural, one {# item} other {# items}}", map[string]any{"n": 1.5}, "1.5 items"},
+		{"plural offset", "en", "{n, plural, offset:1 =1 {just you} one {you and # other} other {you and # others}}", map[string]any{"n": 3}, "you and 2 others"},
+		{"plural offset exact", "en", "{n, plural, offset:1 =1 {just you} one {you and # other} other {you and # others}}", map[string]any{"n": 1}, "just you"},
+		{"select", "en", "{g, select, female {She} male {He} other {They}} left", map[string]any{"g": "female"}, "She left"},
+		{"select other", "en", "{g, select, female {She} male {He} other {They}} left", map[string]any{"g": "x"}, "They left"},
+		{"nested", "en", "{g, select, female {{n, plural, one {her # cat} other {her # cats}}} other {{n, plural, one {their # cat} other {their # cats}}}}", map[string]any{"g": "female", "n": 2}, "her 2 cats"},
+		{"ordinal", "en", "{n, selectordinal, one {#st} two {#nd} few {#rd} other {#th}}", map[string]any{"n": 22}, "22nd"},
+		{"ordinal teen", "en", "{n, selectordinal, one {#st} two {#nd} few {#rd} other {#th}}", map[string]any{"n": 13}, "13th"},
+		{"quoted braces", "en", "Use '{name}' literally", nil, "Use {name} literally"},
+		{"escaped apostrophe", "en", "It''s {n}", map[string]any{"n": 1}, "It's 1"},
+		{"lone apostrophe", "en", "It's", nil, "It's"},
+		{"pound outside plural", "en", "#1", nil, "#1"},
+		{"russian few", "ru", "{n, plural, one {# файл} few {# файла} many {# файлов} other {# файла}}", map[string]any{"n": 3}, "3 файла"},
+		{"russian many", "ru", "{n, plural, one {# файл} few {# файла} many {# файлов} other {# файла}}", map[string]any{"n": 11}, "11 файлов"},
+		{"french zero is one", "fr", "{n, plural, one {# fichier} other {# fichiers}}", map[string]any{"n": 0}, "0 fichier"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := ParseMessage(tt.pattern)
+			if err != nil {
+				t.Fatalf("ParseMessage(%q) error: %v", tt.pattern, err)
+			}
+			if got := msg.Format(tt.locale, tt.args); got != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMessageErrors(t *testing.T) {
+	patterns := []string{
+		"Hello {name",
+		"Hello }",
+		"{}",
+		"{n, plural, one {# item}}",
+		"{g, select, male {He}}",
+		"{n, plural, lots {x} other {y}}",
+		"{n, frobnicate}",
+		"{n, plural, one # other {y}}",
+	}
+
+	for _, pattern := range patterns {
+		if _, err := ParseMessage(pattern); err == nil {
+			t.Errorf("ParseMessage(%q) should fail", pattern)
+		}
+	}
+}
+
+func TestPluralCategoryOf(t *testing.T) {
+	tests := []struct {
+		locale string
+		n      float64
+		want   PluralCategory
+	}{
+		{"en", 1, One},
+		{"en", 0, Other},
+		{"en-GB", 1, One},
+		{"ja", 1, Other},
+		{"pl", 1, One},
+		{"pl", 22, Few},
+		{"pl", 25, Many},
+		{"cs", 3, Few},
+		{"ar", 0, Zero},
+		{"ar", 2, Two},
+		{"ar", 105, Few},
+		{"ar", 111, Many},
+		{"he", 2, Two},
+		{"fr", 1.5, One},
+	}
+
+	for _, tt := range tests {
+		if got := PluralCategoryOf(tt.locale, tt.n); got != tt.want {
+			t.Errorf("PluralCategoryOf(%q, %v) = %q, want %q", tt.locale, tt.n, got, tt.want)
+		}
+	}
+}
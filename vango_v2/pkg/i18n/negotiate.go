@@ -0,0 +1,179 @@
+package i18n
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CanonicalLocale normalizes a locale tag: "en_us" and "EN-us" both become
+// "en-US". Script subtags are title-cased ("zh-hant" → "zh-Hant").
+// Returns "" for an empty or malformed tag.
+func CanonicalLocale(tag string) string {
+	tag = strings.TrimSpace(strings.ReplaceAll(tag, "_", "-"))
+	if tag == "" || tag == "*" {
+		return ""
+	}
+
+	parts := strings.Split(tag, "-")
+	for i, p := range parts {
+		if p == "" || !isAlnum(p) {
+			return ""
+		}
+		switch {
+		case i == 0:
+			parts[i] = strings.ToLower(p)
+		case len(p) == 4 && isAlpha(p):
+			parts[i] = strings.ToUpper(p[:1]) + strings.ToLower(p[1:])
+		case len(p) == 2 || (len(p) == 3 && !isAlpha(p)):
+			parts[i] = strings.ToUpper(p)
+		default:
+			parts[i] = strings.ToLower(p)
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// baseLanguage returns the language subtag of a locale ("pt-BR" → "pt").
+func baseLanguage(locale string) string {
+	locale = strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+	if i := strings.IndexByte(locale, '-'); i >= 0 {
+		return locale[:i]
+	}
+	return locale
+}
+
+func isAlpha(s string) bool {
+	for _, c := range s {
+		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlnum(s string) bool {
+	for _, c := range s {
+		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// rtlLanguages are written right to left.
+var rtlLanguages = map[string]bool{
+	"ar": true, "fa": true, "he": true, "ur": true, "ps": true, "yi": true,
+}
+
+// Direction returns "rtl" for right-to-left locales and "ltr" otherwise.
+func Direction(locale string) string {
+	if rtlLanguages[baseLanguage(locale)] {
+		return "rtl"
+	}
+	return "ltr"
+}
+
+// ParseAcceptLanguage returns the locales of an Accept-Language header
+// ordered by preference. Entries with q=0 and wildcards are dropped.
+//
+//	ParseAcceptLanguage("fr-CH, fr;q=0.9, en;q=0.8, *;q=0.5")
+//	// ["fr-CH", "fr", "en"]
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		locale string
+		q      float64
+	}
+
+	var prefs []weighted
+	for _, entry := range strings.Split(header, ",") {
+		fields := strings.Split(entry, ";")
+		locale := CanonicalLocale(fields[0])
+		if locale == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if v, ok := strings.CutPrefix(param, "q="); ok {
+				if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		prefs = append(prefs, weighted{locale: locale, q: q})
+	}
+
+	sort.SliceStable(prefs, func(i, j int) bool {
+		return prefs[i].q > prefs[j].q
+	})
+
+	locales := make([]string, len(prefs))
+	for i, p := range prefs {
+		locales[i] = p.locale
+	}
+	return locales
+}
+
+// Match returns the best supported locale for the preferences, in order.
+// A preference matches a catalog exactly, by its base language ("fr-CA"
+// matches "fr"), or by region variant ("pt" matches "pt-BR").
+// Returns the default locale if nothing matches.
+func (b *Bundle) Match(preferences ...string) string {
+	supported := b.Locales()
+	for _, pref := range preferences {
+		pref = CanonicalLocale(pref)
+		if pref == "" {
+			continue
+		}
+		for _, locale := range supported {
+			if locale == pref {
+				return locale
+			}
+		}
+		base := baseLanguage(pref)
+		for _, locale := range supported {
+			if locale == base {
+				return locale
+			}
+		}
+		for _, locale := range supported {
+			if baseLanguage(locale) == base {
+				return locale
+			}
+		}
+	}
+	return b.defaultLocale
+}
+
+// Negotiate picks the locale for an HTTP request. The locale cookie wins
+// over the Accept-Language header; the default locale is the fallback.
+func (b *Bundle) Negotiate(r *http.Request) string {
+	if r == nil {
+		return b.defaultLocale
+	}
+	var prefs []string
+	if c, err := r.Cookie(b.cookieName); err == nil && c.Value != "" {
+		prefs = append(prefs, c.Value)
+	}
+	prefs = append(prefs, ParseAcceptLanguage(r.Header.Get("Accept-Language"))...)
+	return b.Match(prefs...)
+}
+
+// Cookie returns a cookie that persists locale as the user's choice.
+// Set it from a handler when the user switches languages so the choice
+// survives reloads and new sessions.
+func (b *Bundle) Cookie(locale string) *http.Cookie {
+	return &http.Cookie{
+		Name:     b.cookieName,
+		Value:    CanonicalLocale(locale),
+		Path:     "/",
+		MaxAge:   365 * 24 * 60 * 60,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
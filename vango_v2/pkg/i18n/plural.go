@@ -0,0 +1,205 @@
+package i18n
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// PluralCategory is a CLDR plural category.
+type PluralCategory string
+
+// CLDR plural categories.
+const (
+	Zero  PluralCategory = "zero"
+	One   PluralCategory = "one"
+	Two   PluralCategory = "two"
+	Few   PluralCategory = "few"
+	Many  PluralCategory = "many"
+	Other PluralCategory = "other"
+)
+
+func parsePluralCategory(s string) (PluralCategory, bool) {
+	switch c := PluralCategory(s); c {
+	case Zero, One, Two, Few, Many, Other:
+		return c, true
+	}
+	return "", false
+}
+
+// operands are the CLDR plural operands of a number.
+type operands struct {
+	n float64 // absolute value
+	i int64   // integer digits
+	v int     // number of visible fraction digits
+	f int64   // visible fraction digits
+}
+
+func newOperands(n float64) operands {
+	n = math.Abs(n)
+	s := strconv.FormatFloat(n, 'f', -1, 64)
+	op := operands{n: n, i: int64(n)}
+	if dot := strings.IndexByte(s, '.'); dot >= 0 {
+		frac := s[dot+1:]
+		op.v = len(frac)
+		op.f, _ = strconv.ParseInt(frac, 10, 64)
+	}
+	return op
+}
+
+// isInt reports whether the number has no visible fraction digits.
+func (o operands) isInt() bool {
+	return o.v == 0
+}
+
+// cardinalRules maps a base language to its cardinal plural rule.
+// Languages not listed here use the English rule.
+var cardinalRules = map[string]func(operands) PluralCategory{
+	// No plural distinctions
+	"ja": pluralOther, "zh": pluralOther, "ko": pluralOther, "vi": pluralOther,
+	"th": pluralOther, "id": pluralOther, "ms": pluralOther, "tr": pluralOneOther,
+
+	// one: i = 0,1
+	"fr": pluralFrench, "pt": pluralFrench,
+
+	// Slavic
+	"ru": pluralEastSlavic, "uk": pluralEastSlavic, "be": pluralEastSlavic,
+	"pl": pluralPolish,
+	"cs": pluralCzech, "sk": pluralCzech,
+
+	"ar": pluralArabic,
+	"he": pluralHebrew,
+}
+
+func pluralOther(operands) PluralCategory {
+	return Other
+}
+
+// pluralOneOther is the rule for languages where only n = 1 is singular.
+func pluralOneOther(o operands) PluralCategory {
+	if o.n == 1 {
+		return One
+	}
+	return Other
+}
+
+// pluralEnglish: one if i = 1 and v = 0.
+func pluralEnglish(o operands) PluralCategory {
+	if o.i == 1 && o.isInt() {
+		return One
+	}
+	return Other
+}
+
+func pluralFrench(o operands) PluralCategory {
+	if o.i == 0 || o.i == 1 {
+		return One
+	}
+	if o.isInt() && o.i != 0 && o.i%1000000 == 0 {
+		return Many
+	}
+	return Other
+}
+
+func pluralEastSlavic(o operands) PluralCategory {
+	if !o.isInt() {
+		return Other
+	}
+	mod10, mod100 := o.i%10, o.i%100
+	switch {
+	case mod10 == 1 && mod100 != 11:
+		return One
+	case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+		return Few
+	default:
+		return Many
+	}
+}
+
+func pluralPolish(o operands) PluralCategory {
+	if !o.isInt() {
+		return Other
+	}
+	mod10, mod100 := o.i%10, o.i%100
+	switch {
+	case o.i == 1:
+		return One
+	case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+		return Few
+	default:
+		return Many
+	}
+}
+
+func pluralCzech(o operands) PluralCategory {
+	switch {
+	case !o.isInt():
+		return Many
+	case o.i == 1:
+		return One
+	case o.i >= 2 && o.i <= 4:
+		return Few
+	default:
+		return Other
+	}
+}
+
+func pluralArabic(o operands) PluralCategory {
+	if !o.isInt() {
+		return Other
+	}
+	mod100 := o.i % 100
+	switch {
+	case o.i == 0:
+		return Zero
+	case o.i == 1:
+		return One
+	case o.i == 2:
+		return Two
+	case mod100 >= 3 && mod100 <= 10:
+		return Few
+	case mod100 >= 11 && mod100 <= 99:
+		return Many
+	default:
+		return Other
+	}
+}
+
+func pluralHebrew(o operands) PluralCategory {
+	switch {
+	case o.isInt() && o.i == 1:
+		return One
+	case o.isInt() && o.i == 2:
+		return Two
+	default:
+		return Other
+	}
+}
+
+// PluralCategoryOf returns the cardinal plural category of n in locale.
+func PluralCategoryOf(locale string, n float64) PluralCategory {
+	if rule, ok := cardinalRules[baseLanguage(locale)]; ok {
+		return rule(newOperands(n))
+	}
+	return pluralEnglish(newOperands(n))
+}
+
+// OrdinalCategory returns the ordinal plural category of n in locale.
+// English ordinals are supported; other languages use "other".
+func OrdinalCategory(locale string, n float64) PluralCategory {
+	o := newOperands(n)
+	if baseLanguage(locale) != "en" || !o.isInt() {
+		return Other
+	}
+	mod10, mod100 := o.i%10, o.i%100
+	switch {
+	case mod10 == 1 && mod100 != 11:
+		return One
+	case mod10 == 2 && mod100 != 12:
+		return Two
+	case mod10 == 3 && mod100 != 13:
+		return Few
+	default:
+		return Other
+	}
+}
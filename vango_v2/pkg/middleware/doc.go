@@ -46,6 +46,13 @@
 //	http.Handle("/metrics", promhttp.Handler())
 //	go http.ListenAndServe(":9090", nil)
 //
+// # Live Events
+//
+// To trace and measure the events of live sessions, add the middleware to
+// the server's event middleware too. ctx.Event() is then set:
+//
+//	app.UseEvents(middleware.OpenTelemetry(), middleware.Prometheus())
+//
 // # Context Propagation
 //
 // Both middlewares inject trace context into ctx.StdContext(), allowing
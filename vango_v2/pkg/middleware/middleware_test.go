@@ -59,6 +59,11 @@ func (m *mockCtx) Done() <-chan struct{}                { return nil }
 func (m *mockCtx) SetValue(key, value any)              { m.values[key] = value }
 func (m *mockCtx) Value(key any) any                    { return m.values[key] }
 func (m *mockCtx) Emit(name string, data any)           {}
+func (m *mockCtx) Call(hid, name string, args ...any) (server.Result, error) {
+	return server.Result{}, server.ErrCallsUnsupported
+}
+func (m *mockCtx) Nonce() string                        { return "" }
+func (m *mockCtx) ClientInfo() server.ClientInfo         { return server.ClientInfo{} }
 func (m *mockCtx) StdContext() context.Context          { return m.stdCtx }
 func (m *mockCtx) WithStdContext(ctx context.Context) server.Ctx {
 	clone := *m
@@ -0,0 +1,162 @@
+package protocol
+
+import "errors"
+
+// Blob transfer constants.
+const (
+	// BlobChunkSize is the largest payload of a BlobChunk message, well
+	// under MaxPayloadSize and the server's default MaxMessageSize.
+	BlobChunkSize = 32 * 1024
+
+	// BlobWindow is the initial flow-control window of a blob: the bytes a
+	// sender may send before the receiver grants more with BlobCredit.
+	BlobWindow = 256 * 1024
+)
+
+// BlobOp identifies the type of a blob message.
+type BlobOp uint8
+
+const (
+	BlobOpen   BlobOp = 0x01 // Sender starts a blob
+	BlobChunk  BlobOp = 0x02 // Sender sends a chunk of data
+	BlobEnd    BlobOp = 0x03 // Sender has sent all the data
+	BlobCredit BlobOp = 0x04 // Receiver consumed data and grants more window
+	BlobAbort  BlobOp = 0x05 // Either side cancels the blob
+)
+
+// String returns the string representation of the blob op.
+func (op BlobOp) String() string {
+	switch op {
+	case BlobOpen:
+		return "Open"
+	case BlobChunk:
+		return "Chunk"
+	case BlobEnd:
+		return "End"
+	case BlobCredit:
+		return "Credit"
+	case BlobAbort:
+		return "Abort"
+	default:
+		return "Unknown"
+	}
+}
+
+// ErrInvalidBlobOp is returned when decoding a blob message of unknown type.
+var ErrInvalidBlobOp = errors.New("protocol: invalid blob op")
+
+// BlobMessage is the payload of a FrameBlob. Blobs flow in both directions
+// on the live connection: files dropped on an element from the client, and
+// generated binaries from the server.
+//
+// The sender picks the ID of a blob: odd for the client, even for the
+// server, so the IDs of both directions never collide. It opens the blob,
+// sends its data in chunks of at most BlobChunkSize bytes and ends it.
+// It may have at most BlobWindow bytes not yet credited by the receiver.
+//
+// Wire format, after [op:1][id:varint]:
+//
+//	Open:   [hid:string][name:string][mimeType:string][size:varint]
+//	Chunk:  [data:len-prefixed]
+//	End:    (nothing)
+//	Credit: [bytes:varint]
+//	Abort:  [reason:string]
+type BlobMessage struct {
+	Op BlobOp
+	ID uint64
+
+	// Open: HID is the element the blob is for, Size its length in bytes
+	// or 0 if unknown.
+	HID      string
+	Name     string
+	MimeType string
+	Size     uint64
+
+	Data   []byte // Chunk
+	Credit uint64 // Credit
+	Reason string // Abort
+}
+
+// IsClientBlob reports whether id is the ID of a blob sent by the client.
+func IsClientBlob(id uint64) bool {
+	return id%2 == 1
+}
+
+// EncodeBlob encodes a blob message to bytes.
+func EncodeBlob(m *BlobMessage) []byte {
+	e := NewEncoder()
+	EncodeBlobTo(e, m)
+	return e.Bytes()
+}
+
+// EncodeBlobTo encodes a blob message using the provided encoder.
+func EncodeBlobTo(e *Encoder, m *BlobMessage) {
+	e.WriteByte(byte(m.Op))
+	e.WriteUvarint(m.ID)
+
+	switch m.Op {
+	case BlobOpen:
+		e.WriteString(m.HID)
+		e.WriteString(m.Name)
+		e.WriteString(m.MimeType)
+		e.WriteUvarint(m.Size)
+	case BlobChunk:
+		e.WriteLenBytes(m.Data)
+	case BlobCredit:
+		e.WriteUvarint(m.Credit)
+	case BlobAbort:
+		e.WriteString(m.Reason)
+	}
+}
+
+// DecodeBlob decodes a blob message from bytes.
+func DecodeBlob(data []byte) (*BlobMessage, error) {
+	d := NewDecoder(data)
+	return DecodeBlobFrom(d)
+}
+
+// DecodeBlobFrom decodes a blob message from a decoder.
+func DecodeBlobFrom(d *Decoder) (*BlobMessage, error) {
+	op, err := d.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	id, err := d.ReadUvarint()
+	if err != nil {
+		return nil, err
+	}
+	m := &BlobMessage{Op: BlobOp(op), ID: id}
+
+	switch m.Op {
+	case BlobOpen:
+		if m.HID, err = d.ReadString(); err != nil {
+			return nil, err
+		}
+		if m.Name, err = d.ReadString(); err != nil {
+			return nil, err
+		}
+		if m.MimeType, err = d.ReadString(); err != nil {
+			return nil, err
+		}
+		if m.Size, err = d.ReadUvarint(); err != nil {
+			return nil, err
+		}
+	case BlobChunk:
+		if m.Data, err = d.ReadLenBytes(); err != nil {
+			return nil, err
+		}
+	case BlobEnd:
+	case BlobCredit:
+		if m.Credit, err = d.ReadUvarint(); err != nil {
+			return nil, err
+		}
+	case BlobAbort:
+		if m.Reason, err = d.ReadString(); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, ErrInvalidBlobOp
+	}
+
+	return m, nil
+}
@@ -0,0 +1,71 @@
+package protocol
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBlobEncodeDecode(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  *BlobMessage
+	}{
+		{
+			name: "open",
+			msg:  &BlobMessage{Op: BlobOpen, ID: 1, HID: "h3", Name: "photo.png", MimeType: "image/png", Size: 1 << 20},
+		},
+		{
+			name: "chunk",
+			msg:  &BlobMessage{Op: BlobChunk, ID: 1, Data: bytes.Repeat([]byte{0xAB}, BlobChunkSize)},
+		},
+		{
+			name: "end",
+			msg:  &BlobMessage{Op: BlobEnd, ID: 2},
+		},
+		{
+			name: "credit",
+			msg:  &BlobMessage{Op: BlobCredit, ID: 2, Credit: BlobWindow},
+		},
+		{
+			name: "abort",
+			msg:  &BlobMessage{Op: BlobAbort, ID: 3, Reason: "too large"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			decoded, err := DecodeBlob(EncodeBlob(tc.msg))
+			if err != nil {
+				t.Fatalf("DecodeBlob() error = %v", err)
+			}
+			if decoded.Op != tc.msg.Op || decoded.ID != tc.msg.ID {
+				t.Errorf("Op, ID = %s %d, want %s %d", decoded.Op, decoded.ID, tc.msg.Op, tc.msg.ID)
+			}
+			if decoded.HID != tc.msg.HID || decoded.Name != tc.msg.Name || decoded.MimeType != tc.msg.MimeType || decoded.Size != tc.msg.Size {
+				t.Errorf("Open = %+v, want %+v", decoded, tc.msg)
+			}
+			if !bytes.Equal(decoded.Data, tc.msg.Data) {
+				t.Errorf("Data = %d bytes, want %d", len(decoded.Data), len(tc.msg.Data))
+			}
+			if decoded.Credit != tc.msg.Credit || decoded.Reason != tc.msg.Reason {
+				t.Errorf("Credit, Reason = %d %q, want %d %q", decoded.Credit, decoded.Reason, tc.msg.Credit, tc.msg.Reason)
+			}
+		})
+	}
+
+	if _, err := DecodeBlob([]byte{0x09, 0x01}); err != ErrInvalidBlobOp {
+		t.Errorf("DecodeBlob(unknown op) error = %v, want ErrInvalidBlobOp", err)
+	}
+
+	// A full chunk fits in a frame
+	chunk := EncodeBlob(&BlobMessage{Op: BlobChunk, ID: ^uint64(0), Data: make([]byte, BlobChunkSize)})
+	if len(chunk) > MaxPayloadSize {
+		t.Errorf("chunk message = %d bytes, over MaxPayloadSize", len(chunk))
+	}
+}
+
+func TestIsClientBlob(t *testing.T) {
+	if !IsClientBlob(1) || IsClientBlob(2) {
+		t.Error("client blobs have odd IDs, server blobs even IDs")
+	}
+}
@@ -0,0 +1,179 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strconv"
+
+	"github.com/vango-dev/vango/v2/pkg/protocol"
+)
+
+// ErrBlobAborted is returned by SendFile when the server cancels the blob.
+var ErrBlobAborted = errors.New("client: blob aborted")
+
+// Blob is a blob received from the server.
+type Blob struct {
+	ID       uint64
+	Name     string
+	MimeType string
+	Data     []byte
+}
+
+// blobState is the state of the blobs of a client, guarded by Client.mu.
+type blobState struct {
+	nextID   uint64           // Last ID of a blob sent (odd)
+	credits  map[uint64]int   // Window of the blobs being sent
+	aborted  map[uint64]bool  // Blobs being sent that the server canceled
+	incoming map[uint64]*Blob // Blobs being received
+	received map[uint64]*Blob // Blobs received
+}
+
+// SendFile sends the data of r to the element hid as a blob, like a file
+// dropped on it. It waits for the server to grant window as it reads the
+// data, and returns when the blob is sent.
+func (c *Client) SendFile(ctx context.Context, hid, name, mimeType string, r io.Reader) error {
+	var size uint64
+	if l, ok := r.(interface{ Len() int }); ok {
+		size = uint64(l.Len())
+	}
+
+	c.mu.Lock()
+	c.blobs.nextID += 2
+	id := c.blobs.nextID
+	c.blobs.credits[id] = protocol.BlobWindow
+	c.mu.Unlock()
+	defer c.update(func() {
+		delete(c.blobs.credits, id)
+		delete(c.blobs.aborted, id)
+	})
+
+	err := c.write(protocol.FrameBlob, protocol.EncodeBlob(&protocol.BlobMessage{
+		Op: protocol.BlobOpen, ID: id, HID: hid, Name: name, MimeType: mimeType, Size: size,
+	}))
+	if err != nil {
+		return err
+	}
+
+	buf := make([]byte, protocol.BlobChunkSize)
+	for {
+		window, err := c.blobWindow(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		n, rerr := io.ReadFull(r, buf[:min(window, len(buf))])
+		if n > 0 {
+			c.update(func() { c.blobs.credits[id] -= n })
+			err := c.write(protocol.FrameBlob, protocol.EncodeBlob(&protocol.BlobMessage{Op: protocol.BlobChunk, ID: id, Data: buf[:n]}))
+			if err != nil {
+				return err
+			}
+		}
+
+		switch {
+		case rerr == io.EOF || rerr == io.ErrUnexpectedEOF:
+			return c.write(protocol.FrameBlob, protocol.EncodeBlob(&protocol.BlobMessage{Op: protocol.BlobEnd, ID: id}))
+		case rerr != nil:
+			c.write(protocol.FrameBlob, protocol.EncodeBlob(&protocol.BlobMessage{Op: protocol.BlobAbort, ID: id, Reason: "read error"}))
+			return rerr
+		}
+	}
+}
+
+// blobWindow waits until the blob id may be sent and returns its window.
+func (c *Client) blobWindow(ctx context.Context, id uint64) (int, error) {
+	for {
+		c.mu.Lock()
+		window, aborted, changed, err := c.blobs.credits[id], c.blobs.aborted[id], c.changed, c.err
+		c.mu.Unlock()
+
+		switch {
+		case aborted:
+			return 0, ErrBlobAborted
+		case err != nil:
+			return 0, err
+		case window > 0:
+			return window, nil
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+}
+
+// handleBlob handles a blob message of the server: the blobs it sends,
+// and the window it grants to ours.
+func (c *Client) handleBlob(m *protocol.BlobMessage) {
+	if protocol.IsClientBlob(m.ID) {
+		c.update(func() {
+			if _, sending := c.blobs.credits[m.ID]; !sending {
+				return
+			}
+			switch m.Op {
+			case protocol.BlobCredit:
+				c.blobs.credits[m.ID] += int(min(m.Credit, protocol.BlobWindow))
+			case protocol.BlobAbort:
+				c.blobs.aborted[m.ID] = true
+			}
+		})
+		return
+	}
+
+	credit := 0
+	c.update(func() {
+		switch m.Op {
+		case protocol.BlobOpen:
+			c.blobs.incoming[m.ID] = &Blob{ID: m.ID, Name: m.Name, MimeType: m.MimeType}
+		case protocol.BlobChunk:
+			if b := c.blobs.incoming[m.ID]; b != nil {
+				b.Data = append(b.Data, m.Data...)
+				credit = len(m.Data)
+			}
+		case protocol.BlobEnd:
+			if b := c.blobs.incoming[m.ID]; b != nil {
+				delete(c.blobs.incoming, m.ID)
+				c.blobs.received[m.ID] = b
+			}
+		case protocol.BlobAbort:
+			delete(c.blobs.incoming, m.ID)
+		}
+	})
+
+	if credit > 0 {
+		c.write(protocol.FrameBlob, protocol.EncodeBlob(&protocol.BlobMessage{Op: protocol.BlobCredit, ID: m.ID, Credit: uint64(credit)}))
+	}
+}
+
+// Blob returns the blob received from the server with the given ref, the
+// value of a data-blob-src or data-blob-href attribute.
+func (c *Client) Blob(ref string) (Blob, bool) {
+	id, err := strconv.ParseUint(ref, 10, 64)
+	if err != nil {
+		return Blob{}, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	b, ok := c.blobs.received[id]
+	if !ok {
+		return Blob{}, false
+	}
+	return *b, true
+}
+
+// WaitForBlob waits until the blob with the given ref is received.
+func (c *Client) WaitForBlob(ctx context.Context, ref string) (Blob, error) {
+	err := c.WaitFor(ctx, func(*Document) bool {
+		id, _ := strconv.ParseUint(ref, 10, 64)
+		_, ok := c.blobs.received[id]
+		return ok
+	})
+	if err != nil {
+		return Blob{}, err
+	}
+	b, _ := c.Blob(ref)
+	return b, nil
+}
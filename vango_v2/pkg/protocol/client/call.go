@@ -0,0 +1,50 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/vango-dev/vango/v2/pkg/protocol"
+)
+
+// Function is a client function the server can call, like a function
+// registered in the thin client. It receives the element of the call and
+// its JSON arguments; its result is sent back as JSON, and its error as
+// the message of a failed call.
+type Function func(hid string, args []json.RawMessage) (any, error)
+
+// Register registers fn as the client function name. Calls of functions
+// not registered fail.
+func (c *Client) Register(name string, fn Function) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.functions == nil {
+		c.functions = make(map[string]Function)
+	}
+	c.functions[name] = fn
+}
+
+// call runs the client function of a Call patch and sends its result.
+func (c *Client) call(p protocol.Patch) {
+	c.mu.Lock()
+	fn := c.functions[p.Key]
+	c.mu.Unlock()
+
+	result := &protocol.CallResultData{ID: p.CallID}
+	var args []json.RawMessage
+	switch err := json.Unmarshal([]byte(p.Value), &args); {
+	case fn == nil:
+		result.Value = fmt.Sprintf("unknown function %q", p.Key)
+	case err != nil:
+		result.Value = err.Error()
+	default:
+		if value, err := fn(p.HID, args); err != nil {
+			result.Value = err.Error()
+		} else if data, err := json.Marshal(value); err != nil {
+			result.Value = err.Error()
+		} else {
+			result.OK, result.Value = true, string(data)
+		}
+	}
+	c.Send(&protocol.Event{Type: protocol.EventCallResult, Payload: result})
+}
@@ -0,0 +1,650 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/vango-dev/vango/v2/pkg/protocol"
+)
+
+// DefaultTimeout is how long Dial waits for the page and the handshake.
+const DefaultTimeout = 10 * time.Second
+
+// LivePath is the WebSocket endpoint of the server.
+const LivePath = "/_vango/ws"
+
+// csrfCookieName is the cookie carrying the CSRF token (server.CSRFCookieName).
+const csrfCookieName = "__vango_csrf"
+
+// ErrClosed is returned by operations on a closed client.
+var ErrClosed = errors.New("client: connection closed")
+
+// Options configure a client.
+type Options struct {
+	// Path is the page the session starts on. Default: "/"
+	Path string
+
+	// Header is sent with the page request and the WebSocket handshake,
+	// e.g. for authentication.
+	Header http.Header
+
+	// Timeout is how long to wait for the page and the handshake.
+	// Default: DefaultTimeout
+	Timeout time.Duration
+
+	// ViewportWidth and ViewportHeight are reported in the handshake.
+	ViewportWidth  uint16
+	ViewportHeight uint16
+
+	// TimeZone, Locale and ColorScheme describe the browser in the
+	// handshake, e.g. "Europe/Paris", "fr-FR" and protocol.ColorSchemeDark.
+	TimeZone    string
+	Locale      string
+	ColorScheme protocol.ColorScheme
+
+	// Version is the protocol version announced in the handshake, to test
+	// older clients. Default: protocol.CurrentVersion.
+	Version protocol.ProtocolVersion
+
+	// Dialer connects the WebSocket. Default: websocket.DefaultDialer
+	Dialer *websocket.Dialer
+
+	// HTTPClient loads the page. Default: http.DefaultClient
+	HTTPClient *http.Client
+
+	// Logger receives the patches that did not apply to the mirrored
+	// document. Default: discard
+	Logger *slog.Logger
+}
+
+// Dispatch is a custom event dispatched on an element by a Dispatch patch.
+type Dispatch struct {
+	HID    string
+	Name   string
+	Detail string // JSON
+}
+
+// Client is a headless live session. It mirrors the page in a Document,
+// applying the patches sent by the server, and sends events like the
+// thin client. Heartbeats, acknowledgments and resyncs are handled
+// automatically.
+//
+// A Client is safe for concurrent use.
+type Client struct {
+	conn      *websocket.Conn
+	sessionID string
+	logger    *slog.Logger
+
+	writeMu  sync.Mutex
+	eventSeq uint64
+	closing  atomic.Bool
+
+	mu         sync.Mutex
+	doc        *Document
+	lastSeq    uint64
+	changed    chan struct{} // closed and replaced on every change
+	dispatched []Dispatch
+	errors     []protocol.ErrorMessage
+	blobs      blobState
+	functions  map[string]Function // Client functions the server calls
+	err        error               // why the connection ended
+
+	done chan struct{}
+}
+
+// Dial starts a session on the app at baseURL, e.g. "http://localhost:3000".
+// Like a browser it loads the page, whose markup becomes the document and
+// whose CSRF token is used for the handshake, and then connects the
+// WebSocket. If the page cannot be loaded the document starts empty.
+func Dial(ctx context.Context, baseURL string, opts *Options) (*Client, error) {
+	var o Options
+	if opts != nil {
+		o = *opts
+	}
+	if o.Path == "" {
+		o.Path = "/"
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = DefaultTimeout
+	}
+	if o.Dialer == nil {
+		o.Dialer = websocket.DefaultDialer
+	}
+	if o.HTTPClient == nil {
+		o.HTTPClient = http.DefaultClient
+	}
+	if o.Logger == nil {
+		o.Logger = discardLogger()
+	}
+
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("client: invalid URL %q: %w", baseURL, err)
+	}
+	page, err := url.Parse(o.Path)
+	if err != nil {
+		return nil, fmt.Errorf("client: invalid path %q: %w", o.Path, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, o.Timeout)
+	defer cancel()
+
+	doc, vars, cookie := loadPage(ctx, base.ResolveReference(page), o)
+	doc.URL = &url.URL{Path: page.Path, RawQuery: page.RawQuery}
+
+	token := cookie
+	if token == "" {
+		token = vars["__VANGO_CSRF__"]
+	}
+	header := o.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	if cookie != "" {
+		header.Add("Cookie", (&http.Cookie{Name: csrfCookieName, Value: cookie}).String())
+	}
+	header.Set("Origin", base.Scheme+"://"+base.Host)
+
+	params := url.Values{}
+	params.Set("path", page.RequestURI())
+	if load := vars["__VANGO_LOAD__"]; load != "" {
+		params.Set("load", load)
+	}
+	wsURL := *base
+	wsURL.Scheme = "ws"
+	if base.Scheme == "https" {
+		wsURL.Scheme = "wss"
+	}
+	wsURL.Path = LivePath
+	wsURL.RawQuery = params.Encode()
+
+	conn, _, err := o.Dialer.DialContext(ctx, wsURL.String(), header)
+	if err != nil {
+		return nil, fmt.Errorf("client: connect to %s: %w", wsURL.String(), err)
+	}
+
+	hello := protocol.NewClientHello(token)
+	hello.ViewportW = o.ViewportWidth
+	hello.ViewportH = o.ViewportHeight
+	hello.TimeZone = o.TimeZone
+	hello.Locale = o.Locale
+	hello.ColorScheme = o.ColorScheme
+	if o.Version != (protocol.ProtocolVersion{}) {
+		hello.Version = o.Version
+		hello.Capabilities = protocol.VersionCapabilities(o.Version)
+	}
+	sh, err := handshake(ctx, conn, hello)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	c := &Client{
+		conn:      conn,
+		sessionID: sh.SessionID,
+		logger:    o.Logger,
+		doc:       doc,
+		lastSeq:   uint64(sh.NextSeq),
+		changed:   make(chan struct{}),
+		blobs: blobState{
+			nextID:   ^uint64(0), // Client blobs have odd IDs: 1, 3, ...
+			credits:  make(map[uint64]int),
+			aborted:  make(map[uint64]bool),
+			incoming: make(map[uint64]*Blob),
+			received: make(map[uint64]*Blob),
+		},
+		done: make(chan struct{}),
+	}
+	go c.readLoop()
+	return c, nil
+}
+
+// discardLogger returns a logger that discards its records.
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// bootstrapVars matches the variables set by the page's bootstrap scripts,
+// e.g. window.__VANGO_CSRF__="...".
+var bootstrapVars = regexp.MustCompile(`window\.(__VANGO_[A-Z]+__)="([^"]*)"`)
+
+// loadPage loads the page and returns its document, the variables of its
+// bootstrap scripts and its CSRF cookie.
+func loadPage(ctx context.Context, page *url.URL, o Options) (*Document, map[string]string, string) {
+	vars := make(map[string]string)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, page.String(), nil)
+	if err != nil {
+		return NewDocument(), vars, ""
+	}
+	for name, values := range o.Header {
+		req.Header[name] = values
+	}
+	resp, err := o.HTTPClient.Do(req)
+	if err != nil {
+		return NewDocument(), vars, ""
+	}
+	defer resp.Body.Close()
+
+	cookie := ""
+	for _, c := range resp.Cookies() {
+		if c.Name == csrfCookieName {
+			cookie = c.Value
+		}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 16<<20))
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return NewDocument(), vars, cookie
+	}
+	for _, m := range bootstrapVars.FindAllStringSubmatch(string(body), -1) {
+		vars[m[1]] = m[2]
+	}
+	return ParseDocument(string(body)), vars, cookie
+}
+
+// handshake sends the ClientHello and reads the ServerHello.
+func handshake(ctx context.Context, conn *websocket.Conn, hello *protocol.ClientHello) (*protocol.ServerHello, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetReadDeadline(deadline)
+		conn.SetWriteDeadline(deadline)
+		defer conn.SetReadDeadline(time.Time{})
+		defer conn.SetWriteDeadline(time.Time{})
+	}
+
+	if err := conn.WriteMessage(websocket.BinaryMessage, protocol.EncodeClientHello(hello)); err != nil {
+		return nil, fmt.Errorf("client: handshake: %w", err)
+	}
+	_, msg, err := conn.ReadMessage()
+	if err != nil {
+		return nil, fmt.Errorf("client: handshake: %w", err)
+	}
+
+	frame, err := protocol.DecodeFrame(msg)
+	if err != nil || frame.Type != protocol.FrameHandshake {
+		return nil, errors.New("client: handshake: unexpected response")
+	}
+	sh, err := protocol.DecodeServerHello(frame.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("client: handshake: %w", err)
+	}
+	if sh.Status != protocol.HandshakeOK {
+		return nil, fmt.Errorf("client: handshake rejected: %s", sh.Status)
+	}
+	return sh, nil
+}
+
+// SessionID returns the ID of the session.
+func (c *Client) SessionID() string {
+	return c.sessionID
+}
+
+// readLoop handles the frames sent by the server until the connection
+// ends.
+func (c *Client) readLoop() {
+	var err error
+	for {
+		var msg []byte
+		_, msg, err = c.conn.ReadMessage()
+		if err != nil {
+			break
+		}
+		frame, ferr := protocol.DecodeFrame(msg)
+		if ferr != nil {
+			c.logger.Warn("invalid frame", "error", ferr)
+			continue
+		}
+
+		switch frame.Type {
+		case protocol.FramePatches:
+			pf, perr := protocol.DecodePatches(frame.Payload)
+			if perr != nil {
+				c.logger.Warn("invalid patches", "error", perr)
+				continue
+			}
+			c.handlePatches(pf)
+
+		case protocol.FrameControl:
+			if c.handleControl(frame.Payload) {
+				err = ErrClosed
+			}
+
+		case protocol.FrameError:
+			em, eerr := protocol.DecodeErrorMessage(frame.Payload)
+			if eerr != nil {
+				continue
+			}
+			c.update(func() { c.errors = append(c.errors, *em) })
+
+		case protocol.FrameBlob:
+			m, berr := protocol.DecodeBlob(frame.Payload)
+			if berr != nil {
+				c.logger.Warn("invalid blob message", "error", berr)
+				continue
+			}
+			c.handleBlob(m)
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	if c.closing.Load() || websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+		err = ErrClosed
+	} else if err != ErrClosed {
+		err = fmt.Errorf("client: connection lost: %w", err)
+	}
+	c.conn.Close()
+	c.update(func() { c.err = err })
+	close(c.done)
+}
+
+// handlePatches applies a patches frame and acknowledges it. A gap in the
+// sequence numbers means frames were lost; the client asks for a resync.
+func (c *Client) handlePatches(pf *protocol.PatchesFrame) {
+	var gap bool
+	var last uint64
+	c.update(func() {
+		gap, last = pf.Seq > c.lastSeq+1, c.lastSeq
+		c.apply(pf.Patches)
+		c.lastSeq = max(c.lastSeq, pf.Seq)
+	})
+
+	if gap {
+		ct, rr := protocol.NewResyncRequest(last)
+		c.write(protocol.FrameControl, protocol.EncodeControl(ct, rr))
+	}
+	c.write(protocol.FrameAck, protocol.EncodeAck(protocol.NewAck(pf.Seq, protocol.DefaultWindow)))
+}
+
+// handleControl handles a control message and reports whether the server
+// closed the session.
+func (c *Client) handleControl(payload []byte) bool {
+	ct, data, err := protocol.DecodeControl(payload)
+	if err != nil {
+		c.logger.Warn("invalid control message", "error", err)
+		return false
+	}
+
+	switch ct {
+	case protocol.ControlPing:
+		if pp, ok := data.(*protocol.PingPong); ok {
+			ct, pong := protocol.NewPong(pp.Timestamp)
+			c.write(protocol.FrameControl, protocol.EncodeControl(ct, pong))
+		}
+
+	case protocol.ControlResyncPatches:
+		if rr, ok := data.(*protocol.ResyncResponse); ok {
+			c.update(func() {
+				c.apply(rr.Patches)
+				c.lastSeq = max(c.lastSeq, rr.FromSeq)
+			})
+		}
+
+	case protocol.ControlResyncFull:
+		if rr, ok := data.(*protocol.ResyncResponse); ok {
+			c.update(func() {
+				doc := ParseDocument(rr.HTML)
+				doc.URL = c.doc.URL
+				c.doc = doc
+			})
+		}
+
+	case protocol.ControlClose:
+		return true
+	}
+	return false
+}
+
+// apply applies patches to the document. The caller holds c.mu.
+func (c *Client) apply(patches []protocol.Patch) {
+	for _, p := range patches {
+		if p.Op == protocol.PatchCall {
+			go c.call(p) // Functions may block; answer off the read loop
+			continue
+		}
+		if p.Op == protocol.PatchDispatch {
+			c.dispatched = append(c.dispatched, Dispatch{HID: p.HID, Name: p.Key, Detail: p.Value})
+		}
+		if err := c.doc.Apply(p); err != nil {
+			c.logger.Warn("patch not applied", "error", err)
+		}
+	}
+}
+
+// update runs fn with the lock held and wakes up the waiters.
+func (c *Client) update(fn func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	fn()
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+// write sends a frame.
+func (c *Client) write(ft protocol.FrameType, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return c.writeLocked(ft, payload)
+}
+
+func (c *Client) writeLocked(ft protocol.FrameType, payload []byte) error {
+	select {
+	case <-c.done:
+		return ErrClosed
+	default:
+	}
+	frame := protocol.NewFrame(ft, payload)
+	if err := c.conn.WriteMessage(websocket.BinaryMessage, frame.Encode()); err != nil {
+		return fmt.Errorf("client: write: %w", err)
+	}
+	return nil
+}
+
+// Send sends an event. Its sequence number is set by the client.
+func (c *Client) Send(e *protocol.Event) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	c.eventSeq++
+	e.Seq = c.eventSeq
+	return c.writeLocked(protocol.FrameEvent, protocol.EncodeEvent(e))
+}
+
+// Click sends a click on the element with the HID.
+func (c *Client) Click(hid string) error {
+	return c.Send(&protocol.Event{Type: protocol.EventClick, HID: hid})
+}
+
+// Input sends an input event with the value of the element.
+func (c *Client) Input(hid, value string) error {
+	return c.Send(&protocol.Event{Type: protocol.EventInput, HID: hid, Payload: value})
+}
+
+// Change sends a change event with the value of the element.
+func (c *Client) Change(hid, value string) error {
+	return c.Send(&protocol.Event{Type: protocol.EventChange, HID: hid, Payload: value})
+}
+
+// Submit sends the submission of a form with its fields.
+func (c *Client) Submit(hid string, fields map[string]string) error {
+	return c.Send(&protocol.Event{Type: protocol.EventSubmit, HID: hid, Payload: &protocol.SubmitEventData{Fields: fields}})
+}
+
+// Focus sends a focus event.
+func (c *Client) Focus(hid string) error {
+	return c.Send(&protocol.Event{Type: protocol.EventFocus, HID: hid})
+}
+
+// Blur sends a blur event.
+func (c *Client) Blur(hid string) error {
+	return c.Send(&protocol.Event{Type: protocol.EventBlur, HID: hid})
+}
+
+// KeyDown sends a key press on the element.
+func (c *Client) KeyDown(hid, key string, mods protocol.Modifiers) error {
+	return c.Send(&protocol.Event{Type: protocol.EventKeyDown, HID: hid, Payload: &protocol.KeyboardEventData{Key: key, Modifiers: mods}})
+}
+
+// Navigate asks the server to navigate to path, like a click on a link
+// with data-link.
+func (c *Client) Navigate(path string, replace bool) error {
+	return c.Send(&protocol.Event{Type: protocol.EventNavigate, Payload: &protocol.NavigateEventData{Path: path, Replace: replace}})
+}
+
+// Resize sends a resize of the window.
+func (c *Client) Resize(width, height int) error {
+	return c.Send(&protocol.Event{Type: protocol.EventResize, Payload: &protocol.ResizeEventData{Width: width, Height: height}})
+}
+
+// Media sends a change of the media query preferences of the browser.
+func (c *Client) Media(data *protocol.MediaEventData) error {
+	return c.Send(&protocol.Event{Type: protocol.EventMedia, Payload: data})
+}
+
+// Hook sends an event of a client hook.
+func (c *Client) Hook(hid, name string, data map[string]any) error {
+	return c.Send(&protocol.Event{Type: protocol.EventHook, HID: hid, Payload: &protocol.HookEventData{Name: name, Data: data}})
+}
+
+// Custom sends a custom event.
+func (c *Client) Custom(hid, name string, data []byte) error {
+	return c.Send(&protocol.Event{Type: protocol.EventCustom, HID: hid, Payload: &protocol.CustomEventData{Name: name, Data: data}})
+}
+
+// LastSeq returns the sequence number of the last patches applied.
+func (c *Client) LastSeq() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lastSeq
+}
+
+// WaitFor waits until cond reports true for the document. cond is called
+// with the document locked, after every change; it must not keep it.
+// WaitFor returns an error if ctx is done or the connection ends first.
+func (c *Client) WaitFor(ctx context.Context, cond func(d *Document) bool) error {
+	for {
+		c.mu.Lock()
+		ok, changed, err := cond(c.doc), c.changed, c.err
+		c.mu.Unlock()
+
+		if ok {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// WaitForSeq waits until the patches with sequence number seq are applied.
+// To wait for the patches of an event:
+//
+//	seq := c.LastSeq()
+//	c.Click(hid)
+//	err := c.WaitForSeq(ctx, seq+1)
+func (c *Client) WaitForSeq(ctx context.Context, seq uint64) error {
+	return c.WaitFor(ctx, func(*Document) bool { return c.lastSeq >= seq })
+}
+
+// Document returns a copy of the mirrored document.
+func (c *Client) Document() *Document {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.doc.Clone()
+}
+
+// Query returns a copy of the first element matching a selector, or nil.
+// See Document.Query for the selector syntax.
+func (c *Client) Query(selector string) *protocol.VNodeWire {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return cloneNode(c.doc.Query(selector))
+}
+
+// Text returns the text of the first element matching a selector.
+func (c *Client) Text(selector string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return TextContent(c.doc.Query(selector))
+}
+
+// HID returns the HID of the first element matching a selector, or "".
+func (c *Client) HID(selector string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if n := c.doc.Query(selector); n != nil {
+		return n.HID
+	}
+	return ""
+}
+
+// Title returns the document title.
+func (c *Client) Title() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.doc.Title
+}
+
+// Dispatched returns the custom events dispatched by the server so far.
+func (c *Client) Dispatched() []Dispatch {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Dispatch(nil), c.dispatched...)
+}
+
+// Errors returns the error messages sent by the server so far.
+func (c *Client) Errors() []protocol.ErrorMessage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]protocol.ErrorMessage(nil), c.errors...)
+}
+
+// Done returns a channel closed when the connection ends.
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err returns why the connection ended: ErrClosed if it was closed by
+// either side, nil while it is open.
+func (c *Client) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// Close ends the session and closes the connection.
+func (c *Client) Close() error {
+	c.closing.Store(true)
+	ct, cm := protocol.NewClose(protocol.CloseNormal, "")
+	err := c.write(protocol.FrameControl, protocol.EncodeControl(ct, cm))
+	if errors.Is(err, ErrClosed) {
+		return nil
+	}
+
+	c.writeMu.Lock()
+	c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	c.writeMu.Unlock()
+
+	select {
+	case <-c.done:
+	case <-time.After(time.Second):
+		c.conn.Close()
+		<-c.done
+	}
+	return nil
+}
@@ -0,0 +1,472 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vango-dev/vango/v2/pkg/head"
+	"github.com/vango-dev/vango/v2/pkg/protocol"
+	"github.com/vango-dev/vango/v2/pkg/render"
+	"github.com/vango-dev/vango/v2/pkg/server"
+	"github.com/vango-dev/vango/v2/pkg/vango"
+	"github.com/vango-dev/vango/v2/pkg/vdom"
+)
+
+// todoState is the state of the todo app.
+type todoState struct {
+	items *vango.Signal[[]string]
+	draft *vango.Signal[string]
+}
+
+func newTodoState() *todoState {
+	return &todoState{
+		items: vango.NewSignal([]string{"Milk"}),
+		draft: vango.NewSignal(""),
+	}
+}
+
+// todoView renders a todo list with a form to add items.
+func todoView(s *todoState) *vdom.VNode {
+	items := s.items.Get()
+	var lis []any
+	for i, item := range items {
+		lis = append(lis, vdom.Li(vdom.Key(item), vdom.Class("item"),
+			vdom.Span(vdom.Text(item)),
+			vdom.Button(vdom.Class("remove"), vdom.OnClick(func() {
+				s.items.Set(append(append([]string(nil), items[:i]...), items[i+1:]...))
+			}), vdom.Text("x")),
+		))
+	}
+
+	return vdom.Div(vdom.ID("app"),
+		head.Title(fmt.Sprintf("%d todos", len(items))),
+		vdom.Form(vdom.ID("add"),
+			vdom.OnSubmit(func(f server.FormData) {
+				s.items.Set(append(append([]string(nil), items...), f.Get("title")))
+				s.draft.Set("")
+			}),
+			vdom.Input(vdom.Name("title"), vdom.Value(s.draft.Get()), vdom.OnInput(func(v string) { s.draft.Set(v) })),
+			vdom.Button(vdom.Type("submit"), vdom.Text("Add")),
+		),
+		vdom.P(vdom.Class("draft"), vdom.Text("Draft: "+s.draft.Get())),
+		vdom.Ul(lis...),
+	)
+}
+
+// todoApp serves the todo app: the page and its live sessions.
+func todoApp(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	srv := server.New(server.DefaultServerConfig())
+	srv.SetRootComponent(func() server.Component {
+		s := newTodoState()
+		return server.FuncComponent(func() *vdom.VNode { return todoView(s) })
+	})
+	srv.SetHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r2 := render.NewRenderer(render.RendererConfig{})
+		w.Header().Set("Content-Type", "text/html")
+		r2.RenderPage(w, render.PageData{Body: todoView(newTodoState()), Title: "Todos"})
+	}))
+
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestClient(t *testing.T) {
+	ts := todoApp(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c, err := Dial(ctx, ts.URL, &Options{Path: "/todos"})
+	if err != nil {
+		t.Fatalf("Dial() error: %v", err)
+	}
+	defer c.Close()
+
+	if c.SessionID() == "" {
+		t.Error("SessionID() is empty")
+	}
+	if got := c.Title(); got != "1 todos" {
+		t.Errorf("Title() = %q, want the page title", got)
+	}
+	if got := c.Text("ul > li.item span"); got != "Milk" {
+		t.Fatalf("first item = %q, want Milk", got)
+	}
+
+	// Typing re-renders the draft
+	seq := c.LastSeq()
+	if err := c.Input(c.HID("form#add input[name=title]"), "Eggs"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.WaitForSeq(ctx, seq+1); err != nil {
+		t.Fatalf("WaitForSeq() error: %v", err)
+	}
+	if got := c.Text("p.draft"); got != "Draft: Eggs" {
+		t.Errorf("draft = %q after input", got)
+	}
+
+	// Submitting inserts an item and updates the title
+	if err := c.Submit(c.HID("form#add"), map[string]string{"title": "Eggs"}); err != nil {
+		t.Fatal(err)
+	}
+	err = c.WaitFor(ctx, func(d *Document) bool { return len(d.QueryAll("li.item")) == 2 })
+	if err != nil {
+		t.Fatalf("WaitFor(2 items) error: %v", err)
+	}
+	if err := c.WaitFor(ctx, func(d *Document) bool { return d.Title == "2 todos" }); err != nil {
+		t.Errorf("title = %q, want 2 todos", c.Title())
+	}
+	doc := c.Document()
+	items := doc.QueryAll("li.item")
+	if TextContent(items[0].Children[0]) != "Milk" || TextContent(items[1].Children[0]) != "Eggs" {
+		t.Errorf("items = %q, %q", TextContent(items[0]), TextContent(items[1]))
+	}
+	if got := doc.Query("input[name=title]").Attrs["value"]; got != "" {
+		t.Errorf("input value = %q after submit", got)
+	}
+
+	// Removing an item removes its element and handlers
+	remove := items[0].Children[1].HID
+	if err := c.Click(remove); err != nil {
+		t.Fatal(err)
+	}
+	err = c.WaitFor(ctx, func(d *Document) bool { return len(d.QueryAll("li.item")) == 1 })
+	if err != nil {
+		t.Fatalf("WaitFor(1 item) error: %v", err)
+	}
+	if got := c.Text("li.item span"); got != "Eggs" {
+		t.Errorf("remaining item = %q, want Eggs", got)
+	}
+	if len(c.Errors()) != 0 {
+		t.Errorf("Errors() = %v", c.Errors())
+	}
+
+	if err := c.Close(); err != nil {
+		t.Errorf("Close() error: %v", err)
+	}
+	if !errors.Is(c.Err(), ErrClosed) {
+		t.Errorf("Err() = %v after Close, want ErrClosed", c.Err())
+	}
+	if err := c.Click(remove); !errors.Is(err, ErrClosed) {
+		t.Errorf("Click() after Close = %v, want ErrClosed", err)
+	}
+	if err := c.WaitForSeq(ctx, 1000); !errors.Is(err, ErrClosed) {
+		t.Errorf("WaitForSeq() after Close = %v, want ErrClosed", err)
+	}
+}
+
+func TestClientEmit(t *testing.T) {
+	srv := server.New(server.DefaultServerConfig())
+	srv.UseEvents(eventMiddleware(func(ctx server.Ctx, next func() error) error {
+		if ctx.Event().HID == "h3" {
+			return errors.New("forbidden")
+		}
+		return next()
+	}))
+	view := func(status *vango.Signal[string]) *vdom.VNode {
+		return vdom.Div(
+			vdom.Button(vdom.ID("save"), vdom.OnClick(func(ctx server.Ctx) {
+				ctx.Emit("toast", map[string]string{"message": "Saved " + ctx.Path()})
+				status.Set("saved")
+			})),
+			vdom.Button(vdom.ID("delete"), vdom.OnClick(func() {
+				t.Error("rejected handler should not run")
+			})),
+			vdom.P(vdom.ID("status"), vdom.Text(status.Get())),
+		)
+	}
+	srv.SetRootComponent(func() server.Component {
+		status := vango.NewSignal("editing")
+		return server.FuncComponent(func() *vdom.VNode { return view(status) })
+	})
+	srv.SetHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		render.NewRenderer(render.RendererConfig{}).RenderPage(w, render.PageData{Body: view(vango.NewSignal("editing"))})
+	}))
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	c, err := Dial(ctx, ts.URL, &Options{Path: "/settings"})
+	if err != nil {
+		t.Fatalf("Dial() error: %v", err)
+	}
+	defer c.Close()
+
+	seq := c.LastSeq()
+	if err := c.Click(c.HID("#save")); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.WaitForSeq(ctx, seq+1); err != nil {
+		t.Fatalf("WaitForSeq() error: %v", err)
+	}
+	// The event arrives with the render patches of its handler
+	if got := c.Text("#status"); got != "saved" {
+		t.Errorf("status = %q when the event arrived, want the handler's update", got)
+	}
+	want := Dispatch{HID: c.HID("#save"), Name: "toast", Detail: `{"message":"Saved /settings"}`}
+	if got := c.Dispatched(); len(got) != 1 || got[0] != want {
+		t.Errorf("Dispatched() = %+v, want %+v", got, want)
+	}
+
+	if c.HID("#delete") != "h3" {
+		t.Fatalf("delete button is %s, want h3", c.HID("#delete"))
+	}
+	if err := c.Click("h3"); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for len(c.Errors()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if errs := c.Errors(); len(errs) != 1 || errs[0].Code != protocol.ErrServerError {
+		t.Errorf("Errors() = %v, want the rejected event", errs)
+	}
+
+	// The rejection is sent after every frame of the save click
+	if got := c.LastSeq(); got != seq+1 {
+		t.Errorf("save click sent %d frames, want the render and event in one", got-seq)
+	}
+}
+
+func TestClientBlobs(t *testing.T) {
+	view := func(ref *vango.Signal[string]) *vdom.VNode {
+		return vdom.Div(
+			vdom.Div(vdom.ID("drop"), vdom.OnDrop(func(ctx server.Ctx, b *server.Blob) {
+				s := ctx.Session()
+				go func() {
+					defer b.Close()
+					data, err := io.ReadAll(b)
+					if err != nil {
+						t.Errorf("reading blob: %v", err)
+						return
+					}
+					// Echo the file back, upper-cased
+					tr, err := s.SendBlob("echo-"+b.Name, b.MimeType, bytes.NewReader(bytes.ToUpper(data)))
+					if err != nil {
+						t.Errorf("SendBlob() error: %v", err)
+						return
+					}
+					s.Dispatch(func() { ref.Set(tr.Ref()) })
+				}()
+			})),
+			vdom.Img(vdom.ID("echo"), vdom.BlobSrc(ref.Get())),
+		)
+	}
+	srv := server.New(server.DefaultServerConfig())
+	srv.SetRootComponent(func() server.Component {
+		ref := vango.NewSignal("")
+		return server.FuncComponent(func() *vdom.VNode { return view(ref) })
+	})
+	srv.SetHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		render.NewRenderer(render.RendererConfig{}).RenderPage(w, render.PageData{Body: view(vango.NewSignal(""))})
+	}))
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	c, err := Dial(ctx, ts.URL, nil)
+	if err != nil {
+		t.Fatalf("Dial() error: %v", err)
+	}
+	defer c.Close()
+
+	// Larger than the window, so the transfer waits for credit
+	data := bytes.Repeat([]byte("vango "), 100_000)
+	if err := c.SendFile(ctx, c.HID("#drop"), "words.txt", "text/plain", bytes.NewReader(data)); err != nil {
+		t.Fatalf("SendFile() error: %v", err)
+	}
+
+	var ref string
+	err = c.WaitFor(ctx, func(d *Document) bool {
+		ref = d.Query("#echo").Attrs["data-blob-src"]
+		return ref != ""
+	})
+	if err != nil {
+		t.Fatalf("waiting for the blob ref: %v", err)
+	}
+	b, err := c.WaitForBlob(ctx, ref)
+	if err != nil {
+		t.Fatalf("WaitForBlob() error: %v", err)
+	}
+	if b.Name != "echo-words.txt" || b.MimeType != "text/plain" || !bytes.Equal(b.Data, bytes.ToUpper(data)) {
+		t.Errorf("blob = %s %s, %d bytes", b.Name, b.MimeType, len(b.Data))
+	}
+}
+
+func TestClientCalls(t *testing.T) {
+	view := func(out *vango.Signal[string]) *vdom.VNode {
+		return vdom.Div(
+			vdom.Input(vdom.ID("name"), vdom.OnClick(func(ctx server.Ctx) {
+				r, err := ctx.Call(ctx.Event().HID, "selection")
+				if err != nil {
+					out.Set("error: " + err.Error())
+					return
+				}
+				var sel struct{ Start, End int }
+				r.Decode(&sel)
+				out.Set(fmt.Sprintf("caret at %d", sel.Start))
+			})),
+			vdom.Button(vdom.ID("share"), vdom.OnClick(func(ctx server.Ctx) {
+				ctx.Session().CallAsync("", "share", map[string]string{"url": "/p/1"}).Then(func(_ server.Result, err error) {
+					out.Set("share: " + err.Error())
+				})
+			})),
+			vdom.P(vdom.ID("out"), vdom.Text(out.Get())),
+		)
+	}
+	srv := server.New(server.DefaultServerConfig())
+	srv.SetRootComponent(func() server.Component {
+		out := vango.NewSignal("")
+		return server.FuncComponent(func() *vdom.VNode { return view(out) })
+	})
+	srv.SetHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		render.NewRenderer(render.RendererConfig{}).RenderPage(w, render.PageData{Body: view(vango.NewSignal(""))})
+	}))
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	c, err := Dial(ctx, ts.URL, nil)
+	if err != nil {
+		t.Fatalf("Dial() error: %v", err)
+	}
+	defer c.Close()
+
+	input := c.HID("#name")
+	c.Register("selection", func(hid string, args []json.RawMessage) (any, error) {
+		if hid != input || len(args) != 0 {
+			return nil, fmt.Errorf("unexpected call on %s with %s", hid, args)
+		}
+		return map[string]int{"start": 4, "end": 4}, nil
+	})
+
+	tests := []struct {
+		selector string
+		want     string
+	}{
+		{"#name", "caret at 4"},
+		{"#share", `share: server: client call share: unknown function "share"`},
+	}
+	for _, tc := range tests {
+		if err := c.Click(c.HID(tc.selector)); err != nil {
+			t.Fatal(err)
+		}
+		err := c.WaitFor(ctx, func(d *Document) bool {
+			return TextContent(d.Query("#out")) == tc.want
+		})
+		if err != nil {
+			t.Errorf("click on %s: out = %q, want %q", tc.selector, c.Text("#out"), tc.want)
+		}
+	}
+}
+
+// eventMiddleware adapts a function to server.EventMiddleware.
+type eventMiddleware func(ctx server.Ctx, next func() error) error
+
+func (f eventMiddleware) Handle(ctx server.Ctx, next func() error) error {
+	return f(ctx, next)
+}
+
+func TestDialCSRF(t *testing.T) {
+	config := server.DefaultServerConfig()
+	config.CSRFSecret = []byte("0123456789abcdef0123456789abcdef")
+	srv := server.New(config)
+	srv.SetRootComponent(func() server.Component {
+		return server.FuncComponent(func() *vdom.VNode { return vdom.Div() })
+	})
+	srv.SetHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/with-token" {
+			token := srv.GenerateCSRFToken()
+			srv.SetCSRFCookie(w, token)
+			render.NewRenderer(render.RendererConfig{}).RenderPage(w, render.PageData{Body: vdom.Div(), CSRFToken: token})
+		}
+	}))
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	ctx := context.Background()
+	c, err := Dial(ctx, ts.URL, &Options{Path: "/with-token"})
+	if err != nil {
+		t.Fatalf("Dial() error: %v", err)
+	}
+	c.Close()
+
+	if _, err := Dial(ctx, ts.URL, &Options{Path: "/"}); err == nil {
+		t.Error("Dial() should fail without a CSRF token")
+	}
+
+	notFound := httptest.NewServer(http.NotFoundHandler())
+	defer notFound.Close()
+	if _, err := Dial(ctx, notFound.URL, &Options{Timeout: time.Second}); err == nil {
+		t.Error("Dial() should fail without a live endpoint")
+	}
+}
+
+func TestDialVersion(t *testing.T) {
+	ts := todoApp(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := Dial(ctx, ts.URL, &Options{Version: protocol.ProtocolVersion{Major: 1, Minor: 9}})
+	if err == nil || !strings.Contains(err.Error(), "UpgradeRequired") {
+		t.Errorf("Dial(1.9) error = %v, want UpgradeRequired", err)
+	}
+
+	// Clients one minor version behind are served without head patches
+	c, err := Dial(ctx, ts.URL, &Options{Version: protocol.ProtocolVersion{Major: 2, Minor: 0}})
+	if err != nil {
+		t.Fatalf("Dial(2.0) error: %v", err)
+	}
+	defer c.Close()
+
+	if err := c.Submit(c.HID("form#add"), map[string]string{"title": "Eggs"}); err != nil {
+		t.Fatal(err)
+	}
+	err = c.WaitFor(ctx, func(d *Document) bool { return len(d.QueryAll("li.item")) == 2 })
+	if err != nil {
+		t.Fatalf("WaitFor(2 items) error: %v", err)
+	}
+	if got := c.Title(); got != "1 todos" {
+		t.Errorf("Title() = %q, want the title of the page", got)
+	}
+}
+
+func TestClientControl(t *testing.T) {
+	c := &Client{
+		doc:     ParseDocument(`<body><div data-hid="h1">old</div></body>`),
+		changed: make(chan struct{}),
+		logger:  discardLogger(),
+	}
+
+	ct, rr := protocol.NewResyncPatches(4, []protocol.Patch{protocol.NewSetTextPatch("h1", "patched")})
+	if c.handleControl(protocol.EncodeControl(ct, rr)) {
+		t.Error("ResyncPatches should not close the session")
+	}
+	if got := TextContent(c.doc.Node("h1")); got != "patched" || c.lastSeq != 4 {
+		t.Errorf("after ResyncPatches: text %q, last seq %d", got, c.lastSeq)
+	}
+
+	ct, rr = protocol.NewResyncFull(`<div data-hid="h1">full</div><p data-hid="h2"></p>`)
+	c.handleControl(protocol.EncodeControl(ct, rr))
+	if got := TextContent(c.doc.Node("h1")); got != "full" || c.doc.Node("h2") == nil {
+		t.Errorf("after ResyncFull: text %q, h2 %v", got, c.doc.Node("h2"))
+	}
+
+	ct2, cm := protocol.NewClose(protocol.CloseSessionExpired, "")
+	if !c.handleControl(protocol.EncodeControl(ct2, cm)) {
+		t.Error("Close should close the session")
+	}
+}
@@ -0,0 +1,60 @@
+// Package client is a headless client for the live protocol, for
+// integration tests and bots.
+//
+// Dial starts a session like a browser: it loads the page, takes the CSRF
+// token from its cookie, connects to /_vango/ws and performs the
+// handshake. The page markup becomes a Document, which mirrors the DOM:
+// every patches frame is applied to it like the thin client applies it.
+// Heartbeats are answered, frames are acknowledged and missed frames
+// trigger a resync, without involvement of the caller.
+//
+//	c, err := client.Dial(ctx, "http://localhost:3000", &client.Options{Path: "/todos"})
+//	if err != nil {
+//	    return err
+//	}
+//	defer c.Close()
+//
+//	c.Input(c.HID("form#add input[name=title]"), "Buy milk")
+//	c.Submit(c.HID("form#add"), map[string]string{"title": "Buy milk"})
+//
+//	err = c.WaitFor(ctx, func(d *client.Document) bool {
+//	    return len(d.QueryAll("li.item")) == 2
+//	})
+//
+// # Queries
+//
+// Elements are found with a subset of CSS selectors (see Document.Query)
+// and addressed by their HID, which events target. The Client methods
+// Query, Text and HID lock the document; WaitFor calls its condition with
+// the document locked after every change, and Document returns a copy.
+//
+// # Waiting for Patches
+//
+// The server does not relate patches to events. Wait for the next patches
+// frame with WaitForSeq, or for the expected state with WaitFor:
+//
+//	seq := c.LastSeq()
+//	c.Click(hid)
+//	err := c.WaitForSeq(ctx, seq+1)
+//
+// Custom events dispatched by the server are recorded (Dispatched), as are
+// error frames (Errors). The connection ends when the server closes the
+// session or Close is called; Done and Err report it.
+//
+// # Blobs
+//
+// SendFile sends a file to an element like a drop in the browser, with
+// the flow control of the protocol. Blobs sent by the server are kept and
+// returned by Blob and WaitForBlob, by the ref of the attributes
+// referencing them.
+//
+// # Client Calls
+//
+// The server calls functions of the client with Ctx.Call. Register the
+// functions a test needs; calls of other functions fail like in a browser
+// where they are not registered:
+//
+//	c.Register("rect", func(hid string, args []json.RawMessage) (any, error) {
+//	    return map[string]float64{"width": 320, "height": 200}, nil
+//	})
+package client
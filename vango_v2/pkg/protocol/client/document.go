@@ -0,0 +1,492 @@
+package client
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/vango-dev/vango/v2/pkg/protocol"
+	"github.com/vango-dev/vango/v2/pkg/vdom"
+)
+
+// Document is a mirror of the page of a session. Patches are applied to it
+// like the thin client applies them to the DOM.
+//
+// DOM properties set by patches (value, checked, selected) are mirrored as
+// attributes of the same name, and style and dataset changes as the style
+// and data-* attributes.
+type Document struct {
+	// Title is the document title
+	Title string
+
+	// Head holds the managed head elements (data-vango-head) by key
+	Head map[string]*protocol.VNodeWire
+
+	// Body is the body element
+	Body *protocol.VNodeWire
+
+	// URL is the page URL, updated by URLPush and URLReplace patches
+	URL *url.URL
+
+	// Focused is the HID of the element focused by a Focus patch
+	Focused string
+
+	nodes   map[string]*protocol.VNodeWire
+	parents map[*protocol.VNodeWire]*protocol.VNodeWire
+}
+
+// NewDocument returns a document with an empty body.
+func NewDocument() *Document {
+	d := &Document{
+		Head: make(map[string]*protocol.VNodeWire),
+		Body: protocol.NewElementWire("body", nil),
+		URL:  &url.URL{Path: "/"},
+	}
+	d.reindex()
+	return d
+}
+
+// ParseDocument parses a page rendered by the server. Markup outside of a
+// body element, such as the HTML of a full resync, becomes the body.
+func ParseDocument(page string) *Document {
+	d := NewDocument()
+	root := parseHTML(page)
+
+	if head := findTag(root, "head"); head != nil {
+		if title := findTag(head, "title"); title != nil {
+			d.Title = TextContent(title)
+		}
+		for _, child := range head.Children {
+			if key, ok := child.Attrs["data-vango-head"]; ok && child.Kind == vdom.KindElement {
+				d.Head[key] = child
+			}
+		}
+	}
+
+	if body := findTag(root, "body"); body != nil {
+		d.Body = body
+	} else if findTag(root, "html") == nil {
+		d.Body.Children = root.Children
+	}
+	d.reindex()
+	return d
+}
+
+// findTag returns the first element with tag under n, in document order.
+func findTag(n *protocol.VNodeWire, tag string) *protocol.VNodeWire {
+	var found *protocol.VNodeWire
+	walk(n, func(c *protocol.VNodeWire) bool {
+		if found == nil && c != n && c.Kind == vdom.KindElement && c.Tag == tag {
+			found = c
+		}
+		return found == nil
+	})
+	return found
+}
+
+// walk calls fn for n and its descendants in document order until fn
+// returns false.
+func walk(n *protocol.VNodeWire, fn func(*protocol.VNodeWire) bool) bool {
+	if !fn(n) {
+		return false
+	}
+	for _, c := range n.Children {
+		if !walk(c, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// reindex rebuilds the HID and parent indexes from the body.
+func (d *Document) reindex() {
+	d.nodes = make(map[string]*protocol.VNodeWire)
+	d.parents = make(map[*protocol.VNodeWire]*protocol.VNodeWire)
+	d.register(d.Body, nil)
+}
+
+// register adds n and its descendants to the indexes.
+func (d *Document) register(n, parent *protocol.VNodeWire) {
+	if parent != nil {
+		d.parents[n] = parent
+	}
+	if n.HID != "" {
+		d.nodes[n.HID] = n
+	}
+	for _, c := range n.Children {
+		d.register(c, n)
+	}
+}
+
+// unregister removes n and its descendants from the indexes.
+func (d *Document) unregister(n *protocol.VNodeWire) {
+	delete(d.parents, n)
+	if n.HID != "" && d.nodes[n.HID] == n {
+		delete(d.nodes, n.HID)
+	}
+	for _, c := range n.Children {
+		d.unregister(c)
+	}
+}
+
+// Node returns the element with the HID, or nil.
+func (d *Document) Node(hid string) *protocol.VNodeWire {
+	return d.nodes[hid]
+}
+
+// Parent returns the parent of n, or nil for the body and detached nodes.
+func (d *Document) Parent(n *protocol.VNodeWire) *protocol.VNodeWire {
+	return d.parents[n]
+}
+
+// HIDs returns the HIDs of the elements of the document, sorted.
+func (d *Document) HIDs() []string {
+	hids := make([]string, 0, len(d.nodes))
+	for hid := range d.nodes {
+		hids = append(hids, hid)
+	}
+	sort.Strings(hids)
+	return hids
+}
+
+// Clone returns a deep copy of the document.
+func (d *Document) Clone() *Document {
+	c := &Document{
+		Title:   d.Title,
+		Head:    make(map[string]*protocol.VNodeWire, len(d.Head)),
+		Body:    cloneNode(d.Body),
+		Focused: d.Focused,
+	}
+	for key, n := range d.Head {
+		c.Head[key] = cloneNode(n)
+	}
+	u := *d.URL
+	c.URL = &u
+	c.reindex()
+	return c
+}
+
+// cloneNode returns a deep copy of n.
+func cloneNode(n *protocol.VNodeWire) *protocol.VNodeWire {
+	if n == nil {
+		return nil
+	}
+	c := *n
+	if n.Attrs != nil {
+		c.Attrs = make(map[string]string, len(n.Attrs))
+		for k, v := range n.Attrs {
+			c.Attrs[k] = v
+		}
+	}
+	if n.Children != nil {
+		c.Children = make([]*protocol.VNodeWire, len(n.Children))
+		for i, child := range n.Children {
+			c.Children[i] = cloneNode(child)
+		}
+	}
+	return &c
+}
+
+// Apply applies a patch. Like the thin client, it skips patches whose
+// target is not in the document, and returns an error for them.
+func (d *Document) Apply(p protocol.Patch) error {
+	// Head patches target the document head, not a hydrated node
+	switch p.Op {
+	case protocol.PatchSetTitle:
+		d.Title = p.Value
+		return nil
+	case protocol.PatchSetHead:
+		return d.setHead(p.Key, p.Node)
+	case protocol.PatchRemoveHead:
+		delete(d.Head, p.Key)
+		return nil
+	case protocol.PatchURLPush, protocol.PatchURLReplace:
+		q := d.URL.Query()
+		for key, value := range p.Params {
+			if value == "" {
+				q.Del(key)
+			} else {
+				q.Set(key, value)
+			}
+		}
+		d.URL.RawQuery = q.Encode()
+		return nil
+	case protocol.PatchInsertNode:
+		parent := d.nodes[p.ParentID]
+		if parent == nil {
+			return fmt.Errorf("client: %s: parent %s not found", p.Op, p.ParentID)
+		}
+		d.insertBefore(parent, elementAt(parent, p.Index), createNodes(p.Node))
+		return nil
+	}
+
+	el := d.nodes[p.HID]
+	if el == nil {
+		return fmt.Errorf("client: %s: node %s not found", p.Op, p.HID)
+	}
+
+	switch p.Op {
+	case protocol.PatchSetText:
+		for _, c := range el.Children {
+			d.unregister(c)
+		}
+		el.Children = []*protocol.VNodeWire{protocol.NewTextWire(p.Value)}
+		d.parents[el.Children[0]] = el
+
+	case protocol.PatchSetAttr:
+		setAttr(el, p.Key, p.Value)
+
+	case protocol.PatchRemoveAttr:
+		delete(el.Attrs, p.Key)
+
+	case protocol.PatchAddClass, protocol.PatchRemoveClass, protocol.PatchToggleClass:
+		classes := strings.Fields(el.Attrs["class"])
+		i := indexOf(classes, p.Value)
+		switch {
+		case p.Op == protocol.PatchAddClass && i < 0,
+			p.Op == protocol.PatchToggleClass && i < 0:
+			classes = append(classes, p.Value)
+		case p.Op == protocol.PatchRemoveClass && i >= 0,
+			p.Op == protocol.PatchToggleClass && i >= 0:
+			classes = append(classes[:i], classes[i+1:]...)
+		}
+		setAttr(el, "class", strings.Join(classes, " "))
+
+	case protocol.PatchSetStyle, protocol.PatchRemoveStyle:
+		value := ""
+		if p.Op == protocol.PatchSetStyle {
+			value = p.Value
+		}
+		setAttr(el, "style", setStyle(el.Attrs["style"], kebab(p.Key), value))
+
+	case protocol.PatchSetData:
+		setAttr(el, "data-"+kebab(p.Key), p.Value)
+
+	case protocol.PatchRemoveNode:
+		if parent := d.parents[el]; parent != nil {
+			parent.Children = removeChild(parent.Children, el)
+		}
+		d.unregister(el)
+
+	case protocol.PatchMoveNode:
+		parent := d.nodes[p.ParentID]
+		if parent == nil {
+			return fmt.Errorf("client: %s: parent %s not found", p.Op, p.ParentID)
+		}
+		// The position is taken before the node is removed, like
+		// insertBefore(el, parent.children[index])
+		ref := elementAt(parent, p.Index)
+		if ref == el {
+			return nil
+		}
+		if old := d.parents[el]; old != nil {
+			old.Children = removeChild(old.Children, el)
+		}
+		d.insertBefore(parent, ref, []*protocol.VNodeWire{el})
+
+	case protocol.PatchReplaceNode:
+		parent := d.parents[el]
+		d.unregister(el)
+		if parent == nil {
+			return nil
+		}
+		i := indexOf(parent.Children, el)
+		if i < 0 {
+			return nil
+		}
+		nodes := createNodes(p.Node)
+		parent.Children = append(parent.Children[:i], append(nodes, parent.Children[i+1:]...)...)
+		for _, n := range nodes {
+			d.register(n, parent)
+		}
+
+	case protocol.PatchSetValue:
+		setAttr(el, "value", p.Value)
+
+	case protocol.PatchSetChecked:
+		setAttr(el, "checked", fmt.Sprint(p.Bool))
+
+	case protocol.PatchSetSelected:
+		setAttr(el, "selected", fmt.Sprint(p.Bool))
+
+	case protocol.PatchFocus:
+		d.Focused = p.HID
+
+	case protocol.PatchBlur:
+		if d.Focused == p.HID {
+			d.Focused = ""
+		}
+	}
+	return nil
+}
+
+// elementAt returns the index-th element child of parent, or nil. Like
+// the DOM's children, the index counts elements only.
+func elementAt(parent *protocol.VNodeWire, index int) *protocol.VNodeWire {
+	for _, c := range parent.Children {
+		if c.Kind != vdom.KindElement {
+			continue
+		}
+		if index == 0 {
+			return c
+		}
+		index--
+	}
+	return nil
+}
+
+// insertBefore inserts nodes into parent before ref, or at the end if ref
+// is nil.
+func (d *Document) insertBefore(parent, ref *protocol.VNodeWire, nodes []*protocol.VNodeWire) {
+	at := indexOf(parent.Children, ref)
+	if ref == nil || at < 0 {
+		at = len(parent.Children)
+	}
+	parent.Children = append(parent.Children[:at], append(nodes, parent.Children[at:]...)...)
+	for _, n := range nodes {
+		d.register(n, parent)
+	}
+}
+
+// setHead adds or replaces a managed head element. Like the thin client,
+// only metadata elements are accepted.
+func (d *Document) setHead(key string, node *protocol.VNodeWire) error {
+	if node == nil || node.Kind != vdom.KindElement {
+		return nil
+	}
+	tag := strings.ToLower(node.Tag)
+	if tag != "meta" && tag != "link" && !(tag == "script" && node.Attrs["type"] == "application/ld+json") {
+		return fmt.Errorf("client: blocked head element %s", tag)
+	}
+
+	el := protocol.NewElementWire(tag, nil)
+	for name, value := range node.Attrs {
+		setAttr(el, name, value)
+	}
+	setAttr(el, "data-vango-head", key)
+	if tag == "script" {
+		text := ""
+		for _, c := range node.Children {
+			text += c.Text
+		}
+		el.Children = []*protocol.VNodeWire{protocol.NewTextWire(text)}
+	}
+	d.Head[key] = el
+	return nil
+}
+
+// createNodes returns the nodes a VNodeWire creates in the DOM: a
+// fragment creates its children and raw HTML the parsed markup.
+func createNodes(n *protocol.VNodeWire) []*protocol.VNodeWire {
+	if n == nil {
+		return nil
+	}
+	switch n.Kind {
+	case vdom.KindFragment:
+		var nodes []*protocol.VNodeWire
+		for _, c := range n.Children {
+			nodes = append(nodes, createNodes(c)...)
+		}
+		return nodes
+	case vdom.KindRaw:
+		return parseHTML(n.Text).Children
+	case vdom.KindText:
+		return []*protocol.VNodeWire{protocol.NewTextWire(n.Text)}
+	}
+
+	el := &protocol.VNodeWire{Kind: vdom.KindElement, Tag: n.Tag, HID: n.HID}
+	for name, value := range n.Attrs {
+		setAttr(el, name, value)
+	}
+	for _, c := range n.Children {
+		el.Children = append(el.Children, createNodes(c)...)
+	}
+	return []*protocol.VNodeWire{el}
+}
+
+// setAttr sets an attribute like the thin client: event handler
+// attributes are blocked, and boolean attributes and properties are set
+// for "true" and "" and removed otherwise.
+func setAttr(el *protocol.VNodeWire, key, value string) {
+	if len(key) > 2 && strings.EqualFold(key[:2], "on") {
+		return
+	}
+	switch key {
+	case "className":
+		key = "class"
+	case "htmlFor":
+		key = "for"
+	case "checked", "selected", "disabled", "readonly", "required", "multiple", "autofocus":
+		if value != "true" && value != "" {
+			delete(el.Attrs, key)
+			return
+		}
+		value = ""
+	}
+	if el.Attrs == nil {
+		el.Attrs = make(map[string]string)
+	}
+	el.Attrs[key] = value
+}
+
+// setStyle sets a property of a style attribute; an empty value removes
+// it.
+func setStyle(style, property, value string) string {
+	props := make(map[string]string)
+	var order []string
+	for _, decl := range strings.Split(style, ";") {
+		name, v, ok := strings.Cut(decl, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			continue
+		}
+		if _, seen := props[name]; !seen {
+			order = append(order, name)
+		}
+		props[name] = strings.TrimSpace(v)
+	}
+	if _, seen := props[property]; !seen && value != "" {
+		order = append(order, property)
+	}
+	props[property] = value
+
+	var decls []string
+	for _, name := range order {
+		if props[name] != "" {
+			decls = append(decls, name+": "+props[name])
+		}
+	}
+	return strings.Join(decls, "; ")
+}
+
+// kebab converts a camelCase property name, as used by element.style and
+// element.dataset, to its attribute form.
+func kebab(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if r >= 'A' && r <= 'Z' {
+			b.WriteByte('-')
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// removeChild removes n from children.
+func removeChild(children []*protocol.VNodeWire, n *protocol.VNodeWire) []*protocol.VNodeWire {
+	if i := indexOf(children, n); i >= 0 {
+		return append(children[:i], children[i+1:]...)
+	}
+	return children
+}
+
+func indexOf[T comparable](s []T, v T) int {
+	for i, e := range s {
+		if e == v {
+			return i
+		}
+	}
+	return -1
+}
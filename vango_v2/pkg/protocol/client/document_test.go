@@ -0,0 +1,241 @@
+package client
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vango-dev/vango/v2/pkg/protocol"
+	"github.com/vango-dev/vango/v2/pkg/vdom"
+)
+
+const testPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <title>Tom &amp; Jerry</title>
+  <meta name="description" content="Cartoons" data-vango-head="meta:description">
+  <style>p > b { color: red }</style>
+</head>
+<body>
+<div id="app" data-hid="h1"><!-- list -->
+<ul data-hid="h2"><li data-hid="h3" class="item done">Tom</li><li data-hid="h4" class="item">Jerry &lt;3</li></ul>
+<input data-hid="h5" type="checkbox" checked disabled/><br>
+<textarea data-hid="h6">a <b> c</textarea>
+</div>
+<script>window.__VANGO_CSRF__="token";</script>
+</body>
+</html>`
+
+func TestParseDocument(t *testing.T) {
+	d := ParseDocument(testPage)
+
+	if d.Title != "Tom & Jerry" {
+		t.Errorf("Title = %q", d.Title)
+	}
+	if meta := d.Head["meta:description"]; meta == nil || meta.Attrs["content"] != "Cartoons" {
+		t.Errorf("Head = %v", d.Head)
+	}
+	if got := strings.Join(d.HIDs(), ","); got != "h1,h2,h3,h4,h5,h6" {
+		t.Errorf("HIDs() = %s", got)
+	}
+
+	input := d.Node("h5")
+	if input.Tag != "input" || len(input.Children) != 0 {
+		t.Errorf("input = %+v, want a void element", input)
+	}
+	if _, ok := input.Attrs["checked"]; !ok || input.Attrs["type"] != "checkbox" {
+		t.Errorf("input attrs = %v", input.Attrs)
+	}
+	if _, ok := input.Attrs["data-hid"]; ok {
+		t.Error("data-hid should be the HID, not an attribute")
+	}
+	if got := TextContent(d.Node("h4")); got != "Jerry <3" {
+		t.Errorf("text = %q, want unescaped text", got)
+	}
+	if got := TextContent(d.Node("h6")); got != "a <b> c" {
+		t.Errorf("textarea = %q, want raw text", got)
+	}
+	if d.Parent(d.Node("h3")) != d.Node("h2") || d.Parent(d.Node("h1")) != d.Body {
+		t.Error("Parent() does not follow the tree")
+	}
+}
+
+func TestQuery(t *testing.T) {
+	d := ParseDocument(testPage)
+
+	tests := []struct {
+		selector string
+		want     []string
+	}{
+		{"li", []string{"h3", "h4"}},
+		{"#app li.item.done", []string{"h3"}},
+		{"ul > li", []string{"h3", "h4"}},
+		{"div > li", nil},
+		{"div li", []string{"h3", "h4"}},
+		{"input[type=checkbox][disabled]", []string{"h5"}},
+		{`[data-hid="h4"]`, []string{"h4"}},
+		{"#app > *", []string{"h2", "h5", "", "h6"}},
+		{"li.missing", nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, n := range d.QueryAll(tt.selector) {
+			got = append(got, n.HID)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("QueryAll(%q) = %v, want %v", tt.selector, got, tt.want)
+		}
+	}
+
+	if n := d.Query("li"); n == nil || n.HID != "h3" {
+		t.Errorf("Query(li) = %v, want the first item", n)
+	}
+
+	for _, selector := range []string{"", "> li", "li >", "li[", "li.", "a*"} {
+		if _, err := parseSelector(selector); err == nil {
+			t.Errorf("parseSelector(%q) should fail", selector)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	d := ParseDocument(`<body><div data-hid="h1">text<p data-hid="h2">a</p><p data-hid="h3">b</p></div></body>`)
+
+	apply := func(p protocol.Patch) {
+		t.Helper()
+		if err := d.Apply(p); err != nil {
+			t.Fatalf("Apply(%s) error: %v", p.Op, err)
+		}
+	}
+	children := func(hid string) string {
+		var parts []string
+		for _, c := range d.Node(hid).Children {
+			if c.Kind == vdom.KindText {
+				parts = append(parts, "'"+c.Text+"'")
+			} else {
+				parts = append(parts, c.HID)
+			}
+		}
+		return strings.Join(parts, " ")
+	}
+
+	// Indexes count elements only, like parentEl.children
+	apply(protocol.NewInsertNodePatch("h4", "h1", 1, &protocol.VNodeWire{
+		Kind: vdom.KindElement, Tag: "p", HID: "h4",
+		Attrs:    map[string]string{"className": "new", "onclick": "alert(1)"},
+		Children: []*protocol.VNodeWire{protocol.NewTextWire("c")},
+	}))
+	if got := children("h1"); got != "'text' h2 h4 h3" {
+		t.Fatalf("after insert: %s", got)
+	}
+	if attrs := d.Node("h4").Attrs; attrs["class"] != "new" || attrs["onclick"] != "" {
+		t.Errorf("inserted attrs = %v", attrs)
+	}
+
+	apply(protocol.NewMoveNodePatch("h2", "h1", 2))
+	if got := children("h1"); got != "'text' h4 h2 h3" {
+		t.Errorf("after move: %s", got)
+	}
+	apply(protocol.NewMoveNodePatch("h3", "h1", 0))
+	if got := children("h1"); got != "'text' h3 h4 h2" {
+		t.Errorf("after move to front: %s", got)
+	}
+
+	apply(protocol.NewReplaceNodePatch("h4", protocol.NewFragmentWire(
+		&protocol.VNodeWire{Kind: vdom.KindElement, Tag: "b", HID: "h5"},
+		protocol.NewRawWire(`<i data-hid="h6">raw</i>`),
+	)))
+	if got := children("h1"); got != "'text' h3 h5 h6 h2" || d.Node("h4") != nil {
+		t.Errorf("after replace: %s", got)
+	}
+
+	apply(protocol.NewRemoveNodePatch("h2"))
+	if d.Node("h2") != nil || children("h1") != "'text' h3 h5 h6" {
+		t.Errorf("after remove: %s", children("h1"))
+	}
+
+	// SetText replaces the children, unregistering their HIDs
+	apply(protocol.NewSetTextPatch("h1", "plain"))
+	if d.Node("h3") != nil || TextContent(d.Node("h1")) != "plain" {
+		t.Errorf("after set text: %s", children("h1"))
+	}
+
+	el := d.Node("h1")
+	apply(protocol.NewSetAttrPatch("h1", "class", "a b"))
+	apply(protocol.NewAddClassPatch("h1", "c"))
+	apply(protocol.NewRemoveClassPatch("h1", "a"))
+	apply(protocol.NewToggleClassPatch("h1", "b"))
+	apply(protocol.NewToggleClassPatch("h1", "d"))
+	if el.Attrs["class"] != "c d" {
+		t.Errorf("class = %q", el.Attrs["class"])
+	}
+
+	apply(protocol.NewSetStylePatch("h1", "color", "red"))
+	apply(protocol.NewSetStylePatch("h1", "fontSize", "2em"))
+	apply(protocol.NewSetStylePatch("h1", "color", "blue"))
+	apply(protocol.NewRemoveStylePatch("h1", "fontSize"))
+	apply(protocol.NewSetStylePatch("h1", "margin", "0"))
+	if el.Attrs["style"] != "color: blue; margin: 0" {
+		t.Errorf("style = %q", el.Attrs["style"])
+	}
+
+	apply(protocol.NewSetDataPatch("h1", "userId", "7"))
+	apply(protocol.NewSetValuePatch("h1", "typed"))
+	apply(protocol.NewSetCheckedPatch("h1", true))
+	apply(protocol.NewSetAttrPatch("h1", "disabled", "false"))
+	if el.Attrs["data-user-id"] != "7" || el.Attrs["value"] != "typed" || el.Attrs["checked"] != "" {
+		t.Errorf("attrs = %v", el.Attrs)
+	}
+	if _, ok := el.Attrs["disabled"]; ok {
+		t.Error("disabled=false should remove the attribute")
+	}
+	apply(protocol.NewSetCheckedPatch("h1", false))
+	if _, ok := el.Attrs["checked"]; ok {
+		t.Error("SetChecked(false) should remove checked")
+	}
+
+	apply(protocol.NewFocusPatch("h1"))
+	if d.Focused != "h1" {
+		t.Errorf("Focused = %q", d.Focused)
+	}
+	apply(protocol.NewBlurPatch("h1"))
+	if d.Focused != "" {
+		t.Errorf("Focused = %q after blur", d.Focused)
+	}
+
+	apply(protocol.NewSetTitlePatch("Updated"))
+	apply(protocol.NewSetHeadPatch("meta:robots", &protocol.VNodeWire{Kind: vdom.KindElement, Tag: "meta", Attrs: map[string]string{"name": "robots"}}))
+	if d.Title != "Updated" || d.Head["meta:robots"].Attrs["data-vango-head"] != "meta:robots" {
+		t.Errorf("head = %q %v", d.Title, d.Head)
+	}
+	if err := d.Apply(protocol.NewSetHeadPatch("x", &protocol.VNodeWire{Kind: vdom.KindElement, Tag: "script"})); err == nil {
+		t.Error("scripts should be blocked in the head")
+	}
+	apply(protocol.NewRemoveHeadPatch("meta:robots"))
+	if len(d.Head) != 0 {
+		t.Errorf("Head = %v after remove", d.Head)
+	}
+
+	apply(protocol.NewURLPushPatch(map[string]string{"q": "go", "page": "2"}))
+	apply(protocol.NewURLReplacePatch(map[string]string{"page": ""}))
+	if d.URL.String() != "/?q=go" {
+		t.Errorf("URL = %s", d.URL)
+	}
+
+	if err := d.Apply(protocol.NewSetTextPatch("h99", "x")); err == nil {
+		t.Error("Apply() should fail for a missing node")
+	}
+}
+
+func TestClone(t *testing.T) {
+	d := ParseDocument(testPage)
+	c := d.Clone()
+
+	c.Node("h3").Attrs["class"] = "changed"
+	c.Apply(protocol.NewRemoveNodePatch("h4"))
+	if d.Node("h3").Attrs["class"] != "item done" || d.Node("h4") == nil {
+		t.Error("changes to a clone should not affect the document")
+	}
+	if c.Title != d.Title || c.Node("h2") == nil {
+		t.Error("clone should have the content of the document")
+	}
+}
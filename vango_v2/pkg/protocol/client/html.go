@@ -0,0 +1,219 @@
+package client
+
+import (
+	"html"
+	"strings"
+
+	"github.com/vango-dev/vango/v2/pkg/protocol"
+	"github.com/vango-dev/vango/v2/pkg/vdom"
+)
+
+// voidElements have no closing tag.
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true,
+	"hr": true, "img": true, "input": true, "link": true, "meta": true,
+	"param": true, "source": true, "track": true, "wbr": true,
+}
+
+// rawTextElements contain text up to their closing tag, without markup.
+var rawTextElements = map[string]bool{
+	"script": true, "style": true, "textarea": true, "title": true,
+}
+
+// parseHTML parses HTML as written by the server renderer into a fragment
+// of nodes. Elements get their HID from the data-hid attribute.
+//
+// It is not a conforming HTML parser: end tags close the nearest open
+// element with the same name, and optional end tags are not inferred. The
+// renderer always closes elements, so this is enough for its output.
+func parseHTML(src string) *protocol.VNodeWire {
+	root := protocol.NewFragmentWire()
+	stack := []*protocol.VNodeWire{root}
+	top := func() *protocol.VNodeWire { return stack[len(stack)-1] }
+
+	appendText := func(text string) {
+		if text == "" {
+			return
+		}
+		parent := top()
+		if n := len(parent.Children); n > 0 && parent.Children[n-1].Kind == vdom.KindText {
+			parent.Children[n-1].Text += text
+			return
+		}
+		parent.Children = append(parent.Children, protocol.NewTextWire(text))
+	}
+
+	for i := 0; i < len(src); {
+		lt := strings.IndexByte(src[i:], '<')
+		if lt < 0 {
+			appendText(html.UnescapeString(src[i:]))
+			break
+		}
+		appendText(html.UnescapeString(src[i : i+lt]))
+		i += lt
+		rest := src[i:]
+
+		switch {
+		case strings.HasPrefix(rest, "<!--"):
+			end := strings.Index(rest[4:], "-->")
+			if end < 0 {
+				return root
+			}
+			i += 4 + end + 3
+
+		case strings.HasPrefix(rest, "<!"), strings.HasPrefix(rest, "<?"):
+			end := strings.IndexByte(rest, '>')
+			if end < 0 {
+				return root
+			}
+			i += end + 1
+
+		case strings.HasPrefix(rest, "</"):
+			end := strings.IndexByte(rest, '>')
+			if end < 0 {
+				return root
+			}
+			name := strings.ToLower(strings.TrimSpace(rest[2:end]))
+			for j := len(stack) - 1; j > 0; j-- {
+				if stack[j].Tag == name {
+					stack = stack[:j]
+					break
+				}
+			}
+			i += end + 1
+
+		default:
+			el, n, selfClosing := parseStartTag(rest)
+			if el == nil {
+				appendText("<")
+				i++
+				continue
+			}
+			i += n
+			top().Children = append(top().Children, el)
+
+			switch {
+			case rawTextElements[el.Tag]:
+				end := indexFold(src[i:], "</"+el.Tag)
+				if end < 0 {
+					end = len(src) - i
+				}
+				text := src[i : i+end]
+				if el.Tag == "textarea" || el.Tag == "title" {
+					text = html.UnescapeString(text)
+				}
+				if text != "" {
+					el.Children = append(el.Children, protocol.NewTextWire(text))
+				}
+				i += end
+				if close := strings.IndexByte(src[i:], '>'); close >= 0 {
+					i += close + 1
+				}
+			case !selfClosing && !voidElements[el.Tag]:
+				stack = append(stack, el)
+			}
+		}
+	}
+	return root
+}
+
+// parseStartTag parses the start tag at the beginning of s. It returns the
+// element, the length of the tag and whether it is self-closing; nil if s
+// does not start with a tag.
+func parseStartTag(s string) (*protocol.VNodeWire, int, bool) {
+	i := 1
+	start := i
+	for i < len(s) && isNameChar(s[i]) {
+		i++
+	}
+	if i == start || !isLetter(s[start]) {
+		return nil, 0, false
+	}
+	el := &protocol.VNodeWire{Kind: vdom.KindElement, Tag: strings.ToLower(s[start:i])}
+
+	for i < len(s) {
+		for i < len(s) && isSpace(s[i]) {
+			i++
+		}
+		if i >= len(s) {
+			break
+		}
+		switch {
+		case s[i] == '>':
+			return el, i + 1, false
+		case strings.HasPrefix(s[i:], "/>"):
+			return el, i + 2, true
+		case s[i] == '/':
+			i++
+			continue
+		}
+
+		start := i
+		for i < len(s) && !isSpace(s[i]) && s[i] != '=' && s[i] != '>' && !strings.HasPrefix(s[i:], "/>") {
+			i++
+		}
+		name := strings.ToLower(s[start:i])
+		for i < len(s) && isSpace(s[i]) {
+			i++
+		}
+
+		value := ""
+		if i < len(s) && s[i] == '=' {
+			i++
+			for i < len(s) && isSpace(s[i]) {
+				i++
+			}
+			if i < len(s) && (s[i] == '"' || s[i] == '\'') {
+				quote := s[i]
+				end := strings.IndexByte(s[i+1:], quote)
+				if end < 0 {
+					return nil, 0, false
+				}
+				value = s[i+1 : i+1+end]
+				i += end + 2
+			} else {
+				start := i
+				for i < len(s) && !isSpace(s[i]) && s[i] != '>' {
+					i++
+				}
+				value = s[start:i]
+			}
+			value = html.UnescapeString(value)
+		}
+
+		switch name {
+		case "":
+			continue
+		case "data-hid":
+			el.HID = value
+			continue
+		}
+		if el.Attrs == nil {
+			el.Attrs = make(map[string]string)
+		}
+		el.Attrs[name] = value
+	}
+	return nil, 0, false
+}
+
+// indexFold is strings.Index ignoring ASCII case.
+func indexFold(s, substr string) int {
+	for i := 0; i+len(substr) <= len(s); i++ {
+		if strings.EqualFold(s[i:i+len(substr)], substr) {
+			return i
+		}
+	}
+	return -1
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isNameChar(c byte) bool {
+	return isLetter(c) || c >= '0' && c <= '9' || c == '-' || c == '_' || c == ':'
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
@@ -0,0 +1,209 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vango-dev/vango/v2/pkg/protocol"
+	"github.com/vango-dev/vango/v2/pkg/vdom"
+)
+
+// Query returns the first element of the body matching a selector, or nil.
+//
+// Selectors are a subset of CSS: compound selectors of a tag, #id, .class
+// and [attr], [attr=value] conditions, combined with descendant (space)
+// and child (>) combinators, e.g. "form#login > button.primary" or
+// "li[data-done=true]". [data-hid=h3] matches the element with HID h3.
+// Query panics if the selector is invalid.
+func (d *Document) Query(selector string) *protocol.VNodeWire {
+	if all := d.query(selector, true); len(all) > 0 {
+		return all[0]
+	}
+	return nil
+}
+
+// QueryAll returns the elements of the body matching a selector, in
+// document order. See Query for the selector syntax.
+func (d *Document) QueryAll(selector string) []*protocol.VNodeWire {
+	return d.query(selector, false)
+}
+
+func (d *Document) query(selector string, first bool) []*protocol.VNodeWire {
+	sel, err := parseSelector(selector)
+	if err != nil {
+		panic(err)
+	}
+
+	var matches []*protocol.VNodeWire
+	walk(d.Body, func(n *protocol.VNodeWire) bool {
+		if n != d.Body && d.matches(n, sel) {
+			matches = append(matches, n)
+		}
+		return !first || len(matches) == 0
+	})
+	return matches
+}
+
+// TextContent returns the text of n and its descendants.
+func TextContent(n *protocol.VNodeWire) string {
+	if n == nil {
+		return ""
+	}
+	if n.Kind == vdom.KindText {
+		return n.Text
+	}
+	var b strings.Builder
+	for _, c := range n.Children {
+		b.WriteString(TextContent(c))
+	}
+	return b.String()
+}
+
+// selector is a parsed selector: compound selectors from left to right,
+// each with the combinator joining it to the previous one.
+type selector []compound
+
+type compound struct {
+	child bool // joined with ">" instead of a space
+	tag   string
+	id    string
+	class []string
+	attrs []attrCond
+}
+
+type attrCond struct {
+	name     string
+	value    string
+	hasValue bool
+}
+
+// parseSelector parses a selector.
+func parseSelector(s string) (selector, error) {
+	var sel selector
+	child := false
+	for i := 0; i < len(s); {
+		switch {
+		case isSpace(s[i]):
+			i++
+			continue
+		case s[i] == '>':
+			if len(sel) == 0 || child {
+				return nil, fmt.Errorf("client: invalid selector %q", s)
+			}
+			child = true
+			i++
+			continue
+		}
+
+		c := compound{child: child}
+		child = false
+		start := i
+		for i < len(s) && !isSpace(s[i]) && s[i] != '>' {
+			switch s[i] {
+			case '#', '.':
+				kind := s[i]
+				i++
+				name := i
+				for i < len(s) && isNameChar(s[i]) {
+					i++
+				}
+				if i == name {
+					return nil, fmt.Errorf("client: invalid selector %q", s)
+				}
+				if kind == '#' {
+					c.id = s[name:i]
+				} else {
+					c.class = append(c.class, s[name:i])
+				}
+			case '[':
+				end := strings.IndexByte(s[i:], ']')
+				if end < 0 {
+					return nil, fmt.Errorf("client: invalid selector %q", s)
+				}
+				cond := attrCond{name: strings.TrimSpace(s[i+1 : i+end])}
+				if name, value, ok := strings.Cut(cond.name, "="); ok {
+					cond.name = strings.TrimSpace(name)
+					cond.value = strings.Trim(strings.TrimSpace(value), `"'`)
+					cond.hasValue = true
+				}
+				if cond.name == "" {
+					return nil, fmt.Errorf("client: invalid selector %q", s)
+				}
+				c.attrs = append(c.attrs, cond)
+				i += end + 1
+			case '*':
+				if i != start {
+					return nil, fmt.Errorf("client: invalid selector %q", s)
+				}
+				c.tag = "*"
+				i++
+			default:
+				if i != start || !isNameChar(s[i]) {
+					return nil, fmt.Errorf("client: invalid selector %q", s)
+				}
+				for i < len(s) && isNameChar(s[i]) {
+					i++
+				}
+				c.tag = strings.ToLower(s[start:i])
+			}
+		}
+		sel = append(sel, c)
+	}
+	if len(sel) == 0 || child {
+		return nil, fmt.Errorf("client: invalid selector %q", s)
+	}
+	return sel, nil
+}
+
+// matches reports whether n matches sel.
+func (d *Document) matches(n *protocol.VNodeWire, sel selector) bool {
+	last := len(sel) - 1
+	if !sel[last].matches(n) {
+		return false
+	}
+	if last == 0 {
+		return true
+	}
+
+	// Match the rest of the selector against the ancestors
+	rest := sel[:last]
+	for p := d.parents[n]; p != nil && p != d.Body; p = d.parents[p] {
+		if d.matches(p, rest) {
+			return true
+		}
+		if sel[last].child {
+			return false
+		}
+	}
+	return false
+}
+
+func (c compound) matches(n *protocol.VNodeWire) bool {
+	if n.Kind != vdom.KindElement {
+		return false
+	}
+	if c.tag != "" && c.tag != "*" && c.tag != n.Tag {
+		return false
+	}
+	if c.id != "" && n.Attrs["id"] != c.id {
+		return false
+	}
+	if len(c.class) > 0 {
+		classes := strings.Fields(n.Attrs["class"])
+		for _, class := range c.class {
+			if indexOf(classes, class) < 0 {
+				return false
+			}
+		}
+	}
+	for _, cond := range c.attrs {
+		value, ok := n.Attrs[cond.name]
+		if cond.name == "data-hid" {
+			value, ok = n.HID, n.HID != ""
+		}
+		if !ok || cond.hasValue && value != cond.value {
+			return false
+		}
+	}
+	return true
+}
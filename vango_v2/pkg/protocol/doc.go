@@ -29,6 +29,7 @@
 //   - FrameControl (0x03): Control messages (ping, resync)
 //   - FrameAck (0x04): Acknowledgment
 //   - FrameError (0x05): Error message
+//   - FrameBlob (0x06): Binary blob transfer, in both directions
 //
 // # Encoding
 //
@@ -73,6 +74,15 @@
 //	  │     (status, session, time)   │
 //	  │                                │
 //
+// The client announces its protocol version and Capabilities, a bitmap of
+// the optional patch ops and frame flags it supports. The server accepts
+// the versions of its VersionPolicy, by default the current version and one
+// minor version behind, and answers with the negotiated version and the
+// capabilities both sides support. Clients older than the policy are sent
+// HandshakeUpgradeRequired and reload the page to get a current bundle.
+// Patches a client cannot apply are dropped by EncodePatchesFor, so older
+// bundles keep working during a rolling deploy.
+//
 // # Control Messages
 //
 //   - Ping/Pong: Heartbeat for connection health
@@ -80,6 +90,35 @@
 //   - ResyncPatches/ResyncFull: Server response with missed data
 //   - Close: Graceful session termination
 //
+// # Blobs
+//
+// Clients with CapBinaryBlobs exchange binary data on the live connection
+// in FrameBlob frames: files dropped on an element, and binaries generated
+// by the server. A blob is opened, sent in chunks of at most BlobChunkSize
+// bytes and ended. Senders keep at most BlobWindow bytes in flight; the
+// receiver grants more with BlobCredit as it consumes the data. Either side
+// may abort a blob.
+//
+// # Client Calls
+//
+// Clients with CapClientCalls run functions registered in the thin client
+// on request of the server. A Call patch carries a call ID, the name of the
+// function and its arguments as a JSON array; the client answers with an
+// EventCallResult carrying the same ID and the JSON result or an error.
+// There is no way to send code: only registered functions can be called.
+//
+// # Session Recordings
+//
+// RecordingWriter writes the messages of a session, with timestamps, in a
+// compact format read by RecordingReader. The server records sessions
+// when ServerConfig.RecordSession is set; `vango replay` re-drives them.
+//
+// # Go Client
+//
+// Package protocol/client implements the client side of the protocol in
+// Go: it performs the handshake, mirrors the page by applying patches
+// frames and sends typed events, for integration tests and bots.
+//
 // # Usage Example
 //
 //	// Encode an event
@@ -132,4 +171,6 @@
 //   - control.go: Control messages
 //   - ack.go: Acknowledgment
 //   - error.go: Error messages
+//   - blob.go: Blob transfer messages
+//   - recording.go: Session recordings
 package protocol
@@ -31,9 +31,10 @@ const (
 	EventKeyUp    EventType = 0x21
 	EventKeyPress EventType = 0x22
 
-	// Scroll/Resize events (0x30-0x31)
+	// Scroll/Resize events (0x30-0x32)
 	EventScroll EventType = 0x30
 	EventResize EventType = 0x31
+	EventMedia  EventType = 0x32 // Media query change (color scheme, motion, pixel ratio)
 
 	// Touch events (0x40-0x42)
 	EventTouchStart EventType = 0x40
@@ -46,9 +47,10 @@ const (
 	EventDrop      EventType = 0x52
 
 	// Special events (0x60+)
-	EventHook     EventType = 0x60 // Client hook event
-	EventNavigate EventType = 0x70 // Navigation request
-	EventCustom   EventType = 0xFF // Custom event
+	EventHook       EventType = 0x60 // Client hook event
+	EventCallResult EventType = 0x61 // Result of a client call
+	EventNavigate   EventType = 0x70 // Navigation request
+	EventCustom     EventType = 0xFF // Custom event
 )
 
 // String returns the string representation of the event type.
@@ -88,6 +90,8 @@ func (et EventType) String() string {
 		return "Scroll"
 	case EventResize:
 		return "Resize"
+	case EventMedia:
+		return "Media"
 	case EventTouchStart:
 		return "TouchStart"
 	case EventTouchMove:
@@ -102,6 +106,8 @@ func (et EventType) String() string {
 		return "Drop"
 	case EventHook:
 		return "Hook"
+	case EventCallResult:
+		return "CallResult"
 	case EventNavigate:
 		return "Navigate"
 	case EventCustom:
@@ -159,6 +165,14 @@ type ResizeEventData struct {
 	Height int
 }
 
+// MediaEventData contains the media query preferences of the client,
+// sent when one of them changes.
+type MediaEventData struct {
+	ColorScheme   ColorScheme
+	ReducedMotion bool
+	PixelRatio    uint16 // Device pixel ratio × 100
+}
+
 // TouchPoint represents a single touch point.
 type TouchPoint struct {
 	ID      int
@@ -190,6 +204,15 @@ type HookEventData struct {
 	Data map[string]any
 }
 
+// CallResultData contains the result of a client call started by a Call
+// patch. Value is the JSON result if OK is true, and the error message
+// otherwise.
+type CallResultData struct {
+	ID    uint64
+	OK    bool
+	Value string
+}
+
 // NavigateEventData contains navigation event data.
 type NavigateEventData struct {
 	Path    string
@@ -299,6 +322,15 @@ func EncodeEventTo(enc *Encoder, e *Event) {
 			enc.WriteSvarint(int64(data.Height))
 		}
 
+	case EventMedia:
+		data, ok := e.Payload.(*MediaEventData)
+		if !ok || data == nil {
+			data = &MediaEventData{}
+		}
+		enc.WriteByte(byte(data.ColorScheme))
+		enc.WriteBool(data.ReducedMotion)
+		enc.WriteUint16(data.PixelRatio)
+
 	case EventTouchStart, EventTouchMove, EventTouchEnd:
 		data, ok := e.Payload.(*TouchEventData)
 		if !ok || data == nil {
@@ -335,6 +367,15 @@ func EncodeEventTo(enc *Encoder, e *Event) {
 			encodeHookData(enc, data.Data)
 		}
 
+	case EventCallResult:
+		data, ok := e.Payload.(*CallResultData)
+		if !ok || data == nil {
+			data = &CallResultData{}
+		}
+		enc.WriteUvarint(data.ID)
+		enc.WriteBool(data.OK)
+		enc.WriteString(data.Value)
+
 	case EventNavigate:
 		data, ok := e.Payload.(*NavigateEventData)
 		if !ok || data == nil {
@@ -533,6 +574,25 @@ func DecodeEventFrom(d *Decoder) (*Event, error) {
 			Height: int(h),
 		}
 
+	case EventMedia:
+		scheme, err := d.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		reduced, err := d.ReadBool()
+		if err != nil {
+			return nil, err
+		}
+		ratio, err := d.ReadUint16()
+		if err != nil {
+			return nil, err
+		}
+		e.Payload = &MediaEventData{
+			ColorScheme:   ColorScheme(scheme),
+			ReducedMotion: reduced,
+			PixelRatio:    ratio,
+		}
+
 	case EventTouchStart, EventTouchMove, EventTouchEnd:
 		count, err := d.ReadCollectionCount()
 		if err != nil {
@@ -590,6 +650,21 @@ func DecodeEventFrom(d *Decoder) (*Event, error) {
 		}
 		e.Payload = &HookEventData{Name: name, Data: data}
 
+	case EventCallResult:
+		id, err := d.ReadUvarint()
+		if err != nil {
+			return nil, err
+		}
+		ok, err := d.ReadBool()
+		if err != nil {
+			return nil, err
+		}
+		value, err := d.ReadString()
+		if err != nil {
+			return nil, err
+		}
+		e.Payload = &CallResultData{ID: id, OK: ok, Value: value}
+
 	case EventNavigate:
 		path, err := d.ReadString()
 		if err != nil {
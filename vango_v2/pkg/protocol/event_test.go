@@ -133,6 +133,26 @@ func TestEventEncodeDecode(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "media",
+			event: &Event{
+				Seq:  11,
+				Type: EventMedia,
+				Payload: &MediaEventData{
+					ColorScheme:   ColorSchemeDark,
+					ReducedMotion: true,
+					PixelRatio:    200,
+				},
+			},
+		},
+		{
+			name: "call_result",
+			event: &Event{
+				Seq:     11,
+				Type:    EventCallResult,
+				Payload: &CallResultData{ID: 42, OK: true, Value: `{"width":120.5}`},
+			},
+		},
 		{
 			name: "touchstart",
 			event: &Event{
@@ -330,6 +350,26 @@ func verifyPayload(t *testing.T, _ string, got, want any) {
 			t.Errorf("Size = (%d,%d), want (%d,%d)", g.Width, g.Height, w.Width, w.Height)
 		}
 
+	case *MediaEventData:
+		g, ok := got.(*MediaEventData)
+		if !ok {
+			t.Errorf("Payload type = %T, want *MediaEventData", got)
+			return
+		}
+		if *g != *w {
+			t.Errorf("Media = %+v, want %+v", g, w)
+		}
+
+	case *CallResultData:
+		g, ok := got.(*CallResultData)
+		if !ok {
+			t.Errorf("Payload type = %T, want *CallResultData", got)
+			return
+		}
+		if *g != *w {
+			t.Errorf("CallResult = %+v, want %+v", g, w)
+		}
+
 	case *TouchEventData:
 		g, ok := got.(*TouchEventData)
 		if !ok {
@@ -411,6 +451,8 @@ func TestEventTypeString(t *testing.T) {
 		{EventKeyPress, "KeyPress"},
 		{EventScroll, "Scroll"},
 		{EventResize, "Resize"},
+		{EventMedia, "Media"},
+		{EventCallResult, "CallResult"},
 		{EventTouchStart, "TouchStart"},
 		{EventTouchMove, "TouchMove"},
 		{EventTouchEnd, "TouchEnd"},
@@ -24,6 +24,7 @@ const (
 	FrameControl   FrameType = 0x03 // Control messages (ping, etc.)
 	FrameAck       FrameType = 0x04 // Acknowledgment
 	FrameError     FrameType = 0x05 // Error message
+	FrameBlob      FrameType = 0x06 // Binary blob transfer (both directions)
 )
 
 // String returns the string representation of the frame type.
@@ -41,6 +42,8 @@ func (ft FrameType) String() string {
 		return "Ack"
 	case FrameError:
 		return "Error"
+	case FrameBlob:
+		return "Blob"
 	default:
 		return "Unknown"
 	}
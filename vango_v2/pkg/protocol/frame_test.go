@@ -207,6 +207,7 @@ func TestFrameTypeString(t *testing.T) {
 		{FrameControl, "Control"},
 		{FrameAck, "Ack"},
 		{FrameError, "Error"},
+		{FrameBlob, "Blob"},
 		{FrameType(0xFF), "Unknown"},
 	}
 
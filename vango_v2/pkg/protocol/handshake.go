@@ -48,7 +48,8 @@ type ProtocolVersion struct {
 }
 
 // CurrentVersion is the current protocol version.
-var CurrentVersion = ProtocolVersion{Major: 2, Minor: 0}
+// Version 2.1 added capabilities to the handshake and the head patches.
+var CurrentVersion = ProtocolVersion{Major: 2, Minor: 1}
 
 // ClientHello is sent by the client after WebSocket connection is established.
 type ClientHello struct {
@@ -59,6 +60,38 @@ type ClientHello struct {
 	ViewportW uint16          // Viewport width
 	ViewportH uint16          // Viewport height
 	TZOffset  int16           // Timezone offset in minutes from UTC
+
+	// Capabilities are the optional features the client supports.
+	// Clients of protocol 2.0 do not send them; see VersionCapabilities.
+	Capabilities Capabilities
+
+	// Client environment, sent since 2.1
+	TimeZone      string      // IANA time zone, e.g. "Europe/Paris"
+	Locale        string      // Preferred locale, e.g. "fr-FR"
+	ColorScheme   ColorScheme // Preferred color scheme
+	ReducedMotion bool        // Prefers reduced motion
+	PixelRatio    uint16      // Device pixel ratio × 100
+}
+
+// ColorScheme is the color scheme preferred by the client.
+type ColorScheme uint8
+
+const (
+	ColorSchemeNoPreference ColorScheme = 0x00
+	ColorSchemeLight        ColorScheme = 0x01
+	ColorSchemeDark         ColorScheme = 0x02
+)
+
+// String returns the CSS name of the color scheme, or "" for no preference.
+func (cs ColorScheme) String() string {
+	switch cs {
+	case ColorSchemeLight:
+		return "light"
+	case ColorSchemeDark:
+		return "dark"
+	default:
+		return ""
+	}
 }
 
 // ServerHello is the server's response to ClientHello.
@@ -68,6 +101,12 @@ type ServerHello struct {
 	NextSeq    uint32          // Next expected sequence number
 	ServerTime uint64          // Server time in Unix milliseconds
 	Flags      uint16          // Server capability flags
+
+	// Version is the negotiated protocol version and Capabilities the
+	// features both sides support. They follow the fields of protocol 2.0,
+	// which older clients ignore.
+	Version      ProtocolVersion
+	Capabilities Capabilities
 }
 
 // Server capability flags.
@@ -94,6 +133,12 @@ func EncodeClientHelloTo(e *Encoder, ch *ClientHello) {
 	e.WriteUint16(ch.ViewportW)
 	e.WriteUint16(ch.ViewportH)
 	e.WriteInt16(ch.TZOffset)
+	e.WriteUint32(uint32(ch.Capabilities))
+	e.WriteString(ch.TimeZone)
+	e.WriteString(ch.Locale)
+	e.WriteByte(byte(ch.ColorScheme))
+	e.WriteBool(ch.ReducedMotion)
+	e.WriteUint16(ch.PixelRatio)
 }
 
 // DecodeClientHello decodes a ClientHello from bytes.
@@ -147,6 +192,42 @@ func DecodeClientHelloFrom(d *Decoder) (*ClientHello, error) {
 		return nil, err
 	}
 
+	// Capabilities are absent from 2.0 hellos
+	if d.EOF() {
+		ch.Capabilities = VersionCapabilities(ch.Version)
+		return ch, nil
+	}
+	caps, err := d.ReadUint32()
+	if err != nil {
+		return nil, err
+	}
+	ch.Capabilities = Capabilities(caps)
+
+	if d.EOF() {
+		return ch, nil
+	}
+	ch.TimeZone, err = d.ReadString()
+	if err != nil {
+		return nil, err
+	}
+	ch.Locale, err = d.ReadString()
+	if err != nil {
+		return nil, err
+	}
+	scheme, err := d.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	ch.ColorScheme = ColorScheme(scheme)
+	ch.ReducedMotion, err = d.ReadBool()
+	if err != nil {
+		return nil, err
+	}
+	ch.PixelRatio, err = d.ReadUint16()
+	if err != nil {
+		return nil, err
+	}
+
 	return ch, nil
 }
 
@@ -164,6 +245,9 @@ func EncodeServerHelloTo(e *Encoder, sh *ServerHello) {
 	e.WriteUint32(sh.NextSeq)
 	e.WriteUint64(sh.ServerTime)
 	e.WriteUint16(sh.Flags)
+	e.WriteByte(sh.Version.Major)
+	e.WriteByte(sh.Version.Minor)
+	e.WriteUint32(uint32(sh.Capabilities))
 }
 
 // DecodeServerHello decodes a ServerHello from bytes.
@@ -203,30 +287,54 @@ func DecodeServerHelloFrom(d *Decoder) (*ServerHello, error) {
 		return nil, err
 	}
 
+	// Servers of protocol 2.0 end here
+	if d.EOF() {
+		sh.Version = ProtocolVersion{Major: 2, Minor: 0}
+		return sh, nil
+	}
+	major, err := d.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	minor, err := d.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	sh.Version = ProtocolVersion{Major: major, Minor: minor}
+	caps, err := d.ReadUint32()
+	if err != nil {
+		return nil, err
+	}
+	sh.Capabilities = Capabilities(caps)
+
 	return sh, nil
 }
 
 // NewClientHello creates a new ClientHello with default version.
 func NewClientHello(csrfToken string) *ClientHello {
 	return &ClientHello{
-		Version:   CurrentVersion,
-		CSRFToken: csrfToken,
+		Version:      CurrentVersion,
+		CSRFToken:    csrfToken,
+		Capabilities: SupportedCapabilities,
 	}
 }
 
 // NewServerHello creates a new successful ServerHello.
 func NewServerHello(sessionID string, nextSeq uint32, serverTime uint64) *ServerHello {
 	return &ServerHello{
-		Status:     HandshakeOK,
-		SessionID:  sessionID,
-		NextSeq:    nextSeq,
-		ServerTime: serverTime,
+		Status:       HandshakeOK,
+		SessionID:    sessionID,
+		NextSeq:      nextSeq,
+		ServerTime:   serverTime,
+		Version:      CurrentVersion,
+		Capabilities: SupportedCapabilities,
 	}
 }
 
 // NewServerHelloError creates a ServerHello with an error status.
 func NewServerHelloError(status HandshakeStatus) *ServerHello {
 	return &ServerHello{
-		Status: status,
+		Status:  status,
+		Version: CurrentVersion,
 	}
 }
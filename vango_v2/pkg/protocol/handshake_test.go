@@ -33,6 +33,21 @@ func TestClientHelloEncodeDecode(t *testing.T) {
 				TZOffset:  60, // UTC+1
 			},
 		},
+		{
+			name: "environment",
+			hello: &ClientHello{
+				Version:       CurrentVersion,
+				CSRFToken:     "token",
+				ViewportW:     390,
+				ViewportH:     844,
+				TZOffset:      120,
+				TimeZone:      "Europe/Paris",
+				Locale:        "fr-FR",
+				ColorScheme:   ColorSchemeDark,
+				ReducedMotion: true,
+				PixelRatio:    300,
+			},
+		},
 		{
 			name: "minimal",
 			hello: &ClientHello{
@@ -71,6 +86,13 @@ func TestClientHelloEncodeDecode(t *testing.T) {
 			if decoded.TZOffset != tc.hello.TZOffset {
 				t.Errorf("TZOffset = %d, want %d", decoded.TZOffset, tc.hello.TZOffset)
 			}
+			if decoded.TimeZone != tc.hello.TimeZone || decoded.Locale != tc.hello.Locale {
+				t.Errorf("TimeZone, Locale = %q, %q, want %q, %q", decoded.TimeZone, decoded.Locale, tc.hello.TimeZone, tc.hello.Locale)
+			}
+			if decoded.ColorScheme != tc.hello.ColorScheme || decoded.ReducedMotion != tc.hello.ReducedMotion || decoded.PixelRatio != tc.hello.PixelRatio {
+				t.Errorf("media = %v %v %d, want %v %v %d", decoded.ColorScheme, decoded.ReducedMotion, decoded.PixelRatio,
+					tc.hello.ColorScheme, tc.hello.ReducedMotion, tc.hello.PixelRatio)
+			}
 		})
 	}
 }
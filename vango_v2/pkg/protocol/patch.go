@@ -30,12 +30,18 @@ const (
 	PatchRemoveStyle PatchOp = 0x14 // Remove style property
 	PatchSetData     PatchOp = 0x15 // Set data attribute
 	PatchDispatch    PatchOp = 0x20 // Dispatch client event
+	PatchCall        PatchOp = 0x22 // Call a registered client function
 	// NOTE: PatchEval (0x21) has been REMOVED for security.
 	// Sending arbitrary JS from server to client is an XSS/RCE risk.
 
 	// URL operations (Phase 12: URLParam 2.0)
 	PatchURLPush    PatchOp = 0x30 // Update query params, push to history
 	PatchURLReplace PatchOp = 0x31 // Update query params, replace current entry
+
+	// Document head operations
+	PatchSetTitle   PatchOp = 0x40 // Set document.title
+	PatchSetHead    PatchOp = 0x41 // Add or replace a managed head element
+	PatchRemoveHead PatchOp = 0x42 // Remove a managed head element
 )
 
 // String returns the string representation of the patch operation.
@@ -81,10 +87,18 @@ func (op PatchOp) String() string {
 		return "SetData"
 	case PatchDispatch:
 		return "Dispatch"
+	case PatchCall:
+		return "Call"
 	case PatchURLPush:
 		return "URLPush"
 	case PatchURLReplace:
 		return "URLReplace"
+	case PatchSetTitle:
+		return "SetTitle"
+	case PatchSetHead:
+		return "SetHead"
+	case PatchRemoveHead:
+		return "RemoveHead"
 	default:
 		return "Unknown"
 	}
@@ -112,6 +126,7 @@ type Patch struct {
 	Y        int            // For ScrollTo
 	Behavior ScrollBehavior // For ScrollTo
 	Params   map[string]string // For URLPush/URLReplace
+	CallID   uint64            // For Call
 }
 
 // PatchesFrame represents a batch of patches with sequence number.
@@ -201,6 +216,11 @@ func encodePatch(e *Encoder, p *Patch) {
 		e.WriteString(p.Value) // Event detail (JSON)
 		// NOTE: PatchEval case removed for security
 
+	case PatchCall:
+		e.WriteUvarint(p.CallID)
+		e.WriteString(p.Key)   // Function name
+		e.WriteString(p.Value) // Arguments (JSON array)
+
 	case PatchURLPush, PatchURLReplace:
 		// Encode params as varint count + key/value pairs
 		e.WriteUvarint(uint64(len(p.Params)))
@@ -208,6 +228,16 @@ func encodePatch(e *Encoder, p *Patch) {
 			e.WriteString(key)
 			e.WriteString(value)
 		}
+
+	case PatchSetTitle:
+		e.WriteString(p.Value)
+
+	case PatchSetHead:
+		e.WriteString(p.Key) // Head entry key
+		EncodeVNodeWire(e, p.Node)
+
+	case PatchRemoveHead:
+		e.WriteString(p.Key)
 	}
 }
 
@@ -372,6 +402,17 @@ func decodePatchWithDepth(d *Decoder, p *Patch, depth int) error {
 		p.Value, err = d.ReadString()
 		// NOTE: PatchEval case removed for security
 
+	case PatchCall:
+		p.CallID, err = d.ReadUvarint()
+		if err != nil {
+			return err
+		}
+		p.Key, err = d.ReadString()
+		if err != nil {
+			return err
+		}
+		p.Value, err = d.ReadString()
+
 	case PatchURLPush, PatchURLReplace:
 		// Decode params
 		count, err := d.ReadCollectionCount()
@@ -391,6 +432,20 @@ func decodePatchWithDepth(d *Decoder, p *Patch, depth int) error {
 			p.Params[key] = value
 		}
 
+	case PatchSetTitle:
+		p.Value, err = d.ReadString()
+
+	case PatchSetHead:
+		p.Key, err = d.ReadString()
+		if err != nil {
+			return err
+		}
+		// SECURITY: Use depth-aware VNode decoding
+		p.Node, err = decodeVNodeWireWithDepth(d, depth)
+
+	case PatchRemoveHead:
+		p.Key, err = d.ReadString()
+
 	default:
 		// Unknown patch op - skip for forward compatibility
 	}
@@ -498,6 +553,13 @@ func NewDispatchPatch(hid, eventName, detail string) Patch {
 	return Patch{Op: PatchDispatch, HID: hid, Key: eventName, Value: detail}
 }
 
+// NewCallPatch creates a Call patch, which calls the client function name
+// registered in the thin client with the element hid and the JSON array
+// args. The client answers with an EventCallResult carrying id.
+func NewCallPatch(id uint64, hid, name, args string) Patch {
+	return Patch{Op: PatchCall, HID: hid, Key: name, Value: args, CallID: id}
+}
+
 // NOTE: NewEvalPatch has been REMOVED for security.
 // Sending arbitrary JS from server to client is an XSS/RCE risk.
 // Use client-side hooks or PatchDispatch for safe interop.
@@ -511,3 +573,18 @@ func NewURLPushPatch(params map[string]string) Patch {
 func NewURLReplacePatch(params map[string]string) Patch {
 	return Patch{Op: PatchURLReplace, Params: params}
 }
+
+// NewSetTitlePatch creates a SetTitle patch.
+func NewSetTitlePatch(title string) Patch {
+	return Patch{Op: PatchSetTitle, Value: title}
+}
+
+// NewSetHeadPatch creates a SetHead patch for the head element identified by key.
+func NewSetHeadPatch(key string, node *VNodeWire) Patch {
+	return Patch{Op: PatchSetHead, Key: key, Node: node}
+}
+
+// NewRemoveHeadPatch creates a RemoveHead patch.
+func NewRemoveHeadPatch(key string) Patch {
+	return Patch{Op: PatchRemoveHead, Key: key}
+}
@@ -114,7 +114,30 @@ func TestPatchEncodeDecode(t *testing.T) {
 			name:  "dispatch",
 			patch: NewDispatchPatch("h22", "custom-event", `{"detail":"value"}`),
 		},
+		{
+			name:  "call",
+			patch: NewCallPatch(7, "h23", "selection", `[{"start":true}]`),
+		},
 		// NOTE: eval test case removed - PatchEval removed for security
+		{
+			name:  "set_title",
+			patch: NewSetTitlePatch("Profile | Acme"),
+		},
+		{
+			name: "set_head",
+			patch: NewSetHeadPatch("name:description", &VNodeWire{
+				Kind: 0, // Element
+				Tag:  "meta",
+				Attrs: map[string]string{
+					"name":    "description",
+					"content": "A profile",
+				},
+			}),
+		},
+		{
+			name:  "remove_head",
+			patch: NewRemoveHeadPatch("property:og:image"),
+		},
 	}
 
 	for _, tc := range tests {
@@ -183,6 +206,9 @@ func verifyPatch(t *testing.T, _ string, got, want Patch) {
 	if got.Behavior != want.Behavior {
 		t.Errorf("Behavior = %v, want %v", got.Behavior, want.Behavior)
 	}
+	if got.CallID != want.CallID {
+		t.Errorf("CallID = %d, want %d", got.CallID, want.CallID)
+	}
 }
 
 func TestPatchesFrameMultiple(t *testing.T) {
@@ -249,7 +275,11 @@ func TestPatchOpString(t *testing.T) {
 		{PatchRemoveStyle, "RemoveStyle"},
 		{PatchSetData, "SetData"},
 		{PatchDispatch, "Dispatch"},
+		{PatchCall, "Call"},
 		// NOTE: PatchEval removed for security
+		{PatchSetTitle, "SetTitle"},
+		{PatchSetHead, "SetHead"},
+		{PatchRemoveHead, "RemoveHead"},
 		{PatchOp(0xFF), "Unknown"},
 	}
 
@@ -0,0 +1,252 @@
+package protocol
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// Session Recordings
+// =============================================================================
+
+// A session recording is the sequence of WebSocket messages exchanged by a
+// session, used to reproduce bugs with `vango replay`:
+//
+//	┌───────────┬──────────────────────────────────────────────────┐
+//	│ "VREC" 01 │ Header: [len][SessionID][Path][Start: uint64]    │
+//	├───────────┴──────────────────────────────────────────────────┤
+//	│ Message: [Dir: 1 byte][Offset µs: varint][Data: len-prefixed]│
+//	│ ...                                                          │
+//	└──────────────────────────────────────────────────────────────┘
+//
+// The first inbound message is the raw ClientHello; the others are encoded
+// frames.
+
+// recordingMagic starts every recording. The last byte is the format version.
+const recordingMagic = "VREC\x01"
+
+// ErrInvalidRecording is returned when a recording is malformed.
+var ErrInvalidRecording = errors.New("protocol: invalid session recording")
+
+// RecordDirection is the direction of a recorded message.
+type RecordDirection uint8
+
+const (
+	RecordIn  RecordDirection = 0x00 // Client → Server
+	RecordOut RecordDirection = 0x01 // Server → Client
+)
+
+// String returns the string representation of the direction.
+func (d RecordDirection) String() string {
+	switch d {
+	case RecordIn:
+		return "In"
+	case RecordOut:
+		return "Out"
+	default:
+		return "Unknown"
+	}
+}
+
+// RecordingHeader describes a recorded session.
+type RecordingHeader struct {
+	SessionID string    // ID of the recorded session
+	Path      string    // Request URI of the WebSocket upgrade
+	Start     time.Time // When the session started
+}
+
+// RecordedMessage is a message of a recording.
+type RecordedMessage struct {
+	Dir    RecordDirection // Direction of the message
+	Offset time.Duration   // Time since the start of the session
+	Data   []byte          // Raw WebSocket message
+}
+
+// RecordingWriter writes a session recording. It is safe for concurrent use.
+type RecordingWriter struct {
+	mu     sync.Mutex
+	w      io.Writer
+	start  time.Time
+	err    error
+	closed bool
+}
+
+// NewRecordingWriter writes the recording header to w and returns a writer
+// for the messages.
+func NewRecordingWriter(w io.Writer, h RecordingHeader) (*RecordingWriter, error) {
+	if h.Start.IsZero() {
+		h.Start = time.Now()
+	}
+
+	header := NewEncoder()
+	header.WriteString(h.SessionID)
+	header.WriteString(h.Path)
+	header.WriteUint64(uint64(h.Start.UnixNano()))
+
+	e := NewEncoderWithCap(len(recordingMagic) + header.Len() + 2)
+	e.WriteBytes([]byte(recordingMagic))
+	e.WriteLenBytes(header.Bytes())
+	if _, err := w.Write(e.Bytes()); err != nil {
+		return nil, err
+	}
+
+	return &RecordingWriter{w: w, start: h.Start}, nil
+}
+
+// Record appends a message sent at the current time.
+func (rw *RecordingWriter) Record(dir RecordDirection, data []byte) error {
+	return rw.RecordAt(dir, time.Now(), data)
+}
+
+// RecordAt appends a message sent at t. After a write error, the writer
+// stops recording and returns that error. Messages recorded after Close
+// are dropped.
+func (rw *RecordingWriter) RecordAt(dir RecordDirection, t time.Time, data []byte) error {
+	offset := t.Sub(rw.start)
+	if offset < 0 {
+		offset = 0
+	}
+
+	e := NewEncoderWithCap(len(data) + 12)
+	e.WriteByte(byte(dir))
+	e.WriteUvarint(uint64(offset / time.Microsecond))
+	e.WriteLenBytes(data)
+
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+	if rw.closed {
+		return nil
+	}
+	if rw.err != nil {
+		return rw.err
+	}
+	_, rw.err = rw.w.Write(e.Bytes())
+	return rw.err
+}
+
+// Close stops recording and closes the underlying writer if it is an
+// io.Closer.
+func (rw *RecordingWriter) Close() error {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+	if rw.closed {
+		return nil
+	}
+	rw.closed = true
+	if c, ok := rw.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// RecordingReader reads a session recording.
+type RecordingReader struct {
+	r      *bufio.Reader
+	header RecordingHeader
+}
+
+// NewRecordingReader reads the recording header from r.
+func NewRecordingReader(r io.Reader) (*RecordingReader, error) {
+	br := bufio.NewReader(r)
+
+	magic := make([]byte, len(recordingMagic))
+	if _, err := io.ReadFull(br, magic); err != nil || string(magic) != recordingMagic {
+		return nil, ErrInvalidRecording
+	}
+
+	data, err := readLenPrefixed(br)
+	if err != nil {
+		return nil, err
+	}
+	d := NewDecoder(data)
+	var h RecordingHeader
+	if h.SessionID, err = d.ReadString(); err != nil {
+		return nil, ErrInvalidRecording
+	}
+	if h.Path, err = d.ReadString(); err != nil {
+		return nil, ErrInvalidRecording
+	}
+	start, err := d.ReadUint64()
+	if err != nil {
+		return nil, ErrInvalidRecording
+	}
+	h.Start = time.Unix(0, int64(start))
+
+	return &RecordingReader{r: br, header: h}, nil
+}
+
+// Header returns the recording header.
+func (rr *RecordingReader) Header() RecordingHeader {
+	return rr.header
+}
+
+// Next returns the next message, or io.EOF at the end of the recording.
+// A recording cut off in the middle of a message (e.g. by a crash) ends
+// with io.ErrUnexpectedEOF.
+func (rr *RecordingReader) Next() (*RecordedMessage, error) {
+	dir, err := rr.r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	if RecordDirection(dir) > RecordOut {
+		return nil, ErrInvalidRecording
+	}
+
+	offset, err := binary.ReadUvarint(rr.r)
+	if err != nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+	data, err := readLenPrefixed(rr.r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RecordedMessage{
+		Dir:    RecordDirection(dir),
+		Offset: time.Duration(offset) * time.Microsecond,
+		Data:   data,
+	}, nil
+}
+
+// ReadRecording reads a whole recording. A truncated last message is
+// dropped.
+func ReadRecording(r io.Reader) (RecordingHeader, []RecordedMessage, error) {
+	rr, err := NewRecordingReader(r)
+	if err != nil {
+		return RecordingHeader{}, nil, err
+	}
+
+	var messages []RecordedMessage
+	for {
+		msg, err := rr.Next()
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return rr.header, messages, nil
+		}
+		if err != nil {
+			return rr.header, messages, err
+		}
+		messages = append(messages, *msg)
+	}
+}
+
+// readLenPrefixed reads a varint length-prefixed byte slice.
+func readLenPrefixed(r *bufio.Reader) ([]byte, error) {
+	length, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+	if length > uint64(DefaultMaxAllocation) {
+		return nil, fmt.Errorf("%w: message of %d bytes", ErrInvalidRecording, length)
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return data, nil
+}
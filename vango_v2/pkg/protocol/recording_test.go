@@ -0,0 +1,114 @@
+package protocol
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestRecordingRoundTrip(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	var buf bytes.Buffer
+
+	w, err := NewRecordingWriter(&buf, RecordingHeader{
+		SessionID: "s1",
+		Path:      "/_vango/live?path=%2Fusers",
+		Start:     start,
+	})
+	if err != nil {
+		t.Fatalf("NewRecordingWriter() error: %v", err)
+	}
+
+	hello := EncodeClientHello(NewClientHello("token"))
+	event := NewFrame(FrameEvent, EncodeEvent(&Event{Seq: 1, Type: EventClick, HID: "h1"})).Encode()
+	patches := NewFrame(FramePatches, EncodePatches(&PatchesFrame{
+		Seq:     1,
+		Patches: []Patch{NewSetTextPatch("h2", "1")},
+	})).Encode()
+
+	w.RecordAt(RecordIn, start, hello)
+	w.RecordAt(RecordIn, start.Add(1500*time.Millisecond), event)
+	w.RecordAt(RecordOut, start.Add(1502*time.Millisecond), patches)
+
+	h, messages, err := ReadRecording(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("ReadRecording() error: %v", err)
+	}
+	if h.SessionID != "s1" || h.Path != "/_vango/live?path=%2Fusers" || !h.Start.Equal(start) {
+		t.Errorf("header = %+v", h)
+	}
+
+	want := []RecordedMessage{
+		{Dir: RecordIn, Offset: 0, Data: hello},
+		{Dir: RecordIn, Offset: 1500 * time.Millisecond, Data: event},
+		{Dir: RecordOut, Offset: 1502 * time.Millisecond, Data: patches},
+	}
+	if len(messages) != len(want) {
+		t.Fatalf("got %d messages, want %d", len(messages), len(want))
+	}
+	for i, m := range messages {
+		if m.Dir != want[i].Dir || m.Offset != want[i].Offset || !bytes.Equal(m.Data, want[i].Data) {
+			t.Errorf("message %d = %v %v %x, want %v %v %x", i, m.Dir, m.Offset, m.Data, want[i].Dir, want[i].Offset, want[i].Data)
+		}
+	}
+
+	// A recording cut off by a crash keeps the complete messages
+	_, messages, err = ReadRecording(bytes.NewReader(buf.Bytes()[:buf.Len()-3]))
+	if err != nil || len(messages) != 2 {
+		t.Errorf("truncated recording: %d messages, error %v; want 2", len(messages), err)
+	}
+}
+
+func TestRecordingReaderErrors(t *testing.T) {
+	if _, err := NewRecordingReader(bytes.NewReader([]byte("VREC\x02"))); err != ErrInvalidRecording {
+		t.Errorf("unknown version error = %v, want ErrInvalidRecording", err)
+	}
+	if _, err := NewRecordingReader(bytes.NewReader(nil)); err != ErrInvalidRecording {
+		t.Errorf("empty input error = %v, want ErrInvalidRecording", err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := NewRecordingWriter(&buf, RecordingHeader{}); err != nil {
+		t.Fatal(err)
+	}
+	buf.WriteByte(0x07) // Unknown direction
+	rr, err := NewRecordingReader(&buf)
+	if err != nil {
+		t.Fatalf("NewRecordingReader() error: %v", err)
+	}
+	if _, err := rr.Next(); err != ErrInvalidRecording {
+		t.Errorf("Next() error = %v, want ErrInvalidRecording", err)
+	}
+	if _, err := rr.Next(); err != io.EOF {
+		t.Errorf("Next() at end = %v, want io.EOF", err)
+	}
+}
+
+type failingWriter struct{ n int }
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.n == 0 {
+		return 0, io.ErrShortWrite
+	}
+	w.n--
+	return len(p), nil
+}
+
+func TestRecordingWriterError(t *testing.T) {
+	w, err := NewRecordingWriter(&failingWriter{n: 1}, RecordingHeader{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Record(RecordIn, []byte{1}); err != io.ErrShortWrite {
+		t.Errorf("Record() error = %v, want io.ErrShortWrite", err)
+	}
+	if err := w.Record(RecordIn, []byte{1}); err != io.ErrShortWrite {
+		t.Errorf("Record() after failure = %v, want the first error", err)
+	}
+
+	w.Close()
+	if err := w.Record(RecordIn, []byte{1}); err != nil {
+		t.Errorf("Record() after Close = %v, want nil", err)
+	}
+}
@@ -0,0 +1,140 @@
+package protocol
+
+import "fmt"
+
+// String returns the version as "major.minor".
+func (v ProtocolVersion) String() string {
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
+
+// Compare returns -1, 0 or +1 if v is older than, equal to or newer than o.
+func (v ProtocolVersion) Compare(o ProtocolVersion) int {
+	switch {
+	case v.Major != o.Major:
+		if v.Major < o.Major {
+			return -1
+		}
+		return 1
+	case v.Minor < o.Minor:
+		return -1
+	case v.Minor > o.Minor:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// VersionPolicy is the range of client protocol versions a server accepts.
+type VersionPolicy struct {
+	// Min is the oldest version accepted. Older clients are sent
+	// HandshakeUpgradeRequired so they reload a current bundle.
+	Min ProtocolVersion
+
+	// Max is the newest version spoken. Newer clients of the same major
+	// version are answered with Max.
+	Max ProtocolVersion
+}
+
+// DefaultVersionPolicy accepts the current version and clients one minor
+// version behind it, so cached bundles keep working during a deploy.
+func DefaultVersionPolicy() VersionPolicy {
+	oldest := CurrentVersion
+	if oldest.Minor > 0 {
+		oldest.Minor--
+	}
+	return VersionPolicy{Min: oldest, Max: CurrentVersion}
+}
+
+// Negotiate returns the version to speak with a client announcing v.
+// If the client cannot be served, it returns the handshake status to reject
+// it with: HandshakeUpgradeRequired for clients older than Min, and
+// HandshakeVersionMismatch for clients of a newer major version than Max.
+func (p VersionPolicy) Negotiate(v ProtocolVersion) (ProtocolVersion, HandshakeStatus) {
+	switch {
+	case v.Compare(p.Min) < 0:
+		return ProtocolVersion{}, HandshakeUpgradeRequired
+	case v.Major > p.Max.Major:
+		return ProtocolVersion{}, HandshakeVersionMismatch
+	case v.Compare(p.Max) > 0:
+		return p.Max, HandshakeOK
+	default:
+		return v, HandshakeOK
+	}
+}
+
+// Capabilities is a bitmap of optional protocol features: patch ops and
+// frame flags beyond those of protocol 2.0. Clients announce theirs in
+// ClientHello; the server answers with the ones both sides support.
+type Capabilities uint32
+
+const (
+	// CapHeadPatches covers the SetTitle, SetHead and RemoveHead patches.
+	CapHeadPatches Capabilities = 1 << iota
+
+	// CapCompressedFrames covers frames with FlagCompressed.
+	CapCompressedFrames
+
+	// CapBinaryBlobs covers FrameBlob, the transfer of binary blobs on the
+	// live connection.
+	CapBinaryBlobs
+
+	// CapClientCalls covers the Call patch and EventCallResult, calls of
+	// client functions awaiting their result.
+	CapClientCalls
+)
+
+// SupportedCapabilities are the capabilities implemented by this package.
+const SupportedCapabilities = CapHeadPatches | CapBinaryBlobs | CapClientCalls
+
+// Has returns true if c includes all capabilities of want.
+func (c Capabilities) Has(want Capabilities) bool {
+	return c&want == want
+}
+
+// VersionCapabilities returns the capabilities of a client of version v that
+// does not announce them. Clients of protocol 2.0 predate the bitmap.
+func VersionCapabilities(v ProtocolVersion) Capabilities {
+	if v.Compare(ProtocolVersion{Major: 2, Minor: 1}) < 0 {
+		return 0
+	}
+	return SupportedCapabilities
+}
+
+// PatchCapability returns the capability a patch op requires, or 0 for the
+// ops every client supports.
+func PatchCapability(op PatchOp) Capabilities {
+	switch op {
+	case PatchSetTitle, PatchSetHead, PatchRemoveHead:
+		return CapHeadPatches
+	case PatchCall:
+		return CapClientCalls
+	default:
+		return 0
+	}
+}
+
+// DowngradePatches returns the patches a client with caps can apply,
+// dropping the others. It returns patches itself if nothing is dropped.
+func DowngradePatches(patches []Patch, caps Capabilities) []Patch {
+	for i := range patches {
+		if caps.Has(PatchCapability(patches[i].Op)) {
+			continue
+		}
+
+		// Copy the supported patches
+		out := append(make([]Patch, 0, len(patches)-1), patches[:i]...)
+		for _, p := range patches[i+1:] {
+			if caps.Has(PatchCapability(p.Op)) {
+				out = append(out, p)
+			}
+		}
+		return out
+	}
+	return patches
+}
+
+// EncodePatchesFor encodes a patches frame for a client with caps,
+// dropping the patches it cannot apply.
+func EncodePatchesFor(pf *PatchesFrame, caps Capabilities) []byte {
+	return EncodePatches(&PatchesFrame{Seq: pf.Seq, Patches: DowngradePatches(pf.Patches, caps)})
+}
@@ -0,0 +1,121 @@
+package protocol
+
+import (
+	"testing"
+)
+
+func TestVersionPolicyNegotiate(t *testing.T) {
+	policy := VersionPolicy{
+		Min: ProtocolVersion{Major: 2, Minor: 1},
+		Max: ProtocolVersion{Major: 2, Minor: 3},
+	}
+
+	tests := []struct {
+		client ProtocolVersion
+		want   ProtocolVersion
+		status HandshakeStatus
+	}{
+		{ProtocolVersion{2, 2}, ProtocolVersion{2, 2}, HandshakeOK},
+		{ProtocolVersion{2, 1}, ProtocolVersion{2, 1}, HandshakeOK},
+		{ProtocolVersion{2, 3}, ProtocolVersion{2, 3}, HandshakeOK},
+		{ProtocolVersion{2, 7}, ProtocolVersion{2, 3}, HandshakeOK},
+		{ProtocolVersion{2, 0}, ProtocolVersion{}, HandshakeUpgradeRequired},
+		{ProtocolVersion{1, 9}, ProtocolVersion{}, HandshakeUpgradeRequired},
+		{ProtocolVersion{3, 0}, ProtocolVersion{}, HandshakeVersionMismatch},
+	}
+	for _, tt := range tests {
+		got, status := policy.Negotiate(tt.client)
+		if got != tt.want || status != tt.status {
+			t.Errorf("Negotiate(%s) = %s, %s; want %s, %s", tt.client, got, status, tt.want, tt.status)
+		}
+	}
+}
+
+func TestDefaultVersionPolicy(t *testing.T) {
+	p := DefaultVersionPolicy()
+	if p.Max != CurrentVersion {
+		t.Errorf("Max = %s, want %s", p.Max, CurrentVersion)
+	}
+	if p.Min.Major != CurrentVersion.Major || p.Min.Minor != CurrentVersion.Minor-1 {
+		t.Errorf("Min = %s, want one minor version behind %s", p.Min, CurrentVersion)
+	}
+}
+
+func TestDowngradePatches(t *testing.T) {
+	patches := []Patch{
+		NewSetTextPatch("h1", "a"),
+		NewSetTitlePatch("Title"),
+		NewSetAttrPatch("h1", "class", "b"),
+		NewRemoveHeadPatch("meta:robots"),
+		NewCallPatch(1, "h1", "copy", "[]"),
+	}
+
+	if got := DowngradePatches(patches, SupportedCapabilities); len(got) != 5 || &got[0] != &patches[0] {
+		t.Errorf("DowngradePatches() should return the patches unchanged, got %v", got)
+	}
+
+	got := DowngradePatches(patches, 0)
+	if len(got) != 2 || got[0].Op != PatchSetText || got[1].Op != PatchSetAttr {
+		t.Errorf("DowngradePatches(0) = %v, want the 2.0 patches", got)
+	}
+	if patches[1].Op != PatchSetTitle {
+		t.Error("DowngradePatches() should not modify its input")
+	}
+
+	pf, err := DecodePatches(EncodePatchesFor(&PatchesFrame{Seq: 3, Patches: patches}, 0))
+	if err != nil {
+		t.Fatalf("DecodePatches() error: %v", err)
+	}
+	if pf.Seq != 3 || len(pf.Patches) != 2 {
+		t.Errorf("EncodePatchesFor(0) = seq %d, %d patches", pf.Seq, len(pf.Patches))
+	}
+}
+
+func TestHelloCapabilities(t *testing.T) {
+	// 2.0 hellos end before the capabilities
+	e := NewEncoder()
+	e.WriteByte(2)
+	e.WriteByte(0)
+	e.WriteString("token")
+	e.WriteString("")
+	e.WriteUint32(0)
+	e.WriteUint16(800)
+	e.WriteUint16(600)
+	e.WriteInt16(0)
+	ch, err := DecodeClientHello(e.Bytes())
+	if err != nil {
+		t.Fatalf("DecodeClientHello(2.0) error: %v", err)
+	}
+	if ch.Capabilities != 0 || ch.CSRFToken != "token" {
+		t.Errorf("2.0 hello = %+v, want no capabilities", ch)
+	}
+
+	ch, err = DecodeClientHello(EncodeClientHello(NewClientHello("token")))
+	if err != nil {
+		t.Fatalf("DecodeClientHello() error: %v", err)
+	}
+	if ch.Version != CurrentVersion || ch.Capabilities != SupportedCapabilities {
+		t.Errorf("hello = %s %b, want %s %b", ch.Version, ch.Capabilities, CurrentVersion, SupportedCapabilities)
+	}
+
+	sh := NewServerHello("s1", 1, 1702000000000)
+	sh.Version = ProtocolVersion{Major: 2, Minor: 0}
+	sh.Capabilities = 0
+	decoded, err := DecodeServerHello(EncodeServerHello(sh))
+	if err != nil {
+		t.Fatalf("DecodeServerHello() error: %v", err)
+	}
+	if decoded.Version != sh.Version || decoded.Capabilities != 0 {
+		t.Errorf("server hello = %s %b", decoded.Version, decoded.Capabilities)
+	}
+
+	// 2.0 servers end after the flags
+	payload := EncodeServerHello(NewServerHello("s1", 1, 0))
+	decoded, err = DecodeServerHello(payload[:len(payload)-6])
+	if err != nil {
+		t.Fatalf("DecodeServerHello(2.0) error: %v", err)
+	}
+	if decoded.Version != (ProtocolVersion{Major: 2, Minor: 0}) || decoded.SessionID != "s1" {
+		t.Errorf("2.0 server hello = %+v", decoded)
+	}
+}
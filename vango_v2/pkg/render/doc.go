@@ -48,9 +48,39 @@
 //	sr := render.NewStreamingRenderer(w, config)
 //	err := sr.RenderPage(page)
 //
+// # Suspense
+//
+// With StreamingRenderer, vdom.Suspense boundaries whose content is still
+// loading are sent with their fallback so the rest of the page is not held
+// back. Each boundary's content is streamed later in the same response, in
+// the order it resolves, and swapped into place by a small inline script.
+// Elements inside a boundary get HIDs scoped to the boundary (h5-1, h5-2),
+// so streamed content hydrates identically to a live session's render.
+// Boundaries still pending after RendererConfig.SuspenseTimeout keep their
+// fallback; the live session swaps them once their data arrives.
+//
+// Set PageData.Resources to a resource.Handover so the live session starts
+// from the data the streamed boundaries show rather than fetching it again
+// behind their fallbacks.
+//
+// # Portals
+//
+// The children of a vdom.Portal are rendered into its vdom.PortalTarget,
+// wrapped in a vango-portal-content element naming the portal's HID, when
+// the target comes after the portal in the page. Portals rendered after
+// their target keep their children in place and the client moves them;
+// content for a target missing from the page is written after the tree.
+//
 // # Security
 //
 // All text content is escaped by default to prevent XSS attacks.
 // Raw HTML can be inserted using KindRaw nodes, but should only be
 // used with trusted content.
+//
+// PageData.Nonce adds a Content-Security-Policy nonce to every inline
+// script and style the renderer emits, including script elements in the
+// body and the suspense swap scripts. With RendererConfig.StrictCSP or
+// PageData.StrictCSP, raw HTML that runs script fails with ErrRawScript and
+// inline JavaScript with ErrInlineScript, so every script of the page comes
+// from a file or the framework itself.
 package render
@@ -1,10 +1,12 @@
 package render
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
 
+	"github.com/vango-dev/vango/v2/pkg/head"
 	"github.com/vango-dev/vango/v2/pkg/vdom"
 )
 
@@ -13,7 +15,13 @@ type PageData struct {
 	// Body is the root VNode for the page content
 	Body *vdom.VNode
 
-	// Title is the page title
+	// Head contains document-level head declarations (head.Title, head.Meta,
+	// ...), such as the output of router.MatchResult.Head. Declarations in
+	// Body override them.
+	Head []*vdom.VNode
+
+	// Title is the page title.
+	// A title declared with head.Title takes precedence.
 	Title string
 
 	// Meta contains meta tags for the page
@@ -34,16 +42,57 @@ type PageData struct {
 	// CSRFToken is the CSRF token for form submissions and WebSocket
 	CSRFToken string
 
+	// LoadToken identifies the route data loaded for this render, so the
+	// live session reuses it instead of running the loaders again.
+	// See router.LoaderStore.
+	LoadToken string
+
+	// Resources hands the data of the resources resolved in this render
+	// over to the live session (see resource.Handover). It is sealed once
+	// suspended content has been streamed, so the session starts from the
+	// state the page shows.
+	Resources Handover
+
 	// ClientScript is the path to the thin client JavaScript
 	// Defaults to "/_vango/client.js" if not specified
 	ClientScript string
 
+	// Static omits the client bootstrap: the thin client, session and CSRF
+	// scripts. Use it for pages without interactivity, such as static
+	// exports of marketing and docs pages.
+	Static bool
+
 	// StyleSheets contains paths to external stylesheets
 	StyleSheets []string
 
 	// Lang is the language attribute for the html element
 	// Defaults to "en" if not specified
 	Lang string
+
+	// Dir is the text direction for the html element ("ltr" or "rtl").
+	// Omitted if not specified.
+	Dir string
+
+	// Nonce is the Content-Security-Policy nonce of the request.
+	// It is added to every inline script and style of the page, so the
+	// policy does not need 'unsafe-inline'. See server.Ctx.Nonce.
+	Nonce string
+
+	// StrictCSP enables RendererConfig.StrictCSP for this page. Set it
+	// from server.StrictCSPFromContext so the server's CSPConfig.Strict
+	// setting applies to the pages it serves.
+	StrictCSP bool
+}
+
+// Handover is data resolved during rendering that the live session of the
+// page starts from, such as a resource.Handover.
+type Handover interface {
+	// Seal fixes the data handed over. It is called after the page content
+	// has been written.
+	Seal()
+
+	// Token identifies the data to the live session.
+	Token() string
 }
 
 // MetaTag represents a meta element in the document head.
@@ -102,13 +151,26 @@ func (r *Renderer) RenderPage(w io.Writer, page PageData) error {
 		return err
 	}
 
-	// HTML tag with lang
-	if _, err := fmt.Fprintf(w, `<html lang="%s">`+"\n", escapeAttr(lang)); err != nil {
+	// HTML tag with lang and optional dir
+	dir := ""
+	if page.Dir != "" {
+		dir = ` dir="` + escapeAttr(page.Dir) + `"`
+	}
+	if _, err := fmt.Fprintf(w, `<html lang="%s"%s>`+"\n", escapeAttr(lang), dir); err != nil {
+		return err
+	}
+
+	r.nonce = page.Nonce
+	r.strict = page.StrictCSP
+
+	// Render the body first so its head declarations are known
+	body, docHead, err := r.renderBody(page)
+	if err != nil {
 		return err
 	}
 
 	// Head
-	if err := r.renderHead(w, page); err != nil {
+	if err := r.renderHead(w, page, docHead); err != nil {
 		return err
 	}
 
@@ -118,7 +180,7 @@ func (r *Renderer) RenderPage(w io.Writer, page PageData) error {
 	}
 
 	// Main content
-	if err := r.RenderToWriter(w, page.Body); err != nil {
+	if _, err := w.Write(body); err != nil {
 		return err
 	}
 
@@ -135,8 +197,31 @@ func (r *Renderer) RenderPage(w io.Writer, page PageData) error {
 	return nil
 }
 
+// renderBody renders the page body to a buffer and returns it with the
+// document head: the declarations in page.Head overridden by those in the body.
+func (r *Renderer) renderBody(page PageData) ([]byte, *head.Head, error) {
+	var buf bytes.Buffer
+	r.head = head.New()
+	r.headDepth = 0
+	err := r.RenderToWriter(&buf, page.Body)
+	bodyHead := r.head
+	r.head = nil
+	if err != nil {
+		return nil, nil, err
+	}
+
+	docHead := head.New()
+	for _, node := range page.Head {
+		docHead.Collect(node, 0)
+	}
+	docHead.Merge(bodyHead)
+	return buf.Bytes(), docHead, nil
+}
+
 // renderHead renders the document head section.
-func (r *Renderer) renderHead(w io.Writer, page PageData) error {
+// Entries of docHead replace the title, meta and link tags of page with
+// the same key.
+func (r *Renderer) renderHead(w io.Writer, page PageData, docHead *head.Head) error {
 	if _, err := w.Write([]byte("<head>\n")); err != nil {
 		return err
 	}
@@ -152,14 +237,21 @@ func (r *Renderer) renderHead(w io.Writer, page PageData) error {
 	}
 
 	// Title
-	if page.Title != "" {
-		if _, err := fmt.Fprintf(w, "  <title>%s</title>\n", escapeHTML(page.Title)); err != nil {
+	title := page.Title
+	if t := docHead.Title(); t != "" {
+		title = t
+	}
+	if title != "" {
+		if _, err := fmt.Fprintf(w, "  <title>%s</title>\n", escapeHTML(title)); err != nil {
 			return err
 		}
 	}
 
 	// Meta tags
 	for _, meta := range page.Meta {
+		if docHead.Has(metaKey(meta)) {
+			continue
+		}
 		if err := r.renderMetaTag(w, meta); err != nil {
 			return err
 		}
@@ -167,11 +259,21 @@ func (r *Renderer) renderHead(w io.Writer, page PageData) error {
 
 	// Link tags (stylesheets, favicon, etc.)
 	for _, link := range page.Links {
+		if link.Rel == "canonical" && docHead.Has("link:canonical") {
+			continue
+		}
 		if err := r.renderLinkTag(w, link); err != nil {
 			return err
 		}
 	}
 
+	// Managed head entries
+	for _, entry := range docHead.Entries() {
+		if err := r.renderHeadEntry(w, entry); err != nil {
+			return err
+		}
+	}
+
 	// Stylesheets
 	for _, href := range page.StyleSheets {
 		if _, err := fmt.Fprintf(w, `  <link rel="stylesheet" href="%s">`+"\n", escapeAttr(href)); err != nil {
@@ -181,7 +283,7 @@ func (r *Renderer) renderHead(w io.Writer, page PageData) error {
 
 	// Inline styles
 	for _, style := range page.Styles {
-		if _, err := fmt.Fprintf(w, "  <style>%s</style>\n", style); err != nil {
+		if _, err := fmt.Fprintf(w, "  <style%s>%s</style>\n", r.nonceAttr(), style); err != nil {
 			return err
 		}
 	}
@@ -245,6 +347,48 @@ func (r *Renderer) renderMetaTag(w io.Writer, meta MetaTag) error {
 	return nil
 }
 
+// metaKey returns the head entry key that replaces meta.
+func metaKey(meta MetaTag) string {
+	switch {
+	case meta.Name != "":
+		return "name:" + meta.Name
+	case meta.Property != "":
+		return "property:" + meta.Property
+	}
+	return ""
+}
+
+// renderHeadEntry renders a managed head element, marked with head.KeyAttr
+// so live sessions can update it.
+func (r *Renderer) renderHeadEntry(w io.Writer, entry *head.Entry) error {
+	if _, err := fmt.Fprintf(w, "  <%s", entry.Tag); err != nil {
+		return err
+	}
+
+	for _, key := range entry.AttrKeys() {
+		if _, err := fmt.Fprintf(w, ` %s="%s"`, key, escapeAttr(entry.Attrs[key])); err != nil {
+			return err
+		}
+	}
+
+	if _, err := fmt.Fprintf(w, ` %s="%s">`, head.KeyAttr, escapeAttr(entry.Key)); err != nil {
+		return err
+	}
+
+	// JSON-LD is encoded with <, > and & escaped, so it is safe as script text
+	if entry.Tag == "script" {
+		if _, err := fmt.Fprintf(w, "%s</script>", entry.Text); err != nil {
+			return err
+		}
+	}
+
+	if _, err := w.Write([]byte("\n")); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // renderLinkTag renders a link element.
 func (r *Renderer) renderLinkTag(w io.Writer, link LinkTag) error {
 	if _, err := w.Write([]byte("  <link")); err != nil {
@@ -296,6 +440,10 @@ func (r *Renderer) renderLinkTag(w io.Writer, link LinkTag) error {
 
 // renderScriptTag renders a script element.
 func (r *Renderer) renderScriptTag(w io.Writer, script ScriptTag) error {
+	if script.Inline != "" && r.strictCSP() && (script.Module || isJavaScript(script.Type)) {
+		return ErrInlineScript
+	}
+
 	if _, err := w.Write([]byte("  <script")); err != nil {
 		return err
 	}
@@ -328,6 +476,10 @@ func (r *Renderer) renderScriptTag(w io.Writer, script ScriptTag) error {
 		}
 	}
 
+	if _, err := w.Write([]byte(r.nonceAttr())); err != nil {
+		return err
+	}
+
 	if _, err := w.Write([]byte(">")); err != nil {
 		return err
 	}
@@ -347,22 +499,46 @@ func (r *Renderer) renderScriptTag(w io.Writer, script ScriptTag) error {
 
 // renderClientScript injects the Vango thin client and configuration.
 func (r *Renderer) renderClientScript(w io.Writer, page PageData) error {
+	if page.Static {
+		return nil
+	}
+
 	// CSRF token for WebSocket handshake
 	if page.CSRFToken != "" {
-		if _, err := fmt.Fprintf(w, `  <script>window.__VANGO_CSRF__="%s";</script>`+"\n",
-			escapeAttr(page.CSRFToken)); err != nil {
+		if _, err := fmt.Fprintf(w, `  <script%s>window.__VANGO_CSRF__="%s";</script>`+"\n",
+			r.nonceAttr(), escapeAttr(page.CSRFToken)); err != nil {
 			return err
 		}
 	}
 
 	// Session ID for reconnection
 	if page.SessionID != "" {
-		if _, err := fmt.Fprintf(w, `  <script>window.__VANGO_SESSION__="%s";</script>`+"\n",
-			escapeAttr(page.SessionID)); err != nil {
+		if _, err := fmt.Fprintf(w, `  <script%s>window.__VANGO_SESSION__="%s";</script>`+"\n",
+			r.nonceAttr(), escapeAttr(page.SessionID)); err != nil {
 			return err
 		}
 	}
 
+	// Loader data handover
+	if page.LoadToken != "" {
+		if _, err := fmt.Fprintf(w, `  <script%s>window.__VANGO_LOAD__="%s";</script>`+"\n",
+			r.nonceAttr(), escapeAttr(page.LoadToken)); err != nil {
+			return err
+		}
+	}
+
+	// Resource data handover. All content has been written, so the data
+	// the page shows is final.
+	if page.Resources != nil {
+		page.Resources.Seal()
+		if token := page.Resources.Token(); token != "" {
+			if _, err := fmt.Fprintf(w, `  <script%s>window.__VANGO_RESOURCES__="%s";</script>`+"\n",
+				r.nonceAttr(), escapeAttr(token)); err != nil {
+				return err
+			}
+		}
+	}
+
 	// Thin client script
 	clientPath := page.ClientScript
 	if clientPath == "" {
@@ -370,8 +546,8 @@ func (r *Renderer) renderClientScript(w io.Writer, page PageData) error {
 	}
 
 	// Enable debug mode for development
-	if _, err := fmt.Fprintf(w, `  <script src="%s" data-debug="true" defer></script>`+"\n",
-		escapeAttr(clientPath)); err != nil {
+	if _, err := fmt.Fprintf(w, `  <script src="%s" data-debug="true" defer%s></script>`+"\n",
+		escapeAttr(clientPath), r.nonceAttr()); err != nil {
 		return err
 	}
 
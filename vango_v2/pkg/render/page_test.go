@@ -5,6 +5,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/vango-dev/vango/v2/pkg/head"
 	"github.com/vango-dev/vango/v2/pkg/vdom"
 )
 
@@ -287,6 +288,242 @@ func TestRenderPageWithCustomLang(t *testing.T) {
 	}
 }
 
+func TestRenderPageWithDir(t *testing.T) {
+	renderer := NewRenderer(RendererConfig{})
+
+	page := PageData{
+		Body:  vdom.Div(),
+		Title: "Arabic Page",
+		Lang:  "ar",
+		Dir:   "rtl",
+	}
+
+	var buf bytes.Buffer
+	err := renderer.RenderPage(&buf, page)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	html := buf.String()
+
+	if !strings.Contains(html, `<html lang="ar" dir="rtl">`) {
+		t.Errorf("should contain lang and dir, got %q", html)
+	}
+}
+
+func TestRenderPageHead(t *testing.T) {
+	renderer := NewRenderer(RendererConfig{})
+
+	layout := func(children ...any) *vdom.VNode {
+		return vdom.Div(
+			head.Title("Acme"),
+			head.Meta("description", "Layout description"),
+			vdom.Main(children...),
+		)
+	}
+
+	page := PageData{
+		Head: []*vdom.VNode{head.Meta("robots", "noindex")},
+		Body: layout(
+			head.Title("Profile | Acme"),
+			head.Meta("description", "Profile </title>"),
+			head.JSONLD(map[string]string{"@type": "Person", "name": "<Ada>"}),
+			vdom.H1(vdom.Text("Profile")),
+		),
+		Title: "Default",
+		Meta: []MetaTag{
+			{Name: "description", Content: "Default description"},
+			{Name: "author", Content: "Acme"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := renderer.RenderPage(&buf, page); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	html := buf.String()
+
+	wants := []string{
+		"<title>Profile | Acme</title>",
+		`<meta name="author" content="Acme">`,
+		`<meta content="noindex" name="robots" data-vango-head="name:robots">`,
+		`<meta content="Profile &lt;/title&gt;" name="description" data-vango-head="name:description">`,
+		`<script type="application/ld+json" data-vango-head="jsonld:Person">{"@type":"Person","name":"\u003cAda\u003e"}</script>`,
+		`<main data-hid="h2"><h1 data-hid="h3">Profile</h1></main>`,
+	}
+	for _, want := range wants {
+		if !strings.Contains(html, want) {
+			t.Errorf("should contain %s, got %q", want, html)
+		}
+	}
+
+	for _, unwanted := range []string{"Default description", "<title>Default", "Layout description"} {
+		if strings.Contains(html, unwanted) {
+			t.Errorf("should not contain %q", unwanted)
+		}
+	}
+
+	// Head must come before the body
+	if strings.Index(html, "<title>") > strings.Index(html, "<body>") {
+		t.Error("title should be rendered in the head")
+	}
+}
+
+func TestRenderPageNonce(t *testing.T) {
+	renderer := NewRenderer(RendererConfig{})
+
+	page := PageData{
+		Body: vdom.Div(
+			vdom.Script(vdom.Text("console.log(1)")),
+		),
+		Styles:    []string{"body{margin:0}"},
+		Scripts:   []ScriptTag{{Inline: "window.x=1", Defer: true}},
+		CSRFToken: "token",
+		SessionID: "session",
+		Nonce:     "abc123",
+	}
+
+	var buf bytes.Buffer
+	if err := renderer.RenderPage(&buf, page); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	html := buf.String()
+
+	wants := []string{
+		`<style nonce="abc123">body{margin:0}</style>`,
+		`<script defer nonce="abc123">window.x=1</script>`,
+		`<script nonce="abc123">window.__VANGO_CSRF__="token";</script>`,
+		`<script nonce="abc123">window.__VANGO_SESSION__="session";</script>`,
+		`<script src="/_vango/client.js" data-debug="true" defer nonce="abc123"></script>`,
+		`<script nonce="abc123" data-hid="h2">console.log(1)</script>`,
+	}
+	for _, want := range wants {
+		if !strings.Contains(html, want) {
+			t.Errorf("should contain %s, got %q", want, html)
+		}
+	}
+	if strings.Count(html, "<script") != strings.Count(html, `nonce="abc123"`)-1 {
+		t.Errorf("every script should carry the nonce, got %q", html)
+	}
+}
+
+func TestRenderPageStatic(t *testing.T) {
+	renderer := NewRenderer(RendererConfig{})
+
+	var buf bytes.Buffer
+	err := renderer.RenderPage(&buf, PageData{
+		Body:      vdom.Div(vdom.Text("Docs")),
+		SessionID: "session",
+		CSRFToken: "token",
+		Static:    true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if strings.Contains(buf.String(), "<script") {
+		t.Errorf("static page should not include the client bootstrap, got %q", buf.String())
+	}
+}
+
+func TestRenderPageLoadToken(t *testing.T) {
+	renderer := NewRenderer(RendererConfig{})
+
+	var buf bytes.Buffer
+	err := renderer.RenderPage(&buf, PageData{
+		Body:      vdom.Div(),
+		LoadToken: "abc123",
+		Nonce:     "n0nce",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := `<script nonce="n0nce">window.__VANGO_LOAD__="abc123";</script>`
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("page should hand over the load token, got %q", buf.String())
+	}
+}
+
+func TestRenderStrictCSPRefusesRawScript(t *testing.T) {
+	strict := NewRenderer(RendererConfig{StrictCSP: true})
+
+	bodies := []*vdom.VNode{
+		vdom.Div(vdom.Raw(`<SCRIPT>alert(1)</SCRIPT>`)),
+		vdom.Div(vdom.Attr{Key: "dangerouslySetInnerHTML", Value: `<p><script src="/x.js"></script></p>`}),
+		vdom.Div(vdom.Raw(`<script/src="/x.js"></script>`)),
+		vdom.Div(vdom.Raw(`<img src=x OnError=alert(1)>`)),
+		vdom.Div(vdom.Raw(`<svg/onload=alert(1)>`)),
+		vdom.Div(vdom.Raw(`<a href="  JaVaScRiPt:alert(1)">x</a>`)),
+		vdom.Div(vdom.Raw(`<a href="java&#x09;script&colon;alert(1)">x</a>`)),
+		vdom.Div(vdom.Raw(`<a href='jav&#97;script:alert(1)'>x</a>`)),
+		vdom.Div(vdom.Raw(`<iframe src=javascript:alert(1)></iframe>`)),
+	}
+	for _, body := range bodies {
+		var buf bytes.Buffer
+		if err := strict.RenderPage(&buf, PageData{Body: body}); err != ErrRawScript {
+			t.Errorf("RenderPage(%s) error = %v, want ErrRawScript", body.Children[0].Text, err)
+		}
+	}
+
+	for _, raw := range []string{
+		"<b>safe</b>",
+		`<a href="/docs/javascript:intro" title="onload">script</a>`,
+		"a < script > b",
+	} {
+		var buf bytes.Buffer
+		if err := strict.RenderPage(&buf, PageData{Body: vdom.Div(vdom.Raw(raw))}); err != nil {
+			t.Errorf("raw HTML %q should render, got %v", raw, err)
+		}
+	}
+}
+
+func TestRenderStrictCSPRefusesInlineScript(t *testing.T) {
+	strict := NewRenderer(RendererConfig{StrictCSP: true})
+
+	pages := []PageData{
+		{Body: vdom.Div(), Scripts: []ScriptTag{{Inline: "alert(1)", Defer: true}}},
+		{Body: vdom.Div(), Scripts: []ScriptTag{{Inline: "import './x.js'", Module: true, Async: true}}},
+		{Body: vdom.Div(vdom.Script(vdom.Text("alert(1)")))},
+	}
+	for i, page := range pages {
+		var buf bytes.Buffer
+		if err := strict.RenderPage(&buf, page); err != ErrInlineScript {
+			t.Errorf("page %d: RenderPage() error = %v, want ErrInlineScript", i, err)
+		}
+	}
+
+	// Script files and data blocks are allowed
+	var buf bytes.Buffer
+	err := strict.RenderPage(&buf, PageData{
+		Body: vdom.Div(vdom.Script(vdom.Type("application/ld+json"), vdom.Text(`{"@type":"Thing"}`))),
+		Scripts: []ScriptTag{
+			{Src: "/app.js", Defer: true},
+			{Type: "application/json", Inline: `{"a":1}`, Async: true},
+		},
+	})
+	if err != nil {
+		t.Errorf("script files and data blocks should render, got %v", err)
+	}
+}
+
+func TestRenderPageStrictCSP(t *testing.T) {
+	renderer := NewRenderer(RendererConfig{})
+	page := PageData{Body: vdom.Div(vdom.Raw("<script>alert(1)</script>"))}
+
+	var buf bytes.Buffer
+	if err := renderer.RenderPage(&buf, page); err != nil {
+		t.Fatalf("without strict mode the page should render, got %v", err)
+	}
+
+	page.StrictCSP = true
+	buf.Reset()
+	if err := renderer.RenderPage(&buf, page); err != ErrRawScript {
+		t.Errorf("PageData.StrictCSP: RenderPage() error = %v, want ErrRawScript", err)
+	}
+}
+
 func TestRenderPageEscaping(t *testing.T) {
 	renderer := NewRenderer(RendererConfig{})
 
@@ -2,11 +2,15 @@ package render
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	stdhtml "html"
 	"io"
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/vango-dev/vango/v2/pkg/head"
 	"github.com/vango-dev/vango/v2/pkg/vdom"
 )
 
@@ -28,13 +32,76 @@ type RendererConfig struct {
 
 	// InlineCriticalCSS indicates whether to inline critical CSS.
 	InlineCriticalCSS bool
+
+	// SuspenseTimeout is how long streaming SSR waits for suspended
+	// boundaries before ending the response with their fallbacks.
+	// Defaults to DefaultSuspenseTimeout if not specified.
+	SuspenseTimeout time.Duration
+
+	// StrictCSP refuses scripts a strict Content-Security-Policy is meant
+	// to keep out: raw HTML that runs script fails with ErrRawScript, and
+	// inline script content with ErrInlineScript, instead of producing a
+	// page that breaks or relies on the nonce for unreviewed code.
+	// PageData.StrictCSP enables it for a single page.
+	StrictCSP bool
 }
 
+// ErrRawScript is returned in strict CSP mode when raw HTML contains a
+// script element, an event handler attribute or a javascript: URL. Use a
+// script file or a client hook instead.
+var ErrRawScript = errors.New("render: raw HTML contains script (not allowed in strict CSP mode)")
+
+// ErrInlineScript is returned in strict CSP mode for a script element or
+// ScriptTag with inline JavaScript. Use a script file instead. Data blocks,
+// such as JSON-LD, are allowed.
+var ErrInlineScript = errors.New("render: inline script (not allowed in strict CSP mode)")
+
+// DefaultSuspenseTimeout is the default RendererConfig.SuspenseTimeout.
+const DefaultSuspenseTimeout = 10 * time.Second
+
 // Renderer handles server-side rendering of VNode trees to HTML.
 type Renderer struct {
 	config     RendererConfig
 	hidCounter uint32
 	handlers   map[string]any
+
+	// scope is the HID scope of the element being rendered ("" for the
+	// document scope); scopes holds the counter of each scope.
+	scope  string
+	scopes map[string]uint32
+
+	// deferSuspense makes pending Suspense boundaries collect into
+	// deferred so their content can be streamed later.
+	deferSuspense bool
+	deferred      []deferredBoundary
+
+	// head collects head declarations while a page body renders;
+	// headDepth is the nesting depth of the node being rendered.
+	head      *head.Head
+	headDepth int
+
+	// nonce is the CSP nonce added to inline scripts and styles; strict
+	// enables strict CSP mode for the page being rendered.
+	nonce  string
+	strict bool
+
+	// portals holds the children of portals rendered before their target;
+	// targets records the portal targets already rendered.
+	portals []renderedPortal
+	targets map[string]bool
+}
+
+// deferredBoundary is a pending Suspense boundary awaiting its content.
+type deferredBoundary struct {
+	hid      string
+	boundary *vdom.SuspenseBoundary
+	pending  []<-chan struct{}
+}
+
+// renderedPortal is the content of a portal awaiting its target.
+type renderedPortal struct {
+	target string
+	html   []byte
 }
 
 // NewRenderer creates a new Renderer with the given configuration.
@@ -42,6 +109,9 @@ func NewRenderer(config RendererConfig) *Renderer {
 	if config.Indent == "" {
 		config.Indent = "  "
 	}
+	if config.SuspenseTimeout <= 0 {
+		config.SuspenseTimeout = DefaultSuspenseTimeout
+	}
 	return &Renderer{
 		config:   config,
 		handlers: make(map[string]any),
@@ -58,8 +128,12 @@ func (r *Renderer) RenderToString(node *vdom.VNode) (string, error) {
 }
 
 // RenderToWriter streams a VNode tree to the given writer.
+// Portals whose target is not in the tree are written after it.
 func (r *Renderer) RenderToWriter(w io.Writer, node *vdom.VNode) error {
-	return r.renderNode(w, node, 0)
+	if err := r.renderNode(w, node, 0); err != nil {
+		return err
+	}
+	return r.flushPortals(w)
 }
 
 // GetHandlers returns the handler registry collected during rendering.
@@ -73,6 +147,13 @@ func (r *Renderer) GetHandlers() map[string]any {
 func (r *Renderer) Reset() {
 	r.hidCounter = 0
 	r.handlers = make(map[string]any)
+	r.scope = ""
+	r.scopes = nil
+	r.deferred = nil
+	r.nonce = ""
+	r.strict = false
+	r.portals = nil
+	r.targets = nil
 }
 
 // renderNode dispatches rendering based on node kind.
@@ -81,6 +162,16 @@ func (r *Renderer) renderNode(w io.Writer, node *vdom.VNode, depth int) error {
 		return nil
 	}
 
+	// Collect head declarations by nesting depth
+	if r.head != nil {
+		if entry := head.EntryOf(node); entry != nil {
+			r.head.Add(r.headDepth, entry)
+			return nil
+		}
+		r.headDepth++
+		defer func() { r.headDepth-- }()
+	}
+
 	switch node.Kind {
 	case vdom.KindElement:
 		return r.renderElement(w, node, depth)
@@ -119,6 +210,19 @@ func (r *Renderer) renderElement(w io.Writer, node *vdom.VNode, depth int) error
 		return err
 	}
 
+	if tag == "script" && r.strictCSP() && len(node.Children) > 0 && isJavaScript(attrToString(node.Props["type"])) {
+		return ErrInlineScript
+	}
+
+	// Inline scripts and styles carry the page's CSP nonce
+	if tag == "script" || tag == "style" {
+		if _, ok := node.Props["nonce"]; !ok {
+			if _, err := w.Write([]byte(r.nonceAttr())); err != nil {
+				return err
+			}
+		}
+	}
+
 	// Check if this element needs a hydration ID
 	if r.needsHID(node) {
 		hid := r.nextHID()
@@ -130,6 +234,26 @@ func (r *Renderer) renderElement(w io.Writer, node *vdom.VNode, depth int) error
 		r.registerHandlers(hid, node)
 	}
 
+	// Suspense boundaries number their children in their own scope
+	if b := vdom.SuspenseOf(node); b != nil {
+		if b.IsPending() && r.deferSuspense {
+			r.deferred = append(r.deferred, deferredBoundary{hid: node.HID, boundary: b, pending: b.Pending})
+		}
+		parentScope := r.scope
+		r.scope = vdom.SuspenseScope(node.HID, b.IsPending())
+		defer func() { r.scope = parentScope }()
+	}
+
+	// Portals number their children in their own scope and render them
+	// into their target, if it comes later
+	target, isPortal := vdom.PortalOf(node)
+	if isPortal {
+		parentScope := r.scope
+		r.scope = vdom.PortalScope(node.HID)
+		defer func() { r.scope = parentScope }()
+	}
+	teleport := isPortal && !r.targets[target]
+
 	// Self-closing check for void elements
 	if isVoidElement(tag) {
 		if _, err := w.Write([]byte{'>'}); err != nil {
@@ -147,6 +271,9 @@ func (r *Renderer) renderElement(w io.Writer, node *vdom.VNode, depth int) error
 
 	// Handle dangerouslySetInnerHTML
 	if rawHTML, ok := node.Props["dangerouslySetInnerHTML"].(string); ok {
+		if r.strictCSP() && containsScript(rawHTML) {
+			return ErrRawScript
+		}
 		if _, err := w.Write([]byte(rawHTML)); err != nil {
 			return err
 		}
@@ -158,8 +285,21 @@ func (r *Renderer) renderElement(w io.Writer, node *vdom.VNode, depth int) error
 		}
 
 		// Render children
-		for _, child := range node.Children {
-			if err := r.renderNode(w, child, depth+1); err != nil {
+		if teleport {
+			if err := r.renderPortal(node, target); err != nil {
+				return err
+			}
+		} else {
+			for _, child := range node.Children {
+				if err := r.renderNode(w, child, depth+1); err != nil {
+					return err
+				}
+			}
+		}
+
+		// Portal targets receive the portals rendered so far
+		if name := vdom.PortalTargetOf(node); name != "" {
+			if err := r.renderTarget(w, name); err != nil {
 				return err
 			}
 		}
@@ -181,6 +321,58 @@ func (r *Renderer) renderElement(w io.Writer, node *vdom.VNode, depth int) error
 	return nil
 }
 
+// renderPortal renders the children of portal into a content element
+// held until target is rendered. Portals coming after their target are
+// rendered in place instead; the client moves their children.
+func (r *Renderer) renderPortal(portal *vdom.VNode, target string) error {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<%s data-portal-for="%s" style="display:contents">`, vdom.PortalContentTag, escapeAttr(portal.HID))
+	for _, child := range portal.Children {
+		if err := r.renderNode(&buf, child, 0); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(&buf, "</%s>", vdom.PortalContentTag)
+
+	r.portals = append(r.portals, renderedPortal{target: target, html: buf.Bytes()})
+	return nil
+}
+
+// renderTarget writes the content of the portals held for target name and
+// marks it rendered.
+func (r *Renderer) renderTarget(w io.Writer, name string) error {
+	if r.targets == nil {
+		r.targets = make(map[string]bool)
+	}
+	r.targets[name] = true
+
+	held := r.portals[:0]
+	for _, p := range r.portals {
+		if p.target != name {
+			held = append(held, p)
+			continue
+		}
+		if _, err := w.Write(p.html); err != nil {
+			return err
+		}
+	}
+	r.portals = held
+	return nil
+}
+
+// flushPortals writes the content of the portals still held, whose target
+// was not rendered. The client moves it to the target, or leaves it at the
+// end of the body.
+func (r *Renderer) flushPortals(w io.Writer) error {
+	for _, p := range r.portals {
+		if _, err := w.Write(p.html); err != nil {
+			return err
+		}
+	}
+	r.portals = nil
+	return nil
+}
+
 // renderText renders a text node with HTML escaping.
 func (r *Renderer) renderText(w io.Writer, node *vdom.VNode) error {
 	escaped := escapeHTML(node.Text)
@@ -210,10 +402,132 @@ func (r *Renderer) renderComponent(w io.Writer, node *vdom.VNode, depth int) err
 
 // renderRaw renders raw HTML without escaping.
 func (r *Renderer) renderRaw(w io.Writer, node *vdom.VNode) error {
+	if r.strictCSP() && containsScript(node.Text) {
+		return ErrRawScript
+	}
 	_, err := w.Write([]byte(node.Text))
 	return err
 }
 
+// strictCSP reports whether strict CSP mode applies to the current render.
+func (r *Renderer) strictCSP() bool {
+	return r.config.StrictCSP || r.strict
+}
+
+// isJavaScript reports whether a script element of the given type runs
+// JavaScript, as opposed to holding data.
+func isJavaScript(typ string) bool {
+	typ = strings.ToLower(strings.TrimSpace(typ))
+	if i := strings.IndexByte(typ, ';'); i >= 0 {
+		typ = strings.TrimSpace(typ[:i])
+	}
+	switch typ {
+	case "", "module", "text/javascript", "application/javascript",
+		"text/ecmascript", "application/ecmascript", "text/jscript":
+		return true
+	}
+	return false
+}
+
+// containsScript reports whether html contains markup that runs script: a
+// script element, an event handler attribute or a javascript: URL. Tags
+// are parsed the way browsers tokenize them, so case, attribute quoting and
+// character references do not hide them.
+func containsScript(html string) bool {
+	for i := 0; i < len(html); i++ {
+		if html[i] != '<' || i+1 >= len(html) || !isASCIILetter(html[i+1]) {
+			continue
+		}
+
+		// Tag name
+		j := i + 1
+		for j < len(html) && !isTagSpace(html[j]) && html[j] != '/' && html[j] != '>' {
+			j++
+		}
+		if strings.EqualFold(html[i+1:j], "script") {
+			return true
+		}
+
+		// Attributes
+		for j < len(html) && html[j] != '>' {
+			if isTagSpace(html[j]) || html[j] == '/' {
+				j++
+				continue
+			}
+			start := j
+			j++
+			for j < len(html) && !isTagSpace(html[j]) && html[j] != '/' && html[j] != '>' && html[j] != '=' {
+				j++
+			}
+			name := strings.ToLower(html[start:j])
+			if strings.HasPrefix(name, "on") {
+				return true
+			}
+
+			for j < len(html) && isTagSpace(html[j]) {
+				j++
+			}
+			if j >= len(html) || html[j] != '=' {
+				continue
+			}
+			j++
+			for j < len(html) && isTagSpace(html[j]) {
+				j++
+			}
+			var value string
+			if j < len(html) && (html[j] == '"' || html[j] == '\'') {
+				end := strings.IndexByte(html[j+1:], html[j])
+				if end < 0 {
+					value, j = html[j+1:], len(html)
+				} else {
+					value, j = html[j+1:j+1+end], j+2+end
+				}
+			} else {
+				start := j
+				for j < len(html) && !isTagSpace(html[j]) && html[j] != '>' {
+					j++
+				}
+				value = html[start:j]
+			}
+			if isJavaScriptURL(value) {
+				return true
+			}
+		}
+		i = j
+	}
+	return false
+}
+
+// isJavaScriptURL reports whether an attribute value is a javascript: URL.
+// Browsers decode character references and ignore leading control
+// characters and spaces, and tabs and newlines anywhere in the URL.
+func isJavaScriptURL(value string) bool {
+	value = stdhtml.UnescapeString(value)
+	value = strings.TrimLeft(value, "\x00\x01\x02\x03\x04\x05\x06\x07\x08\t\n\v\f\r\x0e\x0f"+
+		"\x10\x11\x12\x13\x14\x15\x16\x17\x18\x19\x1a\x1b\x1c\x1d\x1e\x1f ")
+	value = strings.NewReplacer("\t", "", "\n", "", "\r", "").Replace(value)
+	return len(value) >= len("javascript:") && strings.EqualFold(value[:len("javascript:")], "javascript:")
+}
+
+// isASCIILetter reports whether c is an ASCII letter.
+func isASCIILetter(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// isTagSpace reports whether c separates the parts of a tag.
+func isTagSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\f' || c == '\r'
+}
+
+// nonceAttr returns the nonce attribute for inline scripts and styles,
+// or "" if the page has no nonce.
+func (r *Renderer) nonceAttr() string {
+	if r.nonce == "" {
+		return ""
+	}
+	return ` nonce="` + escapeAttr(r.nonce) + `"`
+}
+
 // renderAttributes renders all attributes for an element.
 func (r *Renderer) renderAttributes(w io.Writer, node *vdom.VNode) error {
 	if node.Props == nil {
@@ -311,8 +625,16 @@ func (r *Renderer) needsHID(node *vdom.VNode) bool {
 	return true
 }
 
-// nextHID generates the next sequential hydration ID.
+// nextHID generates the next sequential hydration ID in the current scope.
+// This must match vdom.HIDGenerator so SSR and live sessions agree.
 func (r *Renderer) nextHID() string {
+	if r.scope != "" {
+		if r.scopes == nil {
+			r.scopes = make(map[string]uint32)
+		}
+		r.scopes[r.scope]++
+		return fmt.Sprintf("%s%d", r.scope, r.scopes[r.scope])
+	}
 	r.hidCounter++
 	return fmt.Sprintf("h%d", r.hidCounter)
 }
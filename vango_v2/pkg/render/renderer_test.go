@@ -421,3 +421,65 @@ func TestRenderDataAttributes(t *testing.T) {
 		t.Errorf("should contain data-name, got %q", html)
 	}
 }
+
+func TestRenderPortal(t *testing.T) {
+	dialog := func() *vdom.VNode {
+		return vdom.Portal("modals", vdom.P(vdom.Text("dialog")))
+	}
+
+	t.Run("target after portal", func(t *testing.T) {
+		renderer := NewRenderer(RendererConfig{})
+		html, err := renderer.RenderToString(vdom.Div(dialog(), vdom.PortalTarget("modals")))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := `<div data-hid="h1">` +
+			`<vango-portal data-portal="modals" style="display:contents" data-hid="h2"></vango-portal>` +
+			`<div data-portal-target="modals" data-hid="h3">` +
+			`<vango-portal-content data-portal-for="h2" style="display:contents"><p data-hid="h2-p1">dialog</p></vango-portal-content>` +
+			`</div></div>`
+		if html != want {
+			t.Errorf("got  %q\nwant %q", html, want)
+		}
+	})
+
+	t.Run("target before portal", func(t *testing.T) {
+		renderer := NewRenderer(RendererConfig{})
+		html, err := renderer.RenderToString(vdom.Div(vdom.PortalTarget("modals"), dialog()))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(html, `data-hid="h3"><p data-hid="h3-p1">dialog</p></vango-portal>`) {
+			t.Errorf("portal should render its children in place for the client to move, got %q", html)
+		}
+	})
+
+	t.Run("no target", func(t *testing.T) {
+		renderer := NewRenderer(RendererConfig{})
+		html, err := renderer.RenderToString(vdom.Div(dialog()))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.HasSuffix(html, `</div><vango-portal-content data-portal-for="h2" style="display:contents"><p data-hid="h2-p1">dialog</p></vango-portal-content>`) {
+			t.Errorf("portal content should follow the tree, got %q", html)
+		}
+	})
+
+	t.Run("handlers", func(t *testing.T) {
+		renderer := NewRenderer(RendererConfig{})
+		tree := vdom.Div(vdom.Portal("modals", vdom.Button(vdom.OnClick(func() {}))), vdom.PortalTarget("modals"))
+		if _, err := renderer.RenderToString(tree); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := renderer.GetHandlers()["h2-p1_onclick"]; !ok {
+			t.Errorf("handlers = %v, want h2-p1_onclick", renderer.GetHandlers())
+		}
+
+		// A live session assigns the same HIDs
+		live := vdom.Div(vdom.Portal("modals", vdom.Button(vdom.OnClick(func() {}))), vdom.PortalTarget("modals"))
+		vdom.AssignHIDs(live, vdom.NewHIDGenerator())
+		if hid := live.Children[0].Children[0].HID; hid != "h2-p1" {
+			t.Errorf("live HID = %q, want h2-p1", hid)
+		}
+	})
+}
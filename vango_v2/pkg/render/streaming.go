@@ -1,13 +1,22 @@
 package render
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"net/http"
+	"time"
+
+	"github.com/vango-dev/vango/v2/pkg/vdom"
 )
 
 // StreamingRenderer wraps Renderer with chunked output support.
 // It flushes content incrementally for faster time-to-first-byte.
+//
+// Suspense boundaries that are still pending when the body is rendered are
+// sent with their fallback. Their content is streamed afterwards, in the
+// order it resolves, as a <template> chunk with a small inline script that
+// swaps it into place.
 type StreamingRenderer struct {
 	*Renderer
 	flusher http.Flusher
@@ -19,15 +28,18 @@ type StreamingRenderer struct {
 // content will be flushed after each section for faster TTFB.
 func NewStreamingRenderer(w http.ResponseWriter, config RendererConfig) *StreamingRenderer {
 	flusher, _ := w.(http.Flusher)
+	renderer := NewRenderer(config)
+	renderer.deferSuspense = true
 	return &StreamingRenderer{
-		Renderer: NewRenderer(config),
+		Renderer: renderer,
 		flusher:  flusher,
 		w:        w,
 	}
 }
 
 // RenderPage renders a complete HTML document with incremental flushing.
-// The head section is flushed immediately for faster first paint.
+// The head section is flushed as soon as the shell has rendered, before
+// suspended content resolves.
 func (s *StreamingRenderer) RenderPage(page PageData) error {
 	// Set default language
 	lang := page.Lang
@@ -40,13 +52,28 @@ func (s *StreamingRenderer) RenderPage(page PageData) error {
 		return err
 	}
 
-	// HTML tag with lang
-	if _, err := fmt.Fprintf(s.w, `<html lang="%s">`+"\n", escapeAttr(lang)); err != nil {
+	// HTML tag with lang and optional dir
+	dir := ""
+	if page.Dir != "" {
+		dir = ` dir="` + escapeAttr(page.Dir) + `"`
+	}
+	if _, err := fmt.Fprintf(s.w, `<html lang="%s"%s>`+"\n", escapeAttr(lang), dir); err != nil {
+		return err
+	}
+
+	s.nonce = page.Nonce
+	s.strict = page.StrictCSP
+
+	// Render the shell first so its head declarations are known.
+	// Suspended content is streamed after the head has been sent, so
+	// declarations inside pending boundaries apply once the session connects.
+	body, docHead, err := s.renderBody(page)
+	if err != nil {
 		return err
 	}
 
 	// Head section
-	if err := s.renderHead(s.w, page); err != nil {
+	if err := s.renderHead(s.w, page, docHead); err != nil {
 		return err
 	}
 
@@ -59,13 +86,19 @@ func (s *StreamingRenderer) RenderPage(page PageData) error {
 	}
 
 	// Main content
-	if err := s.RenderToWriter(s.w, page.Body); err != nil {
+	if _, err := s.w.Write(body); err != nil {
 		return err
 	}
 
 	// Flush body content
 	s.flush()
 
+	// Stream suspended boundaries as they resolve. The client script comes
+	// after them so the live session connects to the final DOM.
+	if err := s.streamSuspended(); err != nil {
+		return err
+	}
+
 	// Inject Vango client script
 	if err := s.renderClientScript(s.w, page); err != nil {
 		return err
@@ -82,6 +115,118 @@ func (s *StreamingRenderer) RenderPage(page PageData) error {
 	return nil
 }
 
+// suspenseBootstrap defines the swap function used by streamed chunks.
+// It is a format string taking the nonce attribute of the script.
+const suspenseBootstrap = `<script%s>window.__vangoSuspense=function(id){` +
+	`var t=document.querySelector('template[data-suspense-chunk="'+id+'"]'),` +
+	`b=document.querySelector('` + vdom.SuspenseTag + `[data-hid="'+id+'"]');` +
+	`if(t&&b){b.replaceChildren(t.content);b.setAttribute("data-suspense","ready")}` +
+	`if(t)t.remove();var s=document.currentScript;if(s)s.remove()}</script>` + "\n"
+
+// streamSuspended waits for deferred Suspense boundaries and streams the
+// content of each one as soon as it resolves. Boundaries discovered inside
+// streamed content are handled the same way. Boundaries still pending after
+// SuspenseTimeout keep their fallback.
+func (s *StreamingRenderer) streamSuspended() error {
+	if len(s.deferred) == 0 {
+		return nil
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	settled := make(chan int)
+
+	var queue []deferredBoundary
+	wait := func(i int, pending []<-chan struct{}) {
+		go func() {
+			for _, ch := range pending {
+				select {
+				case <-ch:
+				case <-stop:
+					return
+				}
+			}
+			select {
+			case settled <- i:
+			case <-stop:
+			}
+		}()
+	}
+	enqueue := func() int {
+		n := len(s.deferred)
+		for _, d := range s.deferred {
+			queue = append(queue, d)
+			wait(len(queue)-1, d.pending)
+		}
+		s.deferred = nil
+		return n
+	}
+
+	remaining := enqueue()
+	deadline := time.NewTimer(s.config.SuspenseTimeout)
+	defer deadline.Stop()
+	bootstrapped := false
+
+	for remaining > 0 {
+		select {
+		case i := <-settled:
+			d := queue[i]
+			content, pending := d.boundary.Resolve()
+			if len(pending) > 0 {
+				// Resolved into more async work; keep waiting
+				wait(i, pending)
+				continue
+			}
+			remaining--
+
+			var buf bytes.Buffer
+			if !bootstrapped {
+				fmt.Fprintf(&buf, suspenseBootstrap, s.nonceAttr())
+				bootstrapped = true
+			}
+			if err := s.renderSuspenseChunk(&buf, d.hid, content); err != nil {
+				return err
+			}
+			remaining += enqueue()
+
+			if _, err := s.w.Write(buf.Bytes()); err != nil {
+				return err
+			}
+			s.flush()
+
+		case <-deadline.C:
+			s.deferred = nil
+			return nil
+		}
+	}
+	return nil
+}
+
+// renderSuspenseChunk writes the resolved content of boundary hid as a
+// template followed by the script that swaps it in.
+func (s *StreamingRenderer) renderSuspenseChunk(w *bytes.Buffer, hid string, content *vdom.VNode) error {
+	fmt.Fprintf(w, `<template data-suspense-chunk="%s">`, escapeAttr(hid))
+
+	parentScope := s.scope
+	s.scope = vdom.SuspenseScope(hid, false)
+	err := s.renderNode(w, content, 0)
+	s.scope = parentScope
+	if err != nil {
+		return err
+	}
+
+	w.WriteString("</template>")
+
+	// Content of portals whose target was not rendered goes outside the
+	// boundary
+	if err := s.flushPortals(w); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "<script%s>__vangoSuspense(%q)</script>\n", s.nonceAttr(), hid)
+	return nil
+}
+
 // flush flushes the writer if it supports flushing.
 func (s *StreamingRenderer) flush() {
 	if s.flusher != nil {
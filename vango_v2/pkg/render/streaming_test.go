@@ -5,7 +5,9 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/vango-dev/vango/v2/pkg/vango"
 	"github.com/vango-dev/vango/v2/pkg/vdom"
 )
 
@@ -157,3 +159,142 @@ func TestStreamingRendererLargeContent(t *testing.T) {
 		t.Errorf("should contain last item")
 	}
 }
+
+// suspendingContent renders text once ready is closed and suspends until then.
+func suspendingContent(ready chan struct{}, text string) func() *vdom.VNode {
+	return func() *vdom.VNode {
+		select {
+		case <-ready:
+			return vdom.P(vdom.Text(text))
+		default:
+			vango.Suspend(ready)
+			return nil
+		}
+	}
+}
+
+func TestStreamingRendererSuspenseOutOfOrder(t *testing.T) {
+	slowReady := make(chan struct{})
+	fastReady := make(chan struct{})
+
+	body := vdom.Div(
+		vdom.Suspense(vdom.Span(vdom.Text("loading slow")), suspendingContent(slowReady, "slow done")),
+		vdom.Suspense(vdom.Span(vdom.Text("loading fast")), suspendingContent(fastReady, "fast done")),
+		vdom.Footer(vdom.Text("footer")),
+	)
+
+	var buf bytes.Buffer
+	fw := &FlushableWriter{Writer: &buf}
+	sr := &StreamingRenderer{Renderer: NewRenderer(RendererConfig{}), flusher: fw, w: fw}
+	sr.deferSuspense = true
+
+	go func() {
+		close(fastReady)
+		time.Sleep(20 * time.Millisecond)
+		close(slowReady)
+	}()
+
+	if err := sr.RenderPage(PageData{Body: body, Title: "Suspense"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	html := buf.String()
+
+	// Fallbacks are part of the shell
+	if !strings.Contains(html, "loading slow") || !strings.Contains(html, "loading fast") {
+		t.Errorf("shell should contain both fallbacks, got %q", html)
+	}
+	// Siblings after a boundary keep document-scope HIDs
+	if !strings.Contains(html, `<footer data-hid="h4">footer</footer>`) {
+		t.Errorf("footer HID should not depend on boundary contents, got %q", html)
+	}
+
+	fast := strings.Index(html, `<template data-suspense-chunk="h3">`)
+	slow := strings.Index(html, `<template data-suspense-chunk="h2">`)
+	if fast < 0 || slow < 0 {
+		t.Fatalf("both boundaries should be streamed, got %q", html)
+	}
+	if fast > slow {
+		t.Error("the boundary that resolved first should be streamed first")
+	}
+	if strings.Count(html, "window.__vangoSuspense=") != 1 {
+		t.Error("swap script should be defined once")
+	}
+	if !strings.Contains(html, `<p data-hid="h3-1">fast done</p>`) {
+		t.Errorf("streamed content should use scoped HIDs, got %q", html)
+	}
+	if client := strings.Index(html, "/_vango/client.js"); client < slow {
+		t.Error("client script should come after streamed chunks")
+	}
+
+	// A live session rendering the resolved tree assigns the same HIDs
+	live := vdom.Div(
+		vdom.Suspense(nil, suspendingContent(slowReady, "slow done")),
+		vdom.Suspense(nil, suspendingContent(fastReady, "fast done")),
+		vdom.Footer(vdom.Text("footer")),
+	)
+	vdom.AssignHIDs(live, vdom.NewHIDGenerator())
+	if p := live.Children[1].Children[0].Children[0]; p.HID != "h3-1" {
+		t.Errorf("live content HID = %q, want h3-1", p.HID)
+	}
+}
+
+func TestStreamingRendererSuspenseTimeout(t *testing.T) {
+	never := make(chan struct{})
+	body := vdom.Div(vdom.Suspense(vdom.Span(vdom.Text("loading")), suspendingContent(never, "done")))
+
+	var buf bytes.Buffer
+	fw := &FlushableWriter{Writer: &buf}
+	sr := &StreamingRenderer{
+		Renderer: NewRenderer(RendererConfig{SuspenseTimeout: 10 * time.Millisecond}),
+		flusher:  fw,
+		w:        fw,
+	}
+	sr.deferSuspense = true
+
+	if err := sr.RenderPage(PageData{Body: body}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	html := buf.String()
+	if !strings.Contains(html, "loading") || strings.Contains(html, "data-suspense-chunk") {
+		t.Errorf("timed out boundary should keep its fallback, got %q", html)
+	}
+	if !strings.HasSuffix(html, "</html>\n") {
+		t.Error("document should still be completed")
+	}
+}
+
+// sealRecorder is a Handover recording how much of the page was written
+// when it was sealed.
+type sealRecorder struct {
+	buf    *bytes.Buffer
+	sealed int
+}
+
+func (h *sealRecorder) Seal()         { h.sealed = h.buf.Len() }
+func (h *sealRecorder) Token() string { return "tok" }
+
+func TestStreamingRendererSealsResourcesAfterSuspense(t *testing.T) {
+	ready := make(chan struct{})
+	body := vdom.Div(vdom.Suspense(vdom.Span(vdom.Text("loading")), suspendingContent(ready, "done")))
+
+	var buf bytes.Buffer
+	fw := &FlushableWriter{Writer: &buf}
+	sr := &StreamingRenderer{Renderer: NewRenderer(RendererConfig{}), flusher: fw, w: fw}
+	sr.deferSuspense = true
+	h := &sealRecorder{buf: &buf}
+
+	go close(ready)
+	if err := sr.RenderPage(PageData{Body: body, Resources: h}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	html := buf.String()
+
+	chunk := strings.Index(html, "__vangoSuspense(")
+	if chunk < 0 || h.sealed < chunk {
+		t.Errorf("resources sealed at %d, before the streamed chunk at %d", h.sealed, chunk)
+	}
+	token := strings.Index(html, `window.__VANGO_RESOURCES__="tok"`)
+	if token < 0 || token > strings.Index(html, "/_vango/client.js") {
+		t.Errorf("resource token should precede the client script, got %q", html)
+	}
+}
@@ -0,0 +1,33 @@
+// Package replay re-drives recorded sessions against an app to reproduce
+// bugs.
+//
+// It backs the `vango replay` command. Sessions are recorded by the server
+// when ServerConfig.RecordSession is set:
+//
+//	config.RecordSession = server.RecordToDir("recordings")
+//
+// A recording holds the handshake, the events sent by the client and the
+// frames sent by the server, with timestamps (see protocol.RecordingWriter).
+// The events carry whatever the user typed or submitted, so treat
+// recordings as user data. The CSRF token of the handshake is not
+// recorded; Run fetches a fresh one.
+//
+// # Replaying
+//
+// Run opens a new session at the recorded URL of a running app, sends the
+// recorded events in order and compares each patches frame the app sends
+// with the recorded one:
+//
+//	rec, err := replay.LoadFile("recordings/4f9c2e.vrec")
+//	result, err := replay.Run(ctx, rec, replay.Options{URL: "http://localhost:3000"})
+//	if result.Diverged() {
+//	    result.WriteText(os.Stdout)
+//	}
+//
+// Each event is sent once the frames recorded before it arrived, or at the
+// recorded time with Options.Realtime. Patch sequence numbers, heartbeats
+// and acknowledgments are not compared.
+//
+// Replays are deterministic as long as the app is: components reading the
+// clock, random numbers or external data may differ between runs.
+package replay
@@ -0,0 +1,336 @@
+package replay
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/vango-dev/vango/v2/pkg/protocol"
+	"github.com/vango-dev/vango/v2/pkg/server"
+)
+
+// DefaultTimeout is how long Run waits for each recorded frame.
+const DefaultTimeout = 2 * time.Second
+
+// ErrNoHandshake is returned for recordings without a ClientHello.
+var ErrNoHandshake = errors.New("replay: recording has no handshake")
+
+// Options configure a replay.
+type Options struct {
+	// URL is the base URL of the app, e.g. "http://localhost:3000".
+	URL string
+
+	// Realtime waits between events as long as in the recording. By default
+	// each event is sent as soon as the frames before it arrived.
+	Realtime bool
+
+	// Timeout is how long to wait for each recorded frame.
+	// Default: DefaultTimeout
+	Timeout time.Duration
+
+	// Dialer connects the WebSocket. Default: websocket.DefaultDialer
+	Dialer *websocket.Dialer
+
+	// HTTPClient fetches the page for the CSRF cookie. Default: http.DefaultClient
+	HTTPClient *http.Client
+}
+
+// Recording is a session recording loaded for replay.
+type Recording struct {
+	Header   protocol.RecordingHeader
+	Messages []protocol.RecordedMessage
+}
+
+// Load reads a recording from r.
+func Load(r io.Reader) (*Recording, error) {
+	h, messages, err := protocol.ReadRecording(r)
+	if err != nil {
+		return nil, err
+	}
+	return &Recording{Header: h, Messages: messages}, nil
+}
+
+// LoadFile reads a recording file.
+func LoadFile(path string) (*Recording, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Load(f)
+}
+
+// PagePath returns the path of the page the recorded session started on.
+func (rec *Recording) PagePath() string {
+	u, err := url.Parse(rec.Header.Path)
+	if err != nil {
+		return "/"
+	}
+	if p := u.Query().Get("path"); strings.HasPrefix(p, "/") {
+		return p
+	}
+	return "/"
+}
+
+// Events returns the number of recorded events.
+func (rec *Recording) Events() int {
+	n := 0
+	for _, m := range rec.Messages[min(1, len(rec.Messages)):] {
+		if m.Dir == protocol.RecordIn {
+			n++
+		}
+	}
+	return n
+}
+
+// Run replays rec against the app at opts.URL: it starts a new session at
+// the recorded URL, sends the recorded events in order and compares the
+// frames the app sends with the recorded ones. It returns an error only if
+// the replay could not run; differences are reported in the result.
+func Run(ctx context.Context, rec *Recording, opts Options) (*Result, error) {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if len(rec.Messages) == 0 || rec.Messages[0].Dir != protocol.RecordIn {
+		return nil, ErrNoHandshake
+	}
+	hello, err := protocol.DecodeClientHello(rec.Messages[0].Data)
+	if err != nil {
+		return nil, fmt.Errorf("replay: recorded handshake: %w", err)
+	}
+
+	conn, err := connect(ctx, rec, hello, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	// Read the app's frames in the background
+	frames := make(chan *protocol.Frame, 64)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		defer close(frames)
+		for {
+			_, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			frame, err := protocol.DecodeFrame(msg)
+			if err != nil || !compared(frame.Type) {
+				continue
+			}
+			select {
+			case frames <- frame:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	result := &Result{Header: rec.Header}
+	start := time.Now()
+
+	next := func() (*protocol.Frame, bool) {
+		timer := time.NewTimer(opts.Timeout)
+		defer timer.Stop()
+		select {
+		case f, ok := <-frames:
+			return f, ok
+		case <-timer.C:
+			return nil, false
+		case <-ctx.Done():
+			return nil, false
+		}
+	}
+
+	for _, m := range rec.Messages[1:] {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		frame, err := protocol.DecodeFrame(m.Data)
+		if err != nil {
+			continue
+		}
+
+		switch m.Dir {
+		case protocol.RecordIn:
+			if opts.Realtime {
+				if wait := m.Offset - time.Since(start); wait > 0 {
+					select {
+					case <-time.After(wait):
+					case <-ctx.Done():
+						return result, ctx.Err()
+					}
+				}
+			}
+			if err := conn.WriteMessage(websocket.BinaryMessage, m.Data); err != nil {
+				return result, fmt.Errorf("replay: send event: %w", err)
+			}
+			result.Events++
+			result.Steps = append(result.Steps, Step{Dir: protocol.RecordIn, Offset: m.Offset, Want: describeFrame(frame)})
+
+		case protocol.RecordOut:
+			if !compared(frame.Type) {
+				continue
+			}
+			// Frames are compared in order: after a missing or extra frame,
+			// the following ones are reported as well
+			s := Step{Dir: protocol.RecordOut, Offset: m.Offset, Want: describeFrame(frame)}
+			if got, ok := next(); ok {
+				s.Got = describeFrame(got)
+				s.Match = sameFrame(frame, got)
+			}
+			if !s.Match {
+				result.Divergences = append(result.Divergences, len(result.Steps))
+			}
+			result.Steps = append(result.Steps, s)
+		}
+	}
+
+	// Frames the recording does not have
+	drain := time.NewTimer(opts.Timeout / 4)
+	defer drain.Stop()
+	for {
+		select {
+		case f, ok := <-frames:
+			if !ok {
+				return result, nil
+			}
+			result.Divergences = append(result.Divergences, len(result.Steps))
+			result.Steps = append(result.Steps, Step{Dir: protocol.RecordOut, Got: describeFrame(f)})
+		case <-drain.C:
+			return result, nil
+		case <-ctx.Done():
+			return result, ctx.Err()
+		}
+	}
+}
+
+// connect opens a session at the recorded URL and completes the handshake.
+func connect(ctx context.Context, rec *Recording, hello *protocol.ClientHello, opts Options) (*websocket.Conn, error) {
+	base, err := url.Parse(opts.URL)
+	if err != nil {
+		return nil, fmt.Errorf("replay: invalid URL %q: %w", opts.URL, err)
+	}
+
+	// Fetch the page for a fresh CSRF token; the recorded one is stale
+	header := http.Header{}
+	hello.SessionID = ""
+	hello.CSRFToken = ""
+	if token, err := csrfToken(ctx, base, rec.PagePath(), opts.HTTPClient); err == nil && token != "" {
+		hello.CSRFToken = token
+		header.Set("Cookie", (&http.Cookie{Name: server.CSRFCookieName, Value: token}).String())
+	}
+
+	wsURL := *base
+	switch base.Scheme {
+	case "https":
+		wsURL.Scheme = "wss"
+	default:
+		wsURL.Scheme = "ws"
+	}
+	path, err := url.Parse(rec.Header.Path)
+	if err != nil || path.Path == "" {
+		path = &url.URL{Path: "/_vango/live"}
+	}
+	wsURL.Path = path.Path
+	wsURL.RawQuery = path.RawQuery
+	header.Set("Origin", base.Scheme+"://"+base.Host)
+
+	dialer := opts.Dialer
+	if dialer == nil {
+		dialer = websocket.DefaultDialer
+	}
+	conn, _, err := dialer.DialContext(ctx, wsURL.String(), header)
+	if err != nil {
+		return nil, fmt.Errorf("replay: connect to %s: %w", wsURL.String(), err)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(opts.Timeout))
+	if err := conn.WriteMessage(websocket.BinaryMessage, protocol.EncodeClientHello(hello)); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("replay: handshake: %w", err)
+	}
+	_, msg, err := conn.ReadMessage()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("replay: handshake: %w", err)
+	}
+	conn.SetReadDeadline(time.Time{})
+
+	frame, err := protocol.DecodeFrame(msg)
+	if err != nil || frame.Type != protocol.FrameHandshake {
+		conn.Close()
+		return nil, errors.New("replay: handshake: unexpected response")
+	}
+	sh, err := protocol.DecodeServerHello(frame.Payload)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("replay: handshake: %w", err)
+	}
+	if sh.Status != protocol.HandshakeOK {
+		conn.Close()
+		return nil, fmt.Errorf("replay: handshake rejected: %s", sh.Status)
+	}
+	return conn, nil
+}
+
+// csrfToken loads the page at path and returns its CSRF cookie.
+func csrfToken(ctx context.Context, base *url.URL, path string, client *http.Client) (string, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	page := base.ResolveReference(&url.URL{Path: path})
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, page.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	for _, c := range resp.Cookies() {
+		if c.Name == server.CSRFCookieName {
+			return c.Value, nil
+		}
+	}
+	return "", nil
+}
+
+// compared reports whether frames of type ft are compared by a replay.
+// Handshakes, heartbeats and acknowledgments differ between runs.
+func compared(ft protocol.FrameType) bool {
+	return ft == protocol.FramePatches || ft == protocol.FrameError
+}
+
+// sameFrame reports whether two frames have the same content. Patch
+// sequence numbers are ignored.
+func sameFrame(want, got *protocol.Frame) bool {
+	if want.Type != got.Type {
+		return false
+	}
+	if want.Type != protocol.FramePatches {
+		return bytes.Equal(want.Payload, got.Payload)
+	}
+
+	wp, err1 := protocol.DecodePatches(want.Payload)
+	gp, err2 := protocol.DecodePatches(got.Payload)
+	if err1 != nil || err2 != nil {
+		return bytes.Equal(want.Payload, got.Payload)
+	}
+	wp.Seq, gp.Seq = 0, 0
+	return bytes.Equal(protocol.EncodePatches(wp), protocol.EncodePatches(gp))
+}
@@ -0,0 +1,197 @@
+package replay
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/vango-dev/vango/v2/pkg/protocol"
+	"github.com/vango-dev/vango/v2/pkg/server"
+	"github.com/vango-dev/vango/v2/pkg/vango"
+	"github.com/vango-dev/vango/v2/pkg/vdom"
+)
+
+// counter renders a label and a button incrementing count.
+func counter(label string, count *vango.Signal[int]) *vdom.VNode {
+	return vdom.Div(
+		vdom.Span(vdom.Text(fmt.Sprintf("%s %d", label, count.Get()))),
+		vdom.Button(vdom.OnClick(func() { count.Set(count.Get() + 1) }), vdom.Text("+")),
+	)
+}
+
+// buttonHID returns the HID the server assigns to the counter's button.
+func buttonHID() string {
+	tree := counter("", vango.NewSignal(0))
+	vdom.AssignHIDs(tree, vdom.NewHIDGenerator())
+	return tree.Children[1].HID
+}
+
+// recordingBuffer is a writer that reports when the session closed it.
+type recordingBuffer struct {
+	mu     sync.Mutex
+	buf    bytes.Buffer
+	closed chan struct{}
+}
+
+func (b *recordingBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *recordingBuffer) Close() error {
+	close(b.closed)
+	return nil
+}
+
+// counterApp serves a counter showing label and records its sessions to
+// rec, if set.
+func counterApp(t *testing.T, label string, rec *recordingBuffer) *httptest.Server {
+	t.Helper()
+
+	config := server.DefaultServerConfig()
+	if rec != nil {
+		config.RecordSession = func(r *http.Request, s *server.Session) io.Writer {
+			return rec
+		}
+	}
+	srv := server.New(config)
+	srv.SetRootComponent(func() server.Component {
+		count := vango.NewSignal(0)
+		return server.FuncComponent(func() *vdom.VNode {
+			return counter(label, count)
+		})
+	})
+
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// clickTwice connects to the app like the thin client and clicks the
+// button twice.
+func clickTwice(t *testing.T, ts *httptest.Server) {
+	t.Helper()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http")+"/_vango/live?path=%2F", nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+
+	conn.WriteMessage(websocket.BinaryMessage, protocol.EncodeClientHello(protocol.NewClientHello("")))
+	if _, _, err := conn.ReadMessage(); err != nil {
+		t.Fatalf("handshake: %v", err)
+	}
+
+	hid := buttonHID()
+	for seq := uint64(1); seq <= 2; seq++ {
+		event := protocol.EncodeEvent(&protocol.Event{Seq: seq, Type: protocol.EventClick, HID: hid})
+		conn.WriteMessage(websocket.BinaryMessage, protocol.NewFrame(protocol.FrameEvent, event).Encode())
+		for {
+			_, msg, err := conn.ReadMessage()
+			if err != nil {
+				t.Fatalf("read patches: %v", err)
+			}
+			if f, _ := protocol.DecodeFrame(msg); f != nil && f.Type == protocol.FramePatches {
+				break
+			}
+		}
+	}
+}
+
+// record records a session clicking the counter twice.
+func record(t *testing.T) *Recording {
+	t.Helper()
+
+	buf := &recordingBuffer{closed: make(chan struct{})}
+	clickTwice(t, counterApp(t, "Count", buf))
+
+	select {
+	case <-buf.closed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("the session did not close its recording")
+	}
+	rec, err := Load(bytes.NewReader(buf.buf.Bytes()))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	return rec
+}
+
+func TestRecordSession(t *testing.T) {
+	rec := record(t)
+
+	if rec.Header.Path != "/_vango/live?path=%2F" || rec.PagePath() != "/" {
+		t.Errorf("header = %+v", rec.Header)
+	}
+	if rec.Events() != 2 {
+		t.Errorf("Events() = %d, want 2", rec.Events())
+	}
+
+	var types []string
+	for _, m := range rec.Messages[1:] {
+		f, err := protocol.DecodeFrame(m.Data)
+		if err != nil {
+			t.Fatalf("recorded frame: %v", err)
+		}
+		types = append(types, m.Dir.String()+" "+f.Type.String())
+	}
+	want := "Out Handshake, In Event, Out Patches, In Event, Out Patches"
+	if got := strings.Join(types, ", "); got != want {
+		t.Errorf("recorded %s, want %s", got, want)
+	}
+}
+
+func TestRun(t *testing.T) {
+	rec := record(t)
+
+	result, err := Run(context.Background(), rec, Options{URL: counterApp(t, "Count", nil).URL})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if result.Diverged() || result.Events != 2 || len(result.Steps) != 4 {
+		var out bytes.Buffer
+		result.WriteText(&out)
+		t.Errorf("replay against the same app:\n%s", out.String())
+	}
+
+	// The app changed since the recording
+	result, err = Run(context.Background(), rec, Options{
+		URL:     counterApp(t, "Total", nil).URL,
+		Timeout: 500 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if len(result.Divergences) != 2 {
+		t.Fatalf("Divergences = %v, want both patch frames", result.Divergences)
+	}
+	first := result.FirstDivergence()
+	if first.Match || !strings.Contains(first.Want[0], `"Count 1"`) || !strings.Contains(first.Got[0], `"Total 1"`) {
+		t.Errorf("first divergence = %+v", first)
+	}
+
+	var text, report bytes.Buffer
+	result.WriteText(&text)
+	if !strings.Contains(text.String(), "2 frames differ") || !strings.Contains(text.String(), "after 1 events") {
+		t.Errorf("WriteText() = %s", text.String())
+	}
+	if err := result.WriteHTML(&report); err != nil || !strings.Contains(report.String(), `class="diff"`) {
+		t.Errorf("WriteHTML() error = %v", err)
+	}
+}
+
+func TestRunNoHandshake(t *testing.T) {
+	if _, err := Run(context.Background(), &Recording{}, Options{URL: "http://localhost"}); err != ErrNoHandshake {
+		t.Errorf("Run() error = %v, want ErrNoHandshake", err)
+	}
+}
@@ -0,0 +1,171 @@
+package replay
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+
+	"github.com/vango-dev/vango/v2/pkg/protocol"
+)
+
+// Result is the outcome of a replay.
+type Result struct {
+	// Header describes the recorded session
+	Header protocol.RecordingHeader
+
+	// Events is the number of events sent
+	Events int
+
+	// Steps are the events sent and the frames compared, in order
+	Steps []Step
+
+	// Divergences are the indexes of the steps whose frames differ
+	Divergences []int
+}
+
+// Step is an event sent or a frame compared during a replay.
+type Step struct {
+	// Dir is RecordIn for events and RecordOut for frames of the app
+	Dir protocol.RecordDirection
+
+	// Offset is the time of the recorded message
+	Offset time.Duration
+
+	// Want describes the recorded message; empty for frames the recording
+	// does not have
+	Want []string
+
+	// Got describes the frame sent by the app; empty if it sent none
+	Got []string
+
+	// Match reports whether the app sent the recorded frame
+	Match bool
+}
+
+// Diverged reports whether the app sent different frames than recorded.
+func (r *Result) Diverged() bool {
+	return len(r.Divergences) > 0
+}
+
+// FirstDivergence returns the first differing step, or nil.
+func (r *Result) FirstDivergence() *Step {
+	if !r.Diverged() {
+		return nil
+	}
+	return &r.Steps[r.Divergences[0]]
+}
+
+// WriteText writes a summary of the replay and its divergences.
+func (r *Result) WriteText(w io.Writer) {
+	fmt.Fprintf(w, "Session %s at %s: %d events, %d frames differ\n",
+		r.Header.SessionID, r.Header.Path, r.Events, len(r.Divergences))
+
+	for _, i := range r.Divergences {
+		s := r.Steps[i]
+		events := 0
+		for _, prev := range r.Steps[:i] {
+			if prev.Dir == protocol.RecordIn {
+				events++
+			}
+		}
+
+		fmt.Fprintf(w, "\nStep %d (after %d events, at %s):\n", i+1, events, s.Offset.Round(time.Millisecond))
+		switch {
+		case len(s.Want) == 0:
+			fmt.Fprintln(w, "  unexpected frame:")
+		case len(s.Got) == 0:
+			fmt.Fprintln(w, "  missing frame:")
+		}
+		for _, line := range s.Want {
+			fmt.Fprintf(w, "  - %s\n", line)
+		}
+		for _, line := range s.Got {
+			fmt.Fprintf(w, "  + %s\n", line)
+		}
+	}
+}
+
+// WriteHTML writes the replay as an HTML report.
+func (r *Result) WriteHTML(w io.Writer) error {
+	return reportTemplate.Execute(w, r)
+}
+
+// describeFrame describes a frame, one line per patch.
+func describeFrame(f *protocol.Frame) []string {
+	switch f.Type {
+	case protocol.FramePatches:
+		pf, err := protocol.DecodePatches(f.Payload)
+		if err != nil {
+			return []string{"invalid patches: " + err.Error()}
+		}
+		if len(pf.Patches) == 0 {
+			return []string{"no patches"}
+		}
+		lines := make([]string, len(pf.Patches))
+		for i, p := range pf.Patches {
+			lines[i] = describePatch(p)
+		}
+		return lines
+
+	case protocol.FrameEvent:
+		e, err := protocol.DecodeEvent(f.Payload)
+		if err != nil {
+			return []string{"invalid event: " + err.Error()}
+		}
+		return []string{fmt.Sprintf("%s %s", e.Type, e.HID)}
+
+	case protocol.FrameError:
+		e, err := protocol.DecodeErrorMessage(f.Payload)
+		if err != nil {
+			return []string{"invalid error: " + err.Error()}
+		}
+		return []string{fmt.Sprintf("error %s: %s", e.Code, e.Message)}
+
+	default:
+		return []string{fmt.Sprintf("%s frame, %d bytes", f.Type, len(f.Payload))}
+	}
+}
+
+// describePatch describes a patch in one line.
+func describePatch(p protocol.Patch) string {
+	desc := p.Op.String() + " " + p.HID
+	if p.Key != "" {
+		desc += " " + p.Key
+	}
+	if p.Value != "" {
+		desc += fmt.Sprintf(" = %q", p.Value)
+	}
+	if p.Node != nil {
+		desc += " <" + p.Node.Tag + ">"
+	}
+	return desc
+}
+
+var reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html><head><title>Replay of {{.Header.SessionID}}</title>
+<style>
+body { font: 14px/1.4 system-ui, sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { border-bottom: 1px solid #ddd; padding: 4px 8px; text-align: left; vertical-align: top; }
+code, pre { font: 12px ui-monospace, monospace; margin: 0; }
+.in { color: #555; }
+.diff { background: #fee; }
+</style></head><body>
+<h1>Replay of <code>{{.Header.SessionID}}</code></h1>
+<p>Recorded {{.Header.Start.Format "2006-01-02 15:04:05"}} at <code>{{.Header.Path}}</code>.
+{{.Events}} events, {{if .Diverged}}<strong>{{len .Divergences}} frames differ</strong>{{else}}all frames match{{end}}.</p>
+<table>
+<tr><th>#</th><th>Time</th><th></th><th>Recorded</th><th>Replayed</th></tr>
+{{range $i, $s := .Steps}}{{if eq $s.Dir 0}}<tr class="in">
+<td>{{$i}}</td><td>{{$s.Offset}}</td><td>event</td><td colspan="2"><pre>{{range $s.Want}}{{.}}
+{{end}}</pre></td>
+</tr>{{else}}<tr{{if not $s.Match}} class="diff"{{end}}>
+<td>{{$i}}</td><td>{{$s.Offset}}</td><td>{{if $s.Match}}same{{else}}differs{{end}}</td>
+<td><pre>{{range $s.Want}}{{.}}
+{{end}}</pre></td>
+<td><pre>{{range $s.Got}}{{.}}
+{{end}}</pre></td>
+</tr>{{end}}{{end}}
+</table>
+</body></html>`))
@@ -0,0 +1,737 @@
+package router
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// ClientGenerator generates typed API clients from the endpoints found by
+// an OpenAPIGenerator: a Go package and a TypeScript module with a method
+// per endpoint, typed path parameters and request and response types.
+type ClientGenerator struct {
+	endpoints   []APIEndpoint
+	types       map[string]*TypeInfo
+	packageName string
+}
+
+// NewClientGenerator scans the API routes of gen and prepares a client
+// generator. packageName is the Go package name of the generated client.
+func NewClientGenerator(gen *OpenAPIGenerator, packageName string) (*ClientGenerator, error) {
+	endpoints, types, err := gen.Endpoints()
+	if err != nil {
+		return nil, err
+	}
+	if packageName == "" {
+		packageName = "apiclient"
+	}
+	return &ClientGenerator{
+		endpoints:   endpoints,
+		types:       types,
+		packageName: packageName,
+	}, nil
+}
+
+// Endpoints scans the API routes and returns the endpoints, sorted by path
+// and method, with the request and response types they declare.
+func (g *OpenAPIGenerator) Endpoints() ([]APIEndpoint, map[string]*TypeInfo, error) {
+	endpoints, types, err := g.scanAPIRoutes(filepath.Join(g.routesDir, "api"))
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.Slice(endpoints, func(i, j int) bool {
+		if endpoints[i].Path != endpoints[j].Path {
+			return endpoints[i].Path < endpoints[j].Path
+		}
+		return endpoints[i].Method < endpoints[j].Method
+	})
+	return endpoints, types, nil
+}
+
+// clientMethod describes a generated client method.
+type clientMethod struct {
+	ep       APIEndpoint
+	name     string // exported Go name, e.g. "GetUsers"
+	body     string // request type name, "" if none
+	response string // response element type name, "" if none
+	slice    bool   // response is a list
+}
+
+// methods returns the client methods in endpoint order.
+func (c *ClientGenerator) methods() []clientMethod {
+	var methods []clientMethod
+	used := make(map[string]int)
+
+	for _, ep := range c.endpoints {
+		m := clientMethod{ep: ep}
+
+		base := strings.TrimSuffix(ep.FuncName, ep.Method)
+		if base == "" {
+			base = pathToMethodBase(ep.Path)
+		}
+		m.name = exportedIdent(strings.ToLower(ep.Method)) + base
+		if n := used[m.name]; n > 0 {
+			m.name = fmt.Sprintf("%s%d", m.name, n+1)
+		}
+		used[m.name]++
+
+		if ep.RequestType != nil && methodHasBody(ep.Method) {
+			m.body = elemTypeName(ep.RequestType.Name)
+		}
+		if ep.ResponseType != nil {
+			m.response = elemTypeName(ep.ResponseType.Name)
+			m.slice = strings.HasPrefix(ep.ResponseType.Name, "[]")
+		}
+
+		methods = append(methods, m)
+	}
+
+	return methods
+}
+
+// typeNames returns the declared type names, sorted.
+func (c *ClientGenerator) typeNames() []string {
+	names := make([]string, 0, len(c.types))
+	for name := range c.types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// =============================================================================
+// Go Client
+// =============================================================================
+
+// GenerateGo produces the Go client package source.
+func (c *ClientGenerator) GenerateGo() ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteString("// Code generated by vango gen client. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "// Package %s is a typed client for the API routes.\n", c.packageName)
+	fmt.Fprintf(&buf, "package %s\n\n", c.packageName)
+
+	imports := []string{"bytes", "context", "encoding/json", "errors", "fmt", "io", "net/http", "net/url", "strings"}
+	if c.usesGoType("time.Time") {
+		imports = append(imports, "time")
+		sort.Strings(imports)
+	}
+	buf.WriteString("import (\n")
+	for _, imp := range imports {
+		fmt.Fprintf(&buf, "\t%q\n", imp)
+	}
+	buf.WriteString(")\n\n")
+
+	// Types
+	for _, name := range c.typeNames() {
+		info := c.types[name]
+		fmt.Fprintf(&buf, "// %s mirrors the API type of the same name.\n", name)
+		fmt.Fprintf(&buf, "type %s struct {\n", name)
+		for _, field := range info.Fields {
+			if field.JSONName == "-" || !isExportedName(field.Name) {
+				continue
+			}
+			tag := field.JSONName
+			if tag == "" {
+				tag = field.Name
+			}
+			fmt.Fprintf(&buf, "\t%s %s `json:%q`\n", field.Name, c.goFieldType(field.Type), tag)
+		}
+		buf.WriteString("}\n\n")
+	}
+
+	buf.WriteString(goClientRuntime)
+
+	// Methods
+	for _, m := range c.methods() {
+		c.writeGoMethod(&buf, m)
+	}
+
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting Go client: %w", err)
+	}
+	return out, nil
+}
+
+// writeGoMethod writes the client method for an endpoint.
+func (c *ClientGenerator) writeGoMethod(buf *bytes.Buffer, m clientMethod) {
+	args := []string{"ctx context.Context"}
+	for _, p := range m.ep.Params {
+		args = append(args, fmt.Sprintf("%s %s", paramIdent(p.Name), c.paramGoType(p.Type)))
+	}
+	if m.body != "" {
+		args = append(args, "body "+c.goFieldType(m.body))
+	}
+
+	result := "error"
+	zero := ""
+	if m.response != "" {
+		typ := c.goFieldType(m.response)
+		if m.slice {
+			typ = "[]" + typ
+		} else {
+			typ = "*" + typ
+		}
+		result = "(" + typ + ", error)"
+		zero = "nil, "
+	}
+
+	if m.ep.Description != "" {
+		for _, line := range strings.Split(m.ep.Description, "\n") {
+			fmt.Fprintf(buf, "// %s\n", strings.TrimSpace(line))
+		}
+	} else {
+		fmt.Fprintf(buf, "// %s calls %s %s.\n", m.name, m.ep.Method, m.ep.Path)
+	}
+	fmt.Fprintf(buf, "func (c *Client) %s(%s) %s {\n", m.name, strings.Join(args, ", "), result)
+
+	fmt.Fprintf(buf, "\tpath := %s\n", goPathExpr(m.ep.Path))
+
+	bodyArg := "nil"
+	if m.body != "" {
+		bodyArg = "body"
+	}
+
+	if m.response == "" {
+		fmt.Fprintf(buf, "\treturn c.do(ctx, %q, path, %s, nil)\n", m.ep.Method, bodyArg)
+		buf.WriteString("}\n\n")
+		return
+	}
+
+	outArg := "out"
+	if m.slice {
+		fmt.Fprintf(buf, "\tvar out []%s\n", c.goFieldType(m.response))
+		outArg = "&out"
+	} else {
+		fmt.Fprintf(buf, "\tout := new(%s)\n", c.goFieldType(m.response))
+	}
+	fmt.Fprintf(buf, "\tif err := c.do(ctx, %q, path, %s, %s); err != nil {\n", m.ep.Method, bodyArg, outArg)
+	fmt.Fprintf(buf, "\t\treturn %serr\n", zero)
+	buf.WriteString("\t}\n")
+	buf.WriteString("\treturn out, nil\n")
+	buf.WriteString("}\n\n")
+}
+
+// goFieldType maps an API Go type to the client's Go type. Types the
+// client does not know are passed through as raw JSON.
+func (c *ClientGenerator) goFieldType(typ string) string {
+	switch {
+	case strings.HasPrefix(typ, "[]"):
+		return "[]" + c.goFieldType(typ[2:])
+	case strings.HasPrefix(typ, "*"):
+		return "*" + c.goFieldType(typ[1:])
+	}
+	switch typ {
+	case "string", "bool", "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64", "float32", "float64",
+		"any", "time.Time":
+		return typ
+	case "interface{}":
+		return "any"
+	}
+	if _, ok := c.types[typ]; ok {
+		return typ
+	}
+	return "json.RawMessage"
+}
+
+// paramGoType returns the Go type of a path parameter.
+func (c *ClientGenerator) paramGoType(typ string) string {
+	switch typ {
+	case "int", "int64", "int32", "uint", "uint64":
+		return typ
+	default:
+		return "string"
+	}
+}
+
+// usesGoType reports whether a declared type has a field of type typ.
+func (c *ClientGenerator) usesGoType(typ string) bool {
+	for _, info := range c.types {
+		for _, field := range info.Fields {
+			if strings.TrimLeft(field.Type, "[]*") == typ {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// goPathExpr returns a Go expression building the request path of an
+// OpenAPI path such as "/api/users/{id}".
+func goPathExpr(path string) string {
+	var parts []string
+	for _, seg := range strings.Split(strings.TrimPrefix(path, "/"), "/") {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			name := paramIdent(seg[1 : len(seg)-1])
+			if strings.HasPrefix(seg, "{...") {
+				parts = append(parts, fmt.Sprintf("escapePath(fmt.Sprint(%s))", name))
+			} else {
+				parts = append(parts, fmt.Sprintf("url.PathEscape(fmt.Sprint(%s))", name))
+			}
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%q", seg))
+	}
+	return `"/" + ` + strings.Join(parts, ` + "/" + `)
+}
+
+// goClientRuntime is the request plumbing and error types of the Go client.
+const goClientRuntime = `// Errors matched by errors.Is against the errors the client returns.
+var (
+	ErrBadRequest   = errors.New("bad request")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrValidation   = errors.New("validation failed")
+	ErrServer       = errors.New("server error")
+)
+
+// Error is returned for responses with a non-2xx status.
+//
+//	user, err := client.GetUser(ctx, 42)
+//	if errors.Is(err, apiclient.ErrNotFound) {
+//	    // ...
+//	}
+type Error struct {
+	// Status is the HTTP status code.
+	Status int
+
+	// Code is the error code from the response body, if any.
+	Code string
+
+	// Message is the error message from the response body, or the status text.
+	Message string
+
+	// Body is the raw response body.
+	Body []byte
+}
+
+// Error implements error.
+func (e *Error) Error() string {
+	return fmt.Sprintf("api error %d: %s", e.Status, e.Message)
+}
+
+// Is matches the error against the Err* values for its status.
+func (e *Error) Is(target error) bool {
+	switch e.Status {
+	case http.StatusBadRequest:
+		return target == ErrBadRequest
+	case http.StatusUnauthorized:
+		return target == ErrUnauthorized
+	case http.StatusForbidden:
+		return target == ErrForbidden
+	case http.StatusNotFound:
+		return target == ErrNotFound
+	case http.StatusConflict:
+		return target == ErrConflict
+	case http.StatusUnprocessableEntity:
+		return target == ErrValidation
+	}
+	return e.Status >= 500 && target == ErrServer
+}
+
+// Client calls the API routes of a Vango application.
+type Client struct {
+	// BaseURL is the application URL, e.g. "https://example.com".
+	BaseURL string
+
+	// HTTPClient sends the requests. Default: http.DefaultClient
+	HTTPClient *http.Client
+
+	// Header is added to every request, e.g. for authorization.
+	Header http.Header
+}
+
+// New creates a client for the application at baseURL.
+func New(baseURL string) *Client {
+	return &Client{
+		BaseURL: strings.TrimSuffix(baseURL, "/"),
+		Header:  make(http.Header),
+	}
+}
+
+// do sends a request and decodes the JSON response into out.
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	for key, values := range c.Header {
+		for _, v := range values {
+			req.Header.Add(key, v)
+		}
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return decodeError(resp.StatusCode, data)
+	}
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
+
+// decodeError builds an *Error from a failed response. Bodies of the form
+// {"error": "...", "code": "..."} or {"message": "..."} fill in the message.
+func decodeError(status int, data []byte) error {
+	e := &Error{Status: status, Message: http.StatusText(status), Body: data}
+
+	var payload struct {
+		Error   string ` + "`json:\"error\"`" + `
+		Message string ` + "`json:\"message\"`" + `
+		Code    string ` + "`json:\"code\"`" + `
+	}
+	if json.Unmarshal(data, &payload) == nil {
+		e.Code = payload.Code
+		if payload.Error != "" {
+			e.Message = payload.Error
+		} else if payload.Message != "" {
+			e.Message = payload.Message
+		}
+	}
+	return e
+}
+
+// escapePath escapes each segment of a catch-all parameter.
+func escapePath(value string) string {
+	parts := strings.Split(strings.Trim(value, "/"), "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+	return strings.Join(parts, "/")
+}
+
+`
+
+// =============================================================================
+// TypeScript Client
+// =============================================================================
+
+// GenerateTS produces the TypeScript client module source.
+func (c *ClientGenerator) GenerateTS() ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteString("// Code generated by vango gen client. DO NOT EDIT.\n\n")
+
+	// Types
+	for _, name := range c.typeNames() {
+		info := c.types[name]
+		fmt.Fprintf(&buf, "export interface %s {\n", name)
+		for _, field := range info.Fields {
+			if field.JSONName == "-" || !isExportedName(field.Name) {
+				continue
+			}
+			key := field.JSONName
+			if key == "" {
+				key = field.Name
+			}
+			optional := ""
+			if strings.HasPrefix(field.Type, "*") {
+				optional = "?"
+			}
+			fmt.Fprintf(&buf, "  %s%s: %s;\n", key, optional, c.tsType(field.Type))
+		}
+		buf.WriteString("}\n\n")
+	}
+
+	buf.WriteString(tsClientRuntime)
+
+	// Methods
+	for _, m := range c.methods() {
+		c.writeTSMethod(&buf, m)
+	}
+
+	buf.WriteString(tsClientRequest)
+	buf.WriteString("}\n")
+
+	return buf.Bytes(), nil
+}
+
+// writeTSMethod writes the client method for an endpoint.
+func (c *ClientGenerator) writeTSMethod(buf *bytes.Buffer, m clientMethod) {
+	var args []string
+	for _, p := range m.ep.Params {
+		typ := "string"
+		if c.paramGoType(p.Type) != "string" {
+			typ = "number"
+		}
+		args = append(args, fmt.Sprintf("%s: %s", lowerIdent(paramIdent(p.Name)), typ))
+	}
+	if m.body != "" {
+		args = append(args, "body: "+c.tsType(m.body))
+	}
+
+	result := "void"
+	if m.response != "" {
+		result = c.tsType(m.response)
+		if m.slice {
+			result += "[]"
+		}
+	}
+
+	bodyArg := ""
+	if m.body != "" {
+		bodyArg = ", body"
+	}
+
+	if m.ep.Description != "" {
+		buf.WriteString("  /**\n")
+		for _, line := range strings.Split(m.ep.Description, "\n") {
+			fmt.Fprintf(buf, "   * %s\n", strings.TrimSpace(line))
+		}
+		buf.WriteString("   */\n")
+	}
+	fmt.Fprintf(buf, "  %s(%s): Promise<%s> {\n", lowerIdent(m.name), strings.Join(args, ", "), result)
+	fmt.Fprintf(buf, "    return this.request<%s>(%q, %s%s);\n", result, m.ep.Method, tsPathExpr(m.ep.Path), bodyArg)
+	buf.WriteString("  }\n\n")
+}
+
+// tsType maps an API Go type to a TypeScript type.
+func (c *ClientGenerator) tsType(typ string) string {
+	switch {
+	case strings.HasPrefix(typ, "[]"):
+		return c.tsType(typ[2:]) + "[]"
+	case strings.HasPrefix(typ, "*"):
+		return c.tsType(typ[1:])
+	}
+	switch typ {
+	case "string", "time.Time":
+		return "string"
+	case "bool":
+		return "boolean"
+	case "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64", "float32", "float64":
+		return "number"
+	}
+	if _, ok := c.types[typ]; ok {
+		return typ
+	}
+	return "unknown"
+}
+
+// tsPathExpr returns a template literal building the request path.
+func tsPathExpr(path string) string {
+	var parts []string
+	for _, seg := range strings.Split(strings.TrimPrefix(path, "/"), "/") {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			name := lowerIdent(paramIdent(seg[1 : len(seg)-1]))
+			if strings.HasPrefix(seg, "{...") {
+				parts = append(parts, fmt.Sprintf("${String(%s).split('/').map(encodeURIComponent).join('/')}", name))
+			} else {
+				parts = append(parts, fmt.Sprintf("${encodeURIComponent(String(%s))}", name))
+			}
+			continue
+		}
+		parts = append(parts, seg)
+	}
+	return "`/" + strings.Join(parts, "/") + "`"
+}
+
+// tsClientRuntime is the error type and client class header of the
+// TypeScript client.
+const tsClientRuntime = `export type ApiErrorKind =
+  | 'bad_request'
+  | 'unauthorized'
+  | 'forbidden'
+  | 'not_found'
+  | 'conflict'
+  | 'validation'
+  | 'server'
+  | 'unknown';
+
+/** Thrown for responses with a non-2xx status. */
+export class ApiError extends Error {
+  readonly status: number;
+  readonly kind: ApiErrorKind;
+  readonly code?: string;
+  readonly body: unknown;
+
+  constructor(status: number, message: string, code: string | undefined, body: unknown) {
+    super(message);
+    this.name = 'ApiError';
+    this.status = status;
+    this.kind = errorKind(status);
+    this.code = code;
+    this.body = body;
+  }
+}
+
+function errorKind(status: number): ApiErrorKind {
+  switch (status) {
+    case 400: return 'bad_request';
+    case 401: return 'unauthorized';
+    case 403: return 'forbidden';
+    case 404: return 'not_found';
+    case 409: return 'conflict';
+    case 422: return 'validation';
+  }
+  return status >= 500 ? 'server' : 'unknown';
+}
+
+export interface ClientOptions {
+  /** Application URL, e.g. "https://example.com". Default: same origin. */
+  baseURL?: string;
+  /** Headers added to every request, e.g. for authorization. */
+  headers?: Record<string, string>;
+  /** fetch implementation. Default: globalThis.fetch. */
+  fetch?: typeof fetch;
+}
+
+/** Typed client for the API routes of a Vango application. */
+export class Client {
+  private readonly baseURL: string;
+  private readonly headers: Record<string, string>;
+  private readonly fetchFn: typeof fetch;
+
+  constructor(options: ClientOptions = {}) {
+    this.baseURL = (options.baseURL ?? '').replace(/\/$/, '');
+    this.headers = options.headers ?? {};
+    this.fetchFn = options.fetch ?? globalThis.fetch.bind(globalThis);
+  }
+
+`
+
+// tsClientRequest is the request method of the TypeScript client.
+const tsClientRequest = `  private async request<T>(method: string, path: string, body?: unknown): Promise<T> {
+    const headers: Record<string, string> = { Accept: 'application/json', ...this.headers };
+    if (body !== undefined) {
+      headers['Content-Type'] = 'application/json';
+    }
+
+    const resp = await this.fetchFn(this.baseURL + path, {
+      method,
+      headers,
+      body: body === undefined ? undefined : JSON.stringify(body),
+    });
+
+    const text = await resp.text();
+    let data: unknown = undefined;
+    if (text) {
+      try {
+        data = JSON.parse(text);
+      } catch {
+        data = text;
+      }
+    }
+
+    if (!resp.ok) {
+      const payload = (data ?? {}) as { error?: string; message?: string; code?: string };
+      const message = payload.error ?? payload.message ?? resp.statusText;
+      throw new ApiError(resp.status, message, payload.code, data);
+    }
+    return data as T;
+  }
+`
+
+// =============================================================================
+// Naming Helpers
+// =============================================================================
+
+// methodHasBody reports whether requests with method carry a JSON body.
+func methodHasBody(method string) bool {
+	return method == "POST" || method == "PUT" || method == "PATCH"
+}
+
+// elemTypeName strips slice and pointer markers from a type name.
+func elemTypeName(typ string) string {
+	return strings.TrimLeft(typ, "[]*")
+}
+
+// pathToMethodBase derives a method name from an API path,
+// e.g. "/api/users/{id}" → "UsersID".
+func pathToMethodBase(path string) string {
+	var b strings.Builder
+	for _, seg := range strings.Split(strings.TrimPrefix(path, "/api"), "/") {
+		seg = strings.Trim(seg, "{}.")
+		for _, part := range strings.FieldsFunc(seg, func(r rune) bool {
+			return r == '-' || r == '_'
+		}) {
+			b.WriteString(exportedIdent(part))
+		}
+	}
+	if b.Len() == 0 {
+		return "Root"
+	}
+	return b.String()
+}
+
+// paramIdent returns the identifier of a path parameter, e.g. "...path" → "path".
+func paramIdent(name string) string {
+	name = strings.TrimPrefix(name, "...")
+	name = strings.NewReplacer("-", "_", ".", "_").Replace(name)
+	switch name {
+	case "ctx", "body", "path", "out", "err", "c", "url", "fmt", "http":
+		return name + "Param"
+	}
+	return name
+}
+
+// exportedIdent upper-cases the first letter of s.
+func exportedIdent(s string) string {
+	if s == "" {
+		return s
+	}
+	upper := strings.ToUpper(s)
+	switch upper {
+	case "ID", "URL", "API", "HTTP", "UUID":
+		return upper
+	}
+	runes := []rune(s)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// lowerIdent lower-cases the leading capitals of s, e.g. "GetUsers" → "getUsers".
+func lowerIdent(s string) string {
+	runes := []rune(s)
+	for i := 0; i < len(runes) && unicode.IsUpper(runes[i]); i++ {
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
+
+// isExportedName reports whether name is an exported Go identifier.
+func isExportedName(name string) bool {
+	for _, r := range name {
+		return unicode.IsUpper(r)
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+package router
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeClientFixture creates an API routes directory for client generation.
+func writeClientFixture(t *testing.T) string {
+	t.Helper()
+
+	routesDir := filepath.Join(t.TempDir(), "routes")
+	files := map[string]string{
+		"api/users.go": `package api
+
+import "github.com/vango-dev/vango"
+
+type User struct {
+	ID      int      ` + "`json:\"id\"`" + `
+	Name    string   ` + "`json:\"name\"`" + `
+	Tags    []string ` + "`json:\"tags\"`" + `
+	Manager *User    ` + "`json:\"manager\"`" + `
+	secret  string
+}
+
+type CreateUserInput struct {
+	Name string ` + "`json:\"name\" validate:\"required\"`" + `
+}
+
+// UsersGET lists all users.
+func UsersGET(ctx vango.Ctx) ([]*User, error) { return nil, nil }
+
+func UsersPOST(ctx vango.Ctx, input CreateUserInput) (vango.Response[User], error) {
+	return vango.Response[User]{}, nil
+}
+`,
+		"api/users/[id].go": `package users
+
+import "github.com/vango-dev/vango"
+
+func UserGET(ctx vango.Ctx, p Params) (*User, error) { return nil, nil }
+
+func UserDELETE(ctx vango.Ctx, p Params) error { return nil }
+`,
+	}
+
+	for path, content := range files {
+		full := filepath.Join(routesDir, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return routesDir
+}
+
+func TestClientGenerator_Go(t *testing.T) {
+	gen, err := NewClientGenerator(NewOpenAPIGenerator(writeClientFixture(t), "github.com/example/app", OpenAPIInfo{}), "")
+	if err != nil {
+		t.Fatalf("NewClientGenerator error: %v", err)
+	}
+
+	code, err := gen.GenerateGo()
+	if err != nil {
+		t.Fatalf("GenerateGo error: %v", err)
+	}
+	src := string(code)
+
+	wants := []string{
+		"package apiclient",
+		"type User struct {",
+		"Manager *User",
+		"func (c *Client) GetUsers(ctx context.Context) ([]User, error)",
+		"// UsersGET lists all users.",
+		"func (c *Client) PostUsers(ctx context.Context, body CreateUserInput) (*User, error)",
+		"func (c *Client) GetUser(ctx context.Context, id int) (*User, error)",
+		"func (c *Client) DeleteUser(ctx context.Context, id int) error",
+		`path := "/" + "api" + "/" + "users" + "/" + url.PathEscape(fmt.Sprint(id))`,
+		"ErrNotFound",
+	}
+	for _, want := range wants {
+		if !strings.Contains(src, want) {
+			t.Errorf("Go client should contain %q", want)
+		}
+	}
+	if strings.Contains(src, "secret") {
+		t.Error("unexported fields should not be generated")
+	}
+}
+
+func TestClientGenerator_TS(t *testing.T) {
+	gen, err := NewClientGenerator(NewOpenAPIGenerator(writeClientFixture(t), "github.com/example/app", OpenAPIInfo{}), "")
+	if err != nil {
+		t.Fatalf("NewClientGenerator error: %v", err)
+	}
+
+	code, err := gen.GenerateTS()
+	if err != nil {
+		t.Fatalf("GenerateTS error: %v", err)
+	}
+	src := string(code)
+
+	wants := []string{
+		"export interface User {",
+		"  id: number;",
+		"  tags: string[];",
+		"  manager?: User;",
+		"getUsers(): Promise<User[]>",
+		"postUsers(body: CreateUserInput): Promise<User>",
+		"getUser(id: number): Promise<User>",
+		"deleteUser(id: number): Promise<void>",
+		"`/api/users/${encodeURIComponent(String(id))}`",
+		"export class ApiError extends Error",
+	}
+	for _, want := range wants {
+		if !strings.Contains(src, want) {
+			t.Errorf("TS client should contain %q", want)
+		}
+	}
+}
+
+func TestLowerIdent(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"GetUsers", "getUsers"},
+		{"ID", "id"},
+		{"GetUserID", "getUserID"},
+		{"HTTPStatus", "httpStatus"},
+	}
+	for _, tt := range tests {
+		if got := lowerIdent(tt.in); got != tt.want {
+			t.Errorf("lowerIdent(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
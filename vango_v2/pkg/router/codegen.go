@@ -3,6 +3,8 @@ package router
 import (
 	"bytes"
 	"fmt"
+	"go/token"
+	"path"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -11,28 +13,85 @@ import (
 
 // Generator generates Go code for registering routes.
 type Generator struct {
-	routes     []ScannedRoute
-	modulePath string
+	routes       []ScannedRoute
+	modulePath   string
+	routesImport string
+
+	// imports holds the route packages referenced by the generated code,
+	// by directory
+	imports map[string]*importDef
 }
 
-// NewGenerator creates a new code generator.
+// FrameworkRouterImport is the import path of this package, used by the
+// generated code.
+const FrameworkRouterImport = "github.com/vango-dev/vango/v2/pkg/router"
+
+// NewGenerator creates a new code generator. The routes are assumed to be
+// scanned from the app/routes directory of the module; use
+// SetRoutesPackage otherwise.
 func NewGenerator(routes []ScannedRoute, modulePath string) *Generator {
 	// Sort routes for deterministic output
 	sortedRoutes := make([]ScannedRoute, len(routes))
 	copy(sortedRoutes, routes)
 	sort.Slice(sortedRoutes, func(i, j int) bool {
-		return sortedRoutes[i].Path < sortedRoutes[j].Path
+		a, b := sortedRoutes[i], sortedRoutes[j]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.FilePath < b.FilePath
 	})
 
 	return &Generator{
-		routes:     sortedRoutes,
-		modulePath: modulePath,
+		routes:       sortedRoutes,
+		modulePath:   modulePath,
+		routesImport: path.Join(modulePath, "app/routes"),
+	}
+}
+
+// SetRoutesPackage sets the import path of the scanned directory. Route
+// files in its subdirectories are imported below it.
+func (g *Generator) SetRoutesPackage(importPath string) {
+	g.routesImport = importPath
+}
+
+// RoutesPackagePath returns the import path of routesDir in the module
+// rooted at moduleDir.
+func RoutesPackagePath(modulePath, moduleDir, routesDir string) (string, error) {
+	rel, err := filepath.Rel(moduleDir, routesDir)
+	if err != nil {
+		return "", err
+	}
+	rel = filepath.ToSlash(rel)
+	if rel == ".." || strings.HasPrefix(rel, "../") {
+		return "", fmt.Errorf("%s is outside the module at %s", routesDir, moduleDir)
 	}
+	return path.Join(modulePath, rel), nil
 }
 
 // Generate produces the routes_gen.go file content.
 // The output is deterministic - same input produces identical output.
 func (g *Generator) Generate() ([]byte, error) {
+	g.imports = make(map[string]*importDef)
+
+	// Generate the body first to know the packages it references
+	var body bytes.Buffer
+
+	// Generate param structs for routes with parameters
+	paramStructs := g.generateAllParamStructs()
+	if len(paramStructs) > 0 {
+		body.WriteString(paramStructs)
+		body.WriteString("\n")
+	}
+
+	// Generate the Register function (Phase 14 spec format)
+	g.generateRegisterFunction(&body)
+
+	// Generate route path constants for type-safe linking
+	g.generateRouteConstants(&body)
+
+	// Generate URL builder functions
+	g.generateURLBuilders(&body)
+
 	var buf bytes.Buffer
 
 	// Write header
@@ -53,19 +112,7 @@ func (g *Generator) Generate() ([]byte, error) {
 		buf.WriteString(")\n\n")
 	}
 
-	// Generate param structs for routes with parameters
-	paramStructs := g.generateAllParamStructs()
-	if len(paramStructs) > 0 {
-		buf.WriteString(paramStructs)
-		buf.WriteString("\n")
-	}
-
-	// Generate the Register function (Phase 14 spec format)
-	g.generateRegisterFunction(&buf)
-
-	// Generate route path constants for type-safe linking
-	g.generateRouteConstants(&buf)
-
+	buf.Write(body.Bytes())
 	return buf.Bytes(), nil
 }
 
@@ -73,79 +120,72 @@ func (g *Generator) Generate() ([]byte, error) {
 func (g *Generator) generateRegisterFunction(buf *bytes.Buffer) {
 	buf.WriteString("// Register adds all routes to the router.\n")
 	buf.WriteString("// Generated by `vango dev` or `vango gen routes`.\n")
-	buf.WriteString("func Register(r *Router) {\n")
+	buf.WriteString("func Register(r *router.Router) {\n")
 
-	// Group routes by type
-	var pageRoutes, apiRoutes []ScannedRoute
+	// Group registrations by kind, in the order of BuildFromScanned
+	var layouts, pages, middleware, apis, loaders, errorPages []string
 	for _, route := range g.routes {
-		if route.IsAPI || len(route.Methods) > 0 {
-			apiRoutes = append(apiRoutes, route)
-		} else if route.HasPage {
-			pageRoutes = append(pageRoutes, route)
+		pattern := route.RouterPath()
+		prefix := g.getPackagePrefix(route)
+
+		if route.HasLayout {
+			layouts = append(layouts, fmt.Sprintf("r.AddLayout(%q, %sLayout)", pattern, prefix))
+		}
+		if route.HasPage {
+			pages = append(pages, fmt.Sprintf("r.AddPage(%q, %s)", pattern, g.getHandlerName(route)))
+			if route.IsStatic {
+				pages = append(pages, fmt.Sprintf("r.SetStatic(%q)", pattern))
+			}
+		}
+		if route.HasMeta {
+			pages = append(pages, fmt.Sprintf("r.AddMeta(%q, %sMeta)", pattern, prefix))
+		}
+		if route.HasStaticParams {
+			pages = append(pages, fmt.Sprintf("r.AddStaticParams(%q, %sStaticParams)", pattern, prefix))
+		}
+		if route.HasMiddleware {
+			middleware = append(middleware, fmt.Sprintf("r.AddMiddleware(%q, %sMiddleware()...)", pattern, prefix))
+		}
+		for _, method := range route.Methods {
+			apis = append(apis, fmt.Sprintf("r.AddAPI(%q, %q, %s%s)",
+				pattern, method, prefix, g.getAPIFuncName(route, method)))
+		}
+		if route.HasLoad {
+			loaders = append(loaders, fmt.Sprintf("r.AddLoader(%q, router.Loader(%sLoad))", pattern, prefix))
+		}
+		if route.HasErrorPage {
+			errorPages = append(errorPages, fmt.Sprintf("r.AddErrorPage(%q, %sErrorPage)", pattern, prefix))
 		}
 	}
 
-	// Generate page routes
-	if len(pageRoutes) > 0 {
-		buf.WriteString("\t// Page routes\n")
-		for _, route := range pageRoutes {
-			g.generatePageRegistration(buf, route)
+	separate := false
+	for _, section := range []struct {
+		title string
+		lines []string
+	}{
+		{"Layouts", layouts},
+		{"Page routes", pages},
+		{"Middleware", middleware},
+		{"API routes", apis},
+		{"Data loaders", loaders},
+		{"Error pages", errorPages},
+	} {
+		if len(section.lines) == 0 {
+			continue
 		}
-		if len(apiRoutes) > 0 {
+		if separate {
 			buf.WriteString("\n")
 		}
-	}
-
-	// Generate API routes
-	if len(apiRoutes) > 0 {
-		buf.WriteString("\t// API routes\n")
-		for _, route := range apiRoutes {
-			g.generateAPIRegistration(buf, route)
+		separate = true
+		buf.WriteString("\t// " + section.title + "\n")
+		for _, line := range section.lines {
+			buf.WriteString("\t" + line + "\n")
 		}
 	}
 
 	buf.WriteString("}\n\n")
 }
 
-// generatePageRegistration generates a single r.Page() call.
-func (g *Generator) generatePageRegistration(buf *bytes.Buffer, route ScannedRoute) {
-	// Determine handler name
-	handlerName := g.getHandlerName(route)
-
-	// Determine layout (if any)
-	layoutName := ""
-	if route.HasLayout {
-		layoutName = ", Layout"
-	} else {
-		// Check for parent layout
-		parentLayout := g.findParentLayout(route)
-		if parentLayout != "" {
-			layoutName = ", " + parentLayout
-		}
-	}
-
-	// Determine middleware (if any)
-	middlewareName := ""
-	if route.HasMiddleware {
-		middlewareName = ", Middleware..."
-	}
-
-	buf.WriteString(fmt.Sprintf("\tr.Page(%q, %s%s%s)\n",
-		route.Path, handlerName, layoutName, middlewareName))
-}
-
-// generateAPIRegistration generates r.API() calls for API routes.
-func (g *Generator) generateAPIRegistration(buf *bytes.Buffer, route ScannedRoute) {
-	// Get package prefix if not in root routes package
-	prefix := g.getPackagePrefix(route)
-
-	for _, method := range route.Methods {
-		funcName := g.getAPIFuncName(route, method)
-		buf.WriteString(fmt.Sprintf("\tr.API(%q, %q, %s%s)\n",
-			method, route.Path, prefix, funcName))
-	}
-}
-
 // generateAllParamStructs generates all param structs.
 func (g *Generator) generateAllParamStructs() string {
 	var buf bytes.Buffer
@@ -187,63 +227,205 @@ func (g *Generator) generateRouteConstants(buf *bytes.Buffer) {
 	buf.WriteString(")\n")
 }
 
-// getHandlerName returns the handler function name for a route.
+// generateURLBuilders generates a function building the URL of each page
+// route, e.g. UsersIDURL(id int) string for /users/:id.
+func (g *Generator) generateURLBuilders(buf *bytes.Buffer) {
+	for _, route := range g.routes {
+		if !route.HasPage {
+			continue
+		}
+
+		name := g.pathToConstName(route.Path) + "URL"
+		types := make(map[string]string, len(route.Params))
+		for _, p := range route.Params {
+			types[p.Name] = g.paramTypeToGoType(p.Type)
+		}
+
+		// Arguments follow the order of the parameters in the path
+		var args, parts []string
+		static := ""
+		required := false
+		for _, seg := range splitPath(route.Path) {
+			static += "/"
+			switch {
+			case isOptionalSegment(seg):
+				// Optional segments bring their own slash
+				param, _ := parseParamSegment(seg)
+				ident := g.paramIdentifier(param)
+				typ, ok := types[param]
+				if !ok {
+					typ = "string"
+				}
+				args = append(args, ident+" "+typ)
+
+				if static = strings.TrimSuffix(static, "/"); static != "" {
+					parts = append(parts, fmt.Sprintf("%q", static))
+					required = true
+				}
+				static = ""
+				parts = append(parts, "router.OptionalSegment("+ident+")")
+			case strings.HasPrefix(seg, ":"), strings.HasPrefix(seg, "*"):
+				required = true
+				param := seg[1:]
+				if seg[0] == ':' {
+					param, _ = parseParamSegment(seg)
+				}
+				ident := g.paramIdentifier(param)
+				typ, ok := types[param]
+				if !ok {
+					typ = "string"
+					if strings.HasPrefix(seg, "*") {
+						typ = "[]string"
+					}
+				}
+				args = append(args, ident+" "+typ)
+
+				parts = append(parts, fmt.Sprintf("%q", static))
+				static = ""
+				if typ == "[]string" {
+					parts = append(parts, "router.CatchAllSegments("+ident+")")
+				} else {
+					parts = append(parts, "router.PathSegment("+ident+")")
+				}
+			default:
+				static += seg
+			}
+		}
+		if static != "" || len(parts) == 0 {
+			if static == "" {
+				static = "/"
+			}
+			parts = append(parts, fmt.Sprintf("%q", static))
+			required = true
+		}
+		expr := strings.Join(parts, " + ")
+		if !required {
+			// Only optional segments: all absent is the root
+			expr = "router.RootIfEmpty(" + expr + ")"
+		}
+
+		if route.HasQuery {
+			args = append(args, "query *"+g.getPackagePrefix(route)+"Query")
+			expr = "router.WithQuery(" + expr + ", query)"
+		}
+
+		buf.WriteString(fmt.Sprintf("\n// %s returns the URL of %s.\n", name, route.Path))
+		buf.WriteString(fmt.Sprintf("func %s(%s) string {\n", name, strings.Join(args, ", ")))
+		buf.WriteString(fmt.Sprintf("\treturn %s\n", expr))
+		buf.WriteString("}\n")
+	}
+}
+
+// paramIdentifier converts a route parameter name to a Go identifier.
+func (g *Generator) paramIdentifier(name string) string {
+	ident := sanitizeIdentifier(name)
+	if ident == "" {
+		return "param"
+	}
+	runes := []rune(ident)
+	runes[0] = unicode.ToLower(runes[0])
+	ident = string(runes)
+	if token.IsKeyword(ident) || ident == "query" || ident == "router" {
+		ident += "Param"
+	}
+	return ident
+}
+
+// getHandlerName returns the page handler of a route, with its package
+// prefix.
 func (g *Generator) getHandlerName(route ScannedRoute) string {
-	prefix := g.getPackagePrefix(route)
-
-	// Determine function name from path
-	baseName := filepath.Base(strings.TrimSuffix(route.FilePath, ".go"))
-
-	switch baseName {
-	case "index":
-		return prefix + "IndexPage"
-	case "_layout":
-		return prefix + "Layout"
-	case "_middleware":
-		return prefix + "Middleware"
-	default:
-		// Remove brackets and convert to PascalCase
-		name := strings.ReplaceAll(baseName, "[", "")
-		name = strings.ReplaceAll(name, "]", "")
-		name = strings.ReplaceAll(name, "...", "")
-		return prefix + g.toExportedName(name) + "Page"
+	if route.PageFunc != "" {
+		return g.getPackagePrefix(route) + route.PageFunc
 	}
+	return g.getPackagePrefix(route) + pageFuncName(route.FilePath)
 }
 
 // getAPIFuncName returns the API handler function name.
 func (g *Generator) getAPIFuncName(route ScannedRoute, method string) string {
-	baseName := filepath.Base(strings.TrimSuffix(route.FilePath, ".go"))
-	return g.toExportedName(baseName) + method
+	if fn, ok := route.APIFuncs[method]; ok {
+		return fn
+	}
+	return apiFuncName(route.FilePath, method)
+}
+
+// pageFuncName returns the page function name derived from the name of a
+// route file, e.g. IndexPage for index.go and IDPage for [id].go.
+func pageFuncName(filePath string) string {
+	baseName := filepath.Base(strings.TrimSuffix(filePath, ".go"))
+	if baseName == "index" {
+		return "IndexPage"
+	}
+
+	// Keep the parameter names and convert to PascalCase
+	name := paramSegmentPattern.ReplaceAllString(baseName, "$2")
+	name = strings.ReplaceAll(name, "...", "")
+	return exportedName(name) + "Page"
 }
 
-// getPackagePrefix returns the package prefix for a route.
+// apiFuncName returns the API handler name derived from the name of a
+// route file, e.g. HealthGET for health.go.
+func apiFuncName(filePath, method string) string {
+	baseName := filepath.Base(strings.TrimSuffix(filePath, ".go"))
+	return exportedName(baseName) + method
+}
+
+// getPackagePrefix returns the package prefix for a route, and records the
+// import of its package.
 func (g *Generator) getPackagePrefix(route ScannedRoute) string {
 	if route.Package == "routes" {
 		return ""
 	}
-	return route.Package + "."
+
+	dir := route.Dir
+	if dir == "" {
+		dir = path.Dir(filepath.ToSlash(route.FilePath))
+	}
+	imp, ok := g.imports[dir]
+	if !ok {
+		imp = &importDef{path: path.Join(g.routesImport, dir)}
+		name := route.Package
+		for i := 2; g.aliasTaken(name); i++ {
+			name = fmt.Sprintf("%s%d", route.Package, i)
+		}
+		if name != path.Base(imp.path) {
+			imp.alias = name
+		}
+		imp.name = name
+		if g.imports == nil {
+			g.imports = make(map[string]*importDef)
+		}
+		g.imports[dir] = imp
+	}
+	return imp.name + "."
 }
 
-// findParentLayout finds a parent layout for a route.
-func (g *Generator) findParentLayout(route ScannedRoute) string {
-	// Simple implementation: check if root layout exists
-	for _, r := range g.routes {
-		if r.HasLayout && r.Path == "/" {
-			return "Layout"
+// aliasTaken reports whether name is already used by an import.
+func (g *Generator) aliasTaken(name string) bool {
+	if name == "router" {
+		return true
+	}
+	for _, imp := range g.imports {
+		if imp.name == name {
+			return true
 		}
 	}
-	return ""
+	return false
 }
 
 type importDef struct {
 	alias string
 	path  string
+	name  string
 }
 
-// collectImports gathers all required imports.
+// collectImports gathers all required imports: the framework router and
+// the route packages referenced by the generated code.
 func (g *Generator) collectImports() []importDef {
 	imports := []importDef{
-		{path: g.modulePath + "/pkg/router"},
+		{path: FrameworkRouterImport},
+	}
+	for _, imp := range g.imports {
+		imports = append(imports, *imp)
 	}
 
 	// Sort for deterministic output
@@ -315,7 +497,7 @@ func (g *Generator) pathToStructName(path string) string {
 
 	// Split by / and :
 	parts := strings.FieldsFunc(path, func(r rune) bool {
-		return r == '/' || r == ':' || r == '*'
+		return r == '/' || r == ':' || r == '*' || r == '?'
 	})
 
 	var result strings.Builder
@@ -333,6 +515,11 @@ func (g *Generator) pathToConstName(path string) string {
 
 // toExportedName converts a string to an exported Go identifier.
 func (g *Generator) toExportedName(s string) string {
+	return exportedName(s)
+}
+
+// exportedName converts a string to an exported Go identifier.
+func exportedName(s string) string {
 	if s == "" {
 		return ""
 	}
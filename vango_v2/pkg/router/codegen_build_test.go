@@ -0,0 +1,189 @@
+package router
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// buildFixture is an app whose routes use every kind of file the generator
+// registers, in the root routes package and in imported ones. The go tool
+// rejects file names with brackets and ignores those starting with an
+// underscore, so it has no dynamic segments and declares its layout and
+// middleware in index files.
+var buildFixture = map[string]string{
+	"app/routes/index.go": `package routes
+
+import (
+	"github.com/vango-dev/vango/v2/pkg/router"
+	"github.com/vango-dev/vango/v2/pkg/server"
+	"github.com/vango-dev/vango/v2/pkg/vdom"
+)
+
+type Query struct {
+	Tab string ` + "`query:\"tab\"`" + `
+}
+
+func IndexPage(ctx server.Ctx, params any) vdom.Component {
+	return vdom.Func(func() *vdom.VNode { return vdom.Text("home") })
+}
+
+func Meta(ctx server.Ctx, params any) router.PageMeta {
+	return router.PageMeta{Title: "Home"}
+}
+
+func Layout(ctx server.Ctx, children router.Slot) *vdom.VNode {
+	return vdom.Div(children)
+}
+
+func ErrorPage(ctx server.Ctx, err error) *vdom.VNode {
+	return vdom.Text(err.Error())
+}
+`,
+	"app/routes/blog/latest.go": `//vango:static
+
+package blog
+
+import (
+	"github.com/vango-dev/vango/v2/pkg/router"
+	"github.com/vango-dev/vango/v2/pkg/server"
+	"github.com/vango-dev/vango/v2/pkg/vdom"
+)
+
+type Query struct {
+	Ref string ` + "`query:\"ref\"`" + `
+}
+
+type Post struct{ Title string }
+
+func Page(ctx server.Ctx, params any) vdom.Component {
+	post, _ := router.Data[Post](ctx)
+	return vdom.Func(func() *vdom.VNode { return vdom.Text(post.Title) })
+}
+
+func Load(ctx server.Ctx, params map[string]string) (Post, error) {
+	return Post{Title: "Latest"}, nil
+}
+
+func StaticParams() []map[string]string {
+	return []map[string]string{{}}
+}
+`,
+	"app/routes/admin/index.go": `package admin
+
+import (
+	"github.com/vango-dev/vango/v2/pkg/router"
+	"github.com/vango-dev/vango/v2/pkg/server"
+	"github.com/vango-dev/vango/v2/pkg/vdom"
+)
+
+func Middleware() []router.Middleware {
+	return nil
+}
+
+func IndexPage(ctx server.Ctx, params any) vdom.Component {
+	return vdom.Func(func() *vdom.VNode { return vdom.Text("admin") })
+}
+`,
+	"app/routes/api/health.go": `package api
+
+import "github.com/vango-dev/vango/v2/pkg/server"
+
+func HealthGET(ctx server.Ctx, params any, body any) (any, error) {
+	return map[string]string{"status": "ok"}, nil
+}
+`,
+	"app/routes/api/users/index.go": `package users
+
+import "github.com/vango-dev/vango/v2/pkg/server"
+
+func GET(ctx server.Ctx, params any, body any) (any, error) {
+	return nil, nil
+}
+
+func POST(ctx server.Ctx, params any, body any) (any, error) {
+	return nil, nil
+}
+`,
+	"main.go": `package main
+
+import (
+	"example.com/app/app/routes"
+	"example.com/app/app/routes/blog"
+	"github.com/vango-dev/vango/v2/pkg/router"
+)
+
+func main() {
+	r := router.NewRouter()
+	routes.Register(r)
+	_ = routes.BlogLatestURL(&blog.Query{})
+}
+`,
+}
+
+// TestGeneratedRoutesBuild scans a fixture app, generates its routes and
+// builds it with the go tool.
+func TestGeneratedRoutesBuild(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a module")
+	}
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go tool not found")
+	}
+	framework, err := filepath.Abs("../..")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.23\n\n" +
+			"require github.com/vango-dev/vango/v2 v2.0.0\n\n" +
+			"replace github.com/vango-dev/vango/v2 => " + framework + "\n",
+	}
+	for name, content := range buildFixture {
+		files[name] = content
+	}
+	sum, err := os.ReadFile(filepath.Join(framework, "go.sum"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files["go.sum"] = string(sum)
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	routesDir := filepath.Join(dir, "app", "routes")
+	routes, err := NewScanner(routesDir).Scan()
+	if err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	gen := NewGenerator(routes, "example.com/app")
+	routesPkg, err := RoutesPackagePath("example.com/app", dir, routesDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gen.SetRoutesPackage(routesPkg)
+	code, err := gen.Generate()
+	if err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(routesDir, "routes_gen.go"), code, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(goTool, "build", "./...")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("go build failed: %v\n%s\ngenerated:\n%s", err, out, code)
+	}
+}
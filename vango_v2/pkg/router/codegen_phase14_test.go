@@ -99,7 +99,7 @@ func TestGeneratorRegisterFunction(t *testing.T) {
 	content := string(output)
 
 	t.Run("has Register function", func(t *testing.T) {
-		if !strings.Contains(content, "func Register(r *Router)") {
+		if !strings.Contains(content, "func Register(r *router.Router)") {
 			t.Error("should have Register function")
 		}
 	})
@@ -116,17 +116,17 @@ func TestGeneratorRegisterFunction(t *testing.T) {
 		}
 	})
 
-	t.Run("registers page routes with r.Page", func(t *testing.T) {
-		if !strings.Contains(content, `r.Page("/", IndexPage`) {
+	t.Run("registers page routes with r.AddPage", func(t *testing.T) {
+		if !strings.Contains(content, `r.AddPage("/", IndexPage)`) {
 			t.Error("should register root page")
 		}
-		if !strings.Contains(content, `r.Page("/about", AboutPage`) {
+		if !strings.Contains(content, `r.AddPage("/about", AboutPage)`) {
 			t.Error("should register about page")
 		}
 	})
 
-	t.Run("registers API routes with r.API", func(t *testing.T) {
-		if !strings.Contains(content, `r.API("GET", "/api/health"`) {
+	t.Run("registers API routes with r.AddAPI", func(t *testing.T) {
+		if !strings.Contains(content, `r.AddAPI("/api/health", "GET", api.HealthGET)`) {
 			t.Error("should register health API")
 		}
 	})
@@ -6,7 +6,7 @@ import (
 )
 
 func TestGeneratorGenerate(t *testing.T) {
-	// Phase 14 format: uses Register(r *Router) instead of RegisterRoutes with HandlerRegistry
+	// Phase 14 format: uses Register(r *router.Router) instead of RegisterRoutes with HandlerRegistry
 	routes := []ScannedRoute{
 		{
 			Path:     "/",
@@ -85,18 +85,34 @@ func TestGeneratorGenerate(t *testing.T) {
 	}
 
 	// Phase 14: Check Register function (not RegisterRoutes)
-	if !strings.Contains(code, "func Register(r *Router)") {
+	if !strings.Contains(code, "func Register(r *router.Router)") {
 		t.Error("missing Register function")
 	}
 
 	// Phase 14: Check page registration
-	if !strings.Contains(code, `r.Page("/", IndexPage`) {
+	if !strings.Contains(code, `r.AddPage("/", IndexPage)`) {
 		t.Error("missing / page registration")
 	}
-	if !strings.Contains(code, `r.Page("/about", AboutPage`) {
+	if !strings.Contains(code, `r.AddPage("/about", AboutPage)`) {
 		t.Error("missing /about page registration")
 	}
 
+	// Check imports of the framework router and the api package
+	for _, want := range []string{
+		`"github.com/vango-dev/vango/v2/pkg/router"`,
+		`"github.com/example/app/app/routes/api"`,
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("missing import %s", want)
+		}
+	}
+	if !strings.Contains(code, `r.AddAPI("/api/users", "POST", api.UsersPOST)`) {
+		t.Error("missing /api/users POST registration")
+	}
+	if !strings.Contains(code, `r.AddMiddleware("/users/:id", Middleware()...)`) {
+		t.Error("missing /users/:id middleware registration")
+	}
+
 	// Check route constants
 	if !strings.Contains(code, "const (") {
 		t.Error("missing route constants")
@@ -109,6 +125,127 @@ func TestGeneratorGenerate(t *testing.T) {
 	}
 }
 
+func TestGeneratorLoaders(t *testing.T) {
+	routes := []ScannedRoute{
+		{Path: "/", FilePath: "_layout.go", Package: "routes", HasLayout: true, HasLoad: true},
+		{Path: "/", FilePath: "_error.go", Package: "routes", HasErrorPage: true},
+		{Path: "/projects/:id", FilePath: "projects/[id].go", Package: "projects", HasPage: true, HasLoad: true},
+		{Path: "/about", FilePath: "about.go", Package: "routes", HasPage: true},
+	}
+
+	output, err := NewGenerator(routes, "github.com/example/app").Generate()
+	if err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+	code := string(output)
+
+	for _, want := range []string{
+		"// Data loaders",
+		`r.AddLoader("/", router.Loader(Load))`,
+		`r.AddLoader("/projects/:id", router.Loader(projects.Load))`,
+		"// Error pages",
+		`r.AddErrorPage("/", ErrorPage)`,
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code should contain %s, got:\n%s", want, code)
+		}
+	}
+	if strings.Contains(code, `r.AddLoader("/about"`) {
+		t.Error("/about has no loader")
+	}
+}
+
+func TestGeneratorURLBuilders(t *testing.T) {
+	routes := []ScannedRoute{
+		{Path: "/", FilePath: "index.go", Package: "routes", HasPage: true},
+		{
+			Path:     "/users/:id/posts/:slug",
+			FilePath: "users/[id]/posts/[slug].go",
+			Package:  "routes",
+			HasPage:  true,
+			Params: []ParamDef{
+				{Name: "id", Type: "int", Segment: "[id]"},
+				{Name: "slug", Type: "string", Segment: "[slug]"},
+			},
+		},
+		{
+			Path:       "/docs/*path",
+			FilePath:   "docs/[...path].go",
+			Package:    "routes",
+			HasPage:    true,
+			IsCatchAll: true,
+			Params:     []ParamDef{{Name: "path", Type: "[]string", Segment: "[...path]"}},
+		},
+		{Path: "/search", FilePath: "search.go", Package: "routes", HasPage: true, HasQuery: true},
+		{Path: "/api/health", FilePath: "api/health.go", Package: "api", Methods: []string{"GET"}, IsAPI: true},
+	}
+
+	output, err := NewGenerator(routes, "github.com/example/app").Generate()
+	if err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+	code := string(output)
+
+	for _, want := range []string{
+		"func IndexURL() string {\n\treturn \"/\"\n}",
+		"func UsersIDPostsSlugURL(id int, slug string) string {\n" +
+			"\treturn \"/users/\" + router.PathSegment(id) + \"/posts/\" + router.PathSegment(slug)\n}",
+		"func DocsPathURL(path []string) string {\n\treturn \"/docs/\" + router.CatchAllSegments(path)\n}",
+		"func SearchURL(query *Query) string {\n\treturn router.WithQuery(\"/search\", query)\n}",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code should contain\n%s\ngot:\n%s", want, code)
+		}
+	}
+	if strings.Contains(code, "APIHealthURL") {
+		t.Error("API routes should not get URL builders")
+	}
+}
+
+func TestGeneratorGroupsAndOptionalSegments(t *testing.T) {
+	routes := []ScannedRoute{
+		{
+			Path:     "/users/:id",
+			Pattern:  "/(app)/users/:id:int",
+			FilePath: "(app)/users/[id:int].go",
+			Package:  "routes",
+			HasPage:  true,
+			Params:   []ParamDef{{Name: "id", Type: "int", Segment: "[id:int]", Constraint: "int"}},
+		},
+		{
+			Path:     "/:lang?/docs",
+			FilePath: "[[lang]]/docs.go",
+			Package:  "routes",
+			HasPage:  true,
+			Params:   []ParamDef{{Name: "lang", Type: "string", Segment: "[[lang]]", Optional: true}},
+		},
+		{
+			Path:     "/:lang?",
+			FilePath: "[[lang]]/index.go",
+			Package:  "routes",
+			HasPage:  true,
+			Params:   []ParamDef{{Name: "lang", Type: "string", Segment: "[[lang]]", Optional: true}},
+		},
+	}
+
+	output, err := NewGenerator(routes, "github.com/example/app").Generate()
+	if err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+	code := string(output)
+
+	for _, want := range []string{
+		`r.AddPage("/(app)/users/:id:int", IDPage)`,
+		`RouteUsersID = "/users/:id"`,
+		"func LangDocsURL(lang string) string {\n\treturn router.OptionalSegment(lang) + \"/docs\"\n}",
+		"func LangURL(lang string) string {\n\treturn router.RootIfEmpty(router.OptionalSegment(lang))\n}",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code should contain\n%s\ngot:\n%s", want, code)
+		}
+	}
+}
+
 func TestGeneratorPathToStructName(t *testing.T) {
 	gen := NewGenerator(nil, "")
 
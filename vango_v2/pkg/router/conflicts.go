@@ -0,0 +1,140 @@
+package router
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Conflict is a URL matched by more than one route file. The router picks
+// one of them by priority; the others never handle it.
+type Conflict struct {
+	// Path is the conflicting URL pattern (e.g., "/users/:id")
+	Path string
+
+	// Handler is "page" or the HTTP method of the conflicting API routes
+	Handler string
+
+	// Files are the route files matching Path
+	Files []string
+}
+
+// String returns a description of the conflict.
+func (c Conflict) String() string {
+	return fmt.Sprintf("%s %s is matched by %s", c.Handler, c.Path, strings.Join(c.Files, " and "))
+}
+
+// FindConflicts returns the URLs matched by more than one of the scanned
+// routes, used by `vango gen routes`. Routes conflict when they have the
+// same URL after removing route groups and resolving optional segments,
+// and their parameters have the same constraints; parameter names do not
+// matter. Routes that differ only by constraints do not conflict: the
+// constrained one is tried first.
+func FindConflicts(routes []ScannedRoute) []Conflict {
+	type entry struct {
+		path  string
+		files []string
+	}
+	entries := make(map[string]*entry)
+	var keys []string
+
+	add := func(handler, shape, path, file string) {
+		key := handler + " " + shape
+		e, ok := entries[key]
+		if !ok {
+			e = &entry{path: path}
+			entries[key] = e
+			keys = append(keys, key)
+		}
+		for _, f := range e.files {
+			if f == file {
+				return
+			}
+		}
+		e.files = append(e.files, file)
+	}
+
+	for _, route := range routes {
+		var handlers []string
+		if route.HasPage {
+			handlers = append(handlers, "page")
+		}
+		handlers = append(handlers, route.Methods...)
+		if len(handlers) == 0 {
+			continue
+		}
+
+		for _, variant := range expandOptional(splitPath(route.RouterPath())) {
+			shape, path := routeShape(variant)
+			for _, handler := range handlers {
+				add(handler, shape, path, route.FilePath)
+			}
+		}
+	}
+
+	var conflicts []Conflict
+	for _, key := range keys {
+		e := entries[key]
+		if len(e.files) < 2 {
+			continue
+		}
+		conflicts = append(conflicts, Conflict{
+			Path:    e.path,
+			Handler: strings.SplitN(key, " ", 2)[0],
+			Files:   e.files,
+		})
+	}
+	sort.SliceStable(conflicts, func(i, j int) bool {
+		if conflicts[i].Path != conflicts[j].Path {
+			return conflicts[i].Path < conflicts[j].Path
+		}
+		return conflicts[i].Handler < conflicts[j].Handler
+	})
+	return conflicts
+}
+
+// expandOptional returns the segment lists a pattern matches, with each
+// optional segment absent or present.
+func expandOptional(segments []string) [][]string {
+	variants := [][]string{nil}
+	for _, seg := range segments {
+		if isGroupSegment(seg) {
+			continue
+		}
+		var next [][]string
+		for _, v := range variants {
+			if isOptionalSegment(seg) {
+				next = append(next, v)
+			}
+			present := append(append([]string{}, v...), strings.TrimSuffix(seg, "?"))
+			next = append(next, present)
+		}
+		variants = next
+	}
+	return variants
+}
+
+// routeShape returns the segments of a route with parameter names removed,
+// which is equal for routes matching the same URLs, and its URL pattern.
+func routeShape(segments []string) (shape, path string) {
+	shapes := make([]string, len(segments))
+	paths := make([]string, len(segments))
+	for i, seg := range segments {
+		switch {
+		case strings.HasPrefix(seg, ":"):
+			name, paramType := parseParamSegment(seg)
+			if !isConstrained(paramType) {
+				paramType = ""
+			}
+			shapes[i] = ":" + paramType
+			paths[i] = ":" + name
+		case strings.HasPrefix(seg, "*"):
+			shapes[i] = "*"
+			paths[i] = seg
+		default:
+			shapes[i] = seg
+			paths[i] = seg
+		}
+	}
+	return "/" + strings.Join(shapes, "/"), "/" + strings.Join(paths, "/")
+}
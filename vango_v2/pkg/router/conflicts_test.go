@@ -0,0 +1,73 @@
+package router
+
+import "testing"
+
+func TestFindConflicts(t *testing.T) {
+	routes := []ScannedRoute{
+		{Path: "/about", FilePath: "about.go", HasPage: true},
+		{Path: "/about", Pattern: "/(marketing)/about", FilePath: "(marketing)/about.go", HasPage: true},
+		{Path: "/users/:id", FilePath: "users/[id].go", HasPage: true},
+		{Path: "/users/:name", FilePath: "users/[name].go", HasPage: true},
+		{Path: "/posts/:id", Pattern: "/posts/:id:int", FilePath: "posts/[id:int].go", HasPage: true},
+		{Path: "/posts/:slug", FilePath: "posts/[slug].go", HasPage: true},
+		{Path: "/:lang?/docs", FilePath: "[[lang]]/docs.go", HasPage: true},
+		{Path: "/docs", FilePath: "docs.go", HasPage: true},
+		{Path: "/api/items", FilePath: "api/items.go", Methods: []string{"GET"}},
+		{Path: "/api/items", Pattern: "/(v1)/api/items", FilePath: "(v1)/api/items.go", Methods: []string{"POST"}},
+		{Path: "/", FilePath: "_layout.go", HasLayout: true},
+		{Path: "/", Pattern: "/(app)", FilePath: "(app)/_layout.go", HasLayout: true},
+	}
+
+	conflicts := FindConflicts(routes)
+
+	want := []Conflict{
+		{Path: "/about", Handler: "page", Files: []string{"about.go", "(marketing)/about.go"}},
+		{Path: "/docs", Handler: "page", Files: []string{"[[lang]]/docs.go", "docs.go"}},
+		{Path: "/users/:id", Handler: "page", Files: []string{"users/[id].go", "users/[name].go"}},
+	}
+	if len(conflicts) != len(want) {
+		t.Fatalf("FindConflicts() = %v, want %d conflicts", conflicts, len(want))
+	}
+	for i, c := range conflicts {
+		if c.String() != want[i].String() {
+			t.Errorf("conflict %d = %q, want %q", i, c, want[i])
+		}
+	}
+}
+
+func TestMatchesConstraint(t *testing.T) {
+	tests := []struct {
+		constraint string
+		value      string
+		want       bool
+	}{
+		{"", "anything", true},
+		{"string", "anything", true},
+		{"int", "42", true},
+		{"int", "4x", false},
+		{"uint", "-1", false},
+		{"uuid", "550e8400-e29b-41d4-a716-446655440000", true},
+		{"uuid", "nope", false},
+		{"alpha", "abc", true},
+		{"alpha", "abc1", false},
+		{"slug", "hello-world", true},
+		{"slug", "Hello World", false},
+		{"[0-9]{4}", "2024", true},
+		{"[0-9]{4}", "20245", false},
+		{"unregistered", "anything", true},
+	}
+
+	for _, tt := range tests {
+		if got := matchesConstraint(tt.constraint, tt.value); got != tt.want {
+			t.Errorf("matchesConstraint(%q, %q) = %v, want %v", tt.constraint, tt.value, got, tt.want)
+		}
+	}
+
+	if KnownConstraint("unregistered") {
+		t.Error("KnownConstraint() should report unregistered names")
+	}
+	RegisterConstraint("hexcolor", `[0-9a-f]{6}`)
+	if !KnownConstraint("hexcolor") || matchesConstraint("hexcolor", "zzzzzz") {
+		t.Error("registered constraints should be known and enforced")
+	}
+}
@@ -0,0 +1,124 @@
+package router
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+)
+
+// =============================================================================
+// Parameter Constraints
+// =============================================================================
+
+// A parameter constraint rejects a segment before the route's handlers run,
+// so the request falls through to the next candidate route (or 404):
+//
+//	app/routes/users/[id:int].go     // /users/42, not /users/new
+//	app/routes/docs/[page:slug].go   // /docs/getting-started
+//
+// Constraints are the parameter types (int, int64, uint, uuid, ...), the
+// patterns below, and patterns added with RegisterConstraint. Routes
+// registered in code may also use a regular expression:
+//
+//	r.AddPage("/archive/:year:[0-9]{4}", ArchivePage)
+//
+// File names only take constraint names, as regular expressions do not
+// make portable file names; the scanner rejects them. A "?" ending a
+// regular expression is part of it: only segments without a constraint
+// or with a named one can be optional (:lang? or :lang:alpha?).
+//
+// Unknown constraint names accept any value; `vango gen routes` reports
+// them.
+
+var (
+	constraintsMu sync.RWMutex
+
+	// constraints are the named constraint patterns
+	constraints = map[string]*regexp.Regexp{
+		"alpha": regexp.MustCompile(`^[A-Za-z]+$`),
+		"alnum": regexp.MustCompile(`^[A-Za-z0-9]+$`),
+		"slug":  regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`),
+	}
+
+	// inlineConstraints caches compiled inline patterns
+	inlineConstraints = map[string]*regexp.Regexp{}
+)
+
+// identifierPattern matches constraint names, as opposed to inline patterns.
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_]\w*$`)
+
+// RegisterConstraint adds a named constraint. The pattern must match the
+// whole segment. It panics if the pattern does not compile, so call it
+// from init:
+//
+//	func init() {
+//	    router.RegisterConstraint("locale", `[a-z]{2}(-[A-Z]{2})?`)
+//	}
+func RegisterConstraint(name, pattern string) {
+	re := regexp.MustCompile(`^(?:` + pattern + `)$`)
+
+	constraintsMu.Lock()
+	defer constraintsMu.Unlock()
+	constraints[name] = re
+}
+
+// KnownConstraint reports whether name is a parameter type or a
+// registered constraint.
+func KnownConstraint(name string) bool {
+	switch name {
+	case "", "string", "int", "int64", "int32", "int16", "int8",
+		"uint", "uint64", "uint32", "uint16", "uint8", "uuid":
+		return true
+	}
+	constraintsMu.RLock()
+	defer constraintsMu.RUnlock()
+	_, ok := constraints[name]
+	return ok
+}
+
+// isConstrained reports whether paramType restricts the values it matches.
+func isConstrained(paramType string) bool {
+	return paramType != "" && paramType != "string"
+}
+
+// matchesConstraint reports whether value satisfies paramType.
+func matchesConstraint(paramType, value string) bool {
+	if !isConstrained(paramType) {
+		return true
+	}
+	if ValidateParam(value, paramType) != nil {
+		return false
+	}
+
+	re, err := constraintPattern(paramType)
+	if err != nil || re == nil {
+		return true
+	}
+	return re.MatchString(value)
+}
+
+// constraintPattern returns the pattern of a named or inline constraint,
+// or nil for parameter types and unknown names.
+func constraintPattern(paramType string) (*regexp.Regexp, error) {
+	constraintsMu.RLock()
+	re, ok := constraints[paramType]
+	if !ok {
+		re, ok = inlineConstraints[paramType]
+	}
+	constraintsMu.RUnlock()
+	if ok {
+		return re, nil
+	}
+	if identifierPattern.MatchString(paramType) {
+		return nil, nil
+	}
+
+	re, err := regexp.Compile(`^(?:` + paramType + `)$`)
+	if err != nil {
+		return nil, fmt.Errorf("router: invalid constraint %q: %w", paramType, err)
+	}
+	constraintsMu.Lock()
+	inlineConstraints[paramType] = re
+	constraintsMu.Unlock()
+	return re, nil
+}
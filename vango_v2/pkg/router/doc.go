@@ -27,9 +27,35 @@
 // Dynamic route segments are defined with brackets:
 //
 //	[id].go        → :id (string by default)
-//	[id:int].go    → :id (parsed as int)
+//	[id:int].go    → :id (parsed as int, only matches integers)
+//	[page:slug].go → :page (only matches the "slug" constraint)
+//	[[lang]]/      → :lang? (optional segment)
 //	[...slug].go   → *slug (catch-all, []string)
 //
+// An explicit type is also a constraint: a segment that does not satisfy
+// it does not match, so the request moves on to the next candidate route
+// before any handler runs. Besides the parameter types, the constraints
+// alpha, alnum and slug are built in; add others with RegisterConstraint.
+// File names only take constraint names; regular expressions are for
+// routes registered in code.
+//
+// Directories named in parentheses are route groups. They add layouts and
+// middleware to the routes inside them without appearing in the URL:
+//
+//	app/routes/
+//	├── (marketing)/
+//	│   ├── _layout.go     → Layout for /pricing only
+//	│   └── pricing.go     → GET /pricing
+//	└── (app)/
+//	    ├── _layout.go     → Layout for /dashboard only
+//	    └── dashboard.go   → GET /dashboard
+//
+// When routes overlap, the match is deterministic whatever the file or
+// registration order: static segments win over groups, groups over
+// constrained parameters, constrained over unconstrained parameters, and
+// those over optional segments, which are tried absent first. Catch-alls
+// come last. `vango gen routes` reports routes that match the same URLs.
+//
 // # Route Files
 //
 // Each route file can export specific functions:
@@ -38,6 +64,9 @@
 //	func Layout(ctx server.Ctx, children Slot) *vdom.VNode   // Layout wrapper
 //	func Meta(ctx server.Ctx, params Params) PageMeta        // Page metadata
 //	func Middleware() []Middleware                           // Route middleware
+//	func Load(ctx server.Ctx, params Params) (D, error)      // Data loader
+//	func ErrorPage(ctx server.Ctx, err error) *vdom.VNode    // In _error.go
+//	type Query struct{ ... }                                 // Query parameters
 //	func GET(ctx server.Ctx, params Params) (any, error)     // API handlers
 //	func POST(ctx server.Ctx, params Params, body T) (any, error)
 //
@@ -56,4 +85,74 @@
 //	    // result.Params["id"] == "123"
 //	    // result.PageHandler, result.Layouts, result.Middleware available
 //	}
+//
+// # Metadata
+//
+// Meta functions are registered with AddMeta. A Meta on a layout path
+// provides defaults for every page below it, and a page's Meta overrides
+// them field by field. MatchResult.Head turns the chain into head
+// declarations (see package head) for rendering:
+//
+//	renderer.RenderPage(w, render.PageData{
+//	    Head: []*vdom.VNode{result.Head(ctx)},
+//	    Body: body,
+//	})
+//
+// # Live Sessions and Persistent Layouts
+//
+// View renders the router in a live session and handles client-side
+// navigation. Layouts registered with AddLayoutComponent are stateful
+// components mounted once per layout segment; a navigation only re-renders
+// the Outlet below the deepest layout shared by both routes:
+//
+//	r.AddLayoutComponent("/", func(ctx server.Ctx, outlet *router.Outlet) vdom.Component {
+//	    collapsed := vango.NewSignal(false)
+//	    return vdom.Func(func() *vdom.VNode {
+//	        return vdom.Div(Sidebar(collapsed), outlet)
+//	    })
+//	})
+//
+//	config.OnSessionStart = r.StartSession
+//	app.SetSessionRoot(func(s *server.Session) server.Component {
+//	    return r.StartView(s)
+//	})
+//
+// Outlet.OnRouteChange notifies a layout of navigations below it.
+//
+// # URL Builders
+//
+// The generated routes_gen.go has a URL builder for every page, taking the
+// route parameters with their types and escaping them:
+//
+//	routes.UsersIDPostsSlugURL(42, "hello world") // "/users/42/posts/hello%20world"
+//	router.Link(routes.UsersIDURL(user.ID), vdom.Text(user.Name))
+//
+// A route file declaring a Query struct gets a typed query argument, see
+// WithQuery. `vango gen routes --check` uses LinkChecker to report
+// hard-coded hrefs that match no route.
+//
+// # Loaders
+//
+// Pages and layouts load their data with a Load function, registered with
+// AddLoader. MatchResult.Load runs all loaders of a match concurrently
+// before rendering; components read the results with Data:
+//
+//	data, err := result.Load(ctx)
+//	if err != nil {
+//	    return result.ErrorPage(ctx, err) // nearest _error.go
+//	}
+//	project, _ := router.Data[*Project](ctx)
+//
+// A LoaderStore hands the data over to the live session of the page, so
+// the session renders from it instead of loading again.
+//
+// # API Clients
+//
+// OpenAPIGenerator describes the API routes as an OpenAPI specification.
+// ClientGenerator builds on the same scan to emit typed clients, used by
+// `vango gen client`:
+//
+//	gen, err := router.NewClientGenerator(openapi, "apiclient")
+//	goSrc, err := gen.GenerateGo()
+//	tsSrc, err := gen.GenerateTS()
 package router
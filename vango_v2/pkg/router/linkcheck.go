@@ -0,0 +1,158 @@
+package router
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/vango-dev/vango/v2/pkg/server"
+	"github.com/vango-dev/vango/v2/pkg/vdom"
+)
+
+// BrokenLink is a hard-coded href that matches no route.
+type BrokenLink struct {
+	// Pos is the position of the href literal (file:line:column)
+	Pos string
+
+	// Href is the href as written
+	Href string
+}
+
+// String returns "pos: href".
+func (l BrokenLink) String() string {
+	return fmt.Sprintf("%s: %s matches no route", l.Pos, l.Href)
+}
+
+// linkFuncs are the functions whose first argument is an href.
+var linkFuncs = map[string]bool{
+	"Href":             true,
+	"Link":             true,
+	"LinkWithPrefetch": true,
+	"ActiveLink":       true,
+	"NavLink":          true,
+	"Navigate":         true,
+}
+
+// LinkChecker finds hard-coded hrefs that match no scanned route, used by
+// `vango gen routes --check`.
+type LinkChecker struct {
+	router *Router
+
+	// Ignore reports hrefs that are valid without a route, such as files
+	// of the public directory
+	Ignore func(href string) bool
+}
+
+// NewLinkChecker creates a checker for the scanned routes.
+func NewLinkChecker(routes []ScannedRoute) *LinkChecker {
+	r := NewRouter()
+	for _, route := range routes {
+		if route.HasPage {
+			r.AddPage(route.RouterPath(), func(ctx server.Ctx, params any) vdom.Component { return nil })
+		}
+		for _, method := range route.Methods {
+			r.AddAPI(route.RouterPath(), method, func(ctx server.Ctx, params any, body any) (any, error) { return nil, nil })
+		}
+	}
+	return &LinkChecker{router: r}
+}
+
+// Valid reports whether href is external or matches a route.
+func (c *LinkChecker) Valid(href string) bool {
+	// Only check absolute paths; skip external, relative and fragment links
+	if !strings.HasPrefix(href, "/") || strings.HasPrefix(href, "//") {
+		return true
+	}
+	if c.Ignore != nil && c.Ignore(href) {
+		return true
+	}
+
+	path := href
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+	_, ok := c.router.Match("GET", path)
+	return ok
+}
+
+// CheckDir checks the Go files below dir, skipping generated files and
+// vendored or hidden directories.
+func (c *LinkChecker) CheckDir(dir string) ([]BrokenLink, error) {
+	var broken []BrokenLink
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if path != dir && (strings.HasPrefix(name, ".") || name == "vendor" || name == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_gen.go") {
+			return nil
+		}
+
+		links, err := c.CheckFile(path)
+		if err != nil {
+			return err
+		}
+		broken = append(broken, links...)
+		return nil
+	})
+
+	return broken, err
+}
+
+// CheckFile checks the hrefs passed as string literals to Href, Link,
+// NavLink and Navigate calls in a Go file.
+func (c *LinkChecker) CheckFile(path string) ([]BrokenLink, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, path, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var broken []BrokenLink
+	ast.Inspect(f, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) == 0 || !linkFuncs[callName(call)] {
+			return true
+		}
+
+		lit, ok := call.Args[0].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return true
+		}
+		href, err := strconv.Unquote(lit.Value)
+		if err != nil || c.Valid(href) {
+			return true
+		}
+
+		broken = append(broken, BrokenLink{
+			Pos:  fset.Position(lit.Pos()).String(),
+			Href: href,
+		})
+		return true
+	})
+
+	return broken, nil
+}
+
+// callName returns the name of the called function or method.
+func callName(call *ast.CallExpr) string {
+	switch fn := call.Fun.(type) {
+	case *ast.Ident:
+		return fn.Name
+	case *ast.SelectorExpr:
+		return fn.Sel.Name
+	}
+	return ""
+}
@@ -0,0 +1,90 @@
+package router
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLinkCheckerValid(t *testing.T) {
+	checker := NewLinkChecker([]ScannedRoute{
+		{Path: "/", HasPage: true},
+		{Path: "/users/:id", HasPage: true},
+		{Path: "/docs/*path", HasPage: true},
+		{Path: "/api/health", Methods: []string{"GET"}, IsAPI: true},
+	})
+	checker.Ignore = func(href string) bool { return href == "/favicon.ico" }
+
+	tests := []struct {
+		href string
+		want bool
+	}{
+		{"/", true},
+		{"/users/42", true},
+		{"/users/42?tab=posts#top", true},
+		{"/docs/guide/install", true},
+		{"/api/health", true},
+		{"/favicon.ico", true},
+		{"https://example.com/missing", true},
+		{"#section", true},
+		{"relative", true},
+		{"/missing", false},
+		{"/users", false},
+		{"/users/42/posts", false},
+	}
+
+	for _, tt := range tests {
+		if got := checker.Valid(tt.href); got != tt.want {
+			t.Errorf("Valid(%q) = %v, want %v", tt.href, got, tt.want)
+		}
+	}
+}
+
+func TestLinkCheckerCheckDir(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"app/components/nav.go": `package components
+
+func Nav() {
+	_ = router.Link("/users/1", "User")
+	_ = router.NavLink("/setings", "Settings")
+	_ = vdom.A(vdom.Href("/about"))
+	_ = vdom.A(vdom.Href(routes.IndexURL()))
+	_ = fmt.Sprint("/not-a-link")
+}
+`,
+		"app/routes/routes_gen.go": `package routes; func f() { Link("/generated") }`,
+		".vango/export/main.go":    `package main; func f() { Link("/hidden") }`,
+	}
+	for path, content := range files {
+		fullPath := filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatalf("mkdir %s: %v", filepath.Dir(fullPath), err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+			t.Fatalf("write %s: %v", fullPath, err)
+		}
+	}
+
+	checker := NewLinkChecker([]ScannedRoute{
+		{Path: "/users/:id", HasPage: true},
+		{Path: "/settings", HasPage: true},
+	})
+	broken, err := checker.CheckDir(dir)
+	if err != nil {
+		t.Fatalf("CheckDir() error: %v", err)
+	}
+
+	var hrefs []string
+	for _, link := range broken {
+		hrefs = append(hrefs, link.Href)
+	}
+	if got, want := strings.Join(hrefs, " "), "/setings /about"; got != want {
+		t.Errorf("broken = %q, want %q", got, want)
+	}
+	if len(broken) > 0 && !strings.Contains(broken[0].Pos, "nav.go:5:") {
+		t.Errorf("Pos = %q, want nav.go:5", broken[0].Pos)
+	}
+}
@@ -0,0 +1,371 @@
+package router
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/vango-dev/vango/v2/pkg/server"
+)
+
+// =============================================================================
+// Loaders
+// =============================================================================
+
+// LoadFunc loads the data of a page or layout before it renders.
+// Use Loader to adapt a typed Load function.
+type LoadFunc func(ctx server.Ctx, params any) (any, error)
+
+// RouteLoader is a loader registered on a route.
+type RouteLoader struct {
+	// Route is the path the loader was registered on
+	Route string
+
+	// Load loads the data
+	Load LoadFunc
+}
+
+// Loader adapts a typed Load function of a route file:
+//
+//	func Load(ctx server.Ctx, params Params) (*Project, error) {
+//	    return db.Projects.FindByID(params.ID)
+//	}
+//
+//	r.AddLoader("/projects/:id", router.Loader(Load))
+//
+// Route parameters are decoded into P with ParamParser when P is a struct.
+func Loader[P, D any](fn func(ctx server.Ctx, params P) (D, error)) LoadFunc {
+	return func(ctx server.Ctx, params any) (any, error) {
+		var p P
+		switch v := params.(type) {
+		case P:
+			p = v
+		case map[string]string:
+			if reflect.TypeOf(p) != nil && reflect.TypeOf(p).Kind() == reflect.Struct {
+				if err := NewParamParser().Parse(v, &p); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return fn(ctx, p)
+	}
+}
+
+// LoadError is returned by MatchResult.Load when a loader fails.
+type LoadError struct {
+	// Route is the path of the failing loader
+	Route string
+
+	// Err is the loader error
+	Err error
+}
+
+// Error implements error.
+func (e *LoadError) Error() string {
+	return fmt.Sprintf("router: loading %s: %v", e.Route, e.Err)
+}
+
+// Unwrap returns the loader error.
+func (e *LoadError) Unwrap() error {
+	return e.Err
+}
+
+// Load runs the loaders of the match concurrently, layouts and page
+// together, and makes the results available to Data through ctx. Each
+// loader gets its own request-scoped values: values it sets with
+// SetValue are not seen by the others or by ctx.
+//
+// If loaders fail, the error of the outermost one is returned as a
+// *LoadError; render ErrorPage with it instead of the page.
+func (m *MatchResult) Load(ctx server.Ctx) (*LoadedData, error) {
+	data := &LoadedData{entries: make([]loadedEntry, len(m.Loaders))}
+	errs := make([]error, len(m.Loaders))
+
+	var wg sync.WaitGroup
+	for i, loader := range m.Loaders {
+		wg.Add(1)
+		go func(i int, loader RouteLoader) {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					errs[i] = fmt.Errorf("panic: %v", r)
+				}
+			}()
+
+			value, err := loader.Load(&loaderCtx{Ctx: ctx}, m.Params)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			data.entries[i] = loadedEntry{
+				Route: loader.Route,
+				Type:  typeName(reflect.TypeOf(value)),
+				value: value,
+			}
+		}(i, loader)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, &LoadError{Route: m.Loaders[i].Route, Err: err}
+		}
+	}
+
+	ctx.SetValue(loadedDataKey{}, data)
+	return data, nil
+}
+
+// loaderCtx is the context of a loader running concurrently with others.
+// Its values are its own; the values of the shared context are read only.
+type loaderCtx struct {
+	server.Ctx
+	values map[any]any
+}
+
+// SetValue stores a value seen by this loader only.
+func (c *loaderCtx) SetValue(key, value any) {
+	if c.values == nil {
+		c.values = make(map[any]any)
+	}
+	c.values[key] = value
+}
+
+// Value returns a value set by this loader, or of the shared context.
+func (c *loaderCtx) Value(key any) any {
+	if v, ok := c.values[key]; ok {
+		return v
+	}
+	return c.Ctx.Value(key)
+}
+
+// =============================================================================
+// Loaded Data
+// =============================================================================
+
+// LoadedData holds the loader results of a matched route, from the root
+// layout to the page.
+type LoadedData struct {
+	entries []loadedEntry
+}
+
+// loadedEntry is the result of one loader. After a round trip through
+// JSON only raw is set; it is decoded by Data into the requested type.
+type loadedEntry struct {
+	Route string          `json:"route"`
+	Type  string          `json:"type"`
+	Raw   json.RawMessage `json:"data"`
+	value any
+}
+
+// loadedDataKey is the Ctx key of the current LoadedData.
+type loadedDataKey struct{}
+
+// LoaderSessionKey is the session key under which a live session keeps the
+// data loaded during server-side rendering. A View takes it for its first
+// page and removes it, so later pages never see it.
+const LoaderSessionKey = "vango_router_loaded_data"
+
+// Get returns the result of the loader registered on route.
+// Results restored from JSON are returned as json.RawMessage.
+func (d *LoadedData) Get(route string) (any, bool) {
+	if d == nil {
+		return nil, false
+	}
+	for _, e := range d.entries {
+		if e.Route == route {
+			if e.value != nil {
+				return e.value, true
+			}
+			return e.Raw, true
+		}
+	}
+	return nil, false
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d *LoadedData) MarshalJSON() ([]byte, error) {
+	entries := make([]loadedEntry, len(d.entries))
+	for i, e := range d.entries {
+		raw := e.Raw
+		if e.value != nil {
+			b, err := json.Marshal(e.value)
+			if err != nil {
+				return nil, fmt.Errorf("router: encoding data of %s: %w", e.Route, err)
+			}
+			raw = b
+		}
+		entries[i] = loadedEntry{Route: e.Route, Type: e.Type, Raw: raw}
+	}
+	return json.Marshal(entries)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *LoadedData) UnmarshalJSON(b []byte) error {
+	return json.Unmarshal(b, &d.entries)
+}
+
+// Data returns the loaded value of type D: the result of the innermost
+// loader of the route that returned a D. In a live session it returns the
+// data handed over from server-side rendering, so components never fetch
+// twice.
+//
+//	func Page(ctx server.Ctx, params Params) vdom.Component {
+//	    project, _ := router.Data[*Project](ctx)
+//	    // ...
+//	}
+func Data[D any](ctx server.Ctx) (D, bool) {
+	var zero D
+	if ctx == nil {
+		return zero, false
+	}
+
+	data, _ := ctx.Value(loadedDataKey{}).(*LoadedData)
+	if data == nil {
+		if session := ctx.Session(); session != nil {
+			data, _ = session.Get(LoaderSessionKey).(*LoadedData)
+		}
+	}
+	if data == nil {
+		return zero, false
+	}
+
+	want := typeName(reflect.TypeOf((*D)(nil)).Elem())
+	for i := len(data.entries) - 1; i >= 0; i-- {
+		e := data.entries[i]
+		if v, ok := e.value.(D); ok {
+			return v, true
+		}
+		if e.value == nil && e.Type == want && e.Raw != nil {
+			var v D
+			if err := json.Unmarshal(e.Raw, &v); err == nil {
+				return v, true
+			}
+		}
+	}
+	return zero, false
+}
+
+// typeName returns a stable name for t, used to match data across a JSON
+// round trip.
+func typeName(t reflect.Type) string {
+	if t == nil {
+		return ""
+	}
+	return t.String()
+}
+
+// =============================================================================
+// Handover to Live Sessions
+// =============================================================================
+
+// LoaderStore hands the data loaded during server-side rendering over to
+// the live session that the page opens, so loaders do not run twice and
+// the first live render matches the server-rendered HTML.
+//
+// Data is kept serialized and taken once. Entries not claimed within the
+// TTL are dropped.
+//
+//	store := router.NewLoaderStore(time.Minute)
+//
+//	// Page handler
+//	data, err := match.Load(ctx)
+//	token, _ := store.Save(data)
+//	renderer.RenderPage(w, render.PageData{Body: body, LoadToken: token})
+//
+//	// Server config
+//	config.OnSessionStart = func(httpCtx context.Context, s *server.Session) {
+//	    store.StartSession(httpCtx, s)
+//	}
+type LoaderStore struct {
+	mu      sync.Mutex
+	entries map[string]storedData
+	ttl     time.Duration
+}
+
+// storedData is serialized loader data awaiting its session.
+type storedData struct {
+	data    []byte
+	expires time.Time
+}
+
+// LoadTokenParam is the query parameter of the WebSocket URL that carries
+// the load token of the page.
+const LoadTokenParam = "load"
+
+// NewLoaderStore creates a store keeping data for ttl.
+// Default: 1 minute
+func NewLoaderStore(ttl time.Duration) *LoaderStore {
+	if ttl <= 0 {
+		ttl = time.Minute
+	}
+	return &LoaderStore{
+		entries: make(map[string]storedData),
+		ttl:     ttl,
+	}
+}
+
+// Save serializes data and returns the token to render into the page.
+func (s *LoaderStore) Save(data *LoadedData) (string, error) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(buf)
+
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Drop expired entries
+	for k, e := range s.entries {
+		if now.After(e.expires) {
+			delete(s.entries, k)
+		}
+	}
+	s.entries[token] = storedData{data: b, expires: now.Add(s.ttl)}
+	return token, nil
+}
+
+// Take returns the data saved under token and removes it.
+func (s *LoaderStore) Take(token string) (*LoadedData, bool) {
+	s.mu.Lock()
+	e, ok := s.entries[token]
+	delete(s.entries, token)
+	s.mu.Unlock()
+
+	if !ok || time.Now().After(e.expires) {
+		return nil, false
+	}
+
+	data := &LoadedData{}
+	if err := json.Unmarshal(e.data, data); err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// StartSession stores the data of the page that opened session on the
+// session, read by Data. Call it from OnSessionStart.
+func (s *LoaderStore) StartSession(httpCtx context.Context, session *server.Session) bool {
+	r := server.RequestFromContext(httpCtx)
+	if r == nil || session == nil {
+		return false
+	}
+	data, ok := s.Take(r.URL.Query().Get(LoadTokenParam))
+	if !ok {
+		return false
+	}
+	session.Set(LoaderSessionKey, data)
+	return true
+}
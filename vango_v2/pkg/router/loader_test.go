@@ -0,0 +1,281 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vango-dev/vango/v2/pkg/server"
+	"github.com/vango-dev/vango/v2/pkg/vdom"
+)
+
+type testUser struct {
+	Name string `json:"name"`
+}
+
+type testProject struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+}
+
+type testProjectParams struct {
+	ID int `param:"id"`
+}
+
+func loaderRouter(loadProject func(ctx server.Ctx, p testProjectParams) (*testProject, error)) *Router {
+	r := NewRouter()
+	r.AddLayout("/", func(ctx server.Ctx, children Slot) *vdom.VNode { return children })
+	r.AddLoader("/", Loader(func(ctx server.Ctx, _ struct{}) (*testUser, error) {
+		return &testUser{Name: "ada"}, nil
+	}))
+	r.AddPage("/projects/:id", func(ctx server.Ctx, params any) vdom.Component { return nil })
+	r.AddLoader("/projects/:id", Loader(loadProject))
+	return r
+}
+
+func TestMatchLoad(t *testing.T) {
+	r := loaderRouter(func(ctx server.Ctx, p testProjectParams) (*testProject, error) {
+		return &testProject{ID: p.ID, Title: "Vango"}, nil
+	})
+
+	match, ok := r.Match("GET", "/projects/42")
+	if !ok {
+		t.Fatal("expected match")
+	}
+	if len(match.Loaders) != 2 {
+		t.Fatalf("Loaders = %d, want 2", len(match.Loaders))
+	}
+	if match.Loaders[0].Route != "/" || match.Loaders[1].Route != "/projects/:id" {
+		t.Errorf("Loaders should be ordered root to leaf, got %s, %s",
+			match.Loaders[0].Route, match.Loaders[1].Route)
+	}
+
+	ctx := server.NewTestContext(nil)
+	data, err := match.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if v, ok := data.Get("/projects/:id"); !ok || v.(*testProject).ID != 42 {
+		t.Errorf("Get() = %v, want project 42", v)
+	}
+
+	project, ok := Data[*testProject](ctx)
+	if !ok || project.ID != 42 || project.Title != "Vango" {
+		t.Errorf("Data[*testProject]() = %v, %v", project, ok)
+	}
+	user, ok := Data[*testUser](ctx)
+	if !ok || user.Name != "ada" {
+		t.Errorf("Data[*testUser]() = %v, %v", user, ok)
+	}
+	if _, ok := Data[string](ctx); ok {
+		t.Error("Data[string]() should not find a value")
+	}
+}
+
+func TestMatchLoadParallel(t *testing.T) {
+	var running, peak int32
+	slow := func(ctx server.Ctx, params any) (any, error) {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return nil, nil
+	}
+
+	r := NewRouter()
+	r.AddLoader("/", slow)
+	r.AddLoader("/a", slow)
+	r.AddPage("/a/b", func(ctx server.Ctx, params any) vdom.Component { return nil })
+	r.AddLoader("/a/b", slow)
+
+	match, _ := r.Match("GET", "/a/b")
+	if _, err := match.Load(server.NewTestContext(nil)); err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if peak != 3 {
+		t.Errorf("loaders should run concurrently, peak = %d", peak)
+	}
+}
+
+func TestMatchLoadValues(t *testing.T) {
+	type key struct{}
+	setter := func(name string) LoadFunc {
+		return func(ctx server.Ctx, params any) (any, error) {
+			for i := 0; i < 100; i++ {
+				ctx.SetValue(key{}, name)
+			}
+			if got := ctx.Value(key{}); got != name {
+				return nil, fmt.Errorf("Value() = %v, want %s", got, name)
+			}
+			return nil, nil
+		}
+	}
+
+	r := NewRouter()
+	r.AddLoader("/", setter("root"))
+	r.AddPage("/a", func(ctx server.Ctx, params any) vdom.Component { return nil })
+	r.AddLoader("/a", setter("page"))
+
+	match, _ := r.Match("GET", "/a")
+	ctx := server.NewTestContext(nil)
+	if _, err := match.Load(ctx); err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if ctx.Value(key{}) != nil {
+		t.Error("values set by loaders should stay with the loader")
+	}
+}
+
+func TestViewTakesLoadedData(t *testing.T) {
+	var loads int32
+	r := loaderRouter(func(ctx server.Ctx, p testProjectParams) (*testProject, error) {
+		atomic.AddInt32(&loads, 1)
+		return &testProject{ID: p.ID, Title: "Loaded"}, nil
+	})
+	var titles []string
+	r.AddPage("/projects/:id", func(ctx server.Ctx, params any) vdom.Component {
+		project, _ := Data[*testProject](ctx)
+		if project != nil {
+			titles = append(titles, project.Title)
+		}
+		return nil
+	})
+
+	// Data handed over from server-side rendering of the first page
+	data := &LoadedData{entries: []loadedEntry{
+		{Route: "/projects/:id", value: &testProject{ID: 1, Title: "Handed over"}},
+	}}
+	session := server.NewMockSession()
+	session.Set(LoaderSessionKey, data)
+
+	v := NewView(r, session, "/projects/1")
+	if loads != 0 {
+		t.Errorf("the first page should use the handed-over data, loaded %d times", loads)
+	}
+	if session.Get(LoaderSessionKey) != nil {
+		t.Error("the handed-over data should be removed from the session")
+	}
+
+	v.Navigate("/projects/2")
+	if loads != 1 {
+		t.Errorf("later pages should load their data, loaded %d times", loads)
+	}
+	if len(titles) != 2 || titles[0] != "Handed over" || titles[1] != "Loaded" {
+		t.Errorf("pages saw %v", titles)
+	}
+}
+
+func TestMatchLoadError(t *testing.T) {
+	errNotFound := errors.New("project not found")
+	r := loaderRouter(func(ctx server.Ctx, p testProjectParams) (*testProject, error) {
+		return nil, errNotFound
+	})
+
+	var rendered error
+	r.AddErrorPage("/projects", func(ctx server.Ctx, err error) *vdom.VNode {
+		rendered = err
+		return vdom.Div()
+	})
+	r.SetErrorPage(func(ctx server.Ctx, err error) *vdom.VNode { return nil })
+
+	match, _ := r.Match("GET", "/projects/1")
+	_, err := match.Load(server.NewTestContext(nil))
+
+	var loadErr *LoadError
+	if !errors.As(err, &loadErr) {
+		t.Fatalf("Load() error = %v, want *LoadError", err)
+	}
+	if loadErr.Route != "/projects/:id" || !errors.Is(err, errNotFound) {
+		t.Errorf("LoadError = %+v", loadErr)
+	}
+
+	// The nearest error page handles it
+	match.ErrorPage(server.NewTestContext(nil), err)
+	if rendered != err {
+		t.Error("ErrorPage should be the handler of /projects")
+	}
+
+	// Other pages fall back to the router's error page
+	r.AddPage("/about", func(ctx server.Ctx, params any) vdom.Component { return nil })
+	about, _ := r.Match("GET", "/about")
+	if about.ErrorPage == nil || about.ErrorPage(nil, err) != nil {
+		t.Error("ErrorPage should fall back to the router's error page")
+	}
+}
+
+func TestMatchLoadInvalidParams(t *testing.T) {
+	r := loaderRouter(func(ctx server.Ctx, p testProjectParams) (*testProject, error) {
+		return &testProject{ID: p.ID}, nil
+	})
+
+	match, ok := r.Match("GET", "/projects/abc")
+	if !ok {
+		t.Fatal("expected match")
+	}
+	if _, err := match.Load(server.NewTestContext(nil)); err == nil {
+		t.Error("expected an error for a non-integer id")
+	}
+}
+
+func TestLoaderStore(t *testing.T) {
+	r := loaderRouter(func(ctx server.Ctx, p testProjectParams) (*testProject, error) {
+		return &testProject{ID: p.ID, Title: "Vango"}, nil
+	})
+	match, _ := r.Match("GET", "/projects/7")
+	data, err := match.Load(server.NewTestContext(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := NewLoaderStore(time.Minute)
+	token, err := store.Save(data)
+	if err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	restored, ok := store.Take(token)
+	if !ok {
+		t.Fatal("Take() should return the saved data")
+	}
+	if _, ok := store.Take(token); ok {
+		t.Error("data should only be taken once")
+	}
+
+	// The live session reads the handed-over data
+	session := server.NewMockSession()
+	session.Set(LoaderSessionKey, restored)
+	ctx := server.NewTestContext(session)
+
+	project, ok := Data[*testProject](ctx)
+	if !ok || project.ID != 7 || project.Title != "Vango" {
+		t.Errorf("Data[*testProject]() = %v, %v", project, ok)
+	}
+	if user, ok := Data[*testUser](ctx); !ok || user.Name != "ada" {
+		t.Errorf("Data[*testUser]() = %v, %v", user, ok)
+	}
+}
+
+func TestLoaderStoreExpiry(t *testing.T) {
+	store := NewLoaderStore(time.Nanosecond)
+	token, err := store.Save(&LoadedData{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond)
+	if _, ok := store.Take(token); ok {
+		t.Error("expired data should not be returned")
+	}
+
+	// Without the upgrade request there is nothing to hand over
+	if store.StartSession(context.Background(), server.NewMockSession()) {
+		t.Error("StartSession() should report no data")
+	}
+}
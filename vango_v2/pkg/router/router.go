@@ -1,7 +1,13 @@
 package router
 
 import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
 	"github.com/vango-dev/vango/v2/pkg/server"
+	"github.com/vango-dev/vango/v2/pkg/vdom"
 )
 
 // Router manages route matching and handler dispatch.
@@ -31,6 +37,53 @@ func (r *Router) AddLayout(path string, handler LayoutHandler) {
 	node.layoutHandler = handler
 }
 
+// AddLayoutComponent registers a persistent layout for a path. In a live
+// session rendered by View, the layout component is mounted once and kept
+// while navigating between the pages below it. On the server it renders
+// like a layout registered with AddLayout.
+func (r *Router) AddLayoutComponent(path string, fn LayoutComponent) {
+	node := r.root.insertRoute(path)
+	node.layoutComp = fn
+	node.layoutHandler = func(ctx server.Ctx, children Slot) *vdom.VNode {
+		return componentNode(fn(ctx, newOutlet(children)))
+	}
+}
+
+// AddMeta registers a metadata handler for a path.
+// A handler on a layout path applies to every page under it.
+func (r *Router) AddMeta(path string, handler MetaHandler) {
+	node := r.root.insertRoute(path)
+	node.metaHandler = handler
+}
+
+// AddStaticParams registers the parameter values a dynamic page is
+// exported with by `vango export`.
+func (r *Router) AddStaticParams(path string, fn StaticParamsFunc) {
+	node := r.root.insertRoute(path)
+	node.staticParams = fn
+}
+
+// SetStatic marks the page at path as static. Static pages have no
+// interactivity and are exported without the client bootstrap.
+func (r *Router) SetStatic(path string) {
+	node := r.root.insertRoute(path)
+	node.static = true
+}
+
+// AddLoader registers the data loader of a page or layout. Loaders run
+// before rendering, see MatchResult.Load.
+func (r *Router) AddLoader(path string, fn LoadFunc) {
+	node := r.root.insertRoute(path)
+	node.loader = fn
+}
+
+// AddErrorPage registers the error page for loader errors of the pages at
+// and below path.
+func (r *Router) AddErrorPage(path string, handler ErrorHandler) {
+	node := r.root.insertRoute(path)
+	node.errorHandler = handler
+}
+
 // AddAPI registers an API handler for a path and method.
 func (r *Router) AddAPI(path, method string, handler APIHandler) {
 	node := r.root.insertRoute(path)
@@ -84,7 +137,7 @@ func (r *Router) Match(method, path string) (*MatchResult, bool) {
 
 	// Collect middleware from root
 	result.Middleware = append(result.Middleware, r.middleware...)
-	result.Middleware = append(result.Middleware, node.middleware...)
+	result.Middleware = append(result.Middleware, node.middlewareChain()...)
 
 	// Check for API handler
 	if node.apiHandlers != nil {
@@ -100,12 +153,81 @@ func (r *Router) Match(method, path string) (*MatchResult, bool) {
 	// Check for page handler
 	if node.pageHandler != nil {
 		result.PageHandler = node.pageHandler
+		result.Meta = node.metaChain()
+		result.Static = node.static
+		result.Loaders = node.loaderChain()
+		result.ErrorPage = node.nearestErrorHandler()
+		if result.ErrorPage == nil {
+			result.ErrorPage = r.errorPage
+		}
 		return result, true
 	}
 
 	return nil, false
 }
 
+// PageRoute describes a registered page route.
+type PageRoute struct {
+	// Path is the route pattern (e.g., "/blog/:slug")
+	Path string
+
+	// Params are the parameter names in the pattern, in order
+	Params []string
+
+	// StaticParams lists the parameter values to export, if registered
+	StaticParams StaticParamsFunc
+
+	// Static reports whether the page is marked static
+	Static bool
+}
+
+// Pages returns the registered page routes, sorted by path.
+func (r *Router) Pages() []PageRoute {
+	pages := r.root.collectPages(nil)
+	sort.Slice(pages, func(i, j int) bool {
+		return pages[i].Path < pages[j].Path
+	})
+	return pages
+}
+
+// BuildPath fills the parameters of a route pattern.
+// A catch-all value may contain slashes; other values are path-escaped.
+// Optional parameters without a value and route groups are left out.
+//
+//	BuildPath("/blog/:slug", map[string]string{"slug": "hello"}) // "/blog/hello"
+func BuildPath(pattern string, params map[string]string) (string, error) {
+	var segments []string
+	for _, seg := range splitPath(pattern) {
+		switch {
+		case isGroupSegment(seg):
+			continue
+		case strings.HasPrefix(seg, ":"):
+			name, _ := parseParamSegment(seg)
+			value, ok := params[name]
+			if (!ok || value == "") && isOptionalSegment(seg) {
+				continue
+			}
+			if !ok || value == "" {
+				return "", fmt.Errorf("router: missing parameter %q for %s", name, pattern)
+			}
+			seg = url.PathEscape(value)
+		case strings.HasPrefix(seg, "*"):
+			name := seg[1:]
+			value, ok := params[name]
+			if !ok || value == "" {
+				return "", fmt.Errorf("router: missing parameter %q for %s", name, pattern)
+			}
+			parts := strings.Split(strings.Trim(value, "/"), "/")
+			for j, part := range parts {
+				parts[j] = url.PathEscape(part)
+			}
+			seg = strings.Join(parts, "/")
+		}
+		segments = append(segments, seg)
+	}
+	return "/" + strings.Join(segments, "/"), nil
+}
+
 // NotFound returns the 404 handler.
 func (r *Router) NotFound() PageHandler {
 	return r.notFound
@@ -127,6 +249,10 @@ func (r *Router) ServeHTTP(ctx server.Ctx) (*MatchResult, bool) {
 type HandlerRegistry struct {
 	Pages   map[string]PageHandler
 	Layouts map[string]LayoutHandler
+	Metas   map[string]MetaHandler
+	Statics map[string]StaticParamsFunc
+	Loaders map[string]LoadFunc
+	Errors  map[string]ErrorHandler
 	APIs    map[string]map[string]APIHandler // path -> method -> handler
 	MW      map[string][]Middleware
 }
@@ -134,30 +260,70 @@ type HandlerRegistry struct {
 // BuildFromScanned populates the router from scanned routes.
 func (r *Router) BuildFromScanned(routes []ScannedRoute, registry *HandlerRegistry) {
 	for _, route := range routes {
+		pattern := route.RouterPath()
 		if route.HasLayout && registry.Layouts != nil {
-			if handler, ok := registry.Layouts[route.Path]; ok {
-				r.AddLayout(route.Path, handler)
+			if handler, ok := registryEntry(registry.Layouts, route); ok {
+				r.AddLayout(pattern, handler)
 			}
 		}
 
 		if route.HasPage && registry.Pages != nil {
-			if handler, ok := registry.Pages[route.Path]; ok {
-				r.AddPage(route.Path, handler)
+			if handler, ok := registryEntry(registry.Pages, route); ok {
+				r.AddPage(pattern, handler)
+			}
+		}
+
+		if route.HasMeta && registry.Metas != nil {
+			if handler, ok := registryEntry(registry.Metas, route); ok {
+				r.AddMeta(pattern, handler)
+			}
+		}
+
+		if route.HasStaticParams && registry.Statics != nil {
+			if fn, ok := registryEntry(registry.Statics, route); ok {
+				r.AddStaticParams(pattern, fn)
 			}
 		}
 
+		if route.HasLoad && registry.Loaders != nil {
+			if fn, ok := registryEntry(registry.Loaders, route); ok {
+				r.AddLoader(pattern, fn)
+			}
+		}
+
+		if route.HasErrorPage && registry.Errors != nil {
+			if handler, ok := registryEntry(registry.Errors, route); ok {
+				r.AddErrorPage(pattern, handler)
+			}
+		}
+
+		if route.IsStatic && route.HasPage {
+			r.SetStatic(pattern)
+		}
+
 		if len(route.Methods) > 0 && registry.APIs != nil {
-			if handlers, ok := registry.APIs[route.Path]; ok {
+			if handlers, ok := registryEntry(registry.APIs, route); ok {
 				for method, handler := range handlers {
-					r.AddAPI(route.Path, method, handler)
+					r.AddAPI(pattern, method, handler)
 				}
 			}
 		}
 
 		if route.HasMiddleware && registry.MW != nil {
-			if mw, ok := registry.MW[route.Path]; ok {
-				r.AddMiddleware(route.Path, mw...)
+			if mw, ok := registryEntry(registry.MW, route); ok {
+				r.AddMiddleware(pattern, mw...)
 			}
 		}
 	}
 }
+
+// registryEntry returns the entry of route in a HandlerRegistry map. Entries
+// are keyed by the route's pattern, or by its URL path when it has neither
+// groups nor constraints.
+func registryEntry[V any](entries map[string]V, route ScannedRoute) (V, bool) {
+	if v, ok := entries[route.RouterPath()]; ok {
+		return v, true
+	}
+	v, ok := entries[route.Path]
+	return v, ok
+}
@@ -1,8 +1,10 @@
 package router
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/vango-dev/vango/v2/pkg/head"
 	"github.com/vango-dev/vango/v2/pkg/server"
 	"github.com/vango-dev/vango/v2/pkg/vdom"
 )
@@ -130,6 +132,40 @@ func TestRouterNestedLayouts(t *testing.T) {
 	}
 }
 
+func TestRouterMetaChain(t *testing.T) {
+	r := NewRouter()
+	r.AddPage("/users/:id", func(ctx server.Ctx, params any) vdom.Component {
+		return nil
+	})
+	r.AddMeta("/", func(ctx server.Ctx, params any) PageMeta {
+		return PageMeta{Title: "Acme", Description: "Acme app", Robots: "index"}
+	})
+	r.AddMeta("/users/:id", func(ctx server.Ctx, params any) PageMeta {
+		id := params.(map[string]string)["id"]
+		return PageMeta{Title: "User " + id, Description: "Profile of user " + id}
+	})
+
+	result, ok := r.Match("GET", "/users/42")
+	if !ok {
+		t.Fatal("expected match for /users/42")
+	}
+	if len(result.Meta) != 2 {
+		t.Fatalf("len(Meta) = %d, want 2", len(result.Meta))
+	}
+
+	h := head.New()
+	h.Collect(result.Head(nil), 0)
+	if got := h.Title(); got != "User 42" {
+		t.Errorf("Title() = %q, want page meta to win", got)
+	}
+	if got := h.Get("name:description").Attrs["content"]; got != "Profile of user 42" {
+		t.Errorf("description = %q", got)
+	}
+	if !h.Has("name:robots") {
+		t.Error("layout meta should provide robots default")
+	}
+}
+
 func TestRouterAddAPI(t *testing.T) {
 	r := NewRouter()
 
@@ -316,3 +352,244 @@ func TestRouterRootPath(t *testing.T) {
 		t.Error("expected PageHandler")
 	}
 }
+
+func TestRouterPages(t *testing.T) {
+	r := NewRouter()
+	page := func(ctx server.Ctx, params any) vdom.Component { return nil }
+	slugs := func() []map[string]string { return nil }
+
+	r.AddPage("/", page)
+	r.AddPage("/about", page)
+	r.AddPage("/blog/:slug", page)
+	r.AddPage("/docs/*path", page)
+	r.AddAPI("/api/users", "GET", func(ctx server.Ctx, params any, body any) (any, error) {
+		return nil, nil
+	})
+	r.AddStaticParams("/blog/:slug", slugs)
+	r.SetStatic("/about")
+
+	pages := r.Pages()
+	var paths []string
+	for _, p := range pages {
+		paths = append(paths, p.Path)
+	}
+	want := []string{"/", "/about", "/blog/:slug", "/docs/*path"}
+	if strings.Join(paths, " ") != strings.Join(want, " ") {
+		t.Fatalf("Pages() = %v, want %v", paths, want)
+	}
+
+	if !pages[1].Static || pages[0].Static {
+		t.Error("only /about should be static")
+	}
+	if pages[2].StaticParams == nil || len(pages[2].Params) != 1 || pages[2].Params[0] != "slug" {
+		t.Errorf("/blog/:slug = %+v, want StaticParams and [slug]", pages[2])
+	}
+	if len(pages[3].Params) != 1 || pages[3].Params[0] != "path" {
+		t.Errorf("/docs/*path params = %v, want [path]", pages[3].Params)
+	}
+
+	result, ok := r.Match("GET", "/about")
+	if !ok || !result.Static {
+		t.Error("match of /about should be static")
+	}
+}
+
+func TestBuildPath(t *testing.T) {
+	tests := []struct {
+		pattern string
+		params  map[string]string
+		want    string
+		wantErr bool
+	}{
+		{"/", nil, "/", false},
+		{"/about", nil, "/about", false},
+		{"/blog/:slug", map[string]string{"slug": "hello world"}, "/blog/hello%20world", false},
+		{"/users/:id:int/posts", map[string]string{"id": "42"}, "/users/42/posts", false},
+		{"/docs/*path", map[string]string{"path": "guides/intro"}, "/docs/guides/intro", false},
+		{"/:lang?/docs", map[string]string{"lang": "en"}, "/en/docs", false},
+		{"/:lang?/docs", nil, "/docs", false},
+		{"/(shop)/cart", nil, "/cart", false},
+		{"/blog/:slug", map[string]string{}, "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := BuildPath(tt.pattern, tt.params)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("BuildPath(%q) error = %v, wantErr %v", tt.pattern, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("BuildPath(%q) = %q, want %q", tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestRouterRouteGroups(t *testing.T) {
+	r := NewRouter()
+
+	var calls []string
+	layout := func(name string) LayoutHandler {
+		return func(ctx server.Ctx, children Slot) *vdom.VNode {
+			calls = append(calls, name)
+			return children
+		}
+	}
+	authMw := MiddlewareFunc(func(ctx server.Ctx, next func() error) error { return next() })
+
+	r.AddLayout("/(marketing)", layout("marketing"))
+	r.AddLayout("/(app)", layout("app"))
+	r.AddMiddleware("/(app)", authMw)
+	r.AddPage("/(marketing)/pricing", dummyPageHandler)
+	r.AddPage("/(app)/dashboard", dummyPageHandler)
+
+	pricing, ok := r.Match("GET", "/pricing")
+	if !ok {
+		t.Fatal("expected match for /pricing")
+	}
+	for _, l := range pricing.Layouts {
+		l(nil, nil)
+	}
+	if len(calls) != 1 || calls[0] != "marketing" {
+		t.Errorf("/pricing layouts = %v, want [marketing]", calls)
+	}
+	if len(pricing.Middleware) != 0 {
+		t.Errorf("/pricing should have no middleware, got %d", len(pricing.Middleware))
+	}
+
+	dashboard, ok := r.Match("GET", "/dashboard")
+	if !ok {
+		t.Fatal("expected match for /dashboard")
+	}
+	if len(dashboard.Layouts) != 1 || len(dashboard.Middleware) != 1 {
+		t.Errorf("/dashboard layouts = %d, middleware = %d, want 1, 1",
+			len(dashboard.Layouts), len(dashboard.Middleware))
+	}
+
+	if _, ok := r.Match("GET", "/marketing/pricing"); ok {
+		t.Error("groups should not be part of the URL")
+	}
+
+	pages := r.Pages()
+	for _, p := range pages {
+		if strings.Contains(p.Path, "(") {
+			t.Errorf("page path %q should not contain the group", p.Path)
+		}
+	}
+}
+
+func TestRouterOptionalSegments(t *testing.T) {
+	r := NewRouter()
+	r.AddPage("/:lang?/docs", dummyPageHandler)
+	r.AddPage("/about", dummyPageHandler)
+
+	tests := []struct {
+		path string
+		want bool
+		lang string
+	}{
+		{"/docs", true, ""},
+		{"/en/docs", true, "en"},
+		{"/about", true, ""},
+		{"/en/about", false, ""},
+		{"/en/fr/docs", false, ""},
+	}
+	for _, tt := range tests {
+		result, ok := r.Match("GET", tt.path)
+		if ok != tt.want {
+			t.Errorf("Match(%q) ok = %v, want %v", tt.path, ok, tt.want)
+			continue
+		}
+		if ok && result.Params["lang"] != tt.lang {
+			t.Errorf("Match(%q) lang = %q, want %q", tt.path, result.Params["lang"], tt.lang)
+		}
+	}
+
+	// Optional segment at the end
+	r.AddPage("/blog/:page?", dummyPageHandler)
+	if _, ok := r.Match("GET", "/blog"); !ok {
+		t.Error("expected match for /blog")
+	}
+	if result, ok := r.Match("GET", "/blog/2"); !ok || result.Params["page"] != "2" {
+		t.Error("expected match for /blog/2")
+	}
+}
+
+func TestRouterParamConstraints(t *testing.T) {
+	RegisterConstraint("locale", `[a-z]{2}(-[A-Z]{2})?`)
+
+	r := NewRouter()
+	var matched string
+	page := func(name string) PageHandler {
+		return func(ctx server.Ctx, params any) vdom.Component {
+			matched = name
+			return nil
+		}
+	}
+	r.AddPage("/users/:id:int", page("id"))
+	r.AddPage("/users/:name", page("name"))
+	r.AddPage("/users/new", page("new"))
+	r.AddPage("/archive/:year:[0-9]{4}", page("year"))
+	r.AddPage("/:lang:locale?/guide", page("guide"))
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/users/42", "id"},
+		{"/users/ada", "name"},
+		{"/users/new", "new"},
+		{"/archive/2024", "year"},
+		{"/archive/24", ""},
+		{"/guide", "guide"},
+		{"/en-GB/guide", "guide"},
+		{"/english/guide", ""},
+	}
+	for _, tt := range tests {
+		matched = ""
+		result, ok := r.Match("GET", tt.path)
+		if ok {
+			result.PageHandler(nil, nil)
+		}
+		if matched != tt.want {
+			t.Errorf("Match(%q) handled by %q, want %q", tt.path, matched, tt.want)
+		}
+	}
+}
+
+func TestRouterMatchPriority(t *testing.T) {
+	// The same routes registered in any order resolve the same way
+	register := [][]string{
+		{"/docs/*path", "/docs/:page", "/docs/:id:int", "/docs/intro"},
+		{"/docs/intro", "/docs/:id:int", "/docs/:page", "/docs/*path"},
+	}
+	want := map[string]string{
+		"/docs/intro":   "/docs/intro",
+		"/docs/7":       "/docs/:id:int",
+		"/docs/setup":   "/docs/:page",
+		"/docs/a/b/c":   "/docs/*path",
+		"/docs/intro/x": "/docs/*path",
+	}
+
+	for _, order := range register {
+		r := NewRouter()
+		var matched string
+		for _, pattern := range order {
+			pattern := pattern
+			r.AddPage(pattern, func(ctx server.Ctx, params any) vdom.Component {
+				matched = pattern
+				return nil
+			})
+		}
+		for path, pattern := range want {
+			result, ok := r.Match("GET", path)
+			if !ok {
+				t.Errorf("%v: no match for %q", order, path)
+				continue
+			}
+			result.PageHandler(nil, nil)
+			if matched != pattern {
+				t.Errorf("%v: Match(%q) = %q, want %q", order, path, matched, pattern)
+			}
+		}
+	}
+}
@@ -84,8 +84,18 @@ func (s *Scanner) scanFile(path string) (*ScannedRoute, error) {
 		return nil, err
 	}
 
+	if err := checkParamSegments(relPath); err != nil {
+		return nil, err
+	}
+
 	route.Path = s.filePathToURLPath(relPath)
+	if pattern := s.filePathToPattern(relPath); pattern != route.Path {
+		route.Pattern = pattern
+	}
 	route.Params = s.extractParams(relPath)
+	if dir := filepath.ToSlash(filepath.Dir(relPath)); dir != "." {
+		route.Dir = dir
+	}
 	route.IsCatchAll = strings.Contains(relPath, "[...")
 
 	// Check for special files
@@ -100,32 +110,116 @@ func (s *Scanner) scanFile(path string) (*ScannedRoute, error) {
 	// Check for API route
 	route.IsAPI = s.isAPIRoute(relPath)
 
+	// Check for the static directive
+	route.IsStatic = hasStaticDirective(f)
+
 	// Scan for exported functions
 	for _, decl := range f.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.TYPE {
+			route.HasQuery = route.HasQuery || declaresQuery(gen)
+			continue
+		}
+
 		fn, ok := decl.(*ast.FuncDecl)
 		if !ok || fn.Name == nil || !fn.Name.IsExported() {
 			continue
 		}
 
-		switch fn.Name.Name {
+		name := fn.Name.Name
+		switch name {
 		case "Page":
 			route.HasPage = true
+			route.PageFunc = name
 		case "Layout":
 			route.HasLayout = true
 		case "Meta":
 			route.HasMeta = true
 		case "Middleware":
 			route.HasMiddleware = true
+		case "StaticParams":
+			route.HasStaticParams = true
+		case "Load":
+			route.HasLoad = true
+		case "ErrorPage":
+			route.HasErrorPage = true
 		case "GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS":
-			route.Methods = append(route.Methods, fn.Name.Name)
+			route.addMethod(name, name)
+		case pageFuncName(path):
+			route.HasPage = true
+			route.PageFunc = name
+		default:
+			// Handlers named after the file, e.g. HealthGET in health.go
+			for _, method := range apiMethods {
+				if name == apiFuncName(path, method) {
+					route.addMethod(method, name)
+				}
+			}
 		}
 	}
 
 	return route, nil
 }
 
+// apiMethods are the HTTP methods API handlers can be declared for.
+var apiMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
+
+// addMethod records fn as the handler of an API method.
+func (r *ScannedRoute) addMethod(method, fn string) {
+	if r.APIFuncs == nil {
+		r.APIFuncs = make(map[string]string)
+	}
+	if _, ok := r.APIFuncs[method]; !ok {
+		r.Methods = append(r.Methods, method)
+	}
+	r.APIFuncs[method] = fn
+}
+
+// declaresQuery reports whether gen declares the Query struct of a route.
+func declaresQuery(gen *ast.GenDecl) bool {
+	for _, spec := range gen.Specs {
+		ts, ok := spec.(*ast.TypeSpec)
+		if !ok || ts.Name.Name != "Query" {
+			continue
+		}
+		if _, ok := ts.Type.(*ast.StructType); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// StaticDirective marks a route file as static. Static pages have no
+// interactivity and are exported without the client bootstrap. A directive
+// is used rather than a declaration so several route files of a package
+// can be marked.
+const StaticDirective = "//vango:static"
+
+// hasStaticDirective reports whether f contains the static directive.
+func hasStaticDirective(f *ast.File) bool {
+	for _, group := range f.Comments {
+		for _, c := range group.List {
+			if strings.TrimSpace(c.Text) == StaticDirective {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // filePathToURLPath converts a file path to a URL path.
 func (s *Scanner) filePathToURLPath(relPath string) string {
+	return s.convertPath(relPath, false)
+}
+
+// filePathToPattern converts a file path to the pattern the route is
+// registered at, which keeps route groups and parameter constraints.
+func (s *Scanner) filePathToPattern(relPath string) string {
+	return s.convertPath(relPath, true)
+}
+
+// convertPath converts a file path to a route path. Route groups and
+// parameter constraints are kept if pattern is set.
+func (s *Scanner) convertPath(relPath string, pattern bool) string {
 	// Remove .go extension
 	path := strings.TrimSuffix(relPath, ".go")
 
@@ -152,8 +246,19 @@ func (s *Scanner) filePathToURLPath(relPath string) string {
 		}
 	}
 
+	// Route groups ((name) directories) are not part of the URL
+	if !pattern {
+		var segments []string
+		for _, seg := range splitPath(path) {
+			if !isGroupSegment(seg) {
+				segments = append(segments, seg)
+			}
+		}
+		path = strings.Join(segments, "/")
+	}
+
 	// Convert [param] to :param
-	path = s.convertParams(path)
+	path = s.convertSegments(path, pattern)
 
 	// Add leading slash
 	if path == "" {
@@ -162,31 +267,53 @@ func (s *Scanner) filePathToURLPath(relPath string) string {
 	return "/" + path
 }
 
+// paramSegmentPattern matches [param], [param:type], [...param] and
+// [[param]]. Constraints in file names are names only: patterns are
+// registered with RegisterConstraint.
+var paramSegmentPattern = regexp.MustCompile(`(\[)?\[([.\w]+)(?::(\w+))?\](\])?`)
+
+// checkParamSegments returns an error if a segment of relPath has a
+// bracket that is not a parameter, e.g. an inline pattern constraint.
+func checkParamSegments(relPath string) error {
+	for _, seg := range splitPath(filepath.ToSlash(strings.TrimSuffix(relPath, ".go"))) {
+		if !strings.ContainsAny(paramSegmentPattern.ReplaceAllString(seg, ""), "[]") {
+			continue
+		}
+		return fmt.Errorf("invalid parameter segment %q: constraints in file names must be names, register patterns with router.RegisterConstraint", seg)
+	}
+	return nil
+}
+
 // convertParams converts bracket notation to router notation.
 func (s *Scanner) convertParams(path string) string {
+	return s.convertSegments(path, false)
+}
+
+// convertSegments converts bracket notation to router notation, keeping
+// constraints if constrained is set.
+func (s *Scanner) convertSegments(path string, constrained bool) string {
 	// [id] → :id
-	// [id:int] → :id (type stored separately)
+	// [id:int] → :id (type stored separately), or :id:int if constrained
+	// [[lang]] → :lang?
 	// [...slug] → *slug
-
-	// Match [param] or [param:type] or [...param]
-	re := regexp.MustCompile(`\[([.\w]+)(?::(\w+))?\]`)
-	result := re.ReplaceAllStringFunc(path, func(match string) string {
-		inner := match[1 : len(match)-1] // Remove brackets
+	return paramSegmentPattern.ReplaceAllStringFunc(path, func(match string) string {
+		m := paramSegmentPattern.FindStringSubmatch(match)
+		name, constraint := m[2], m[3]
 
 		// Handle catch-all
-		if strings.HasPrefix(inner, "...") {
-			return "*" + inner[3:]
+		if strings.HasPrefix(name, "...") {
+			return "*" + name[3:]
 		}
 
-		// Handle typed params - just use the name
-		if idx := strings.Index(inner, ":"); idx != -1 {
-			return ":" + inner[:idx]
+		seg := ":" + name
+		if constrained && constraint != "" {
+			seg += ":" + constraint
 		}
-
-		return ":" + inner
+		if m[1] != "" && m[4] != "" {
+			seg += "?"
+		}
+		return seg
 	})
-
-	return result
 }
 
 // extractParams extracts parameter definitions from a file path.
@@ -195,28 +322,30 @@ func (s *Scanner) convertParams(path string) string {
 //   - [uuid] → string (UUID stored as string)
 //   - [slug], [name], [title] → string
 //   - [...path], [...rest] → []string (catch-all)
-//   - [param:int], [id:int64] → explicit type annotation
+//   - [param:int], [id:int64] → explicit type annotation, also a constraint
+//   - [[lang]] → optional segment
 func (s *Scanner) extractParams(relPath string) []ParamDef {
 	var params []ParamDef
 
-	// Match [param] or [param:type] or [...param]
-	re := regexp.MustCompile(`\[([.\w]+)(?::(\w+))?\]`)
-	matches := re.FindAllStringSubmatch(relPath, -1)
+	// Match [param] or [param:type] or [...param] or [[param]]
+	matches := paramSegmentPattern.FindAllStringSubmatch(relPath, -1)
 
 	for _, match := range matches {
 		param := ParamDef{
 			Segment: match[0],
 		}
 
-		name := match[1]
+		name := match[2]
 		if strings.HasPrefix(name, "...") {
 			param.Name = name[3:]
 			param.Type = "[]string" // Catch-all is always string slice
 		} else {
 			param.Name = name
-			if match[2] != "" {
+			param.Optional = match[1] != "" && match[4] != ""
+			if match[3] != "" {
 				// Explicit type annotation [param:type]
-				param.Type = match[2]
+				param.Type = match[3]
+				param.Constraint = match[3]
 			} else {
 				// Infer type from naming conventions
 				param.Type = inferParamTypeFromName(name)
@@ -132,6 +132,26 @@ func TestScannerIsAPIRoute(t *testing.T) {
 	}
 }
 
+func TestCheckParamSegments(t *testing.T) {
+	tests := []struct {
+		path    string
+		wantErr bool
+	}{
+		{"users/[id:int].go", false},
+		{"[[lang:alpha]]/about.go", false},
+		{"docs/[...slug].go", false},
+		{"archive/[year:[0-9]{4}].go", true},
+		{"[v:\\d+]/index.go", true},
+	}
+
+	for _, tt := range tests {
+		err := checkParamSegments(tt.path)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("checkParamSegments(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+		}
+	}
+}
+
 func TestScannerScan(t *testing.T) {
 	// Create temp directory with route files
 	dir := t.TempDir()
@@ -217,6 +237,120 @@ func TestScannerScan(t *testing.T) {
 	}
 }
 
+func TestScannerStaticExport(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"about.go":       "//vango:static\n\npackage routes\n\nfunc Page() {}\n",
+		"index.go":       `package routes; func Page() {}`,
+		"blog/[slug].go": `package blog; func Page() {}; func StaticParams() {}`,
+	}
+
+	for path, content := range files {
+		fullPath := filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatalf("mkdir %s: %v", filepath.Dir(fullPath), err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+			t.Fatalf("write %s: %v", fullPath, err)
+		}
+	}
+
+	routes, err := NewScanner(dir).Scan()
+	if err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+
+	routeMap := make(map[string]*ScannedRoute)
+	for i := range routes {
+		routeMap[routes[i].Path] = &routes[i]
+	}
+
+	if r := routeMap["/about"]; r == nil || !r.IsStatic {
+		t.Error("/about should be static")
+	}
+	if r := routeMap["/"]; r == nil || r.IsStatic {
+		t.Error("/ should not be static")
+	}
+	if r := routeMap["/blog/:slug"]; r == nil || !r.HasStaticParams {
+		t.Error("/blog/:slug should have StaticParams")
+	}
+}
+
+func TestScannerLoaders(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"_layout.go":           `package routes; func Layout() {}; func Load() {}`,
+		"_error.go":            `package routes; func ErrorPage() {}`,
+		"projects/[id].go":     `package projects; func Page() {}; func Load() {}`,
+		"projects/settings.go": `package projects; func Page() {}`,
+	}
+
+	for path, content := range files {
+		fullPath := filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatalf("mkdir %s: %v", filepath.Dir(fullPath), err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+			t.Fatalf("write %s: %v", fullPath, err)
+		}
+	}
+
+	routes, err := NewScanner(dir).Scan()
+	if err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+
+	var layoutLoad, errorPage bool
+	for _, r := range routes {
+		switch filepath.Base(r.FilePath) {
+		case "_layout.go":
+			layoutLoad = r.HasLoad
+		case "_error.go":
+			errorPage = r.HasErrorPage && r.Path == "/"
+		case "[id].go":
+			if !r.HasLoad {
+				t.Error("/projects/:id should have Load")
+			}
+		case "settings.go":
+			if r.HasLoad {
+				t.Error("/projects/settings should not have Load")
+			}
+		}
+	}
+	if !layoutLoad {
+		t.Error("root layout should have Load")
+	}
+	if !errorPage {
+		t.Error("_error.go should register ErrorPage on /")
+	}
+}
+
+func TestScannerQuery(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"search.go": "package routes\n\ntype Query struct {\n\tQ string `query:\"q\"`\n}\n\nfunc Page() {}\n",
+		"about.go":  "package about\n\ntype Query = string\n\nfunc Page() {}\n",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, path), []byte(content), 0644); err != nil {
+			t.Fatalf("write %s: %v", path, err)
+		}
+	}
+
+	routes, err := NewScanner(dir).Scan()
+	if err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	for _, r := range routes {
+		if want := r.Path == "/search"; r.HasQuery != want {
+			t.Errorf("%s: HasQuery = %v, want %v", r.Path, r.HasQuery, want)
+		}
+	}
+}
+
 func TestScannerSkipsTestFiles(t *testing.T) {
 	dir := t.TempDir()
 
@@ -242,3 +376,60 @@ func TestScannerSkipsTestFiles(t *testing.T) {
 		t.Errorf("len(routes) = %d, want 1 (should skip test files)", len(routes))
 	}
 }
+
+func TestScannerGroupsAndOptionalSegments(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"(marketing)/_layout.go":       `package marketing; func Layout() {}`,
+		"(marketing)/pricing.go":       `package marketing; func Page() {}`,
+		"(app)/users/[id:int].go":      `package users; func Page() {}`,
+		"[[lang]]/docs/[page:slug].go": `package docs; func Page() {}`,
+	}
+	for path, content := range files {
+		fullPath := filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+			t.Fatalf("write %s: %v", fullPath, err)
+		}
+	}
+
+	routes, err := NewScanner(dir).Scan()
+	if err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+
+	want := map[string]struct{ path, pattern string }{
+		"(marketing)/_layout.go":       {"/", "/(marketing)"},
+		"(marketing)/pricing.go":       {"/pricing", "/(marketing)/pricing"},
+		"(app)/users/[id:int].go":      {"/users/:id", "/(app)/users/:id:int"},
+		"[[lang]]/docs/[page:slug].go": {"/:lang?/docs/:page", "/:lang?/docs/:page:slug"},
+	}
+	for _, r := range routes {
+		rel, _ := filepath.Rel(dir, r.FilePath)
+		w, ok := want[filepath.ToSlash(rel)]
+		if !ok {
+			t.Errorf("unexpected route %s", rel)
+			continue
+		}
+		if r.Path != w.path || r.RouterPath() != w.pattern {
+			t.Errorf("%s: Path = %q, RouterPath() = %q, want %q, %q", rel, r.Path, r.RouterPath(), w.path, w.pattern)
+		}
+	}
+
+	params := NewScanner(dir).extractParams("[[lang]]/docs/[page:slug]/[id].go")
+	if len(params) != 3 {
+		t.Fatalf("len(params) = %d, want 3", len(params))
+	}
+	if !params[0].Optional || params[0].Name != "lang" || params[0].Constraint != "" {
+		t.Errorf("params[0] = %+v, want optional lang", params[0])
+	}
+	if params[1].Optional || params[1].Constraint != "slug" {
+		t.Errorf("params[1] = %+v, want constraint slug", params[1])
+	}
+	if params[2].Constraint != "" || params[2].Type != "int" {
+		t.Errorf("params[2] = %+v, inferred types should not constrain", params[2])
+	}
+}
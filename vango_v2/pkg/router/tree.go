@@ -13,23 +13,44 @@ type RouteNode struct {
 	// isCatchAll indicates this is a catch-all segment (*slug)
 	isCatchAll bool
 
+	// isGroup indicates this is a route group segment ((name)), which
+	// matches no part of the URL
+	isGroup bool
+
+	// optional indicates the parameter segment may be absent (:lang?)
+	optional bool
+
 	// paramName is the parameter name (without : or *)
 	paramName string
 
-	// paramType is the expected parameter type (int, string, uuid)
+	// paramType is the expected parameter type or constraint (int, uuid,
+	// a registered constraint or a regular expression)
 	paramType string
 
+	// parent is the node this node was added to (nil for the root)
+	parent *RouteNode
+
 	// handlers
 	pageHandler   PageHandler
 	layoutHandler LayoutHandler
+	layoutComp    LayoutComponent
+	metaHandler   MetaHandler
+	staticParams  StaticParamsFunc
+	static        bool
+	loader        LoadFunc
+	errorHandler  ErrorHandler
 	apiHandlers   map[string]APIHandler // method -> handler
 	middleware    []Middleware
 
 	// children are static segment children
 	children []*RouteNode
 
-	// paramChild is the dynamic parameter child (:id)
-	paramChild *RouteNode
+	// groups are route group children ((name))
+	groups []*RouteNode
+
+	// paramChildren are the dynamic parameter children (:id), in match
+	// order: constrained, unconstrained, then optional
+	paramChildren []*RouteNode
 
 	// catchAllChild is the catch-all child (*slug)
 	catchAllChild *RouteNode
@@ -61,29 +82,70 @@ func (n *RouteNode) addChild(segment string) *RouteNode {
 
 	// Create new child
 	child := newRouteNode(segment)
+	child.parent = n
 	n.children = append(n.children, child)
 	return child
 }
 
-// addParamChild sets the parameter child node.
-func (n *RouteNode) addParamChild(name, paramType string) *RouteNode {
-	if n.paramChild != nil {
-		return n.paramChild
+// addGroup adds or retrieves a route group child.
+func (n *RouteNode) addGroup(segment string) *RouteNode {
+	for _, child := range n.groups {
+		if child.segment == segment {
+			return child
+		}
+	}
+	child := newRouteNode(segment)
+	child.parent = n
+	child.isGroup = true
+	n.groups = append(n.groups, child)
+	return child
+}
+
+// addParamChild adds or retrieves a parameter child node.
+func (n *RouteNode) addParamChild(name, paramType string, optional bool) *RouteNode {
+	for _, child := range n.paramChildren {
+		if child.paramName == name && child.paramType == paramType && child.optional == optional {
+			return child
+		}
 	}
 	child := newRouteNode("")
+	child.parent = n
 	child.isParam = true
 	child.paramName = name
 	child.paramType = paramType
-	n.paramChild = child
+	child.optional = optional
+
+	// Keep the children in match order; equal ranks keep insertion order
+	i := len(n.paramChildren)
+	for i > 0 && n.paramChildren[i-1].paramRank() > child.paramRank() {
+		i--
+	}
+	n.paramChildren = append(n.paramChildren, nil)
+	copy(n.paramChildren[i+1:], n.paramChildren[i:])
+	n.paramChildren[i] = child
 	return child
 }
 
+// paramRank orders parameter children: constrained parameters are tried
+// before unconstrained ones, and optional parameters last.
+func (n *RouteNode) paramRank() int {
+	rank := 0
+	if !isConstrained(n.paramType) {
+		rank++
+	}
+	if n.optional {
+		rank += 2
+	}
+	return rank
+}
+
 // addCatchAllChild sets the catch-all child node.
 func (n *RouteNode) addCatchAllChild(name string) *RouteNode {
 	if n.catchAllChild != nil {
 		return n.catchAllChild
 	}
 	child := newRouteNode("")
+	child.parent = n
 	child.isCatchAll = true
 	child.paramName = name
 	child.paramType = "[]string"
@@ -105,7 +167,10 @@ func (n *RouteNode) insertRoute(path string) *RouteNode {
 		} else if strings.HasPrefix(seg, ":") {
 			// Parameter segment
 			name, paramType := parseParamSegment(seg)
-			current = current.addParamChild(name, paramType)
+			current = current.addParamChild(name, paramType, isOptionalSegment(seg))
+		} else if isGroupSegment(seg) {
+			// Route group segment
+			current = current.addGroup(seg)
 		} else {
 			// Static segment
 			current = current.addChild(seg)
@@ -117,6 +182,11 @@ func (n *RouteNode) insertRoute(path string) *RouteNode {
 
 // match finds a node matching the given path segments.
 // Returns the node, collected layouts, and extracted parameters.
+//
+// Candidates are tried in a fixed order, so overlapping routes resolve
+// the same way whatever order they were registered in: static segments,
+// route groups, constrained parameters, unconstrained parameters, optional
+// parameters (absent before present), then catch-alls.
 func (n *RouteNode) match(segments []string, params map[string]string, layouts []LayoutHandler) (*RouteNode, []LayoutHandler, bool) {
 	// Collect layout at this node
 	if n.layoutHandler != nil {
@@ -138,6 +208,20 @@ func (n *RouteNode) match(segments []string, params map[string]string, layouts [
 				return child, layouts, true
 			}
 		}
+		// Groups and absent optional parameters match no segment
+		for _, child := range n.groups {
+			if node, lays, ok := child.match(nil, params, layouts); ok {
+				return node, lays, true
+			}
+		}
+		for _, child := range n.paramChildren {
+			if !child.optional {
+				continue
+			}
+			if node, lays, ok := child.match(nil, params, layouts); ok {
+				return node, lays, true
+			}
+		}
 		return nil, nil, false
 	}
 
@@ -151,14 +235,30 @@ func (n *RouteNode) match(segments []string, params map[string]string, layouts [
 		}
 	}
 
-	// Try parameter match
-	if n.paramChild != nil {
-		params[n.paramChild.paramName] = segment
-		if node, lays, ok := n.paramChild.match(remaining, params, layouts); ok {
+	// Try the routes of groups
+	for _, child := range n.groups {
+		if node, lays, ok := child.match(segments, params, layouts); ok {
+			return node, lays, true
+		}
+	}
+
+	// Try parameter matches
+	for _, child := range n.paramChildren {
+		if child.optional {
+			// Absent: the segment belongs to a route below
+			if node, lays, ok := child.match(segments, params, layouts); ok {
+				return node, lays, true
+			}
+		}
+		if !matchesConstraint(child.paramType, segment) {
+			continue
+		}
+		params[child.paramName] = segment
+		if node, lays, ok := child.match(remaining, params, layouts); ok {
 			return node, lays, true
 		}
 		// Backtrack on failure
-		delete(params, n.paramChild.paramName)
+		delete(params, child.paramName)
 	}
 
 	// Try catch-all match
@@ -175,6 +275,112 @@ func (n *RouteNode) match(segments []string, params map[string]string, layouts [
 	return nil, nil, false
 }
 
+// metaChain returns the meta handlers from the root down to n.
+func (n *RouteNode) metaChain() []MetaHandler {
+	var chain []MetaHandler
+	for node := n; node != nil; node = node.parent {
+		if node.metaHandler != nil {
+			chain = append(chain, node.metaHandler)
+		}
+	}
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+	return chain
+}
+
+// middlewareChain returns the middleware from the root down to n.
+func (n *RouteNode) middlewareChain() []Middleware {
+	var chain [][]Middleware
+	for node := n; node != nil; node = node.parent {
+		if len(node.middleware) > 0 {
+			chain = append(chain, node.middleware)
+		}
+	}
+	var mw []Middleware
+	for i := len(chain) - 1; i >= 0; i-- {
+		mw = append(mw, chain[i]...)
+	}
+	return mw
+}
+
+// loaderChain returns the loaders from the root down to n.
+func (n *RouteNode) loaderChain() []RouteLoader {
+	var chain []RouteLoader
+	for node := n; node != nil; node = node.parent {
+		if node.loader != nil {
+			chain = append(chain, RouteLoader{Route: node.pattern(), Load: node.loader})
+		}
+	}
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+	return chain
+}
+
+// nearestErrorHandler returns the error handler of n or its closest ancestor.
+func (n *RouteNode) nearestErrorHandler() ErrorHandler {
+	for node := n; node != nil; node = node.parent {
+		if node.errorHandler != nil {
+			return node.errorHandler
+		}
+	}
+	return nil
+}
+
+// pattern returns the route path of n, e.g. "/blog/:slug".
+func (n *RouteNode) pattern() string {
+	var segments []string
+	for node := n; node.parent != nil; node = node.parent {
+		switch {
+		case node.isCatchAll:
+			segments = append(segments, "*"+node.paramName)
+		case node.isParam && node.optional:
+			segments = append(segments, ":"+node.paramName+"?")
+		case node.isParam:
+			segments = append(segments, ":"+node.paramName)
+		case node.isGroup:
+			// Groups are not part of the URL
+		case node.segment != "":
+			segments = append(segments, node.segment)
+		}
+	}
+	for i, j := 0, len(segments)-1; i < j; i, j = i+1, j-1 {
+		segments[i], segments[j] = segments[j], segments[i]
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+// collectPages appends the page routes at and below n.
+func (n *RouteNode) collectPages(pages []PageRoute) []PageRoute {
+	if n.pageHandler != nil {
+		page := PageRoute{
+			Path:         n.pattern(),
+			StaticParams: n.staticParams,
+			Static:       n.static,
+		}
+		for node := n; node != nil; node = node.parent {
+			if node.isParam || node.isCatchAll {
+				page.Params = append([]string{node.paramName}, page.Params...)
+			}
+		}
+		pages = append(pages, page)
+	}
+	for _, child := range n.children {
+		pages = child.collectPages(pages)
+	}
+	for _, child := range n.groups {
+		pages = child.collectPages(pages)
+	}
+	for _, child := range n.paramChildren {
+		pages = child.collectPages(pages)
+	}
+	if n.catchAllChild != nil {
+		pages = n.catchAllChild.collectPages(pages)
+	}
+	return pages
+}
+
 // splitPath splits a path into segments.
 func splitPath(path string) []string {
 	path = strings.Trim(path, "/")
@@ -186,10 +392,40 @@ func splitPath(path string) []string {
 
 // parseParamSegment extracts name and type from a parameter segment.
 // Input: ":id" or ":id:int" -> name="id", type="string" or "int"
+// A trailing "?" marks an optional segment and is not part of the type,
+// unless the type is an inline pattern: in ":v:[0-9]+?" it is the
+// pattern's.
 func parseParamSegment(seg string) (name, paramType string) {
-	seg = seg[1:] // Remove leading :
-	if idx := strings.Index(seg, ":"); idx != -1 {
-		return seg[:idx], seg[idx+1:]
+	name, paramType, _ = splitParamSegment(seg)
+	return name, paramType
+}
+
+// isOptionalSegment reports whether a parameter segment is optional
+// (:lang? or :lang:alpha?).
+func isOptionalSegment(seg string) bool {
+	if !strings.HasPrefix(seg, ":") {
+		return false
 	}
-	return seg, "string"
+	_, _, optional := splitParamSegment(seg)
+	return optional
+}
+
+// splitParamSegment splits a parameter segment into its name, its type
+// and whether it is optional. Only a segment without a type or with a
+// named one can be optional.
+func splitParamSegment(seg string) (name, paramType string, optional bool) {
+	name, paramType, constrained := strings.Cut(seg[1:], ":") // Remove leading :
+	if !constrained {
+		name, optional = strings.CutSuffix(name, "?")
+		return name, "string", optional
+	}
+	if named, ok := strings.CutSuffix(paramType, "?"); ok && identifierPattern.MatchString(named) {
+		return name, named, true
+	}
+	return name, paramType, false
+}
+
+// isGroupSegment reports whether seg is a route group segment ((name)).
+func isGroupSegment(seg string) bool {
+	return len(seg) > 2 && strings.HasPrefix(seg, "(") && strings.HasSuffix(seg, ")")
 }
@@ -222,6 +222,10 @@ func TestParseParamSegment(t *testing.T) {
 		{":id:int", "id", "int"},
 		{":userId:uuid", "userId", "uuid"},
 		{":name", "name", "string"},
+		{":lang?", "lang", "string"},
+		{":lang:alpha?", "lang", "alpha"},
+		{":v:[0-9]+?", "v", "[0-9]+?"},
+		{":code:[a-z]{2}(-[A-Z]{2})?", "code", "[a-z]{2}(-[A-Z]{2})?"},
 	}
 
 	for _, tt := range tests {
@@ -234,3 +238,23 @@ func TestParseParamSegment(t *testing.T) {
 		}
 	}
 }
+
+func TestIsOptionalSegment(t *testing.T) {
+	tests := []struct {
+		seg  string
+		want bool
+	}{
+		{":lang?", true},
+		{":lang:alpha?", true},
+		{":lang", false},
+		{":lang:alpha", false},
+		{":v:[0-9]+?", false},
+		{"users", false},
+	}
+
+	for _, tt := range tests {
+		if got := isOptionalSegment(tt.seg); got != tt.want {
+			t.Errorf("isOptionalSegment(%q) = %v, want %v", tt.seg, got, tt.want)
+		}
+	}
+}
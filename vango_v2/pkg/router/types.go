@@ -1,6 +1,9 @@
 package router
 
 import (
+	"strings"
+
+	"github.com/vango-dev/vango/v2/pkg/head"
 	"github.com/vango-dev/vango/v2/pkg/server"
 	"github.com/vango-dev/vango/v2/pkg/vdom"
 )
@@ -15,10 +18,25 @@ type PageHandler func(ctx server.Ctx, params any) vdom.Component
 // LayoutHandler wraps child content in a layout.
 type LayoutHandler func(ctx server.Ctx, children Slot) *vdom.VNode
 
+// LayoutComponent builds the component of a persistent layout. It is called
+// once when the layout's segment mounts; the component keeps its state
+// (signals, effects) across navigations below it. The layout renders outlet
+// where the routed content goes, and only the outlet re-renders when the
+// route changes below the layout.
+type LayoutComponent func(ctx server.Ctx, outlet *Outlet) vdom.Component
+
 // APIHandler handles an API request, returning data or an error.
 // The params parameter is a typed params struct, body is the decoded request body.
 type APIHandler func(ctx server.Ctx, params any, body any) (any, error)
 
+// MetaHandler returns the metadata of a page or of the pages under a layout.
+type MetaHandler func(ctx server.Ctx, params any) PageMeta
+
+// StaticParamsFunc lists the parameter values a dynamic route is exported
+// with by `vango export`. Each map is one page; catch-all values are
+// slash-separated.
+type StaticParamsFunc func() []map[string]string
+
 // ErrorHandler handles error pages.
 type ErrorHandler func(ctx server.Ctx, err error) *vdom.VNode
 
@@ -34,23 +52,67 @@ type PageMeta struct {
 	Robots      string
 }
 
+// Head returns the head declarations for the metadata.
+// Empty fields declare nothing, so they keep the value of an outer layout.
+func (m PageMeta) Head() []*vdom.VNode {
+	var nodes []*vdom.VNode
+	if m.Title != "" {
+		nodes = append(nodes, head.Title(m.Title))
+	}
+	if m.Description != "" {
+		nodes = append(nodes, head.Meta("description", m.Description))
+	}
+	if len(m.Keywords) > 0 {
+		nodes = append(nodes, head.Meta("keywords", strings.Join(m.Keywords, ", ")))
+	}
+	if m.Robots != "" {
+		nodes = append(nodes, head.Meta("robots", m.Robots))
+	}
+	if m.OGTitle != "" {
+		nodes = append(nodes, head.Property("og:title", m.OGTitle))
+	}
+	if m.OGDesc != "" {
+		nodes = append(nodes, head.Property("og:description", m.OGDesc))
+	}
+	if m.OGImage != "" {
+		nodes = append(nodes, head.Property("og:image", m.OGImage))
+	}
+	if m.Canonical != "" {
+		nodes = append(nodes, head.Canonical(m.Canonical))
+	}
+	return nodes
+}
+
 // ScannedRoute represents a route discovered by the scanner.
 type ScannedRoute struct {
 	// Path is the URL pattern (e.g., "/projects/:id")
 	Path string
 
+	// Pattern is the pattern the route is registered at when it differs
+	// from Path: it keeps route groups and parameter constraints
+	// (e.g., "/(shop)/products/:id:int")
+	Pattern string
+
 	// FilePath is the source file path
 	FilePath string
 
 	// Package is the Go package name
 	Package string
 
+	// Dir is the directory of the file relative to the scanned directory,
+	// with forward slashes ("" for the scanned directory itself)
+	Dir string
+
 	// Params are the route parameters
 	Params []ParamDef
 
 	// HasPage indicates the file exports a Page function
 	HasPage bool
 
+	// PageFunc is the name of the page function: Page, or the name derived
+	// from the file name (e.g., IndexPage, AboutPage)
+	PageFunc string
+
 	// HasLayout indicates the file exports a Layout function
 	HasLayout bool
 
@@ -60,9 +122,29 @@ type ScannedRoute struct {
 	// HasMiddleware indicates the file exports a Middleware function
 	HasMiddleware bool
 
+	// HasStaticParams indicates the file exports a StaticParams function
+	HasStaticParams bool
+
+	// IsStatic indicates the file contains the //vango:static directive
+	IsStatic bool
+
+	// HasLoad indicates the file exports a Load function
+	HasLoad bool
+
+	// HasErrorPage indicates the file exports an ErrorPage function
+	HasErrorPage bool
+
+	// HasQuery indicates the file declares a Query struct describing the
+	// query parameters of the page
+	HasQuery bool
+
 	// Methods lists HTTP methods for API routes (GET, POST, etc.)
 	Methods []string
 
+	// APIFuncs maps each method to the name of its handler: the method
+	// itself, or the method prefixed with the file name (e.g., HealthGET)
+	APIFuncs map[string]string
+
 	// IsAPI indicates this is an API route (returns JSON)
 	IsAPI bool
 
@@ -70,6 +152,14 @@ type ScannedRoute struct {
 	IsCatchAll bool
 }
 
+// RouterPath returns the pattern to register the route at.
+func (r ScannedRoute) RouterPath() string {
+	if r.Pattern != "" {
+		return r.Pattern
+	}
+	return r.Path
+}
+
 // ParamDef defines a route parameter.
 type ParamDef struct {
 	// Name is the parameter name (e.g., "id")
@@ -80,6 +170,13 @@ type ParamDef struct {
 
 	// Segment is the original segment (e.g., "[id]", "[id:int]")
 	Segment string
+
+	// Constraint is the explicit type or constraint of the segment
+	// (e.g., "int" for "[id:int]"); inferred types do not constrain
+	Constraint string
+
+	// Optional indicates an optional segment (e.g., "[[lang]]")
+	Optional bool
 }
 
 // MatchResult contains the result of matching a path against the router.
@@ -96,13 +193,38 @@ type MatchResult struct {
 	// Middleware is the combined middleware chain
 	Middleware []Middleware
 
+	// Meta are the metadata handlers in order (root to leaf)
+	Meta []MetaHandler
+
 	// Params are the extracted route parameters
 	Params map[string]string
 
+	// Static reports whether the page is marked static
+	Static bool
+
+	// Loaders are the data loaders in order (root to leaf)
+	Loaders []RouteLoader
+
+	// ErrorPage renders loader errors: the handler of the nearest
+	// _error.go, or the router's error page
+	ErrorPage ErrorHandler
+
 	// Route is the matched route definition
 	Route *ScannedRoute
 }
 
+// Head evaluates the Meta handlers of the match and returns their head
+// declarations. Each level is nested inside the one before it, so a page's
+// metadata overrides that of its layouts. Render the result in the page
+// body or pass it as render.PageData.Head.
+func (m *MatchResult) Head(ctx server.Ctx) *vdom.VNode {
+	var node *vdom.VNode
+	for i := len(m.Meta) - 1; i >= 0; i-- {
+		node = vdom.Fragment(m.Meta[i](ctx, m.Params).Head(), node)
+	}
+	return node
+}
+
 // Middleware processes requests before they reach the handler.
 type Middleware interface {
 	// Handle processes the request and optionally calls next.
@@ -0,0 +1,136 @@
+package router
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// =============================================================================
+// URL Builders
+// =============================================================================
+
+// The functions below are used by the URL builders of routes_gen.go:
+//
+//	func UsersIDPostsSlugURL(id int, slug string) string {
+//	    return "/users/" + router.PathSegment(id) + "/posts/" + router.PathSegment(slug)
+//	}
+//
+// The builders return plain strings, so they can be passed to Link, NavLink
+// and Navigate directly:
+//
+//	router.Link(routes.UsersIDPostsSlugURL(user.ID, post.Slug), vdom.Text(post.Title))
+
+// PathSegment formats a route parameter as an escaped path segment.
+func PathSegment(v any) string {
+	return url.PathEscape(fmt.Sprint(v))
+}
+
+// OptionalSegment formats an optional route parameter as "/" followed by
+// the escaped segment, or "" for the zero value.
+func OptionalSegment(v any) string {
+	if v == nil || reflect.ValueOf(v).IsZero() {
+		return ""
+	}
+	return "/" + PathSegment(v)
+}
+
+// RootIfEmpty returns path, or "/" if path is empty.
+func RootIfEmpty(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return path
+}
+
+// CatchAllSegments formats a catch-all parameter, escaping each segment.
+func CatchAllSegments(parts []string) string {
+	escaped := make([]string, len(parts))
+	for i, part := range parts {
+		escaped[i] = url.PathEscape(part)
+	}
+	return strings.Join(escaped, "/")
+}
+
+// WithQuery appends the fields of query to path as query parameters.
+// query is a struct, or a pointer to one, whose fields are named by their
+// `query` tag; zero values are omitted:
+//
+//	// In app/routes/search.go
+//	type Query struct {
+//	    Q    string `query:"q"`
+//	    Page int    `query:"page"`
+//	}
+//
+//	WithQuery("/search", &Query{Q: "go generics", Page: 2}) // "/search?page=2&q=go+generics"
+//
+// A nil query returns path unchanged.
+func WithQuery(path string, query any) string {
+	values := queryValues(query)
+	if len(values) == 0 {
+		return path
+	}
+	return path + "?" + values.Encode()
+}
+
+// queryValues converts a tagged struct into url.Values.
+func queryValues(query any) url.Values {
+	v := reflect.ValueOf(query)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	values := url.Values{}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := field.Tag.Get("query")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+
+		fv := v.Field(i)
+		if fv.IsZero() {
+			continue
+		}
+		if fv.Kind() == reflect.Slice {
+			for j := 0; j < fv.Len(); j++ {
+				values.Add(name, formatQueryValue(fv.Index(j)))
+			}
+			continue
+		}
+		values.Set(name, formatQueryValue(fv))
+	}
+	return values
+}
+
+// formatQueryValue formats a single query value.
+func formatQueryValue(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	}
+	return fmt.Sprint(v.Interface())
+}
@@ -0,0 +1,76 @@
+package router
+
+import (
+	"testing"
+)
+
+func TestPathSegment(t *testing.T) {
+	tests := []struct {
+		value any
+		want  string
+	}{
+		{42, "42"},
+		{"hello", "hello"},
+		{"a b/c", "a%20b%2Fc"},
+		{"ü?", "%C3%BC%3F"},
+	}
+
+	for _, tt := range tests {
+		if got := PathSegment(tt.value); got != tt.want {
+			t.Errorf("PathSegment(%v) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestCatchAllSegments(t *testing.T) {
+	got := CatchAllSegments([]string{"guide", "a b", "c?d"})
+	if want := "guide/a%20b/c%3Fd"; got != want {
+		t.Errorf("CatchAllSegments() = %q, want %q", got, want)
+	}
+}
+
+func TestWithQuery(t *testing.T) {
+	type query struct {
+		Q      string   `query:"q"`
+		Page   int      `query:"page"`
+		Draft  bool     `query:"draft"`
+		Tags   []string `query:"tag"`
+		Secret string   `query:"-"`
+		Sort   string
+	}
+
+	tests := []struct {
+		name  string
+		query any
+		want  string
+	}{
+		{"nil", (*query)(nil), "/search"},
+		{"zero values omitted", &query{}, "/search"},
+		{"fields", &query{Q: "go generics", Page: 2}, "/search?page=2&q=go+generics"},
+		{"slices and bools", query{Tags: []string{"a", "b&c"}, Draft: true}, "/search?draft=true&tag=a&tag=b%26c"},
+		{"untagged and ignored", query{Secret: "x", Sort: "new"}, "/search?sort=new"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WithQuery("/search", tt.query); got != tt.want {
+				t.Errorf("WithQuery() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOptionalSegment(t *testing.T) {
+	if got := OptionalSegment("en"); got != "/en" {
+		t.Errorf("OptionalSegment(%q) = %q, want %q", "en", got, "/en")
+	}
+	if got := OptionalSegment(""); got != "" {
+		t.Errorf("OptionalSegment(%q) = %q, want empty", "", got)
+	}
+	if got := OptionalSegment(0); got != "" {
+		t.Errorf("OptionalSegment(0) = %q, want empty", got)
+	}
+	if got := RootIfEmpty(OptionalSegment("")); got != "/" {
+		t.Errorf("RootIfEmpty() = %q, want %q", got, "/")
+	}
+}
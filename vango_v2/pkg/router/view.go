@@ -0,0 +1,341 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/vango-dev/vango/v2/pkg/server"
+	"github.com/vango-dev/vango/v2/pkg/vango"
+	"github.com/vango-dev/vango/v2/pkg/vdom"
+)
+
+// =============================================================================
+// Outlet
+// =============================================================================
+
+// Outlet is where a persistent layout renders the content below it.
+// Outlet is a component; place it in the layout's tree:
+//
+//	r.AddLayoutComponent("/app", func(ctx server.Ctx, outlet *router.Outlet) vdom.Component {
+//	    collapsed := vango.NewSignal(false)
+//	    return vdom.Func(func() *vdom.VNode {
+//	        return vdom.Div(
+//	            Sidebar(collapsed),
+//	            vdom.Main(outlet),
+//	        )
+//	    })
+//	})
+//
+// The outlet renders its content inside an element with display: contents,
+// so it does not affect the layout's styling.
+type Outlet struct {
+	content *vango.Signal[*vdom.VNode]
+
+	mu        sync.Mutex
+	listeners []func(RouteChange)
+}
+
+// newOutlet creates an outlet showing content.
+func newOutlet(content *vdom.VNode) *Outlet {
+	return &Outlet{
+		content: vango.NewSignal(content).WithEquals(func(a, b *vdom.VNode) bool {
+			return a == b
+		}),
+	}
+}
+
+// Render implements vdom.Component.
+func (o *Outlet) Render() *vdom.VNode {
+	return vdom.Div(
+		vdom.Attr{Key: "data-outlet", Value: "true"},
+		vdom.StyleAttr("display: contents"),
+		o.content.Get(),
+	)
+}
+
+// OnRouteChange registers fn to be called after each navigation while the
+// outlet's layout is mounted. Use it to update state that depends on the
+// route, such as the active item of a sidebar, without re-rendering the
+// layout. The returned function removes fn.
+func (o *Outlet) OnRouteChange(fn func(RouteChange)) (remove func()) {
+	o.mu.Lock()
+	o.listeners = append(o.listeners, fn)
+	index := len(o.listeners) - 1
+	o.mu.Unlock()
+
+	return func() {
+		o.mu.Lock()
+		defer o.mu.Unlock()
+		if index < len(o.listeners) {
+			o.listeners[index] = nil
+		}
+	}
+}
+
+// notify calls the route change listeners.
+func (o *Outlet) notify(change RouteChange) {
+	o.mu.Lock()
+	listeners := append([]func(RouteChange){}, o.listeners...)
+	o.mu.Unlock()
+
+	for _, fn := range listeners {
+		if fn != nil {
+			fn(change)
+		}
+	}
+}
+
+// RouteChange describes a completed navigation.
+type RouteChange struct {
+	// Path is the new URL path
+	Path string
+
+	// Params are the route parameters of the new page
+	Params map[string]string
+
+	// From is the previous URL path (empty for the first render)
+	From string
+}
+
+// =============================================================================
+// View
+// =============================================================================
+
+// View renders the routes of a Router in a live session and handles
+// client-side navigation. Persistent layouts (AddLayoutComponent) stay
+// mounted while the route changes below them: a navigation re-renders only
+// the outlet of the deepest layout shared by the old and new route.
+//
+// Layouts registered with AddLayout are plain functions; they render
+// again whenever the outlet above them changes.
+//
+//	app.SetSessionRoot(func(s *server.Session) server.Component {
+//	    return r.StartView(s)
+//	})
+type View struct {
+	router  *Router
+	session *server.Session
+	outlet  *Outlet
+	path    string
+	layouts []mountedLayout
+	loaded  bool
+}
+
+// mountedLayout is a persistent layout in the current route.
+type mountedLayout struct {
+	// key identifies the layout segment, e.g. "/projects/42" for a layout
+	// on /projects/:id
+	key    string
+	outlet *Outlet
+}
+
+// PathParam is the query parameter of the WebSocket URL that carries the
+// path of the page the session starts on.
+const PathParam = "path"
+
+// ViewSessionKey is the session key of the session's View.
+const ViewSessionKey = "vango_router_view"
+
+// NewView creates a view showing path. If session is set, the view handles
+// its client-side navigations.
+func NewView(r *Router, session *server.Session, path string) *View {
+	v := &View{
+		router:  r,
+		session: session,
+		outlet:  newOutlet(nil),
+	}
+	if session != nil {
+		session.OnNavigate(func(e server.NavigateEvent) {
+			v.Navigate(e.Path)
+		})
+	}
+	v.Navigate(path)
+	return v
+}
+
+// StartView creates the View of a new session, starting at the page that
+// opened it. Use it as the session root:
+//
+//	app.SetSessionRoot(func(s *server.Session) server.Component {
+//	    return r.StartView(s)
+//	})
+func (r *Router) StartView(session *server.Session) *View {
+	path := "/"
+	if p := session.GetString(ViewSessionKey + ".path"); p != "" {
+		path = p
+	}
+	v := NewView(r, session, path)
+	session.Set(ViewSessionKey, v)
+	return v
+}
+
+// StartSession records the page path of the upgrade request for
+// StartView. Call it from OnSessionStart.
+func (r *Router) StartSession(httpCtx context.Context, session *server.Session) {
+	req := server.RequestFromContext(httpCtx)
+	if req == nil || session == nil {
+		return
+	}
+	if p := req.URL.Query().Get(PathParam); p != "" {
+		session.SetString(ViewSessionKey+".path", p)
+	}
+}
+
+// ViewFromSession returns the View of session, or nil.
+func ViewFromSession(session *server.Session) *View {
+	if session == nil {
+		return nil
+	}
+	v, _ := session.Get(ViewSessionKey).(*View)
+	return v
+}
+
+// Render implements vdom.Component.
+func (v *View) Render() *vdom.VNode {
+	return v.outlet.Render()
+}
+
+// Path returns the current path.
+func (v *View) Path() string {
+	return v.path
+}
+
+// OnRouteChange registers fn to be called after each navigation.
+func (v *View) OnRouteChange(fn func(RouteChange)) (remove func()) {
+	return v.outlet.OnRouteChange(fn)
+}
+
+// Navigate shows the page at path. Persistent layouts shared with the
+// current route keep their state.
+func (v *View) Navigate(path string) {
+	u, err := url.Parse(path)
+	if err != nil || u.Path == "" {
+		u = &url.URL{Path: "/"}
+	}
+	req := &http.Request{
+		Method: http.MethodGet,
+		URL:    u,
+		Header: make(http.Header),
+	}
+
+	match, ok := v.router.Match(http.MethodGet, u.Path)
+	if !ok || match.PageHandler == nil {
+		var content *vdom.VNode
+		if notFound := v.router.NotFound(); notFound != nil {
+			ctx := server.NewSessionContext(v.session, req, nil)
+			content = componentNode(notFound(ctx, nil))
+		}
+		v.show(u.Path, nil, 0, nil, content)
+		return
+	}
+
+	ctx := server.NewSessionContext(v.session, req, match.Params)
+	page := v.loadPage(ctx, match)
+
+	// Walk the layout segments from the leaf up to the deepest persistent
+	// layout shared with the current route
+	node, _ := v.router.findNode(u.Path)
+	var chain []*RouteNode
+	for n := node; n != nil; n = n.parent {
+		if n.layoutHandler != nil {
+			chain = append([]*RouteNode{n}, chain...)
+		}
+	}
+
+	var keys []string
+	for _, n := range chain {
+		if n.layoutComp != nil {
+			key, _ := BuildPath(n.pattern(), match.Params)
+			keys = append(keys, key)
+		}
+	}
+	keep := 0
+	for keep < len(keys) && keep < len(v.layouts) && v.layouts[keep].key == keys[keep] {
+		keep++
+	}
+
+	// Build the content below the kept layouts, leaf to root
+	content := page
+	var mounted []mountedLayout
+	persistent := len(keys)
+	for i := len(chain) - 1; i >= 0; i-- {
+		n := chain[i]
+		if n.layoutComp == nil {
+			content = n.layoutHandler(ctx, content)
+			continue
+		}
+		persistent--
+		if persistent < keep {
+			break
+		}
+		outlet := newOutlet(content)
+		content = componentNode(n.layoutComp(ctx, outlet))
+		mounted = append([]mountedLayout{{key: keys[persistent], outlet: outlet}}, mounted...)
+	}
+
+	v.show(u.Path, match.Params, keep, mounted, content)
+}
+
+// loadPage runs the loaders of match and returns the page, or the error
+// page if a loader fails. Data handed over from server-side rendering is
+// used for the first page instead of loading again, and removed from the
+// session so it is never read for another page.
+func (v *View) loadPage(ctx server.Ctx, match *MatchResult) *vdom.VNode {
+	first := !v.loaded
+	v.loaded = true
+	if first && v.session != nil {
+		if data, ok := v.session.Get(LoaderSessionKey).(*LoadedData); ok {
+			v.session.Delete(LoaderSessionKey)
+			ctx.SetValue(loadedDataKey{}, data)
+			return componentNode(match.PageHandler(ctx, match.Params))
+		}
+	}
+
+	if _, err := match.Load(ctx); err != nil {
+		ctx.Logger().Error("route loader failed", "path", ctx.Path(), "error", err)
+		if match.ErrorPage != nil {
+			return match.ErrorPage(ctx, err)
+		}
+		return nil
+	}
+	return componentNode(match.PageHandler(ctx, match.Params))
+}
+
+// show replaces the content of the outlet below the first keep persistent
+// layouts and notifies route change listeners.
+func (v *View) show(path string, params map[string]string, keep int, mounted []mountedLayout, content *vdom.VNode) {
+	if keep > len(v.layouts) {
+		keep = len(v.layouts)
+	}
+	target := v.outlet
+	if keep > 0 {
+		target = v.layouts[keep-1].outlet
+	}
+	v.layouts = append(v.layouts[:keep], mounted...)
+
+	from := v.path
+	v.path = path
+	target.content.Set(content)
+
+	change := RouteChange{Path: path, Params: params, From: from}
+	v.outlet.notify(change)
+	for _, l := range v.layouts {
+		l.outlet.notify(change)
+	}
+}
+
+// findNode returns the route node matching path.
+func (r *Router) findNode(path string) (*RouteNode, bool) {
+	node, _, ok := r.root.match(splitPath(path), make(map[string]string), nil)
+	return node, ok
+}
+
+// componentNode wraps a component in a VNode.
+func componentNode(c vdom.Component) *vdom.VNode {
+	if c == nil {
+		return nil
+	}
+	return &vdom.VNode{Kind: vdom.KindComponent, Comp: c}
+}
@@ -0,0 +1,180 @@
+package router
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vango-dev/vango/v2/pkg/server"
+	"github.com/vango-dev/vango/v2/pkg/vango"
+	"github.com/vango-dev/vango/v2/pkg/vdom"
+)
+
+// renderText renders the text of a tree, expanding components.
+func renderText(node *vdom.VNode) string {
+	if node == nil {
+		return ""
+	}
+	switch node.Kind {
+	case vdom.KindText:
+		return node.Text
+	case vdom.KindComponent:
+		return renderText(node.Comp.Render())
+	}
+	var b strings.Builder
+	for _, child := range node.Children {
+		b.WriteString(renderText(child))
+	}
+	return b.String()
+}
+
+func textPage(text string) PageHandler {
+	return func(ctx server.Ctx, params any) vdom.Component {
+		return vdom.Func(func() *vdom.VNode {
+			return vdom.P(vdom.Text(text + ctx.Param("id")))
+		})
+	}
+}
+
+func TestViewPersistentLayout(t *testing.T) {
+	r := NewRouter()
+
+	mounts := 0
+	var sidebar *vango.Signal[string]
+	r.AddLayoutComponent("/app", func(ctx server.Ctx, outlet *Outlet) vdom.Component {
+		mounts++
+		state := vango.NewSignal("open")
+		sidebar = state
+		return vdom.Func(func() *vdom.VNode {
+			return vdom.Div(vdom.Text("["+state.Get()+"]"), outlet)
+		})
+	})
+	r.AddPage("/app", textPage("home"))
+	r.AddPage("/app/settings", textPage("settings"))
+	r.AddPage("/about", textPage("about"))
+
+	v := NewView(r, nil, "/app")
+	if got := renderText(v.Render()); got != "[open]home" {
+		t.Fatalf("Render() = %q", got)
+	}
+
+	sidebar.Set("closed")
+	v.Navigate("/app/settings")
+	if got := renderText(v.Render()); got != "[closed]settings" {
+		t.Errorf("layout state should survive navigation, got %q", got)
+	}
+	if mounts != 1 {
+		t.Errorf("layout mounted %d times, want 1", mounts)
+	}
+
+	// Leaving the segment unmounts the layout
+	v.Navigate("/about")
+	if got := renderText(v.Render()); got != "about" {
+		t.Errorf("Render() = %q, want about", got)
+	}
+	v.Navigate("/app")
+	if mounts != 2 {
+		t.Errorf("layout mounted %d times, want 2", mounts)
+	}
+	if got := renderText(v.Render()); got != "[open]home" {
+		t.Errorf("remounted layout should start fresh, got %q", got)
+	}
+}
+
+func TestViewOnlyOutletChanges(t *testing.T) {
+	r := NewRouter()
+
+	var outer, inner *Outlet
+	r.AddLayoutComponent("/", func(ctx server.Ctx, outlet *Outlet) vdom.Component {
+		outer = outlet
+		return vdom.Func(func() *vdom.VNode { return vdom.Div(outlet) })
+	})
+	r.AddLayoutComponent("/projects/:id", func(ctx server.Ctx, outlet *Outlet) vdom.Component {
+		inner = outlet
+		return vdom.Func(func() *vdom.VNode {
+			return vdom.Section(vdom.Text("project "+ctx.Param("id")+":"), outlet)
+		})
+	})
+	r.AddPage("/projects/:id", textPage("overview"))
+	r.AddPage("/projects/:id/tasks", textPage("tasks"))
+
+	v := NewView(r, nil, "/projects/1")
+	firstOuter, firstInner := outer, inner
+	outerContent := outer.content.Peek()
+
+	v.Navigate("/projects/1/tasks")
+	if inner != firstInner || outer != firstOuter {
+		t.Error("layouts of the shared segment should not be rebuilt")
+	}
+	if outer.content.Peek() != outerContent {
+		t.Error("only the innermost shared outlet should change")
+	}
+	if got := renderText(v.Render()); got != "project 1:tasks1" {
+		t.Errorf("Render() = %q", got)
+	}
+
+	// Another project is another segment of the same layout
+	v.Navigate("/projects/2")
+	if inner == firstInner {
+		t.Error("layout should be rebuilt for a new parameter value")
+	}
+	if outer != firstOuter {
+		t.Error("root layout should be kept")
+	}
+	if got := renderText(v.Render()); got != "project 2:overview2" {
+		t.Errorf("Render() = %q", got)
+	}
+}
+
+func TestViewRouteChange(t *testing.T) {
+	r := NewRouter()
+
+	var changes []RouteChange
+	r.AddLayoutComponent("/", func(ctx server.Ctx, outlet *Outlet) vdom.Component {
+		outlet.OnRouteChange(func(c RouteChange) { changes = append(changes, c) })
+		return vdom.Func(func() *vdom.VNode { return vdom.Nav(outlet) })
+	})
+	r.AddPage("/", textPage("home"))
+	r.AddPage("/users/:id", textPage("user"))
+
+	v := NewView(r, nil, "/")
+	v.Navigate("/users/7?tab=posts")
+
+	if len(changes) != 2 {
+		t.Fatalf("got %d route changes, want 2", len(changes))
+	}
+	last := changes[1]
+	if last.Path != "/users/7" || last.From != "/" || last.Params["id"] != "7" {
+		t.Errorf("RouteChange = %+v", last)
+	}
+	if v.Path() != "/users/7" {
+		t.Errorf("Path() = %q", v.Path())
+	}
+}
+
+func TestViewNotFound(t *testing.T) {
+	r := NewRouter()
+	r.AddPage("/", textPage("home"))
+	r.SetNotFound(textPage("missing"))
+
+	v := NewView(r, nil, "/nope")
+	if got := renderText(v.Render()); got != "missing" {
+		t.Errorf("Render() = %q, want missing", got)
+	}
+}
+
+func TestViewSession(t *testing.T) {
+	r := NewRouter()
+	r.AddPage("/", textPage("home"))
+	r.AddPage("/about", textPage("about"))
+
+	session := server.NewMockSession()
+	session.SetString(ViewSessionKey+".path", "/about")
+
+	v := r.StartView(session)
+	if ViewFromSession(session) != v {
+		t.Error("ViewFromSession() should return the session's view")
+	}
+	if got := renderText(v.Render()); got != "about" {
+		t.Errorf("Render() = %q, want about", got)
+	}
+}
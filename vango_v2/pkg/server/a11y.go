@@ -0,0 +1,68 @@
+package server
+
+import (
+	"encoding/json"
+	"slices"
+
+	"github.com/vango-dev/vango/v2/pkg/a11y"
+	"github.com/vango-dev/vango/v2/pkg/protocol"
+	"github.com/vango-dev/vango/v2/pkg/vdom"
+)
+
+// A11yEvent is the event dispatched on the page with the accessibility
+// violations of the rendered tree in dev mode. Its detail is the list of
+// violations; the dev server's overlay shows them.
+const A11yEvent = "vango:a11y"
+
+// checkAccessibility checks the rendered tree of the session in dev mode.
+// New violations are logged, and the client is sent the violations
+// whenever they change.
+func (s *Session) checkAccessibility() {
+	if !s.checkA11y || s.root == nil {
+		return
+	}
+
+	// Component nodes resolve to the tree of the instance mounted from them
+	mounted := make(map[*vdom.VNode]*ComponentInstance)
+	var collect func(inst *ComponentInstance)
+	collect = func(inst *ComponentInstance) {
+		for _, child := range inst.Children {
+			mounted[child.node] = child
+			collect(child)
+		}
+	}
+	collect(s.root)
+
+	checker := a11y.Checker{
+		Root: a11y.ComponentName(s.root.Component),
+		Resolve: func(node *vdom.VNode) *vdom.VNode {
+			if inst := mounted[node]; inst != nil {
+				return inst.LastTree()
+			}
+			return nil
+		},
+	}
+
+	violations := checker.Check(s.root.LastTree())
+	found := make([]string, len(violations))
+	for i, v := range violations {
+		found[i] = v.String()
+		if !slices.Contains(s.a11yViolations, found[i]) {
+			s.logger.Warn("accessibility violation", "rule", v.Rule, "path", v.Path, "element", v.Element, "message", v.Message)
+		}
+	}
+	if slices.Equal(found, s.a11yViolations) {
+		return
+	}
+	s.a11yViolations = found
+
+	hid := s.rootHID()
+	if s.conn == nil || hid == "" {
+		return
+	}
+	detail, err := json.Marshal(found)
+	if err != nil {
+		return
+	}
+	s.SendPatches([]protocol.Patch{protocol.NewDispatchPatch(hid, A11yEvent, string(detail))})
+}
@@ -0,0 +1,450 @@
+package server
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/vango-dev/vango/v2/pkg/protocol"
+)
+
+// Blob errors.
+var (
+	// ErrBlobAborted is returned when reading a blob the client canceled.
+	ErrBlobAborted = errors.New("server: blob aborted")
+
+	// ErrBlobTooLarge is returned when reading a blob over MaxBlobSize.
+	ErrBlobTooLarge = errors.New("server: blob too large")
+
+	// ErrBlobsUnsupported is returned by SendBlob for clients without
+	// protocol.CapBinaryBlobs.
+	ErrBlobsUnsupported = errors.New("server: client does not support blobs")
+)
+
+// Blob is a file sent by the client on the live connection, e.g. dropped
+// on an element. Drop handlers of the element receive it:
+//
+//	vdom.OnDrop(func(ctx server.Ctx, b *server.Blob) {
+//	    go func() {
+//	        defer b.Close()
+//	        data, err := io.ReadAll(b)
+//	        ...
+//	    }()
+//	})
+//
+// Its data arrives as it is read, so read it off the event loop. Blobs
+// are flow-controlled: the client sends more only as the data is consumed.
+// Blobs not taken by a handler are canceled.
+type Blob struct {
+	// ID identifies the blob on the connection.
+	ID uint64
+
+	// HID is the element the blob was dropped on.
+	HID string
+
+	// Name and MimeType describe the file, e.g. "photo.png", "image/png".
+	Name     string
+	MimeType string
+
+	// Size is the length announced by the client, or 0 if unknown.
+	Size int64
+
+	s       *Session
+	claimed bool // Taken by a handler
+
+	mu       sync.Mutex
+	cond     *sync.Cond
+	chunks   [][]byte
+	buffered int   // Bytes received and not yet credited back
+	read     int   // Bytes read since the last credit
+	received int64 // Bytes received in total
+	done     bool  // No more chunks will arrive
+	err      error // Why the blob ended early
+}
+
+// Read reads the data of the blob, waiting for the client to send it.
+// It returns io.EOF at the end of the blob, and ErrBlobAborted if the
+// client canceled it.
+func (b *Blob) Read(p []byte) (int, error) {
+	b.mu.Lock()
+	for len(b.chunks) == 0 && !b.done {
+		b.cond.Wait()
+	}
+	if len(b.chunks) == 0 {
+		err := b.err
+		b.mu.Unlock()
+		if err == nil {
+			err = io.EOF
+		}
+		return 0, err
+	}
+
+	n := copy(p, b.chunks[0])
+	b.chunks[0] = b.chunks[0][n:]
+	b.read += n
+	credit := 0
+	if len(b.chunks[0]) == 0 {
+		b.chunks = b.chunks[1:]
+		credit, b.read = b.read, 0
+		b.buffered -= credit
+	}
+	b.mu.Unlock()
+
+	// Grant the window of consumed chunks back to the client
+	if credit > 0 {
+		b.s.sendBlobMessage(&protocol.BlobMessage{Op: protocol.BlobCredit, ID: b.ID, Credit: uint64(credit)})
+	}
+	return n, nil
+}
+
+// Close stops reading the blob. If the client is still sending it, the
+// transfer is canceled.
+func (b *Blob) Close() error {
+	if b.finish(ErrBlobAborted) {
+		b.s.sendBlobMessage(&protocol.BlobMessage{Op: protocol.BlobAbort, ID: b.ID, Reason: "closed"})
+	}
+	return nil
+}
+
+// push adds a chunk sent by the client. Clients that send past their
+// window or past MaxBlobSize are aborted.
+func (b *Blob) push(data []byte) {
+	b.mu.Lock()
+	if b.done {
+		b.mu.Unlock()
+		return
+	}
+	b.received += int64(len(data))
+	over := b.buffered+len(data) > protocol.BlobWindow
+	tooLarge := b.s.config.MaxBlobSize > 0 && b.received > b.s.config.MaxBlobSize
+	if !over && !tooLarge {
+		b.chunks = append(b.chunks, data)
+		b.buffered += len(data)
+		b.cond.Broadcast()
+	}
+	b.mu.Unlock()
+
+	switch {
+	case tooLarge:
+		b.abort(ErrBlobTooLarge, "too large")
+	case over:
+		b.abort(ErrBlobAborted, "window exceeded")
+	}
+}
+
+// abort ends the blob with err and tells the client.
+func (b *Blob) abort(err error, reason string) {
+	if b.finish(err) {
+		b.s.sendBlobMessage(&protocol.BlobMessage{Op: protocol.BlobAbort, ID: b.ID, Reason: reason})
+	}
+}
+
+// finish marks the end of the blob: err is nil at its end, or why it
+// ended early. It reports whether the blob was still open.
+func (b *Blob) finish(err error) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.done {
+		return false
+	}
+	b.done = true
+	b.err = err
+	if err != nil {
+		b.chunks = nil
+	}
+	b.cond.Broadcast()
+
+	b.s.blobMu.Lock()
+	delete(b.s.recvBlobs, b.ID)
+	b.s.blobMu.Unlock()
+	return true
+}
+
+// closeUnclaimed cancels the blob if no handler took it.
+func (b *Blob) closeUnclaimed() {
+	if !b.claimed {
+		b.Close()
+	}
+}
+
+// BlobTransfer is a blob being sent to the client by Session.SendBlob.
+type BlobTransfer struct {
+	// ID identifies the blob on the connection. Reference it from elements
+	// with vdom.BlobSrc and vdom.BlobHref.
+	ID uint64
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	credit int
+	err    error
+	done   chan struct{}
+}
+
+// Ref returns the ID of the blob as an attribute value.
+func (t *BlobTransfer) Ref() string {
+	return strconv.FormatUint(t.ID, 10)
+}
+
+// Wait waits until the blob is sent and returns why it failed, if it did.
+func (t *BlobTransfer) Wait() error {
+	<-t.done
+	return t.err
+}
+
+// grant adds window sent by the client.
+func (t *BlobTransfer) grant(n uint64) {
+	t.mu.Lock()
+	t.credit += int(min(n, protocol.BlobWindow))
+	t.cond.Broadcast()
+	t.mu.Unlock()
+}
+
+// cancel stops the transfer with err.
+func (t *BlobTransfer) cancel(err error) {
+	t.mu.Lock()
+	if t.err == nil {
+		t.err = err
+	}
+	t.cond.Broadcast()
+	t.mu.Unlock()
+}
+
+// window waits for window to send and returns how much, or the error that
+// stopped the transfer.
+func (t *BlobTransfer) window() (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for t.credit <= 0 && t.err == nil {
+		t.cond.Wait()
+	}
+	if t.err != nil {
+		return 0, t.err
+	}
+	return t.credit, nil
+}
+
+// SendBlob sends the data of r to the client as a blob, in the background.
+// Once received, the client exposes it as an object URL to the elements
+// referencing it:
+//
+//	t, err := ctx.Session().SendBlob("chart.png", "image/png", bytes.NewReader(png))
+//	...
+//	vdom.Img(vdom.BlobSrc(t.Ref()))
+//
+// r is closed after it is sent if it is an io.Closer.
+func (s *Session) SendBlob(name, mimeType string, r io.Reader) (*BlobTransfer, error) {
+	if !s.caps.Has(protocol.CapBinaryBlobs) {
+		return nil, ErrBlobsUnsupported
+	}
+	if s.closed.Load() {
+		return nil, ErrSessionClosed
+	}
+
+	t := &BlobTransfer{
+		ID:     s.nextBlobID.Add(2), // Server blobs have even IDs
+		credit: protocol.BlobWindow,
+		done:   make(chan struct{}),
+	}
+	t.cond = sync.NewCond(&t.mu)
+
+	s.blobMu.Lock()
+	s.sendBlobs[t.ID] = t
+	s.blobMu.Unlock()
+
+	go s.streamBlob(t, name, mimeType, r)
+	return t, nil
+}
+
+// streamBlob sends the blob of t, as the client grants window.
+func (s *Session) streamBlob(t *BlobTransfer, name, mimeType string, r io.Reader) {
+	defer func() {
+		if c, ok := r.(io.Closer); ok {
+			c.Close()
+		}
+		s.blobMu.Lock()
+		delete(s.sendBlobs, t.ID)
+		s.blobMu.Unlock()
+		close(t.done)
+	}()
+
+	var size uint64
+	if l, ok := r.(interface{ Len() int }); ok {
+		size = uint64(l.Len())
+	}
+	if err := s.sendBlobMessage(&protocol.BlobMessage{
+		Op: protocol.BlobOpen, ID: t.ID, Name: name, MimeType: mimeType, Size: size,
+	}); err != nil {
+		t.cancel(err)
+		return
+	}
+
+	buf := make([]byte, protocol.BlobChunkSize)
+	for {
+		window, err := t.window()
+		if err != nil {
+			return
+		}
+
+		n, err := io.ReadFull(r, buf[:min(window, len(buf))])
+		if n > 0 {
+			if werr := s.sendBlobMessage(&protocol.BlobMessage{Op: protocol.BlobChunk, ID: t.ID, Data: buf[:n]}); werr != nil {
+				t.cancel(werr)
+				return
+			}
+			t.mu.Lock()
+			t.credit -= n
+			t.mu.Unlock()
+		}
+
+		switch {
+		case err == io.EOF || err == io.ErrUnexpectedEOF:
+			s.sendBlobMessage(&protocol.BlobMessage{Op: protocol.BlobEnd, ID: t.ID})
+			return
+		case err != nil:
+			t.cancel(err)
+			s.sendBlobMessage(&protocol.BlobMessage{Op: protocol.BlobAbort, ID: t.ID, Reason: "read error"})
+			return
+		}
+	}
+}
+
+// handleBlobFrame handles a blob message from the client: the data of the
+// blobs it sends, and the window it grants to ours.
+func (s *Session) handleBlobFrame(payload []byte) {
+	m, err := protocol.DecodeBlob(payload)
+	if err != nil {
+		s.logger.Error("blob decode error", "error", err)
+		return
+	}
+	if !s.caps.Has(protocol.CapBinaryBlobs) {
+		return
+	}
+
+	if !protocol.IsClientBlob(m.ID) {
+		s.blobMu.Lock()
+		t := s.sendBlobs[m.ID]
+		s.blobMu.Unlock()
+		if t == nil {
+			return
+		}
+		switch m.Op {
+		case protocol.BlobCredit:
+			t.grant(m.Credit)
+		case protocol.BlobAbort:
+			t.cancel(ErrBlobAborted)
+		}
+		return
+	}
+
+	if m.Op == protocol.BlobOpen {
+		s.openBlob(m)
+		return
+	}
+
+	s.blobMu.Lock()
+	b := s.recvBlobs[m.ID]
+	s.blobMu.Unlock()
+	if b == nil {
+		return
+	}
+	switch m.Op {
+	case protocol.BlobChunk:
+		b.push(m.Data)
+	case protocol.BlobEnd:
+		b.finish(nil)
+	case protocol.BlobAbort:
+		b.finish(ErrBlobAborted)
+	}
+}
+
+// openBlob starts receiving a blob and queues it as a drop event of its
+// element.
+func (s *Session) openBlob(m *protocol.BlobMessage) {
+	b := &Blob{
+		ID:       m.ID,
+		HID:      m.HID,
+		Name:     m.Name,
+		MimeType: m.MimeType,
+		Size:     int64(m.Size),
+		s:        s,
+	}
+	b.cond = sync.NewCond(&b.mu)
+
+	if limit := s.config.MaxBlobSize; limit > 0 && b.Size > limit {
+		s.sendBlobMessage(&protocol.BlobMessage{Op: protocol.BlobAbort, ID: m.ID, Reason: "too large"})
+		return
+	}
+
+	s.blobMu.Lock()
+	if _, exists := s.recvBlobs[m.ID]; exists {
+		s.blobMu.Unlock()
+		return
+	}
+	s.recvBlobs[m.ID] = b
+	s.blobMu.Unlock()
+
+	event := &Event{
+		Type:    protocol.EventDrop,
+		HID:     m.HID,
+		Payload: b,
+		Session: s,
+		Time:    time.Now(),
+	}
+	if err := s.QueueEvent(event); err != nil {
+		b.abort(ErrBlobAborted, "event queue full")
+	}
+}
+
+// abortBlobs ends the blobs of a closing session.
+func (s *Session) abortBlobs() {
+	s.blobMu.Lock()
+	recv := make([]*Blob, 0, len(s.recvBlobs))
+	for _, b := range s.recvBlobs {
+		recv = append(recv, b)
+	}
+	send := make([]*BlobTransfer, 0, len(s.sendBlobs))
+	for _, t := range s.sendBlobs {
+		send = append(send, t)
+	}
+	s.blobMu.Unlock()
+
+	for _, b := range recv {
+		b.finish(ErrSessionClosed)
+	}
+	for _, t := range send {
+		t.cancel(ErrSessionClosed)
+	}
+}
+
+// sendBlobMessage sends a blob frame to the client.
+func (s *Session) sendBlobMessage(m *protocol.BlobMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed.Load() {
+		return ErrSessionClosed
+	}
+
+	frame := protocol.NewFrame(protocol.FrameBlob, protocol.EncodeBlob(m))
+	frameData := frame.Encode()
+
+	s.conn.SetWriteDeadline(time.Now().Add(s.config.WriteTimeout))
+	if err := s.conn.WriteMessage(websocket.BinaryMessage, frameData); err != nil {
+		s.logger.Error("blob write error", "error", err)
+		return err
+	}
+	s.bytesSent.Add(uint64(len(frameData)))
+	return nil
+}
+
+// blobValue hands the blob of a drop event to its handler.
+func blobValue(e *Event) (*Blob, bool) {
+	b, ok := e.Payload.(*Blob)
+	if ok {
+		b.claimed = true
+	}
+	return b, ok
+}
@@ -0,0 +1,189 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/vango-dev/vango/v2/pkg/protocol"
+)
+
+// Client call errors.
+var (
+	// ErrCallTimeout is returned when the client does not answer a call
+	// within CallTimeout.
+	ErrCallTimeout = errors.New("server: client call timed out")
+
+	// ErrCallsUnsupported is returned by calls to clients without
+	// protocol.CapClientCalls, and outside a live session.
+	ErrCallsUnsupported = errors.New("server: client does not support calls")
+)
+
+// CallError is the error of a client function that threw, rejected or is
+// not registered.
+type CallError struct {
+	Name    string // Function called
+	Message string // Message of the client error
+}
+
+// Error implements the error interface.
+func (e *CallError) Error() string {
+	return "server: client call " + e.Name + ": " + e.Message
+}
+
+// Result is the value returned by a client function, as JSON.
+type Result struct {
+	raw json.RawMessage
+}
+
+// Raw returns the JSON of the result, "null" for functions returning
+// nothing.
+func (r Result) Raw() json.RawMessage {
+	if len(r.raw) == 0 {
+		return json.RawMessage("null")
+	}
+	return r.raw
+}
+
+// Decode unmarshals the result into v.
+func (r Result) Decode(v any) error {
+	return json.Unmarshal(r.Raw(), v)
+}
+
+// ClientCall is a call of a client function awaiting its result.
+type ClientCall struct {
+	// ID correlates the call with its result on the connection.
+	ID uint64
+
+	// Name is the client function called.
+	Name string
+
+	s      *Session
+	timer  *time.Timer
+	once   sync.Once
+	done   chan struct{}
+	result Result
+	err    error
+}
+
+// Done returns a channel closed when the result arrives or the call fails.
+func (c *ClientCall) Done() <-chan struct{} {
+	return c.done
+}
+
+// Wait waits for the result of the call, until it fails, CallTimeout
+// elapses or ctx is done.
+func (c *ClientCall) Wait(ctx context.Context) (Result, error) {
+	select {
+	case <-c.done:
+		return c.result, c.err
+	case <-ctx.Done():
+		return Result{}, ctx.Err()
+	}
+}
+
+// Then runs fn with the result of the call on the session's event loop,
+// like an event handler, without blocking the caller.
+func (c *ClientCall) Then(fn func(Result, error)) {
+	go func() {
+		<-c.done
+		c.s.Dispatch(func() { fn(c.result, c.err) })
+	}()
+}
+
+// resolve ends the call; later results are ignored.
+func (c *ClientCall) resolve(r Result, err error) {
+	c.once.Do(func() {
+		c.result, c.err = r, err
+		if c.s != nil {
+			c.s.callMu.Lock()
+			delete(c.s.calls, c.ID)
+			c.s.callMu.Unlock()
+		}
+		close(c.done)
+	})
+}
+
+// CallAsync calls the client function name, registered with
+// registerFunction in the thin client, with the element hid and args
+// encoded as JSON. It returns immediately; the result is delivered to Wait
+// and Then:
+//
+//	ctx.Session().CallAsync("", "share", shareData).Then(func(r server.Result, err error) {
+//	    shared.Set(err == nil)
+//	})
+//
+// hid may be "" for functions not bound to an element. Calls fail with
+// ErrCallTimeout if the client does not answer within CallTimeout, and with
+// a *CallError if the function throws.
+func (s *Session) CallAsync(hid, name string, args ...any) *ClientCall {
+	c := &ClientCall{Name: name, s: s, done: make(chan struct{})}
+
+	if !s.caps.Has(protocol.CapClientCalls) {
+		c.resolve(Result{}, ErrCallsUnsupported)
+		return c
+	}
+	if s.closed.Load() || s.conn == nil {
+		c.resolve(Result{}, ErrSessionClosed)
+		return c
+	}
+	if args == nil {
+		args = []any{}
+	}
+	data, err := json.Marshal(args)
+	if err != nil {
+		c.resolve(Result{}, err)
+		return c
+	}
+
+	c.ID = s.nextCallID.Add(1)
+	if timeout := s.config.CallTimeout; timeout > 0 {
+		c.timer = time.AfterFunc(timeout, func() { c.resolve(Result{}, ErrCallTimeout) })
+	}
+	s.callMu.Lock()
+	s.calls[c.ID] = c
+	s.callMu.Unlock()
+
+	s.SendPatches([]protocol.Patch{protocol.NewCallPatch(c.ID, hid, name, string(data))})
+	return c
+}
+
+// handleCallResult resolves the call a result event answers. Results are
+// handled as they are read, so calls complete while the event loop waits
+// for them.
+func (s *Session) handleCallResult(data *protocol.CallResultData) {
+	s.callMu.Lock()
+	c := s.calls[data.ID]
+	s.callMu.Unlock()
+	if c == nil {
+		return // Timed out, or not ours
+	}
+
+	if c.timer != nil {
+		c.timer.Stop()
+	}
+	if !data.OK {
+		c.resolve(Result{}, &CallError{Name: c.Name, Message: data.Value})
+		return
+	}
+	c.resolve(Result{raw: json.RawMessage(data.Value)}, nil)
+}
+
+// abortCalls fails the calls of a closing session.
+func (s *Session) abortCalls() {
+	s.callMu.Lock()
+	calls := make([]*ClientCall, 0, len(s.calls))
+	for _, c := range s.calls {
+		calls = append(calls, c)
+	}
+	s.callMu.Unlock()
+
+	for _, c := range calls {
+		if c.timer != nil {
+			c.timer.Stop()
+		}
+		c.resolve(Result{}, ErrSessionClosed)
+	}
+}
@@ -0,0 +1,148 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vango-dev/vango/v2/pkg/protocol"
+)
+
+// ClientInfo describes the browser of a session: its viewport, time zone,
+// preferences and locale. It is sent in the handshake and kept up to date
+// as the window is resized and media queries change. Components reading it
+// through Session.ClientInfo or Ctx.ClientInfo re-render when it changes.
+type ClientInfo struct {
+	// ViewportWidth and ViewportHeight are the size of the window in CSS
+	// pixels, or 0 if unknown.
+	ViewportWidth  int
+	ViewportHeight int
+
+	// TZOffset is the offset of the client's time zone from UTC, in minutes.
+	TZOffset int
+
+	// TimeZone is the IANA time zone of the client, e.g. "Europe/Paris",
+	// or "" if unknown.
+	TimeZone string
+
+	// ColorScheme is the preferred color scheme: "light", "dark", or ""
+	// for no preference.
+	ColorScheme string
+
+	// ReducedMotion reports whether the client prefers reduced motion.
+	ReducedMotion bool
+
+	// Locale is the preferred locale of the client, e.g. "fr-FR".
+	Locale string
+
+	// PixelRatio is the device pixel ratio, or 0 if unknown.
+	PixelRatio float64
+}
+
+// Location returns the time zone of the client: its IANA zone if known,
+// else a fixed zone at its UTC offset.
+//
+//	now := time.Now().In(ctx.ClientInfo().Location())
+func (ci ClientInfo) Location() *time.Location {
+	if ci.TimeZone != "" {
+		if loc, err := time.LoadLocation(ci.TimeZone); err == nil {
+			return loc
+		}
+	}
+	if ci.TZOffset == 0 {
+		return time.UTC
+	}
+	return time.FixedZone("", ci.TZOffset*60)
+}
+
+// Dark reports whether the client prefers a dark color scheme.
+func (ci ClientInfo) Dark() bool {
+	return ci.ColorScheme == "dark"
+}
+
+// clientInfoFromHello returns the client info sent in a handshake. The
+// locale of clients that do not send it comes from the upgrade request.
+func clientInfoFromHello(h *protocol.ClientHello, r *http.Request) ClientInfo {
+	ci := ClientInfo{
+		ViewportWidth:  int(h.ViewportW),
+		ViewportHeight: int(h.ViewportH),
+		TZOffset:       int(h.TZOffset),
+		TimeZone:       h.TimeZone,
+		ColorScheme:    h.ColorScheme.String(),
+		ReducedMotion:  h.ReducedMotion,
+		Locale:         h.Locale,
+		PixelRatio:     float64(h.PixelRatio) / 100,
+	}
+	if ci.Locale == "" && r != nil {
+		ci.Locale = ClientInfoFromRequest(r).Locale
+	}
+	return ci
+}
+
+// ClientInfoFromRequest returns what an HTTP request tells of the client:
+// its locale from Accept-Language, and its viewport width, pixel ratio and
+// preferences from the client hints it sends (Sec-CH-Viewport-Width,
+// Sec-CH-DPR, Sec-CH-Prefers-Color-Scheme and
+// Sec-CH-Prefers-Reduced-Motion). Request the hints with an Accept-CH
+// response header.
+func ClientInfoFromRequest(r *http.Request) ClientInfo {
+	var ci ClientInfo
+
+	if lang := r.Header.Get("Accept-Language"); lang != "" {
+		first, _, _ := strings.Cut(lang, ",")
+		first, _, _ = strings.Cut(first, ";")
+		if first = strings.TrimSpace(first); first != "*" {
+			ci.Locale = first
+		}
+	}
+
+	if w, err := strconv.Atoi(r.Header.Get("Sec-CH-Viewport-Width")); err == nil {
+		ci.ViewportWidth = w
+	}
+	if dpr, err := strconv.ParseFloat(r.Header.Get("Sec-CH-DPR"), 64); err == nil {
+		ci.PixelRatio = dpr
+	}
+	switch strings.Trim(r.Header.Get("Sec-CH-Prefers-Color-Scheme"), `"`) {
+	case "light":
+		ci.ColorScheme = "light"
+	case "dark":
+		ci.ColorScheme = "dark"
+	}
+	ci.ReducedMotion = strings.Trim(r.Header.Get("Sec-CH-Prefers-Reduced-Motion"), `"`) == "reduce"
+
+	return ci
+}
+
+// ClientInfo returns the client info of the session. Reading it during a
+// render subscribes the component to its changes.
+func (s *Session) ClientInfo() ClientInfo {
+	if s.clientInfo == nil {
+		return ClientInfo{}
+	}
+	return s.clientInfo.Get()
+}
+
+// isClientInfoEvent reports whether e reports a change of the window
+// rather than an event of an element.
+func isClientInfoEvent(e *Event) bool {
+	return e.HID == "" && (e.Type == protocol.EventResize || e.Type == protocol.EventMedia)
+}
+
+// updateClientInfo applies a window resize or media query change.
+func (s *Session) updateClientInfo(e *Event) {
+	if s.clientInfo == nil {
+		return
+	}
+	ci := s.clientInfo.Peek()
+	switch data := e.Payload.(type) {
+	case *protocol.ResizeEventData:
+		ci.ViewportWidth = data.Width
+		ci.ViewportHeight = data.Height
+	case *protocol.MediaEventData:
+		ci.ColorScheme = data.ColorScheme.String()
+		ci.ReducedMotion = data.ReducedMotion
+		ci.PixelRatio = float64(data.PixelRatio) / 100
+	}
+	s.clientInfo.Set(ci)
+}
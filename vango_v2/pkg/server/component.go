@@ -41,6 +41,10 @@ type ComponentInstance struct {
 	// Parent is the parent component instance (nil for root).
 	Parent *ComponentInstance
 
+	// node is the component node in the parent's tree this instance was
+	// mounted from (nil for root).
+	node *vdom.VNode
+
 	// Children are child component instances.
 	Children []*ComponentInstance
 
@@ -55,9 +59,16 @@ type ComponentInstance struct {
 
 	// lastTree is the last rendered VNode tree (for diffing).
 	lastTree *vdom.VNode
+
+	// signals are the signals read by the last render, recorded while the
+	// session is traced for the inspector.
+	signals []vango.InspectableSignal
 }
 
-var _ vango.Listener = (*ComponentInstance)(nil)
+var (
+	_ vango.Listener       = (*ComponentInstance)(nil)
+	_ vango.SignalRecorder = (*ComponentInstance)(nil)
+)
 
 // componentIDCounter is used to generate unique component IDs.
 var componentIDCounter atomic.Uint64
@@ -96,6 +107,7 @@ func (c *ComponentInstance) Render() *vdom.VNode {
 	}
 
 	var tree *vdom.VNode
+	c.signals = nil
 
 	// Set up tracking context for this component's owner
 	// This ensures signals created during render are owned by this component
@@ -227,6 +239,21 @@ func (c *ComponentInstance) MemoryUsage() int64 {
 	return size
 }
 
+// RecordSignal implements vango.SignalRecorder. Signals are only recorded
+// when the session inspector is enabled.
+func (c *ComponentInstance) RecordSignal(sig vango.InspectableSignal) {
+	if c.session == nil || c.session.trace == nil {
+		return
+	}
+	id := sig.SignalID()
+	for _, existing := range c.signals {
+		if existing.SignalID() == id {
+			return
+		}
+	}
+	c.signals = append(c.signals, sig)
+}
+
 // ID implements vango.Listener and returns a globally unique identifier.
 func (c *ComponentInstance) ID() uint64 {
 	if c.Owner != nil {
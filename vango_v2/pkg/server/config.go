@@ -2,10 +2,12 @@ package server
 
 import (
 	"context"
+	"io"
 	"net/http"
 	"net/url"
 	"time"
 
+	"github.com/vango-dev/vango/v2/pkg/protocol"
 	"github.com/vango-dev/vango/v2/pkg/session"
 )
 
@@ -33,6 +35,11 @@ type SessionConfig struct {
 	// Default: 30 seconds.
 	HeartbeatInterval time.Duration
 
+	// CallTimeout is the maximum time to wait for the result of a client
+	// call (see Ctx.Call).
+	// Default: 10 seconds.
+	CallTimeout time.Duration
+
 	// Limits
 
 	// MaxMessageSize is the maximum size of an incoming WebSocket message.
@@ -47,6 +54,11 @@ type SessionConfig struct {
 	// Default: 256.
 	MaxEventQueue int
 
+	// MaxBlobSize is the maximum size of a blob sent by the client on the
+	// live connection. 0 means no limit.
+	// Default: 32MB.
+	MaxBlobSize int64
+
 	// Features
 
 	// EnableCompression enables WebSocket compression.
@@ -66,9 +78,11 @@ func DefaultSessionConfig() *SessionConfig {
 		IdleTimeout:       5 * time.Minute,
 		HandshakeTimeout:  10 * time.Second,
 		HeartbeatInterval: 30 * time.Second,
+		CallTimeout:       10 * time.Second,
 		MaxMessageSize:    64 * 1024, // 64KB
 		MaxPatchHistory:   100,
 		MaxEventQueue:     256,
+		MaxBlobSize:       32 * 1024 * 1024, // 32MB
 		EnableCompression: true,
 		EnableOptimistic:  true,
 	}
@@ -145,6 +159,7 @@ type ServerConfig struct {
 	// Use this to copy data from the HTTP context (e.g., authenticated user) to the Vango session.
 	// This runs SYNCHRONOUSLY before the WebSocket upgrade completes, while r.Context() is still alive.
 	// After this callback returns, the HTTP context is dead and cannot be accessed.
+	// The upgrade request itself is available via RequestFromContext(httpCtx).
 	//
 	// Example:
 	//     OnSessionStart: func(httpCtx context.Context, session *Session) {
@@ -175,6 +190,9 @@ type ServerConfig struct {
 	// - Origin checking (allows all origins)
 	// - CSRF validation
 	// - Secure cookie requirements
+	// Sessions also check their tree for accessibility violations after
+	// each render (see package a11y), and hooks.Hook() panics in them on
+	// undefined hooks and unknown config keys (see hooks.SetStrict).
 	// Default: false (secure by default)
 	DevMode bool
 
@@ -193,6 +211,31 @@ type ServerConfig struct {
 	// Default: "" (current domain)
 	CookieDomain string
 
+	// CSP configures the Content-Security-Policy header of page responses.
+	// Request nonces are generated regardless; nil sends no header.
+	// Default: nil
+	CSP *CSPConfig
+
+	// Debug enables the session inspector at /_vango/debug.
+	// Default: nil (disabled)
+	Debug *DebugConfig
+
+	// ProtocolVersions is the range of client protocol versions accepted.
+	// Older clients are told to reload; newer ones of the same major
+	// version are answered with the newest version of the range.
+	// Default: nil (protocol.DefaultVersionPolicy)
+	ProtocolVersions *protocol.VersionPolicy
+
+	// RecordSession returns where to record the messages of a new session,
+	// or nil to not record it. The writer is closed with the session if it
+	// is an io.Closer. Replay recordings with `vango replay`; see
+	// RecordToDir.
+	//
+	// Recordings contain the user's input (form values, typed text, event
+	// payloads) but not the CSRF token of the handshake.
+	// Default: nil (no recording)
+	RecordSession func(r *http.Request, session *Session) io.Writer
+
 	// ==========================================================================
 	// Phase 12: Session Resilience & State Persistence
 	// ==========================================================================
@@ -471,6 +514,16 @@ func (c *ServerConfig) WithSameSiteMode(mode http.SameSite) *ServerConfig {
 	return c
 }
 
+// WithStrictCSP enables the strict Content-Security-Policy header and
+// returns the config for chaining.
+func (c *ServerConfig) WithStrictCSP() *ServerConfig {
+	if c.CSP == nil {
+		c.CSP = &CSPConfig{}
+	}
+	c.CSP.Strict = true
+	return c
+}
+
 // WithCookieDomain sets the domain for session cookies.
 func (c *ServerConfig) WithCookieDomain(domain string) *ServerConfig {
 	c.CookieDomain = domain
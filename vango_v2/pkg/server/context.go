@@ -2,9 +2,12 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"log/slog"
 	"net/http"
 	"net/url"
+
+	"github.com/vango-dev/vango/v2/pkg/protocol"
 )
 
 // Ctx provides access to request data within components.
@@ -59,6 +62,12 @@ type Ctx interface {
 	// SetUser sets the authenticated user.
 	SetUser(user any)
 
+	// ClientInfo returns the browser environment: viewport, time zone,
+	// preferences and locale. For live sessions it follows the session and
+	// subscribes the rendering component to its changes; for HTTP requests
+	// it is read from Accept-Language and client hints.
+	ClientInfo() ClientInfo
+
 	// Logging
 
 	// Logger returns the request-scoped logger.
@@ -67,6 +76,7 @@ type Ctx interface {
 	// Lifecycle
 
 	// Done returns a channel that's closed when the request is canceled.
+	// For live events, it is closed when the session closes.
 	Done() <-chan struct{}
 
 	// Request-scoped values (Phase 10)
@@ -81,10 +91,30 @@ type Ctx interface {
 	// Custom events (Phase 10)
 
 	// Emit dispatches a custom event to the client.
-	// The event will be dispatched as a CustomEvent with the given name and detail.
+	// The event will be dispatched as a CustomEvent with the given name and
+	// data as its detail, on the element of the current event, after the
+	// DOM updates of the handler are applied.
 	// Use this for notifications, toast messages, analytics, etc.
 	Emit(name string, data any)
 
+	// Call calls the client function name, registered in the thin client,
+	// with the element hid and args encoded as JSON, and returns its result.
+	// It waits at most CallTimeout. Results are read off the event loop, but
+	// other events of the session wait for the handler: use
+	// Session().CallAsync for functions that may take long, like
+	// navigator.share waiting for the user.
+	//
+	// Example:
+	//     r, err := ctx.Call(hid, "rect")
+	//     var rect struct{ Width, Height float64 }
+	//     err = r.Decode(&rect)
+	Call(hid, name string, args ...any) (Result, error)
+
+	// Nonce returns the Content-Security-Policy nonce of the page request.
+	// Pass it as render.PageData.Nonce so inline scripts are allowed.
+	// Returns "" for live session events, which render no inline scripts.
+	Nonce() string
+
 	// ==========================================================================
 	// Context propagation (Phase 13: Production Hardening)
 	// ==========================================================================
@@ -158,6 +188,57 @@ func newCtx(w http.ResponseWriter, r *http.Request, logger *slog.Logger) *ctx {
 	}
 }
 
+// NewRequestContext creates a context for rendering r outside the server,
+// such as when exporting pages to static HTML. Params are the route
+// parameters; the context has no session.
+func NewRequestContext(w http.ResponseWriter, r *http.Request, params map[string]string) Ctx {
+	c := newCtx(w, r, slog.Default())
+	for k, v := range params {
+		c.params[k] = v
+	}
+	return c
+}
+
+// NewSessionContext creates a context for rendering a route in a live
+// session, outside of an event. r describes the page being shown; response
+// methods such as SetHeader have no effect.
+func NewSessionContext(s *Session, r *http.Request, params map[string]string) Ctx {
+	c := newCtx(discardWriter{}, r, slog.Default())
+	c.session = s
+	if s != nil && s.logger != nil {
+		c.logger = s.logger
+	}
+	for k, v := range params {
+		c.params[k] = v
+	}
+	return c
+}
+
+// newEventCtx creates the context of a live event. Its request describes
+// the page the session shows, with the headers and cookies of the request
+// that opened the session; response methods have no effect.
+func newEventCtx(s *Session, e *Event) *ctx {
+	if s == nil {
+		r := &http.Request{Method: http.MethodGet, URL: &url.URL{Path: "/"}, Header: make(http.Header)}
+		c := newCtx(discardWriter{}, r, slog.Default())
+		c.event = e
+		return c
+	}
+
+	c := newCtx(discardWriter{}, s.pageRequest(), s.logger)
+	c.session = s
+	c.user = s.user
+	c.event = e
+	return c
+}
+
+// discardWriter is the response writer of a live session context.
+type discardWriter struct{}
+
+func (discardWriter) Header() http.Header         { return http.Header{} }
+func (discardWriter) Write(b []byte) (int, error) { return len(b), nil }
+func (discardWriter) WriteHeader(int)             {}
+
 // Request returns the underlying HTTP request.
 func (c *ctx) Request() *http.Request {
 	return c.request
@@ -229,6 +310,17 @@ func (c *ctx) SetUser(user any) {
 	c.user = user
 }
 
+// ClientInfo returns the browser environment of the session or request.
+func (c *ctx) ClientInfo() ClientInfo {
+	if c.session != nil {
+		return c.session.ClientInfo()
+	}
+	if c.request != nil {
+		return ClientInfoFromRequest(c.request)
+	}
+	return ClientInfo{}
+}
+
 // Logger returns the request-scoped logger.
 func (c *ctx) Logger() *slog.Logger {
 	return c.logger
@@ -304,17 +396,58 @@ func (c *ctx) Value(key any) any {
 // =============================================================================
 
 // Emit dispatches a custom event to the client.
-// The event will be dispatched as a CustomEvent with the given name and detail.
-// Use this for notifications, toast messages, analytics, etc.
-//
-// Note: This is a placeholder implementation. The actual emission happens
-// via the protocol layer which sends a custom event patch to the client.
+// The event is dispatched as a bubbling CustomEvent on the element of the
+// current event, or on the root element of the page, with data encoded as
+// JSON in its detail. The event is sent after the patches of the render
+// that follows, so listeners see the DOM it describes. Emit has no effect
+// outside a live session.
 func (c *ctx) Emit(name string, data any) {
-	// TODO: Implement via protocol layer
-	// For now, log that we would emit
-	if c.logger != nil {
-		c.logger.Debug("emit custom event", "name", name, "data", data)
+	s := c.session
+	if s == nil || s.conn == nil {
+		if c.logger != nil {
+			c.logger.Debug("emit without a live session", "name", name)
+		}
+		return
+	}
+
+	hid := ""
+	if c.event != nil {
+		hid = c.event.HID
+	}
+	if hid == "" {
+		hid = s.rootHID()
+	}
+	if hid == "" {
+		c.logger.Warn("emit: no element to dispatch on", "name", name)
+		return
+	}
+
+	var detail []byte
+	if data != nil {
+		var err error
+		if detail, err = json.Marshal(data); err != nil {
+			c.logger.Error("emit: cannot encode detail", "name", name, "error", err)
+			return
+		}
+	}
+	s.queueEmit(protocol.NewDispatchPatch(hid, name, string(detail)))
+}
+
+// Call calls a client function and waits for its result.
+func (c *ctx) Call(hid, name string, args ...any) (Result, error) {
+	s := c.session
+	if s == nil {
+		return Result{}, ErrCallsUnsupported
+	}
+	return s.CallAsync(hid, name, args...).Wait(s.Context())
+}
+
+// Nonce returns the CSP nonce of the request.
+func (c *ctx) Nonce() string {
+	if c.request == nil {
+		return ""
 	}
+	return NonceFromContext(c.request.Context())
 }
 
 // =============================================================================
@@ -366,6 +499,33 @@ func (c *ctx) AddPatchCount(count int) {
 	c.patchCount += count
 }
 
+// =============================================================================
+// Context Bridge
+// =============================================================================
+
+// requestContextKey is the context key for the upgrade request.
+type requestContextKey struct{}
+
+// RequestFromContext returns the HTTP request that started the session.
+// It is available on the httpCtx passed to OnSessionStart, where it gives
+// access to headers and cookies such as Accept-Language.
+// Returns nil if ctx does not carry a request.
+//
+// Example:
+//
+//	OnSessionStart: func(httpCtx context.Context, session *vango.Session) {
+//	    if r := server.RequestFromContext(httpCtx); r != nil {
+//	        session.Set("theme", themeFromCookie(r))
+//	    }
+//	}
+func RequestFromContext(ctx context.Context) *http.Request {
+	if ctx == nil {
+		return nil
+	}
+	r, _ := ctx.Value(requestContextKey{}).(*http.Request)
+	return r
+}
+
 // =============================================================================
 // Test Helpers (Phase 10F)
 // =============================================================================
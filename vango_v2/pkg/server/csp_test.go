@@ -0,0 +1,123 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCSPPolicy(t *testing.T) {
+	csp := &CSPConfig{
+		Strict: true,
+		Directives: map[string]string{
+			"img-src":    "'self' https://cdn.example.com",
+			"object-src": "",
+			"report-uri": "/csp-report",
+		},
+	}
+
+	policy := csp.Policy("n0nce")
+
+	wants := []string{
+		"script-src 'self' 'nonce-n0nce'",
+		"style-src 'self' 'nonce-n0nce'",
+		"img-src 'self' https://cdn.example.com",
+		"report-uri /csp-report",
+	}
+	for _, want := range wants {
+		if !strings.Contains(policy, want) {
+			t.Errorf("Policy() = %q, should contain %q", policy, want)
+		}
+	}
+	if strings.Contains(policy, "object-src") {
+		t.Errorf("empty directive should be removed, got %q", policy)
+	}
+	if !strings.HasSuffix(policy, "report-uri /csp-report") {
+		t.Errorf("extra directives should follow the defaults, got %q", policy)
+	}
+
+	if csp.HeaderName() != "Content-Security-Policy" {
+		t.Errorf("HeaderName() = %q", csp.HeaderName())
+	}
+	csp.ReportOnly = true
+	if csp.HeaderName() != "Content-Security-Policy-Report-Only" {
+		t.Errorf("HeaderName() = %q", csp.HeaderName())
+	}
+}
+
+func TestServerCSPNonce(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     *ServerConfig
+		wantHeader bool
+	}{
+		{"default", DefaultServerConfig(), false},
+		{"strict", DefaultServerConfig().WithStrictCSP(), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(tt.config)
+			defer s.sessions.Shutdown()
+
+			var nonce string
+			var strict bool
+			s.SetHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				nonce = NonceFromContext(r.Context())
+				strict = StrictCSPFromContext(r.Context())
+			}))
+
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+			if nonce == "" {
+				t.Fatal("page request should carry a nonce")
+			}
+			if strict != tt.wantHeader {
+				t.Errorf("StrictCSPFromContext() = %v, want %v", strict, tt.wantHeader)
+			}
+			header := rec.Header().Get("Content-Security-Policy")
+			if tt.wantHeader {
+				if !strings.Contains(header, "'nonce-"+nonce+"'") {
+					t.Errorf("header %q should contain the request nonce %q", header, nonce)
+				}
+			} else if header != "" {
+				t.Errorf("no policy configured, got header %q", header)
+			}
+		})
+	}
+}
+
+func TestServerCSPReportOnlyNotStrict(t *testing.T) {
+	config := DefaultServerConfig().WithStrictCSP()
+	config.CSP.ReportOnly = true
+	s := New(config)
+	defer s.sessions.Shutdown()
+
+	var strict bool
+	s.SetHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		strict = StrictCSPFromContext(r.Context())
+	}))
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	if rec.Header().Get("Content-Security-Policy-Report-Only") == "" {
+		t.Error("report-only policy header missing")
+	}
+	if strict {
+		t.Error("a report-only policy should not make rendering strict")
+	}
+}
+
+func TestGenerateNonceUnique(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		n := GenerateNonce()
+		if seen[n] {
+			t.Fatalf("duplicate nonce %q", n)
+		}
+		seen[n] = true
+	}
+}
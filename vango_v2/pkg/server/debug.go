@@ -0,0 +1,532 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vango-dev/vango/v2/pkg/protocol"
+	"github.com/vango-dev/vango/v2/pkg/vdom"
+)
+
+// =============================================================================
+// Session Inspector
+// =============================================================================
+
+// DebugPath is the path of the session inspector.
+//
+//	/_vango/debug                    → HTML list of sessions
+//	/_vango/debug/sessions/{id}      → HTML view of a session
+//	/_vango/debug/api/sessions       → JSON list of sessions
+//	/_vango/debug/api/sessions/{id}  → JSON snapshot of a session
+const DebugPath = "/_vango/debug"
+
+// DebugConfig enables the session inspector at DebugPath. The inspector
+// shows the live state of sessions: component tree, handlers, signal values,
+// event queue, recent events and patches, and memory.
+//
+// The inspector exposes user data, so access must be granted explicitly:
+//
+//	Debug: &server.DebugConfig{
+//	    Authorize: func(r *http.Request) bool {
+//	        user := auth.UserFromContext(r.Context())
+//	        return user != nil && user.IsAdmin
+//	    },
+//	}
+type DebugConfig struct {
+	// Authorize reports whether a request may use the inspector.
+	// If nil, the inspector is only served in DevMode.
+	Authorize func(r *http.Request) bool
+
+	// History is the number of recent events and patches kept per session.
+	// Default: 50.
+	History int
+
+	// Timeout is how long a request waits for a busy session.
+	// Default: 2 seconds.
+	Timeout time.Duration
+}
+
+// history returns the number of recorded events and patches.
+func (c *DebugConfig) history() int {
+	if c.History > 0 {
+		return c.History
+	}
+	return 50
+}
+
+// timeout returns the time to wait for a session snapshot.
+func (c *DebugConfig) timeout() time.Duration {
+	if c.Timeout > 0 {
+		return c.Timeout
+	}
+	return 2 * time.Second
+}
+
+// EventRecord is an event handled by a session.
+type EventRecord struct {
+	Seq      uint64    `json:"seq"`
+	Type     string    `json:"type"`
+	HID      string    `json:"hid"`
+	Time     time.Time `json:"time"`
+	Duration int64     `json:"duration_us"`
+	Handled  bool      `json:"handled"`
+}
+
+// PatchRecord is a batch of patches sent by a session.
+type PatchRecord struct {
+	Seq   uint64    `json:"seq"`
+	Time  time.Time `json:"time"`
+	Count int       `json:"count"`
+	Bytes int       `json:"bytes"`
+	Ops   []string  `json:"ops"`
+}
+
+// maxRecordedOps is the number of patch operations described per batch.
+const maxRecordedOps = 20
+
+// sessionTrace keeps the recent events and patches of a session.
+type sessionTrace struct {
+	mu      sync.Mutex
+	size    int
+	events  []EventRecord
+	patches []PatchRecord
+}
+
+// newSessionTrace creates a trace keeping size events and patches.
+func newSessionTrace(size int) *sessionTrace {
+	return &sessionTrace{size: size}
+}
+
+// recordEvent records a handled event; start is when handling began.
+func (t *sessionTrace) recordEvent(event *Event, handled bool, start time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.events = appendBounded(t.events, EventRecord{
+		Seq:      event.Seq,
+		Type:     event.Type.String(),
+		HID:      event.HID,
+		Time:     start,
+		Duration: time.Since(start).Microseconds(),
+		Handled:  handled,
+	}, t.size)
+}
+
+// recordPatches records a sent batch of patches.
+func (t *sessionTrace) recordPatches(seq uint64, patches []protocol.Patch, bytes int) {
+	record := PatchRecord{
+		Seq:   seq,
+		Time:  time.Now(),
+		Count: len(patches),
+		Bytes: bytes,
+	}
+	for i, p := range patches {
+		if i == maxRecordedOps {
+			record.Ops = append(record.Ops, fmt.Sprintf("... %d more", len(patches)-i))
+			break
+		}
+		record.Ops = append(record.Ops, describePatch(p))
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.patches = appendBounded(t.patches, record, t.size)
+}
+
+// recent returns copies of the recorded events and patches.
+func (t *sessionTrace) recent() ([]EventRecord, []PatchRecord) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]EventRecord(nil), t.events...), append([]PatchRecord(nil), t.patches...)
+}
+
+// appendBounded appends v, dropping the oldest values beyond size.
+func appendBounded[T any](values []T, v T, size int) []T {
+	values = append(values, v)
+	if len(values) > size {
+		values = append(values[:0], values[len(values)-size:]...)
+	}
+	return values
+}
+
+// describePatch formats a patch for the inspector.
+func describePatch(p protocol.Patch) string {
+	desc := p.Op.String() + " " + p.HID
+	if p.Key != "" {
+		desc += " " + p.Key
+	}
+	if p.Value != "" {
+		desc += " = " + truncate(p.Value, 40)
+	}
+	return desc
+}
+
+// SessionSummary is the overview of a session in the inspector.
+type SessionSummary struct {
+	ID             string    `json:"id"`
+	UserID         string    `json:"user_id,omitempty"`
+	Route          string    `json:"route,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	LastActive     time.Time `json:"last_active"`
+	EventCount     uint64    `json:"event_count"`
+	PatchCount     uint64    `json:"patch_count"`
+	BytesSent      uint64    `json:"bytes_sent"`
+	BytesRecv      uint64    `json:"bytes_recv"`
+	EventQueue     int       `json:"event_queue"`
+	EventQueueCap  int       `json:"event_queue_cap"`
+	ComponentCount int       `json:"component_count"`
+	HandlerCount   int       `json:"handler_count"`
+	Memory         int64     `json:"memory"`
+
+	// Busy reports that the session did not answer in time; only the
+	// counters are set, not the route, last activity and sizes, which
+	// belong to the event loop.
+	Busy bool `json:"busy,omitempty"`
+}
+
+// SessionSnapshot is the state of a session in the inspector.
+type SessionSnapshot struct {
+	SessionSummary
+
+	Tree          *ComponentSnapshot `json:"tree"`
+	Handlers      []HandlerSnapshot  `json:"handlers"`
+	RecentEvents  []EventRecord      `json:"recent_events"`
+	RecentPatches []PatchRecord      `json:"recent_patches"`
+}
+
+// ComponentSnapshot is a mounted component instance.
+type ComponentSnapshot struct {
+	ID       string               `json:"id"`
+	Type     string               `json:"type"`
+	HID      string               `json:"hid,omitempty"`
+	Dirty    bool                 `json:"dirty,omitempty"`
+	Signals  []SignalSnapshot     `json:"signals,omitempty"`
+	Children []*ComponentSnapshot `json:"children,omitempty"`
+}
+
+// SignalSnapshot is a signal read by a component's last render.
+type SignalSnapshot struct {
+	ID    uint64 `json:"id"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// HandlerSnapshot is an element with event handlers.
+type HandlerSnapshot struct {
+	HID       string   `json:"hid"`
+	Tag       string   `json:"tag"`
+	Events    []string `json:"events"`
+	Component string   `json:"component"`
+}
+
+// maxValueLength bounds the formatted signal values.
+const maxValueLength = 200
+
+// Inspect returns a snapshot of the session. The snapshot is taken on the
+// session's event loop, which only reads the session for it: no effects
+// run and nothing is sent to the client. Inspect waits for it until ctx is
+// done.
+func (s *Session) Inspect(ctx context.Context) (*SessionSnapshot, error) {
+	if s.closed.Load() {
+		return nil, ErrSessionClosed
+	}
+
+	result := make(chan *SessionSnapshot, 1)
+	fn := func() { result <- s.snapshot() }
+	select {
+	case s.inspectCh <- fn:
+	case <-s.done:
+		return nil, ErrSessionClosed
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	select {
+	case snap := <-result:
+		return snap, nil
+	case <-s.done:
+		return nil, ErrSessionClosed
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// counters returns the summary fields of the session that are safe to
+// read from any goroutine.
+func (s *Session) counters() SessionSummary {
+	return SessionSummary{
+		ID:            s.ID,
+		UserID:        s.UserID,
+		CreatedAt:     s.CreatedAt,
+		EventCount:    s.eventCount.Load(),
+		PatchCount:    s.patchCount.Load(),
+		BytesSent:     s.bytesSent.Load(),
+		BytesRecv:     s.bytesRecv.Load(),
+		EventQueue:    len(s.events),
+		EventQueueCap: cap(s.events),
+	}
+}
+
+// snapshot captures the session state. It must run on the event loop.
+func (s *Session) snapshot() *SessionSnapshot {
+	snap := &SessionSnapshot{SessionSummary: s.counters()}
+	snap.Route = s.CurrentRoute
+	snap.LastActive = s.LastActive
+	snap.ComponentCount = len(s.components)
+	snap.HandlerCount = len(s.handlers)
+	snap.Memory = s.MemoryUsage()
+
+	if s.root != nil {
+		snap.Tree = snapshotComponent(s.root)
+		snap.Handlers = s.snapshotHandlers(s.root, nil)
+		sort.Slice(snap.Handlers, func(i, j int) bool {
+			return snap.Handlers[i].HID < snap.Handlers[j].HID
+		})
+	}
+	if s.trace != nil {
+		snap.RecentEvents, snap.RecentPatches = s.trace.recent()
+	}
+	return snap
+}
+
+// snapshotComponent captures a component instance and its children.
+func snapshotComponent(c *ComponentInstance) *ComponentSnapshot {
+	snap := &ComponentSnapshot{
+		ID:    c.InstanceID,
+		Type:  fmt.Sprintf("%T", c.Component),
+		HID:   c.HID,
+		Dirty: c.IsDirty(),
+	}
+	for _, sig := range c.signals {
+		value := sig.GetAny()
+		snap.Signals = append(snap.Signals, SignalSnapshot{
+			ID:    sig.SignalID(),
+			Type:  fmt.Sprintf("%T", value),
+			Value: truncate(fmt.Sprintf("%+v", value), maxValueLength),
+		})
+	}
+	for _, child := range c.Children {
+		snap.Children = append(snap.Children, snapshotComponent(child))
+	}
+	return snap
+}
+
+// snapshotHandlers appends the elements with handlers rendered by c and
+// its children.
+func (s *Session) snapshotHandlers(c *ComponentInstance, handlers []HandlerSnapshot) []HandlerSnapshot {
+	var walk func(node *vdom.VNode)
+	walk = func(node *vdom.VNode) {
+		if node == nil || node.Kind == vdom.KindComponent {
+			return
+		}
+		if _, ok := s.handlers[node.HID]; ok && node.HID != "" {
+			h := HandlerSnapshot{HID: node.HID, Tag: node.Tag, Component: c.InstanceID}
+			for key, value := range node.Props {
+				if strings.HasPrefix(key, "on") && value != nil {
+					h.Events = append(h.Events, key)
+				}
+			}
+			sort.Strings(h.Events)
+			handlers = append(handlers, h)
+		}
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	walk(c.LastTree())
+
+	for _, child := range c.Children {
+		handlers = s.snapshotHandlers(child, handlers)
+	}
+	return handlers
+}
+
+// truncate shortens s to at most n bytes.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "…"
+}
+
+// =============================================================================
+// Inspector HTTP Handler
+// =============================================================================
+
+// DebugHandler returns the handler of the session inspector, for mounting
+// at DebugPath in an external router. It responds 404 unless
+// ServerConfig.Debug is set.
+func (s *Server) DebugHandler() http.Handler {
+	return http.HandlerFunc(s.serveDebug)
+}
+
+// isDebugPath reports whether path belongs to the session inspector.
+func isDebugPath(path string) bool {
+	return path == DebugPath || strings.HasPrefix(path, DebugPath+"/")
+}
+
+// serveDebug serves the session inspector.
+func (s *Server) serveDebug(w http.ResponseWriter, r *http.Request) {
+	cfg := s.config.Debug
+	if cfg == nil {
+		http.NotFound(w, r)
+		return
+	}
+	if cfg.Authorize == nil && !s.config.DevMode || cfg.Authorize != nil && !cfg.Authorize(r) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+
+	ctx, cancel := context.WithTimeout(r.Context(), cfg.timeout())
+	defer cancel()
+
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, DebugPath), "/")
+	switch {
+	case path == "":
+		s.renderDebug(w, debugIndexTemplate, s.inspectSessions(ctx))
+	case path == "api/sessions":
+		writeDebugJSON(w, s.inspectSessions(ctx))
+	case strings.HasPrefix(path, "sessions/"), strings.HasPrefix(path, "api/sessions/"):
+		id := path[strings.LastIndex(path, "/")+1:]
+		session := s.sessions.Get(id)
+		if session == nil {
+			http.NotFound(w, r)
+			return
+		}
+		snap, err := session.Inspect(ctx)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		if strings.HasPrefix(path, "api/") {
+			writeDebugJSON(w, snap)
+		} else {
+			s.renderDebug(w, debugSessionTemplate, snap)
+		}
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// inspectSessions returns the summaries of all sessions, newest first.
+// Sessions are inspected concurrently; busy sessions report counters only.
+func (s *Server) inspectSessions(ctx context.Context) []SessionSummary {
+	var sessions []*Session
+	s.sessions.ForEach(func(session *Session) bool {
+		sessions = append(sessions, session)
+		return true
+	})
+
+	summaries := make([]SessionSummary, len(sessions))
+	var wg sync.WaitGroup
+	for i, session := range sessions {
+		wg.Add(1)
+		go func(i int, session *Session) {
+			defer wg.Done()
+			if snap, err := session.Inspect(ctx); err == nil {
+				summaries[i] = snap.SessionSummary
+				return
+			}
+			summaries[i] = session.counters()
+			summaries[i].Busy = true
+		}(i, session)
+	}
+	wg.Wait()
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].CreatedAt.After(summaries[j].CreatedAt)
+	})
+	return summaries
+}
+
+// writeDebugJSON writes v as indented JSON.
+func writeDebugJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+// renderDebug renders an inspector page.
+func (s *Server) renderDebug(w http.ResponseWriter, tmpl *template.Template, data any) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := tmpl.Execute(w, data); err != nil {
+		s.logger.Error("debug page render failed", "error", err)
+	}
+}
+
+// debugStyle is the stylesheet of the inspector pages.
+const debugStyle = `<style>
+body { font: 14px/1.4 system-ui, sans-serif; margin: 2rem; color: #222; }
+table { border-collapse: collapse; margin-bottom: 1.5rem; }
+th, td { border: 1px solid #ddd; padding: 4px 8px; text-align: left; vertical-align: top; }
+th { background: #f5f5f5; }
+code, pre { font: 12px ui-monospace, monospace; }
+ul.tree { list-style: none; padding-left: 1.25rem; border-left: 1px dashed #ccc; }
+.busy { color: #b00; }
+</style>`
+
+var debugIndexTemplate = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html><head><title>Vango sessions</title>` + debugStyle + `</head><body>
+<h1>Sessions ({{len .}})</h1>
+<p><a href="` + DebugPath + `/api/sessions">JSON</a></p>
+<table>
+<tr><th>Session</th><th>User</th><th>Route</th><th>Created</th><th>Last active</th><th>Events</th><th>Queue</th><th>Components</th><th>Handlers</th><th>Memory</th></tr>
+{{range .}}<tr>
+<td><a href="` + DebugPath + `/sessions/{{.ID}}"><code>{{.ID}}</code></a>{{if .Busy}} <span class="busy">busy</span>{{end}}</td>
+<td>{{.UserID}}</td><td>{{.Route}}</td>
+<td>{{.CreatedAt.Format "15:04:05"}}</td><td>{{.LastActive.Format "15:04:05"}}</td>
+<td>{{.EventCount}}</td><td>{{.EventQueue}}/{{.EventQueueCap}}</td>
+<td>{{.ComponentCount}}</td><td>{{.HandlerCount}}</td><td>{{.Memory}} B</td>
+</tr>{{end}}
+</table>
+</body></html>`))
+
+var debugSessionTemplate = template.Must(template.New("session").Parse(`{{define "component"}}<li>
+<code>{{.ID}}</code> {{.Type}}{{if .HID}} <code>{{.HID}}</code>{{end}}{{if .Dirty}} <em>dirty</em>{{end}}
+{{if .Signals}}<ul>{{range .Signals}}<li>signal #{{.ID}} <code>{{.Type}}</code> = <code>{{.Value}}</code></li>{{end}}</ul>{{end}}
+{{if .Children}}<ul class="tree">{{range .Children}}{{template "component" .}}{{end}}</ul>{{end}}
+</li>{{end}}<!DOCTYPE html>
+<html><head><title>Session {{.ID}}</title>` + debugStyle + `</head><body>
+<p><a href="` + DebugPath + `">All sessions</a> · <a href="` + DebugPath + `/api/sessions/{{.ID}}">JSON</a></p>
+<h1>Session <code>{{.ID}}</code></h1>
+<table>
+<tr><th>User</th><td>{{.UserID}}</td></tr>
+<tr><th>Route</th><td>{{.Route}}</td></tr>
+<tr><th>Created</th><td>{{.CreatedAt}}</td></tr>
+<tr><th>Last active</th><td>{{.LastActive}}</td></tr>
+<tr><th>Events / patches</th><td>{{.EventCount}} / {{.PatchCount}}</td></tr>
+<tr><th>Event queue</th><td>{{.EventQueue}}/{{.EventQueueCap}}</td></tr>
+<tr><th>Bytes sent / received</th><td>{{.BytesSent}} / {{.BytesRecv}}</td></tr>
+<tr><th>Memory</th><td>{{.Memory}} B</td></tr>
+</table>
+<h2>Component tree</h2>
+{{if .Tree}}<ul class="tree">{{template "component" .Tree}}</ul>{{else}}<p>No root component.</p>{{end}}
+<h2>Handlers ({{len .Handlers}})</h2>
+<table>
+<tr><th>HID</th><th>Element</th><th>Events</th><th>Component</th></tr>
+{{range .Handlers}}<tr><td><code>{{.HID}}</code></td><td>&lt;{{.Tag}}&gt;</td><td>{{range .Events}}{{.}} {{end}}</td><td><code>{{.Component}}</code></td></tr>{{end}}
+</table>
+<h2>Recent events</h2>
+<table>
+<tr><th>Seq</th><th>Time</th><th>Type</th><th>HID</th><th>Handled</th><th>Duration</th></tr>
+{{range .RecentEvents}}<tr><td>{{.Seq}}</td><td>{{.Time.Format "15:04:05.000"}}</td><td>{{.Type}}</td><td><code>{{.HID}}</code></td><td>{{.Handled}}</td><td>{{.Duration}} µs</td></tr>{{end}}
+</table>
+<h2>Recent patches</h2>
+<table>
+<tr><th>Seq</th><th>Time</th><th>Count</th><th>Bytes</th><th>Operations</th></tr>
+{{range .RecentPatches}}<tr><td>{{.Seq}}</td><td>{{.Time.Format "15:04:05.000"}}</td><td>{{.Count}}</td><td>{{.Bytes}}</td><td><pre>{{range .Ops}}{{.}}
+{{end}}</pre></td></tr>{{end}}
+</table>
+</body></html>`))
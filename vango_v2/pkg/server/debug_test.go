@@ -0,0 +1,246 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vango-dev/vango/v2/pkg/protocol"
+	"github.com/vango-dev/vango/v2/pkg/vango"
+	"github.com/vango-dev/vango/v2/pkg/vdom"
+)
+
+// inspectedSession returns a running session with a counter component.
+func inspectedSession(t *testing.T) *Session {
+	t.Helper()
+
+	s := NewMockSession()
+	s.trace = newSessionTrace(2)
+
+	count := vango.NewSignal(3)
+	counter := FuncComponent(func() *vdom.VNode {
+		return vdom.Div(
+			vdom.Text("count"),
+			vdom.Button(vdom.OnClick(func() {}), vdom.Text(string(rune('0'+count.Get())))),
+		)
+	})
+	s.MountRoot(FuncComponent(func() *vdom.VNode {
+		return vdom.Div(counter)
+	}))
+
+	go s.EventLoop()
+	t.Cleanup(func() { close(s.done) })
+	return s
+}
+
+func TestSessionInspect(t *testing.T) {
+	s := inspectedSession(t)
+
+	var hid string
+	for h := range s.handlers {
+		hid = h
+	}
+	for i := 0; i < 3; i++ {
+		s.QueueEvent(&Event{Seq: uint64(i + 1), Type: protocol.EventClick, HID: hid})
+	}
+	// The loop picks ready channels at random; wait for the events first
+	deadline := time.Now().Add(time.Second)
+	for s.eventCount.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	snap, err := s.Inspect(context.Background())
+	if err != nil {
+		t.Fatalf("Inspect() error: %v", err)
+	}
+
+	if snap.ID != s.ID || snap.ComponentCount != 1 || snap.HandlerCount != 1 || snap.Memory == 0 {
+		t.Errorf("summary = %+v", snap.SessionSummary)
+	}
+	if snap.Tree == nil || snap.Tree.ID != "root" || len(snap.Tree.Children) != 1 {
+		t.Fatalf("Tree = %+v, want root with one child", snap.Tree)
+	}
+	signals := snap.Tree.Children[0].Signals
+	if len(signals) != 1 || signals[0].Value != "3" || signals[0].Type != "int" {
+		t.Errorf("child signals = %+v, want the count signal", signals)
+	}
+
+	if len(snap.Handlers) != 1 || snap.Handlers[0].HID != hid || snap.Handlers[0].Tag != "button" {
+		t.Errorf("Handlers = %+v", snap.Handlers)
+	}
+	if snap.Handlers[0].Component != snap.Tree.Children[0].ID {
+		t.Errorf("handler component = %q, want %q", snap.Handlers[0].Component, snap.Tree.Children[0].ID)
+	}
+
+	// Only the last two events are kept
+	if len(snap.RecentEvents) != 2 {
+		t.Fatalf("RecentEvents = %+v, want 2", snap.RecentEvents)
+	}
+	for i, e := range snap.RecentEvents {
+		if e.Seq != uint64(i+2) || !e.Handled || e.HID != hid || e.Type != "Click" {
+			t.Errorf("RecentEvents[%d] = %+v, want handled click seq %d", i, e, i+2)
+		}
+	}
+}
+
+func TestSessionInspectReadOnly(t *testing.T) {
+	s := NewMockSession()
+	s.MountRoot(FuncComponent(func() *vdom.VNode { return vdom.Div(vdom.Text("idle")) }))
+
+	// A pending render the inspector must leave to the session
+	s.root.dirty.Store(true)
+	go s.EventLoop()
+	defer close(s.done)
+
+	if _, err := s.Inspect(context.Background()); err != nil {
+		t.Fatalf("Inspect() error: %v", err)
+	}
+	if !s.root.IsDirty() {
+		t.Error("Inspect() rendered the session")
+	}
+}
+
+func TestSessionInspectTimeout(t *testing.T) {
+	s := NewMockSession() // No event loop
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := s.Inspect(ctx); err == nil {
+		t.Error("Inspect() should fail when the session does not answer")
+	}
+
+	s.closed.Store(true)
+	if _, err := s.Inspect(context.Background()); err != ErrSessionClosed {
+		t.Errorf("Inspect() error = %v, want ErrSessionClosed", err)
+	}
+}
+
+func TestInspectSessionsBusy(t *testing.T) {
+	srv := New(&ServerConfig{CSRFSecret: []byte("secret")})
+	defer srv.sessions.Shutdown()
+	s := NewMockSession() // No event loop: the session is busy
+	s.eventCount.Store(4)
+	srv.sessions.mu.Lock()
+	srv.sessions.sessions[s.ID] = s
+	srv.sessions.mu.Unlock()
+
+	// The event loop owns the route and last activity
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				s.CurrentRoute = "/busy"
+				s.LastActive = time.Now()
+			}
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	summaries := srv.inspectSessions(ctx)
+	close(stop)
+	<-done
+
+	if len(summaries) != 1 || !summaries[0].Busy {
+		t.Fatalf("summaries = %+v, want one busy session", summaries)
+	}
+	if sum := summaries[0]; sum.ID != s.ID || sum.EventCount != 4 || sum.Route != "" || !sum.LastActive.IsZero() {
+		t.Errorf("busy summary = %+v, want counters only", sum)
+	}
+}
+
+func TestSessionTrace(t *testing.T) {
+	trace := newSessionTrace(10)
+	trace.recordEvent(&Event{Seq: 1, Type: protocol.EventClick, HID: "missing"}, false, time.Now())
+	if events, _ := trace.recent(); len(events) != 1 || events[0].Handled {
+		t.Errorf("recent events = %+v, want an unhandled event", events)
+	}
+
+	patches := make([]protocol.Patch, maxRecordedOps+5)
+	for i := range patches {
+		patches[i] = protocol.Patch{Op: protocol.PatchSetText, HID: "h1", Value: "hello"}
+	}
+	trace.recordPatches(7, patches, 120)
+
+	_, recent := trace.recent()
+	if len(recent) != 1 || recent[0].Seq != 7 || recent[0].Count != len(patches) || recent[0].Bytes != 120 {
+		t.Fatalf("recent patches = %+v", recent)
+	}
+	ops := recent[0].Ops
+	if len(ops) != maxRecordedOps+1 || ops[len(ops)-1] != "... 5 more" {
+		t.Errorf("Ops = %v, want %d descriptions and a remainder", ops, maxRecordedOps)
+	}
+	if !strings.Contains(ops[0], "h1") || !strings.Contains(ops[0], "hello") {
+		t.Errorf("Ops[0] = %q", ops[0])
+	}
+}
+
+func TestDebugHandler(t *testing.T) {
+	srv := New(&ServerConfig{
+		CSRFSecret: []byte("secret"),
+		Debug: &DebugConfig{
+			Authorize: func(r *http.Request) bool { return r.Header.Get("X-Admin") == "yes" },
+		},
+	})
+	s := inspectedSession(t)
+	srv.sessions.mu.Lock()
+	srv.sessions.sessions[s.ID] = s
+	srv.sessions.mu.Unlock()
+
+	get := func(path string, admin bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		if admin {
+			req.Header.Set("X-Admin", "yes")
+		}
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := get(DebugPath, false); rec.Code != http.StatusForbidden {
+		t.Errorf("unauthorized status = %d, want 403", rec.Code)
+	}
+
+	rec := get(DebugPath+"/api/sessions", true)
+	var summaries []SessionSummary
+	if err := json.NewDecoder(rec.Body).Decode(&summaries); err != nil {
+		t.Fatalf("decode sessions: %v", err)
+	}
+	if len(summaries) != 1 || summaries[0].ID != s.ID || summaries[0].HandlerCount != 1 {
+		t.Errorf("sessions = %+v", summaries)
+	}
+
+	rec = get(DebugPath+"/api/sessions/"+s.ID, true)
+	var snap SessionSnapshot
+	if err := json.NewDecoder(rec.Body).Decode(&snap); err != nil {
+		t.Fatalf("decode session: %v", err)
+	}
+	if snap.Tree == nil || len(snap.Handlers) != 1 {
+		t.Errorf("snapshot = %+v", snap)
+	}
+
+	rec = get(DebugPath+"/sessions/"+s.ID, true)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "Component tree") {
+		t.Errorf("session page status = %d", rec.Code)
+	}
+	if rec := get(DebugPath+"/api/sessions/unknown", true); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown session status = %d, want 404", rec.Code)
+	}
+
+	// Without the config the inspector is not served
+	plain := New(&ServerConfig{CSRFSecret: []byte("secret")})
+	rec = httptest.NewRecorder()
+	plain.ServeHTTP(rec, httptest.NewRequest("GET", DebugPath, nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("disabled inspector status = %d, want 404", rec.Code)
+	}
+}
@@ -32,12 +32,29 @@
 // When a client sends an event:
 //  1. ReadLoop decodes the binary event frame
 //  2. Event is queued for the EventLoop
-//  3. Handler is found by HID and executed
+//  3. Handler is found by HID and executed through the event middleware
 //  4. Pending effects are run
 //  5. Dirty components are re-rendered
 //  6. Diff generates patches
 //  7. Patches are encoded and sent to client
 //
+// # Event Context
+//
+// Handlers may take a Ctx as their first parameter, e.g. func(Ctx),
+// func(Ctx, string) or func(Ctx, FormData). The Ctx of an event describes
+// the page the session shows, with the headers and cookies of the request
+// that opened the session, and its StdContext is canceled when the session
+// closes. Emit dispatches a DOM CustomEvent on the element of the event:
+//
+//	vdom.OnClick(func(ctx server.Ctx) {
+//	    save(ctx.StdContext())
+//	    ctx.Emit("toast", map[string]string{"message": "Saved"})
+//	})
+//
+// Middleware added with Server.UseEvents wraps every handler; it receives
+// the Ctx of the event and may reject it by returning an error.
+// router.Middleware values such as middleware.OpenTelemetry() can be used.
+//
 // # Example Usage
 //
 //	server := server.New(&server.ServerConfig{
@@ -57,6 +74,23 @@
 //
 //	server.Run()
 //
+// # Session Inspector
+//
+// Set ServerConfig.Debug to serve a session inspector at /_vango/debug.
+// It lists the live sessions and shows, for each, the component tree with
+// the signal values each component read, the elements with handlers, the
+// event queue, recent events and patches, and memory usage. The same data
+// is served as JSON under /_vango/debug/api/ for dashboards. Access is
+// granted by DebugConfig.Authorize.
+//
+// # Session Recording
+//
+// Set ServerConfig.RecordSession to record the messages of sessions, for
+// example to <dir>/<session ID>.vrec with RecordToDir. A recording holds
+// the handshake, the events received and the frames sent, with
+// timestamps; `vango replay` sends the events to a running app and reports
+// where its patches differ from the recorded ones.
+//
 // # Thread Safety
 //
 // The server package is designed for concurrent access:
@@ -31,6 +31,18 @@ type Event struct {
 
 	// Time is when the event was received by the server.
 	Time time.Time
+
+	// ctx is the context of the event, created on first use.
+	ctx *ctx
+}
+
+// Ctx returns the context of the event. It is bound to the session and the
+// page it shows, and its StdContext is canceled when the session closes.
+func (e *Event) Ctx() Ctx {
+	if e.ctx == nil {
+		e.ctx = newEventCtx(e.Session, e)
+	}
+	return e.ctx
 }
 
 // TypeString returns the string representation of the event type.
@@ -155,141 +167,239 @@ type NavigateEvent struct {
 	Replace bool
 }
 
+// EventMiddleware wraps the handling of live events, e.g. for tracing,
+// metrics or authorization. ctx is the context of the event; call next to
+// run the handler, or return an error to reject the event.
+// router.Middleware values satisfy EventMiddleware.
+type EventMiddleware interface {
+	Handle(ctx Ctx, next func() error) error
+}
+
 // wrapHandler converts a user-provided handler to the internal Handler type.
-// It supports various function signatures for different event types.
+// It supports various function signatures for different event types, each
+// with an optional leading Ctx parameter.
 func wrapHandler(value any) Handler {
 	switch h := value.(type) {
 	// Simple click handler - no arguments
 	case func():
 		return func(e *Event) { h() }
+	case func(Ctx):
+		return func(e *Event) { h(e.Ctx()) }
 
 	// Click handler with event
 	case func(*Event):
 		return h
+	case func(Ctx, *Event):
+		return func(e *Event) { h(e.Ctx(), e) }
 
 	// Input/Change handler - string value
 	case func(string):
-		return func(e *Event) {
-			if s, ok := e.Payload.(string); ok {
-				h(s)
-			}
-		}
+		return convertHandler(stringValue, h)
+	case func(Ctx, string):
+		return convertCtxHandler(stringValue, h)
 
 	// Mouse event handler
 	case func(MouseEvent):
-		return func(e *Event) {
-			if data, ok := e.Payload.(*protocol.MouseEventData); ok {
-				h(MouseEvent{
-					ClientX:  data.ClientX,
-					ClientY:  data.ClientY,
-					Button:   int(data.Button),
-					CtrlKey:  data.Modifiers.Has(protocol.ModCtrl),
-					ShiftKey: data.Modifiers.Has(protocol.ModShift),
-					AltKey:   data.Modifiers.Has(protocol.ModAlt),
-					MetaKey:  data.Modifiers.Has(protocol.ModMeta),
-				})
-			}
-		}
+		return convertHandler(mouseEvent, h)
+	case func(Ctx, MouseEvent):
+		return convertCtxHandler(mouseEvent, h)
 
 	// Keyboard event handler
 	case func(KeyboardEvent):
-		return func(e *Event) {
-			if data, ok := e.Payload.(*protocol.KeyboardEventData); ok {
-				h(KeyboardEvent{
-					Key:      data.Key,
-					CtrlKey:  data.Modifiers.Has(protocol.ModCtrl),
-					ShiftKey: data.Modifiers.Has(protocol.ModShift),
-					AltKey:   data.Modifiers.Has(protocol.ModAlt),
-					MetaKey:  data.Modifiers.Has(protocol.ModMeta),
-				})
-			}
-		}
+		return convertHandler(keyboardEvent, h)
+	case func(Ctx, KeyboardEvent):
+		return convertCtxHandler(keyboardEvent, h)
 
 	// Form submit handler
 	case func(FormData):
-		return func(e *Event) {
-			if data, ok := e.Payload.(*protocol.SubmitEventData); ok {
-				h(FormData{values: data.Fields})
-			}
-		}
+		return convertHandler(formData, h)
+	case func(Ctx, FormData):
+		return convertCtxHandler(formData, h)
 
 	// Hook event handler (internal server type)
 	case func(HookEvent):
-		return func(e *Event) {
-			if data, ok := e.Payload.(*protocol.HookEventData); ok {
-				h(HookEvent{Name: data.Name, Data: data.Data})
-			} else {
-				// Debug log for type mismatch
-				// fmt.Printf("[DEBUG] Hook handler payload type mismatch: %T\n", e.Payload)
-			}
-		}
+		return convertHandler(hookEvent, h)
+	case func(Ctx, HookEvent):
+		return convertCtxHandler(hookEvent, h)
 
 	// Hook event handler (public hooks package type)
 	case func(hooks.HookEvent):
-		return func(e *Event) {
-			if data, ok := e.Payload.(*protocol.HookEventData); ok {
-				h(hooks.HookEvent{Name: data.Name, Data: data.Data})
-			}
-		}
+		return convertHandler(publicHookEvent, h)
+	case func(Ctx, hooks.HookEvent):
+		return convertCtxHandler(publicHookEvent, h)
 
 	// Scroll event handler
 	case func(ScrollEvent):
-		return func(e *Event) {
-			if data, ok := e.Payload.(*protocol.ScrollEventData); ok {
-				h(ScrollEvent{
-					ScrollTop:  data.ScrollTop,
-					ScrollLeft: data.ScrollLeft,
-				})
-			}
-		}
+		return convertHandler(scrollEvent, h)
+	case func(Ctx, ScrollEvent):
+		return convertCtxHandler(scrollEvent, h)
 
 	// Resize event handler
 	case func(ResizeEvent):
-		return func(e *Event) {
-			if data, ok := e.Payload.(*protocol.ResizeEventData); ok {
-				h(ResizeEvent{
-					Width:  data.Width,
-					Height: data.Height,
-				})
-			}
-		}
+		return convertHandler(resizeEvent, h)
+	case func(Ctx, ResizeEvent):
+		return convertCtxHandler(resizeEvent, h)
 
 	// Touch event handler
 	case func(TouchEvent):
-		return func(e *Event) {
-			if data, ok := e.Payload.(*protocol.TouchEventData); ok {
-				touches := make([]TouchPoint, len(data.Touches))
-				for i, t := range data.Touches {
-					touches[i] = TouchPoint{
-						ID:      t.ID,
-						ClientX: t.ClientX,
-						ClientY: t.ClientY,
-					}
-				}
-				h(TouchEvent{Touches: touches})
-			}
-		}
+		return convertHandler(touchEvent, h)
+	case func(Ctx, TouchEvent):
+		return convertCtxHandler(touchEvent, h)
+
+	// Drop handler receiving a file from the client
+	case func(*Blob):
+		return convertHandler(blobValue, h)
+	case func(Ctx, *Blob):
+		return convertCtxHandler(blobValue, h)
 
 	// Navigate event handler
 	case func(NavigateEvent):
-		return func(e *Event) {
-			if data, ok := e.Payload.(*protocol.NavigateEventData); ok {
-				h(NavigateEvent{
-					Path:    data.Path,
-					Replace: data.Replace,
-				})
-			}
-		}
+		return convertHandler(navigateEvent, h)
+	case func(Ctx, NavigateEvent):
+		return convertCtxHandler(navigateEvent, h)
 
 	default:
 		// Unknown handler type - warn developer and return no-op handler
 		log.Printf("[WARN] wrapHandler: Unrecognized handler type %T. "+
 			"Handler will NOT be called. Supported types: func(), func(*Event), "+
-			"func(string), func(hooks.HookEvent), func(FormData), etc.", value)
+			"func(string), func(hooks.HookEvent), func(FormData), etc., "+
+			"optionally with a leading server.Ctx parameter.", value)
 		return func(e *Event) {}
 	}
 }
 
+// convertHandler returns a handler calling h with the converted payload.
+// Events whose payload does not convert are ignored.
+func convertHandler[T any](convert func(*Event) (T, bool), h func(T)) Handler {
+	return func(e *Event) {
+		if v, ok := convert(e); ok {
+			h(v)
+		}
+	}
+}
+
+// convertCtxHandler is convertHandler for handlers taking the event's Ctx.
+func convertCtxHandler[T any](convert func(*Event) (T, bool), h func(Ctx, T)) Handler {
+	return func(e *Event) {
+		if v, ok := convert(e); ok {
+			h(e.Ctx(), v)
+		}
+	}
+}
+
+func stringValue(e *Event) (string, bool) {
+	s, ok := e.Payload.(string)
+	return s, ok
+}
+
+func mouseEvent(e *Event) (MouseEvent, bool) {
+	data, ok := e.Payload.(*protocol.MouseEventData)
+	if !ok {
+		return MouseEvent{}, false
+	}
+	return MouseEvent{
+		ClientX:  data.ClientX,
+		ClientY:  data.ClientY,
+		Button:   int(data.Button),
+		CtrlKey:  data.Modifiers.Has(protocol.ModCtrl),
+		ShiftKey: data.Modifiers.Has(protocol.ModShift),
+		AltKey:   data.Modifiers.Has(protocol.ModAlt),
+		MetaKey:  data.Modifiers.Has(protocol.ModMeta),
+	}, true
+}
+
+func keyboardEvent(e *Event) (KeyboardEvent, bool) {
+	data, ok := e.Payload.(*protocol.KeyboardEventData)
+	if !ok {
+		return KeyboardEvent{}, false
+	}
+	return KeyboardEvent{
+		Key:      data.Key,
+		CtrlKey:  data.Modifiers.Has(protocol.ModCtrl),
+		ShiftKey: data.Modifiers.Has(protocol.ModShift),
+		AltKey:   data.Modifiers.Has(protocol.ModAlt),
+		MetaKey:  data.Modifiers.Has(protocol.ModMeta),
+	}, true
+}
+
+func formData(e *Event) (FormData, bool) {
+	data, ok := e.Payload.(*protocol.SubmitEventData)
+	if !ok {
+		return FormData{}, false
+	}
+	return FormData{values: data.Fields}, true
+}
+
+func hookEvent(e *Event) (HookEvent, bool) {
+	data, ok := e.Payload.(*protocol.HookEventData)
+	if !ok {
+		return HookEvent{}, false
+	}
+	return HookEvent{Name: data.Name, Data: data.Data}, true
+}
+
+func publicHookEvent(e *Event) (hooks.HookEvent, bool) {
+	data, ok := e.Payload.(*protocol.HookEventData)
+	if !ok {
+		return hooks.HookEvent{}, false
+	}
+	var revert func()
+	if s := e.Session; s != nil {
+		// The invoke client function calls the revert method of the hook
+		revert = func() { s.CallAsync(e.HID, "invoke", "revert", data.Name, data.Data) }
+	}
+	return hooks.NewHookEvent(data.Name, data.Data, revert), true
+}
+
+func scrollEvent(e *Event) (ScrollEvent, bool) {
+	data, ok := e.Payload.(*protocol.ScrollEventData)
+	if !ok {
+		return ScrollEvent{}, false
+	}
+	return ScrollEvent{
+		ScrollTop:  data.ScrollTop,
+		ScrollLeft: data.ScrollLeft,
+	}, true
+}
+
+func resizeEvent(e *Event) (ResizeEvent, bool) {
+	data, ok := e.Payload.(*protocol.ResizeEventData)
+	if !ok {
+		return ResizeEvent{}, false
+	}
+	return ResizeEvent{
+		Width:  data.Width,
+		Height: data.Height,
+	}, true
+}
+
+func touchEvent(e *Event) (TouchEvent, bool) {
+	data, ok := e.Payload.(*protocol.TouchEventData)
+	if !ok {
+		return TouchEvent{}, false
+	}
+	touches := make([]TouchPoint, len(data.Touches))
+	for i, t := range data.Touches {
+		touches[i] = TouchPoint{
+			ID:      t.ID,
+			ClientX: t.ClientX,
+			ClientY: t.ClientY,
+		}
+	}
+	return TouchEvent{Touches: touches}, true
+}
+
+func navigateEvent(e *Event) (NavigateEvent, bool) {
+	data, ok := e.Payload.(*protocol.NavigateEventData)
+	if !ok {
+		return NavigateEvent{}, false
+	}
+	return NavigateEvent{
+		Path:    data.Path,
+		Replace: data.Replace,
+	}, true
+}
+
 // eventFromProtocol converts a protocol.Event to a server.Event.
 func eventFromProtocol(pe *protocol.Event, session *Session) *Event {
 	return &Event{
@@ -349,6 +349,53 @@ func TestWrapHandlerWithString(t *testing.T) {
 	}
 }
 
+func TestWrapHandlerWithCtx(t *testing.T) {
+	var got Ctx
+	var value any
+	tests := []struct {
+		name    string
+		handler any
+		payload any
+		want    any
+	}{
+		{"func(Ctx)", func(c Ctx) { got = c }, nil, nil},
+		{"func(Ctx, string)", func(c Ctx, v string) { got, value = c, v }, "typed", "typed"},
+		{"func(Ctx, FormData)", func(c Ctx, f FormData) { got, value = c, f.Get("name") },
+			&protocol.SubmitEventData{Fields: map[string]string{"name": "Ada"}}, "Ada"},
+		{"func(Ctx, KeyboardEvent)", func(c Ctx, k KeyboardEvent) { got, value = c, k.Key },
+			&protocol.KeyboardEventData{Key: "Enter"}, "Enter"},
+		{"func(Ctx, HookEvent)", func(c Ctx, h HookEvent) { got, value = c, h.Name },
+			&protocol.HookEventData{Name: "reorder"}, "reorder"},
+		{"func(Ctx, NavigateEvent)", func(c Ctx, n NavigateEvent) { got, value = c, n.Path },
+			&protocol.NavigateEventData{Path: "/about"}, "/about"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, value = nil, nil
+			e := &Event{HID: "h1", Payload: tt.payload}
+			wrapHandler(tt.handler)(e)
+
+			if got == nil {
+				t.Fatal("handler was not called with a Ctx")
+			}
+			if got.Event() != e || got != e.Ctx() {
+				t.Error("Ctx should be the context of the event")
+			}
+			if value != tt.want {
+				t.Errorf("value = %v, want %v", value, tt.want)
+			}
+		})
+	}
+
+	// Payloads of another type are ignored, as without a Ctx
+	called := false
+	wrapHandler(func(Ctx, MouseEvent) { called = true })(&Event{Payload: "x"})
+	if called {
+		t.Error("handler should not be called for a mismatched payload")
+	}
+}
+
 func TestWrapHandlerUnknownType(t *testing.T) {
 	fn := func(x int, y int) int { return x + y }
 
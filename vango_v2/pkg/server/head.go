@@ -0,0 +1,62 @@
+package server
+
+import (
+	"github.com/vango-dev/vango/v2/pkg/head"
+	"github.com/vango-dev/vango/v2/pkg/protocol"
+	"github.com/vango-dev/vango/v2/pkg/vdom"
+)
+
+// collectHead merges the head declarations of the mounted component tree.
+// Depths are counted the same way as during server-side rendering, so the
+// live session resolves conflicts exactly like the initial page did.
+func (s *Session) collectHead() *head.Head {
+	h := head.New()
+	if s.root != nil {
+		collectInstanceHead(h, s.root, s.root.LastTree(), 0)
+	}
+	return h
+}
+
+// collectInstanceHead adds the declarations in node, part of the tree
+// rendered by inst, descending into mounted child components.
+func collectInstanceHead(h *head.Head, inst *ComponentInstance, node *vdom.VNode, depth int) {
+	if node == nil {
+		return
+	}
+
+	if entry := head.EntryOf(node); entry != nil {
+		h.Add(depth, entry)
+		return
+	}
+
+	if node.Kind == vdom.KindComponent {
+		if child := inst.childFor(node); child != nil {
+			collectInstanceHead(h, child, child.LastTree(), depth+1)
+		}
+		return
+	}
+
+	for _, child := range node.Children {
+		collectInstanceHead(h, inst, child, depth+1)
+	}
+}
+
+// childFor returns the child instance mounted from the component node.
+// The most recent instance wins, as re-renders mount new children.
+func (c *ComponentInstance) childFor(node *vdom.VNode) *ComponentInstance {
+	for i := len(c.Children) - 1; i >= 0; i-- {
+		if c.Children[i].node == node {
+			return c.Children[i]
+		}
+	}
+	return nil
+}
+
+// headPatches returns the head patches for declarations that changed since
+// the last render, such as a new title after navigation.
+func (s *Session) headPatches() []protocol.Patch {
+	next := s.collectHead()
+	patches := head.Diff(s.head, next)
+	s.head = next
+	return patches
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/vango-dev/vango/v2/pkg/protocol"
+)
+
+// RecordingExt is the file extension of session recordings.
+const RecordingExt = ".vrec"
+
+// RecordToDir returns a ServerConfig.RecordSession function that records
+// every session to <dir>/<session ID>.vrec. Recordings hold everything
+// the user typed or sent, so keep them where the user data they contain
+// may be stored:
+//
+//	config.RecordSession = server.RecordToDir("recordings")
+//
+// To record only some sessions, wrap it:
+//
+//	record := server.RecordToDir("recordings")
+//	config.RecordSession = func(r *http.Request, s *server.Session) io.Writer {
+//	    if r.URL.Query().Get("record") == "" {
+//	        return nil
+//	    }
+//	    return record(r, s)
+//	}
+func RecordToDir(dir string) func(r *http.Request, session *Session) io.Writer {
+	return func(r *http.Request, session *Session) io.Writer {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			session.logger.Error("session recording failed", "error", err)
+			return nil
+		}
+		f, err := os.Create(filepath.Join(dir, session.ID+RecordingExt))
+		if err != nil {
+			session.logger.Error("session recording failed", "error", err)
+			return nil
+		}
+		return f
+	}
+}
+
+// startRecording starts recording the session to w. hello is the
+// ClientHello received at start; it is recorded without its CSRF token,
+// which replays replace with a fresh one anyway.
+func (s *Session) startRecording(w io.Writer, path string, hello *protocol.ClientHello, start time.Time) {
+	rw, err := protocol.NewRecordingWriter(w, protocol.RecordingHeader{
+		SessionID: s.ID,
+		Path:      path,
+		Start:     start,
+	})
+	if err != nil {
+		s.logger.Error("session recording failed", "error", err)
+		if c, ok := w.(io.Closer); ok {
+			c.Close()
+		}
+		return
+	}
+
+	redacted := *hello
+	redacted.CSRFToken = ""
+	s.recorder = rw
+	s.recordAt(protocol.RecordIn, start, protocol.EncodeClientHello(&redacted))
+}
+
+// record appends a message to the session's recording, if any.
+func (s *Session) record(dir protocol.RecordDirection, data []byte) {
+	if s.recorder != nil {
+		s.recordAt(dir, time.Now(), data)
+	}
+}
+
+// recordAt appends a message sent at t to the session's recording. The
+// first write error is logged.
+func (s *Session) recordAt(dir protocol.RecordDirection, t time.Time, data []byte) {
+	if err := s.recorder.RecordAt(dir, t, data); err != nil && !s.recordFailed.Swap(true) {
+		s.logger.Error("session recording failed", "error", err)
+	}
+}
+
+// stopRecording closes the session's recording.
+func (s *Session) stopRecording() {
+	if s.recorder != nil {
+		s.recorder.Close()
+	}
+}
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/vango-dev/vango/v2/pkg/protocol"
+)
+
+func TestRecordToDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "recordings")
+	s := NewMockSession()
+
+	w := RecordToDir(dir)(httptest.NewRequest("GET", "/_vango/live", nil), s)
+	if w == nil {
+		t.Fatal("RecordToDir() returned no writer")
+	}
+	hello := protocol.NewClientHello("token")
+	s.startRecording(w, "/_vango/live?path=%2F", hello, time.Now())
+	s.record(protocol.RecordOut, []byte{1, 2, 3})
+	s.stopRecording()
+	s.record(protocol.RecordOut, []byte{4}) // Dropped after close
+
+	f, err := os.Open(filepath.Join(dir, s.ID+RecordingExt))
+	if err != nil {
+		t.Fatalf("recording file: %v", err)
+	}
+	defer f.Close()
+
+	h, messages, err := protocol.ReadRecording(f)
+	if err != nil {
+		t.Fatalf("ReadRecording() error: %v", err)
+	}
+	if h.SessionID != s.ID || h.Path != "/_vango/live?path=%2F" {
+		t.Errorf("header = %+v", h)
+	}
+	if len(messages) != 2 || messages[0].Dir != protocol.RecordIn || messages[1].Dir != protocol.RecordOut {
+		t.Fatalf("messages = %+v, want the hello and one frame", messages)
+	}
+	recorded, err := protocol.DecodeClientHello(messages[0].Data)
+	if err != nil {
+		t.Fatalf("first message is not a ClientHello: %v", err)
+	}
+	if recorded.CSRFToken != "" {
+		t.Errorf("recorded CSRF token = %q, want it stripped", recorded.CSRFToken)
+	}
+	if hello.CSRFToken != "token" {
+		t.Errorf("hello CSRF token = %q, want it untouched", hello.CSRFToken)
+	}
+}
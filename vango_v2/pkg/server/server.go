@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/vango-dev/vango/v2/pkg/features/hooks"
 	"github.com/vango-dev/vango/v2/pkg/protocol"
 )
 
@@ -28,6 +29,7 @@ type Server struct {
 
 	// Root component factory
 	rootComponent func() Component
+	sessionRoot   func(*Session) Component
 
 	// Configuration
 	config *ServerConfig
@@ -36,7 +38,8 @@ type Server struct {
 	upgrader websocket.Upgrader
 
 	// Middleware
-	middleware []Middleware
+	middleware      []Middleware
+	eventMiddleware []EventMiddleware
 
 	// Authentication
 	authFunc func(*http.Request) (any, error)
@@ -130,6 +133,13 @@ func (s *Server) SetRootComponent(factory func() Component) {
 	s.rootComponent = factory
 }
 
+// SetSessionRoot sets a root component factory that receives the new
+// session, such as a router.View. It takes precedence over
+// SetRootComponent and runs after OnSessionStart.
+func (s *Server) SetSessionRoot(factory func(session *Session) Component) {
+	s.sessionRoot = factory
+}
+
 // SetHandler sets the HTTP handler for non-WebSocket requests.
 func (s *Server) SetHandler(h http.Handler) {
 	s.handler = h
@@ -145,6 +155,15 @@ func (s *Server) Use(mw Middleware) {
 	s.middleware = append(s.middleware, mw)
 }
 
+// UseEvents adds middleware wrapping the handlers of live events, in
+// order. router.Middleware values such as middleware.OpenTelemetry()
+// can be used directly:
+//
+//	app.UseEvents(middleware.OpenTelemetry(), middleware.Prometheus())
+func (s *Server) UseEvents(mw ...EventMiddleware) {
+	s.eventMiddleware = append(s.eventMiddleware, mw...)
+}
+
 // =============================================================================
 // HTTP Handler Interface (Phase 10)
 // =============================================================================
@@ -155,6 +174,7 @@ func (s *Server) Use(mw Middleware) {
 //
 // The handler dispatches based on path:
 //   - /_vango/ws, /_vango/live → WebSocket upgrade
+//   - /_vango/debug → Session inspector (if ServerConfig.Debug is set)
 //   - /_vango/* → Internal routes (future: CSRF, assets)
 //   - /* → Page routes via SSR/handler
 //
@@ -175,6 +195,8 @@ func (s *Server) Handler() http.Handler {
 // Use when you want to handle /_vango/* routes separately.
 func (s *Server) PageHandler() http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = s.withCSP(w, r)
+
 		// Apply middleware and serve
 		handler := s.handler
 		if handler == nil {
@@ -203,6 +225,14 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Session inspector
+	if s.config.Debug != nil && isDebugPath(r.URL.Path) {
+		s.serveDebug(w, r)
+		return
+	}
+
+	r = s.withCSP(w, r)
+
 	// Apply middleware and serve
 	handler := s.handler
 	if handler == nil {
@@ -236,6 +266,7 @@ func (s *Server) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 		conn.Close()
 		return
 	}
+	helloAt := time.Now()
 
 	// Parse client hello
 	hello, err := protocol.DecodeClientHello(msg)
@@ -245,6 +276,22 @@ func (s *Server) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Negotiate the protocol version. Clients too old for this server
+	// reload to get a current bundle.
+	policy := protocol.DefaultVersionPolicy()
+	if s.config.ProtocolVersions != nil {
+		policy = *s.config.ProtocolVersions
+	}
+	version, status := policy.Negotiate(hello.Version)
+	if status != protocol.HandshakeOK {
+		s.logger.Info("protocol version rejected",
+			"version", hello.Version.String(),
+			"status", status.String())
+		s.sendHandshakeError(conn, status)
+		conn.Close()
+		return
+	}
+
 	// Validate CSRF if configured
 	if s.csrfSecret != nil && !s.validateCSRF(r, hello.CSRFToken) {
 		s.sendHandshakeError(conn, protocol.HandshakeInvalidCSRF)
@@ -272,8 +319,9 @@ func (s *Server) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 
 	// Authenticate if auth function is set
 	var userID string
+	var user any
 	if s.authFunc != nil {
-		user, err := s.authFunc(r)
+		user, err = s.authFunc(r)
 		if err != nil {
 			s.sendHandshakeError(conn, protocol.HandshakeNotAuthorized)
 			conn.Close()
@@ -295,6 +343,29 @@ func (s *Server) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 		conn.Close()
 		return
 	}
+	session.request = r
+	session.user = user
+	session.version = version
+	session.caps = hello.Capabilities & protocol.SupportedCapabilities
+	session.clientInfo.Set(clientInfoFromHello(hello, r))
+	session.middleware = s.eventMiddleware
+
+	// Record recent events and patches for the session inspector
+	if s.config.Debug != nil {
+		session.trace = newSessionTrace(s.config.Debug.history())
+	}
+
+	// Report accessibility violations and reject undefined client hooks
+	// while developing
+	session.checkA11y = s.config.DevMode
+	hooks.SetStrict(session.owner, s.config.DevMode)
+
+	// Record the session's messages for `vango replay`
+	if s.config.RecordSession != nil {
+		if w := s.config.RecordSession(r, session); w != nil {
+			session.startRecording(w, r.URL.RequestURI(), hello, helloAt)
+		}
+	}
 
 	// ═══════════════════════════════════════════════════════════════════════════
 	// THE CONTEXT BRIDGE (Phase 10)
@@ -303,14 +374,17 @@ func (s *Server) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 	// After this callback returns, r.Context() is dead.
 	// ═══════════════════════════════════════════════════════════════════════════
 	if s.config.OnSessionStart != nil {
-		s.config.OnSessionStart(r.Context(), session)
+		httpCtx := context.WithValue(r.Context(), requestContextKey{}, r)
+		s.config.OnSessionStart(httpCtx, session)
 	}
 
 	// Send server hello
 	s.sendServerHello(conn, session)
 
 	// Mount root component if factory is set
-	if s.rootComponent != nil {
+	if s.sessionRoot != nil {
+		session.MountRoot(s.sessionRoot(session))
+	} else if s.rootComponent != nil {
 		session.MountRoot(s.rootComponent())
 	}
 
@@ -335,11 +409,19 @@ func (s *Server) sendServerHello(conn *websocket.Conn, session *Session) {
 		uint32(session.sendSeq.Load()),
 		uint64(time.Now().UnixMilli()),
 	)
+	hello.Version = session.version
+	hello.Capabilities = session.caps
+	if session.caps.Has(protocol.CapBinaryBlobs) {
+		hello.Flags |= protocol.ServerFlagBinaryBlobs
+	}
 	payload := protocol.EncodeServerHello(hello)
 	frame := protocol.NewFrame(protocol.FrameHandshake, payload)
 
+	frameData := frame.Encode()
+
 	conn.SetWriteDeadline(time.Now().Add(s.config.SessionConfig.WriteTimeout))
-	conn.WriteMessage(websocket.BinaryMessage, frame.Encode())
+	conn.WriteMessage(websocket.BinaryMessage, frameData)
+	session.record(protocol.RecordOut, frameData)
 }
 
 // CSRFCookieName is the name of the CSRF cookie.
@@ -1,10 +1,13 @@
 package server
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
 	"log/slog"
+	"net/http"
+	"net/url"
 	"reflect"
 	"runtime/debug"
 	"strings"
@@ -13,6 +16,7 @@ import (
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/vango-dev/vango/v2/pkg/head"
 	"github.com/vango-dev/vango/v2/pkg/protocol"
 	"github.com/vango-dev/vango/v2/pkg/vango"
 	"github.com/vango-dev/vango/v2/pkg/vdom"
@@ -54,9 +58,29 @@ type Session struct {
 	CurrentRoute string // Current page route for restoration
 
 	// Connection
-	conn   *websocket.Conn
-	mu     sync.Mutex // Protects conn writes
-	closed atomic.Bool
+	conn    *websocket.Conn
+	mu      sync.Mutex // Protects conn writes
+	closed  atomic.Bool
+	request *http.Request // Request that opened the session
+	user    any           // User returned by the server's auth function
+
+	// Protocol negotiated in the handshake
+	version protocol.ProtocolVersion
+	caps    protocol.Capabilities
+
+	// Browser environment, from the handshake and window events
+	clientInfo *vango.Signal[ClientInfo]
+
+	// Blobs in transfer on the connection
+	blobMu     sync.Mutex
+	recvBlobs  map[uint64]*Blob         // Sent by the client (odd IDs)
+	sendBlobs  map[uint64]*BlobTransfer // Sent by SendBlob (even IDs)
+	nextBlobID atomic.Uint64
+
+	// Client calls awaiting their result
+	callMu     sync.Mutex
+	calls      map[uint64]*ClientCall
+	nextCallID atomic.Uint64
 
 	// Sequence numbers for reliable delivery
 	sendSeq atomic.Uint64 // Next patch sequence to send
@@ -67,6 +91,8 @@ type Session struct {
 	root       *ComponentInstance            // Root component
 	components map[string]*ComponentInstance // HID -> component that owns element
 	handlers   map[string]Handler            // HID -> event handler
+	navigate   func(NavigateEvent)           // Client-side navigation handler
+	middleware []EventMiddleware             // Wraps event handlers
 
 	// Reactive ownership
 	owner *vango.Owner
@@ -74,11 +100,24 @@ type Session struct {
 	// Rendering
 	currentTree *vdom.VNode        // Last rendered tree
 	hidGen      *vdom.HIDGenerator // Hydration ID generator
+	head        *head.Head         // Head declarations the client has applied
+
+	// Dispatch patches queued by Emit, sent after the patches of the next
+	// render so listeners see the DOM they describe
+	emitMu  sync.Mutex
+	emitted []protocol.Patch
 
 	// Channels
-	events   chan *Event   // Incoming events
-	renderCh chan struct{} // Signal for re-render
-	done     chan struct{} // Shutdown signal
+	events     chan *Event   // Incoming events
+	dispatchCh chan func()   // Functions dispatched from background goroutines
+	inspectCh  chan func()   // Read-only functions of the session inspector
+	renderCh   chan struct{} // Signal for re-render
+	done       chan struct{} // Shutdown signal
+
+	// The component tree is disposed of by the event loop when it stops,
+	// or by Close if the loop was never started.
+	loopStarted atomic.Bool
+	disposeOnce sync.Once
 
 	// Configuration
 	config *SessionConfig
@@ -92,6 +131,18 @@ type Session struct {
 	bytesSent  atomic.Uint64
 	bytesRecv  atomic.Uint64
 
+	// Recent events and patches for the session inspector (nil when disabled)
+	trace *sessionTrace
+
+	// Accessibility checks after each render, in dev mode; a11yViolations
+	// are the violations last sent to the client.
+	checkA11y      bool
+	a11yViolations []string
+
+	// Recording of the session's messages (nil when not recording)
+	recorder     *protocol.RecordingWriter
+	recordFailed atomic.Bool
+
 	// General-purpose session data storage (Phase 10)
 	// Use Get/Set/Delete to access. Protected by dataMu.
 	data   map[string]any
@@ -124,12 +175,23 @@ func newSession(conn *websocket.Conn, userID string, config *SessionConfig, logg
 		owner:      vango.NewOwner(nil),
 		hidGen:     vdom.NewHIDGenerator(),
 		events:     make(chan *Event, config.MaxEventQueue),
+		dispatchCh: make(chan func(), config.MaxEventQueue),
+		inspectCh:  make(chan func()),
 		renderCh:   make(chan struct{}, 1),
 		done:       make(chan struct{}),
 		config:     config,
 		logger:     logger.With("session_id", id),
+		version:    protocol.CurrentVersion,
+		caps:       protocol.SupportedCapabilities,
+		clientInfo: vango.NewSignal(ClientInfo{}, vango.Transient()),
+		recvBlobs:  make(map[uint64]*Blob),
+		sendBlobs:  make(map[uint64]*BlobTransfer),
+		calls:      make(map[uint64]*ClientCall),
 	}
 
+	// Background work created under this session reports back through us
+	vango.SetDispatcher(s.owner, s)
+
 	return s
 }
 
@@ -153,12 +215,27 @@ func (s *Session) MountRoot(component Component) {
 	s.root.HID = tree.HID
 	s.root.SetLastTree(tree)
 
+	// The server-rendered page already carries the initial head
+	s.head = s.collectHead()
+
+	s.checkAccessibility()
+
 	s.logger.Info("mounted root component",
 		"handlers", len(s.handlers),
 		"components", len(s.components),
 		"hid_counter", s.hidGen.Current())
 }
 
+// OnNavigate sets the handler of client-side navigations: clicks on
+// links with data-link and browser history changes. router.View installs
+// it to render the new route. Call it before the session starts.
+func (s *Session) OnNavigate(fn func(NavigateEvent)) {
+	s.navigate = func(e NavigateEvent) {
+		s.CurrentRoute = e.Path
+		fn(e)
+	}
+}
+
 // collectHandlers walks the VNode tree and collects event handlers.
 func (s *Session) collectHandlers(node *vdom.VNode, instance *ComponentInstance) {
 	if node == nil {
@@ -184,6 +261,7 @@ func (s *Session) collectHandlers(node *vdom.VNode, instance *ComponentInstance)
 		if child.Kind == vdom.KindComponent && child.Comp != nil {
 			// Mount child component
 			childInstance := newComponentInstance(child.Comp, instance, s)
+			childInstance.node = child
 			instance.AddChild(childInstance)
 
 			// Render child and collect its handlers
@@ -201,12 +279,38 @@ func (s *Session) handleEvent(event *Event) {
 	// Update sequence tracking
 	s.recvSeq.Store(event.Seq)
 	s.eventCount.Add(1)
-	s.LastActive = time.Now()
 
 	if DebugMode {
 		fmt.Printf("[EVENT] Received: HID=%s Type=%v Seq=%d\n", event.HID, event.Type, event.Seq)
 	}
 
+	if s.trace != nil {
+		_, handled := s.handlers[event.HID]
+		handled = handled || (event.Type == protocol.EventNavigate && s.navigate != nil) || isClientInfoEvent(event)
+		defer s.trace.recordEvent(event, handled, time.Now())
+	}
+
+	// Window resizes and media query changes update the client info
+	if isClientInfoEvent(event) {
+		s.updateClientInfo(event)
+		s.owner.RunPendingEffects()
+		s.renderDirty()
+		return
+	}
+
+	// Client-side navigations are not bound to an element
+	if event.Type == protocol.EventNavigate && s.navigate != nil {
+		s.safeExecute(wrapHandler(s.navigate), event)
+		s.owner.RunPendingEffects()
+		s.renderDirty()
+		return
+	}
+
+	// Blobs no handler takes are canceled, so the client stops sending
+	if b, ok := event.Payload.(*Blob); ok {
+		defer b.closeUnclaimed()
+	}
+
 	// Find handler for this HID
 	handler, exists := s.handlers[event.HID]
 	if !exists {
@@ -229,7 +333,8 @@ func (s *Session) handleEvent(event *Event) {
 	s.renderDirty()
 }
 
-// safeExecute runs a handler with panic recovery.
+// safeExecute runs a handler through the event middleware, with panic
+// recovery.
 func (s *Session) safeExecute(handler Handler, event *Event) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -248,7 +353,27 @@ func (s *Session) safeExecute(handler Handler, event *Event) {
 		}
 	}()
 
-	handler(event)
+	if len(s.middleware) == 0 {
+		handler(event)
+		return
+	}
+
+	c := event.Ctx()
+	chain := func() error {
+		handler(event)
+		return nil
+	}
+	for i := len(s.middleware) - 1; i >= 0; i-- {
+		mw, next := s.middleware[i], chain
+		chain = func() error { return mw.Handle(c, next) }
+	}
+	if err := chain(); err != nil {
+		s.logger.Warn("event rejected by middleware",
+			"hid", event.HID,
+			"type", event.Type,
+			"error", err)
+		s.sendErrorMessage(protocol.ErrServerError, "Event rejected")
+	}
 }
 
 // renderDirty re-renders all dirty components and sends patches.
@@ -272,6 +397,7 @@ func (s *Session) renderDirty() {
 		if DebugMode {
 			fmt.Println("[DEBUG] renderDirty: no dirty components")
 		}
+		s.flushPatches(nil)
 		return
 	}
 
@@ -289,13 +415,16 @@ func (s *Session) renderDirty() {
 		allPatches = append(allPatches, patches...)
 	}
 
-	// Send all patches
+	// Send all patches, with the document head's if its declarations
+	// changed and the events emitted meanwhile
 	if DebugMode {
 		fmt.Printf("[DEBUG] renderDirty: sending %d total patches\n", len(allPatches))
 	}
-	if len(allPatches) > 0 {
-		s.sendPatches(allPatches)
-	}
+	patches := s.convertPatches(allPatches)
+	patches = append(patches, s.headPatches()...)
+	s.flushPatches(patches)
+
+	s.checkAccessibility()
 }
 
 // renderComponent re-renders a single component and returns patches.
@@ -354,8 +483,31 @@ func (s *Session) scheduleRender(comp *ComponentInstance) {
 	}
 }
 
+// queueEmit queues a dispatch patch of Emit and schedules a render pass,
+// which sends it after its own patches. It may be called from any
+// goroutine.
+func (s *Session) queueEmit(patch protocol.Patch) {
+	s.emitMu.Lock()
+	s.emitted = append(s.emitted, patch)
+	s.emitMu.Unlock()
+	s.scheduleRender(nil)
+}
+
+// flushPatches sends the patches of a render pass followed by the queued
+// dispatch patches of Emit, in one frame.
+func (s *Session) flushPatches(patches []protocol.Patch) {
+	s.emitMu.Lock()
+	patches = append(patches, s.emitted...)
+	s.emitted = nil
+	s.emitMu.Unlock()
+
+	if len(patches) > 0 {
+		s.sendPatches(patches)
+	}
+}
+
 // sendPatches encodes and sends patches to the client.
-func (s *Session) sendPatches(vdomPatches []vdom.Patch) {
+func (s *Session) sendPatches(protocolPatches []protocol.Patch) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -366,17 +518,14 @@ func (s *Session) sendPatches(vdomPatches []vdom.Patch) {
 	// Increment sequence number
 	seq := s.sendSeq.Add(1)
 
-	// Convert vdom patches to protocol patches
-	protocolPatches := s.convertPatches(vdomPatches)
-
 	// Create patches frame
 	pf := &protocol.PatchesFrame{
 		Seq:     seq,
 		Patches: protocolPatches,
 	}
 
-	// Encode payload
-	payload := protocol.EncodePatches(pf)
+	// Encode payload, without the patches the client cannot apply
+	payload := protocol.EncodePatchesFor(pf, s.caps)
 
 	// Create frame
 	frame := protocol.NewFrame(protocol.FramePatches, payload)
@@ -398,6 +547,10 @@ func (s *Session) sendPatches(vdomPatches []vdom.Patch) {
 	// Update metrics
 	s.bytesSent.Add(uint64(len(frameData)))
 	s.patchCount.Add(uint64(len(protocolPatches)))
+	s.record(protocol.RecordOut, frameData)
+	if s.trace != nil {
+		s.trace.recordPatches(seq, protocolPatches, len(frameData))
+	}
 
 	s.logger.Debug("sent patches",
 		"seq", seq,
@@ -440,9 +593,11 @@ func (s *Session) sendErrorMessage(code protocol.ErrorCode, message string) {
 	errMsg := protocol.NewError(code, message)
 	payload := protocol.EncodeErrorMessage(errMsg)
 	frame := protocol.NewFrame(protocol.FrameError, payload)
+	frameData := frame.Encode()
 
 	s.conn.SetWriteDeadline(time.Now().Add(s.config.WriteTimeout))
-	s.conn.WriteMessage(websocket.BinaryMessage, frame.Encode())
+	s.conn.WriteMessage(websocket.BinaryMessage, frameData)
+	s.record(protocol.RecordOut, frameData)
 }
 
 // sendPing sends a heartbeat ping to the client.
@@ -488,19 +643,14 @@ func (s *Session) closeInternal() {
 		close(s.done)
 	}
 
-	// Dispose reactive owner (cleans up effects and signals)
-	if s.owner != nil {
-		s.owner.Dispose()
-	}
-
-	// Dispose root component
-	if s.root != nil {
-		s.root.Dispose()
+	// The event loop owns the component tree and disposes of it on exit
+	if !s.loopStarted.Load() {
+		s.dispose()
 	}
 
-	// Clear handlers
-	s.handlers = nil
-	s.components = nil
+	// Unblock readers and senders of blobs, and callers awaiting results
+	s.abortBlobs()
+	s.abortCalls()
 
 	// Send close message and close WebSocket
 	if s.conn != nil {
@@ -511,6 +661,7 @@ func (s *Session) closeInternal() {
 		)
 		s.conn.Close()
 	}
+	s.stopRecording()
 
 	s.logger.Info("session closed",
 		"events", s.eventCount.Load(),
@@ -519,6 +670,26 @@ func (s *Session) closeInternal() {
 		"bytes_recv", s.bytesRecv.Load())
 }
 
+// dispose disposes of the session's reactive owner and component tree.
+// It runs on the event loop, or in Close if the loop was never started.
+func (s *Session) dispose() {
+	s.disposeOnce.Do(func() {
+		// Dispose reactive owner (cleans up effects and signals)
+		if s.owner != nil {
+			s.owner.Dispose()
+		}
+
+		// Dispose root component
+		if s.root != nil {
+			s.root.Dispose()
+		}
+
+		// Clear handlers
+		s.handlers = nil
+		s.components = nil
+	})
+}
+
 // IsClosed returns whether the session is closed.
 func (s *Session) IsClosed() bool {
 	return s.closed.Load()
@@ -529,6 +700,83 @@ func (s *Session) Done() <-chan struct{} {
 	return s.done
 }
 
+// ProtocolVersion returns the protocol version negotiated with the client.
+func (s *Session) ProtocolVersion() protocol.ProtocolVersion {
+	return s.version
+}
+
+// Capabilities returns the optional protocol features of the client.
+// Patches it cannot apply are not sent.
+func (s *Session) Capabilities() protocol.Capabilities {
+	return s.caps
+}
+
+// Context returns a context that is canceled when the session closes.
+// It is the StdContext of the contexts of live events.
+func (s *Session) Context() context.Context {
+	return sessionContext{s}
+}
+
+// sessionContext is the context of a session, done with the session.
+type sessionContext struct{ s *Session }
+
+func (sessionContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (c sessionContext) Done() <-chan struct{}     { return c.s.done }
+func (sessionContext) Value(key any) any           { return nil }
+
+func (c sessionContext) Err() error {
+	select {
+	case <-c.s.done:
+		return context.Canceled
+	default:
+		return nil
+	}
+}
+
+// pageRequest returns a request for the page the session shows, carrying
+// the headers and cookies of the request that opened the session.
+func (s *Session) pageRequest() *http.Request {
+	path := s.CurrentRoute
+	var r *http.Request
+	if s.request != nil {
+		r = s.request.Clone(s.Context())
+		if path == "" {
+			path = s.request.URL.Query().Get("path")
+		}
+	} else {
+		r = (&http.Request{Header: make(http.Header)}).WithContext(s.Context())
+	}
+
+	u, err := url.Parse(path)
+	if err != nil || u.Path == "" {
+		u = &url.URL{Path: "/"}
+	}
+	r.Method = http.MethodGet
+	r.URL = u
+	r.RequestURI = u.RequestURI()
+	return r
+}
+
+// rootHID returns the HID of the first element of the rendered tree.
+func (s *Session) rootHID() string {
+	var find func(n *vdom.VNode) string
+	find = func(n *vdom.VNode) string {
+		if n == nil {
+			return ""
+		}
+		if n.HID != "" {
+			return n.HID
+		}
+		for _, c := range n.Children {
+			if hid := find(c); hid != "" {
+				return hid
+			}
+		}
+		return ""
+	}
+	return find(s.currentTree)
+}
+
 // Events returns the events channel for the event loop.
 func (s *Session) Events() <-chan *Event {
 	return s.events
@@ -545,6 +793,37 @@ func (s *Session) QueueEvent(event *Event) error {
 	}
 }
 
+// Dispatch queues fn to run on the session's event loop.
+// It implements vango.Dispatcher and may be called from any goroutine.
+// After fn runs, pending effects are flushed and dirty components re-render,
+// exactly as after an event handler. Functions dispatched to a closed session
+// are dropped.
+func (s *Session) Dispatch(fn func()) {
+	if fn == nil || s.closed.Load() {
+		return
+	}
+	select {
+	case s.dispatchCh <- fn:
+	case <-s.done:
+	}
+}
+
+// runDispatched executes a dispatched function on the event loop.
+func (s *Session) runDispatched(fn func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Error("dispatched function panic",
+				"panic", r,
+				"stack", string(debug.Stack()))
+		}
+	}()
+
+	fn()
+
+	s.owner.RunPendingEffects()
+	s.renderDirty()
+}
+
 // UpdateLastActive updates the last activity timestamp.
 func (s *Session) UpdateLastActive() {
 	s.LastActive = time.Now()
@@ -728,7 +1007,7 @@ func (s *Session) Has(key string) bool {
 // NewMockSession creates a session without a WebSocket connection for testing.
 // The session has all fields initialized except conn.
 func NewMockSession() *Session {
-	return &Session{
+	s := &Session{
 		ID:         "test-session-id",
 		UserID:     "",
 		CreatedAt:  time.Now(),
@@ -738,10 +1017,20 @@ func NewMockSession() *Session {
 		owner:      vango.NewOwner(nil),
 		hidGen:     vdom.NewHIDGenerator(),
 		events:     make(chan *Event, 256),
+		dispatchCh: make(chan func(), 256),
+		inspectCh:  make(chan func()),
 		renderCh:   make(chan struct{}, 1),
 		done:       make(chan struct{}),
 		config:     DefaultSessionConfig(),
 		logger:     slog.Default().With("session_id", "test-session-id"),
 		data:       make(map[string]any),
+		version:    protocol.CurrentVersion,
+		caps:       protocol.SupportedCapabilities,
+		clientInfo: vango.NewSignal(ClientInfo{}, vango.Transient()),
+		recvBlobs:  make(map[uint64]*Blob),
+		sendBlobs:  make(map[uint64]*BlobTransfer),
+		calls:      make(map[uint64]*ClientCall),
 	}
+	vango.SetDispatcher(s.owner, s)
+	return s
 }
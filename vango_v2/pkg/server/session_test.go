@@ -0,0 +1,320 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vango-dev/vango/v2/pkg/head"
+	"github.com/vango-dev/vango/v2/pkg/protocol"
+	"github.com/vango-dev/vango/v2/pkg/vango"
+	"github.com/vango-dev/vango/v2/pkg/vdom"
+)
+
+func TestSessionDispatchRunsOnEventLoop(t *testing.T) {
+	s := NewMockSession()
+	go s.EventLoop()
+	defer close(s.done)
+
+	var dispatcher vango.Dispatcher
+	vango.WithOwner(vango.NewOwner(s.owner), func() {
+		dispatcher = vango.GetDispatcher()
+	})
+	if dispatcher != vango.Dispatcher(s) {
+		t.Fatal("expected session to be the dispatcher for its owners")
+	}
+
+	ran := make(chan struct{})
+	go dispatcher.Dispatch(func() { close(ran) })
+
+	select {
+	case <-ran:
+	case <-time.After(time.Second):
+		t.Fatal("dispatched function did not run")
+	}
+}
+
+func TestSessionDispatchAfterClose(t *testing.T) {
+	s := NewMockSession()
+	s.closed.Store(true)
+
+	// Must not block or run
+	s.Dispatch(func() { t.Error("dispatched function ran on closed session") })
+}
+
+func TestGetDispatcherWithoutSession(t *testing.T) {
+	ran := false
+	vango.GetDispatcher().Dispatch(func() { ran = true })
+	if !ran {
+		t.Error("expected fallback dispatcher to run immediately")
+	}
+}
+
+func TestSessionHeadFollowsComponents(t *testing.T) {
+	s := NewMockSession()
+	title := vango.NewSignal("Profile")
+
+	page := FuncComponent(func() *vdom.VNode {
+		return vdom.Div(
+			head.Title(title.Get()),
+			head.Meta("description", "A profile"),
+		)
+	})
+	s.MountRoot(FuncComponent(func() *vdom.VNode {
+		return vdom.Div(
+			head.Title("Acme"),
+			head.Meta("robots", "index"),
+			page,
+		)
+	}))
+
+	if got := s.head.Title(); got != "Profile" {
+		t.Errorf("mounted title = %q, want the page to override the layout", got)
+	}
+
+	// Re-render only the page component
+	title.Set("Settings")
+	child := s.root.Children[0]
+	if !child.IsDirty() {
+		t.Fatal("page component should be dirty")
+	}
+	s.renderComponent(child)
+
+	next := s.collectHead()
+	patches := head.Diff(s.head, next)
+	if len(patches) != 1 || patches[0].Op != protocol.PatchSetTitle || patches[0].Value != "Settings" {
+		t.Errorf("Diff() = %+v, want a single SetTitle patch", patches)
+	}
+	if !next.Has("name:robots") || !next.Has("name:description") {
+		t.Error("layout and page meta should both be kept")
+	}
+}
+
+func TestSessionOnNavigate(t *testing.T) {
+	s := NewMockSession()
+
+	var got NavigateEvent
+	s.OnNavigate(func(e NavigateEvent) { got = e })
+
+	s.handleEvent(&Event{
+		Type:    protocol.EventNavigate,
+		HID:     "nav",
+		Payload: &protocol.NavigateEventData{Path: "/projects/1", Replace: true},
+	})
+
+	if got.Path != "/projects/1" || !got.Replace {
+		t.Errorf("NavigateEvent = %+v", got)
+	}
+	if s.CurrentRoute != "/projects/1" {
+		t.Errorf("CurrentRoute = %q, want /projects/1", s.CurrentRoute)
+	}
+}
+
+func TestSessionEventCtx(t *testing.T) {
+	s := NewMockSession()
+	s.request = httptest.NewRequest("GET", "/_vango/ws?path=/projects%3Ftab%3D2", nil)
+	s.request.Header.Set("Accept-Language", "fr")
+	s.user = "user-1"
+
+	var c Ctx
+	s.handlers["h1"] = wrapHandler(func(ctx Ctx) { c = ctx })
+	s.handleEvent(&Event{Type: protocol.EventClick, HID: "h1", Session: s})
+
+	if c == nil {
+		t.Fatal("handler was not called")
+	}
+	if c.Path() != "/projects" || c.Query().Get("tab") != "2" {
+		t.Errorf("Path() = %q, Query() = %v, want the page of the session", c.Path(), c.Query())
+	}
+	if c.Header("Accept-Language") != "fr" || c.User() != "user-1" || c.Session() != s {
+		t.Error("Ctx should carry the upgrade request, user and session")
+	}
+
+	// Navigations update the page of later events
+	s.CurrentRoute = "/about"
+	s.handleEvent(&Event{Type: protocol.EventClick, HID: "h1", Session: s})
+	if c.Path() != "/about" {
+		t.Errorf("Path() = %q after navigating, want /about", c.Path())
+	}
+
+	if c.StdContext().Err() != nil {
+		t.Error("StdContext() should be live while the session is open")
+	}
+	close(s.done)
+	select {
+	case <-c.Done():
+	default:
+		t.Error("Done() should be closed with the session")
+	}
+	if c.StdContext().Err() != context.Canceled {
+		t.Errorf("StdContext().Err() = %v after close", c.StdContext().Err())
+	}
+}
+
+func TestSessionEventMiddleware(t *testing.T) {
+	s := NewMockSession()
+
+	var calls []string
+	mw := func(name string) EventMiddleware {
+		return eventMiddlewareFunc(func(ctx Ctx, next func() error) error {
+			calls = append(calls, name+":"+ctx.Event().HID)
+			ctx.SetValue("by", name)
+			return next()
+		})
+	}
+	s.middleware = []EventMiddleware{mw("outer"), mw("inner")}
+
+	s.handlers["h1"] = wrapHandler(func(ctx Ctx) {
+		calls = append(calls, "handler:"+ctx.Value("by").(string))
+	})
+	s.handleEvent(&Event{Type: protocol.EventClick, HID: "h1", Session: s})
+
+	want := "outer:h1,inner:h1,handler:inner"
+	if got := strings.Join(calls, ","); got != want {
+		t.Errorf("calls = %s, want %s", got, want)
+	}
+}
+
+// eventMiddlewareFunc adapts a function to EventMiddleware.
+type eventMiddlewareFunc func(ctx Ctx, next func() error) error
+
+func (f eventMiddlewareFunc) Handle(ctx Ctx, next func() error) error {
+	return f(ctx, next)
+}
+
+func TestSessionClientInfo(t *testing.T) {
+	s := NewMockSession()
+	r := httptest.NewRequest("GET", "/_vango/ws", nil)
+	r.Header.Set("Accept-Language", "de-DE,de;q=0.9")
+	s.clientInfo.Set(clientInfoFromHello(&protocol.ClientHello{
+		ViewportW:   1280,
+		ViewportH:   800,
+		TZOffset:    60,
+		TimeZone:    "Europe/Berlin",
+		ColorScheme: protocol.ColorSchemeLight,
+		PixelRatio:  150,
+	}, r))
+
+	renders := 0
+	var width int
+	s.MountRoot(FuncComponent(func() *vdom.VNode {
+		renders++
+		width = s.ClientInfo().ViewportWidth
+		return vdom.Div()
+	}))
+
+	ci := s.ClientInfo()
+	if ci.Locale != "de-DE" || ci.ColorScheme != "light" || ci.PixelRatio != 1.5 || ci.Dark() {
+		t.Errorf("ClientInfo() = %+v after the handshake", ci)
+	}
+	if _, off := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC).In(ci.Location()).Zone(); off != 3600 {
+		t.Errorf("Location() offset = %d in January, want 3600", off)
+	}
+
+	s.handleEvent(&Event{Type: protocol.EventResize, Payload: &protocol.ResizeEventData{Width: 390, Height: 844}})
+	if width != 390 || renders != 2 {
+		t.Errorf("width = %d after %d renders, want a re-render at 390", width, renders)
+	}
+
+	s.handleEvent(&Event{Type: protocol.EventMedia, Payload: &protocol.MediaEventData{ColorScheme: protocol.ColorSchemeDark, PixelRatio: 300}})
+	if ci := s.ClientInfo(); !ci.Dark() || ci.PixelRatio != 3 || ci.ViewportHeight != 844 {
+		t.Errorf("ClientInfo() = %+v after a media change", ci)
+	}
+}
+
+func TestClientInfoFromRequest(t *testing.T) {
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Accept-Language", "en-GB;q=0.9, en")
+	r.Header.Set("Sec-CH-Viewport-Width", "1024")
+	r.Header.Set("Sec-CH-DPR", "2")
+	r.Header.Set("Sec-CH-Prefers-Color-Scheme", `"dark"`)
+	r.Header.Set("Sec-CH-Prefers-Reduced-Motion", `"reduce"`)
+
+	ci := ClientInfoFromRequest(r)
+	want := ClientInfo{ViewportWidth: 1024, PixelRatio: 2, ColorScheme: "dark", ReducedMotion: true, Locale: "en-GB"}
+	if ci != want {
+		t.Errorf("ClientInfoFromRequest() = %+v, want %+v", ci, want)
+	}
+
+	// Without an IANA zone the offset gives a fixed zone
+	if _, off := time.Now().In(ClientInfo{TZOffset: -300}.Location()).Zone(); off != -5*3600 {
+		t.Errorf("Location() offset = %d, want %d", off, -5*3600)
+	}
+}
+
+func TestSessionCalls(t *testing.T) {
+	s := NewMockSession()
+	ctx := context.Background()
+
+	if _, err := s.CallAsync("h1", "rect").Wait(ctx); err != ErrSessionClosed {
+		t.Errorf("CallAsync() without a connection: error = %v, want ErrSessionClosed", err)
+	}
+	s.caps = 0
+	if _, err := s.CallAsync("h1", "rect").Wait(ctx); err != ErrCallsUnsupported {
+		t.Errorf("CallAsync() without the capability: error = %v, want ErrCallsUnsupported", err)
+	}
+
+	// Register calls as if sent, and answer them
+	pending := func(id uint64, name string) *ClientCall {
+		c := &ClientCall{ID: id, Name: name, s: s, done: make(chan struct{})}
+		s.calls[id] = c
+		return c
+	}
+	rect, share, clip := pending(1, "rect"), pending(2, "share"), pending(3, "copy")
+
+	s.handleCallResult(&protocol.CallResultData{ID: 1, OK: true, Value: `{"width":320}`})
+	var got struct{ Width int }
+	if r, err := rect.Wait(ctx); err != nil || r.Decode(&got) != nil || got.Width != 320 {
+		t.Errorf("rect = %+v, %v; want width 320", got, err)
+	}
+
+	s.handleCallResult(&protocol.CallResultData{ID: 2, Value: "AbortError: Share canceled"})
+	var ce *CallError
+	if _, err := share.Wait(ctx); !errors.As(err, &ce) || ce.Name != "share" || ce.Message != "AbortError: Share canceled" {
+		t.Errorf("share error = %v, want a CallError", err)
+	}
+
+	// Unknown and repeated results are ignored
+	s.handleCallResult(&protocol.CallResultData{ID: 1, OK: true, Value: "null"})
+	s.handleCallResult(&protocol.CallResultData{ID: 9, OK: true})
+
+	s.abortCalls()
+	if _, err := clip.Wait(ctx); err != ErrSessionClosed {
+		t.Errorf("clip error = %v, want ErrSessionClosed", err)
+	}
+	if len(s.calls) != 0 {
+		t.Errorf("%d calls pending, want 0", len(s.calls))
+	}
+}
+
+// a11yAvatar renders an image with the alt text of a signal.
+type a11yAvatar struct{ alt *vango.Signal[string] }
+
+func (a a11yAvatar) Render() *vdom.VNode {
+	return vdom.Img(vdom.Src("/avatar.png"), vdom.Alt(a.alt.Get()))
+}
+
+func TestSessionChecksAccessibility(t *testing.T) {
+	s := NewMockSession()
+	s.checkA11y = true
+	alt := vango.NewSignal("")
+
+	s.MountRoot(FuncComponent(func() *vdom.VNode {
+		return vdom.Div(a11yAvatar{alt: alt})
+	}))
+
+	if len(s.a11yViolations) != 1 || !strings.Contains(s.a11yViolations[0], "> a11yAvatar: img: image has no alt text") {
+		t.Fatalf("violations = %q, want the image of a11yAvatar", s.a11yViolations)
+	}
+
+	// Fixed by a re-render of the child component
+	alt.Set("Ada")
+	s.renderComponent(s.root.Children[0])
+	s.checkAccessibility()
+	if len(s.a11yViolations) != 0 {
+		t.Errorf("violations = %q, want none", s.a11yViolations)
+	}
+}
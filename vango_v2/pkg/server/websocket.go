@@ -44,6 +44,7 @@ func (s *Session) ReadLoop() {
 		// Handle based on frame type
 		switch frame.Type {
 		case protocol.FrameEvent:
+			s.record(protocol.RecordIn, msg)
 			s.handleEventFrame(frame.Payload)
 
 		case protocol.FrameControl:
@@ -52,6 +53,9 @@ func (s *Session) ReadLoop() {
 		case protocol.FrameAck:
 			s.handleAckFrame(frame.Payload)
 
+		case protocol.FrameBlob:
+			s.handleBlobFrame(frame.Payload)
+
 		default:
 			s.logger.Warn("unknown frame type", "type", frame.Type)
 		}
@@ -76,6 +80,12 @@ func (s *Session) handleEventFrame(payload []byte) {
 		fmt.Printf("[WS] Decoded event: HID=%s Type=%v\n", pe.HID, pe.Type)
 	}
 
+	// Call results resolve the calls awaiting them, off the event loop
+	if data, ok := pe.Payload.(*protocol.CallResultData); ok {
+		s.handleCallResult(data)
+		return
+	}
+
 	// Convert to server event
 	event := eventFromProtocol(pe, s)
 
@@ -182,13 +192,23 @@ func (s *Session) WriteLoop() {
 }
 
 // EventLoop processes queued events and render signals.
-// It runs handlers, schedules effects, and triggers re-renders.
+// It runs handlers, schedules effects, and triggers re-renders. When the
+// session closes, it disposes of the component tree.
 func (s *Session) EventLoop() {
+	defer s.dispose()
+
 	for {
 		select {
 		case event := <-s.events:
 			s.handleEvent(event)
 
+		case fn := <-s.dispatchCh:
+			s.runDispatched(fn)
+
+		case fn := <-s.inspectCh:
+			// Reads only: no effects run and nothing is rendered
+			fn()
+
 		case <-s.renderCh:
 			s.renderDirty()
 
@@ -201,6 +221,7 @@ func (s *Session) EventLoop() {
 // Start starts all session loops.
 // This should be called after the handshake is complete.
 func (s *Session) Start() {
+	s.loopStarted.Store(true)
 	go s.ReadLoop()
 	go s.WriteLoop()
 	go s.EventLoop()
@@ -252,7 +273,7 @@ func (s *Session) SendPatches(patches []protocol.Patch) {
 		Patches: patches,
 	}
 
-	payload := protocol.EncodePatches(pf)
+	payload := protocol.EncodePatchesFor(pf, s.caps)
 	frame := protocol.NewFrame(protocol.FramePatches, payload)
 	frameData := frame.Encode()
 
@@ -266,6 +287,7 @@ func (s *Session) SendPatches(patches []protocol.Patch) {
 
 	s.bytesSent.Add(uint64(len(frameData)))
 	s.patchCount.Add(uint64(len(patches)))
+	s.record(protocol.RecordOut, frameData)
 }
 
 // SendClose sends a close control message to the client.
@@ -0,0 +1,70 @@
+package vango
+
+// Dispatcher runs functions on the event loop that owns a reactive scope.
+//
+// Work that finishes in a background goroutine (async validation, fetches,
+// timers) must not race with event handlers and renders. Instead it hands its
+// result to the Dispatcher, which runs the function serially with the rest of
+// the session's work and then re-renders any components it dirtied.
+//
+// The server runtime installs the session as the Dispatcher on its root Owner.
+type Dispatcher interface {
+	// Dispatch queues fn to run on the owning event loop.
+	// It may be called from any goroutine.
+	Dispatch(fn func())
+}
+
+// DispatcherFunc adapts a plain function to the Dispatcher interface.
+type DispatcherFunc func(fn func())
+
+// Dispatch calls f(fn).
+func (f DispatcherFunc) Dispatch(fn func()) {
+	f(fn)
+}
+
+// immediateDispatcher runs functions synchronously on the calling goroutine.
+// It is used when no event loop owns the current scope (tests, SSR).
+var immediateDispatcher = DispatcherFunc(func(fn func()) { fn() })
+
+// dispatcherKey is the Owner value key for the installed Dispatcher.
+type dispatcherKey struct{}
+
+// SetDispatcher installs d as the Dispatcher for owner and its descendants.
+func SetDispatcher(owner *Owner, d Dispatcher) {
+	if owner == nil {
+		return
+	}
+	owner.SetValue(dispatcherKey{}, d)
+}
+
+// GetDispatcher returns the Dispatcher for the current owner scope.
+// Call it during render and keep the result; background goroutines have no
+// owner context of their own.
+//
+// If no Dispatcher is installed, the returned Dispatcher runs functions
+// immediately on the calling goroutine.
+//
+// Example:
+//
+//	dispatch := vango.GetDispatcher()
+//	go func() {
+//	    user, err := api.LoadUser(id)
+//	    dispatch.Dispatch(func() {
+//	        result.Set(user)
+//	        loadErr.Set(err)
+//	    })
+//	}()
+func GetDispatcher() Dispatcher {
+	if d, ok := GetContext(dispatcherKey{}).(Dispatcher); ok && d != nil {
+		return d
+	}
+	return immediateDispatcher
+}
+
+// HasDispatcher reports whether an event loop owns the current owner scope,
+// in which case code running in it must not block waiting on background
+// work.
+func HasDispatcher() bool {
+	d, ok := GetContext(dispatcherKey{}).(Dispatcher)
+	return ok && d != nil
+}
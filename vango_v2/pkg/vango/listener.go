@@ -14,6 +14,22 @@ type Listener interface {
 	ID() uint64
 }
 
+// SignalRecorder is implemented by listeners that record the signals they
+// read, such as component instances while the session inspector is enabled.
+type SignalRecorder interface {
+	// RecordSignal is called each time the listener reads s.
+	RecordSignal(s InspectableSignal)
+}
+
+// InspectableSignal exposes a signal to debugging tools.
+type InspectableSignal interface {
+	// SignalID returns the unique identifier of the signal.
+	SignalID() uint64
+
+	// GetAny returns the current value.
+	GetAny() any
+}
+
 // Cleanup is a function returned by effects to clean up resources.
 // It is called before the effect re-runs and when the effect is disposed.
 type Cleanup func()
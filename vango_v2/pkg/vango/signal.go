@@ -150,6 +150,10 @@ func (s *Signal[T]) Get() T {
 		if m, ok := listener.(memoBase); ok {
 			m.addSource(&s.base)
 		}
+		// If listener records what it reads, report this signal
+		if r, ok := listener.(SignalRecorder); ok {
+			r.RecordSignal(s)
+		}
 	}
 
 	return value
@@ -277,6 +281,11 @@ func (s *Signal[T]) PersistKey() string {
 	return s.persistKey
 }
 
+// SignalID returns the unique identifier of the signal.
+func (s *Signal[T]) SignalID() uint64 {
+	return s.base.id
+}
+
 // GetAny returns the current value as an interface{}.
 // This is used for serialization without knowing the concrete type.
 func (s *Signal[T]) GetAny() any {
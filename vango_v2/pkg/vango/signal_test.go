@@ -357,3 +357,30 @@ func TestSignalUpdateNoChange(t *testing.T) {
 		t.Errorf("expected 1 notification, got %d", listener.getDirtyCount())
 	}
 }
+
+// recordingListener records the signals it reads.
+type recordingListener struct {
+	id   uint64
+	read []InspectableSignal
+}
+
+func (l *recordingListener) MarkDirty()                       {}
+func (l *recordingListener) ID() uint64                       { return l.id }
+func (l *recordingListener) RecordSignal(s InspectableSignal) { l.read = append(l.read, s) }
+
+func TestSignalRecorder(t *testing.T) {
+	name := NewSignal("ada")
+	l := &recordingListener{id: nextID()}
+
+	WithListener(l, func() {
+		name.Get()
+		name.Peek()
+	})
+
+	if len(l.read) != 1 {
+		t.Fatalf("recorded %d signals, want 1 (Peek does not track)", len(l.read))
+	}
+	if l.read[0].SignalID() != name.SignalID() || l.read[0].GetAny() != "ada" {
+		t.Errorf("recorded signal %d = %v", l.read[0].SignalID(), l.read[0].GetAny())
+	}
+}
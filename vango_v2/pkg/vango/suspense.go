@@ -0,0 +1,33 @@
+package vango
+
+// Suspend reports that the current render depends on work that has not
+// finished yet. ready must be closed when the work completes.
+//
+// Data sources such as resources call Suspend when they are read while still
+// loading. The nearest suspense boundary collects these reports to decide
+// between rendering its fallback and its content. Outside a boundary,
+// Suspend does nothing.
+func Suspend(ready <-chan struct{}) {
+	if ready == nil {
+		return
+	}
+	ctx := getTrackingContext()
+	if ctx.suspended != nil {
+		*ctx.suspended = append(*ctx.suspended, ready)
+	}
+}
+
+// CollectSuspense runs fn and returns the channels reported via Suspend
+// while it ran. An empty result means fn rendered without waiting on
+// anything. Nested calls collect independently.
+func CollectSuspense(fn func()) []<-chan struct{} {
+	ctx := getTrackingContext()
+	var pending []<-chan struct{}
+	old := ctx.suspended
+	ctx.suspended = &pending
+	defer func() {
+		ctx.suspended = old
+	}()
+	fn()
+	return pending
+}
@@ -0,0 +1,42 @@
+package vango
+
+import "testing"
+
+func TestCollectSuspense(t *testing.T) {
+	ready := make(chan struct{})
+
+	pending := CollectSuspense(func() {
+		Suspend(ready)
+		Suspend(nil)
+	})
+	if len(pending) != 1 {
+		t.Fatalf("expected 1 pending channel, got %d", len(pending))
+	}
+
+	// Outside a boundary Suspend is a no-op
+	Suspend(ready)
+
+	if pending := CollectSuspense(func() {}); len(pending) != 0 {
+		t.Errorf("expected no pending channels, got %d", len(pending))
+	}
+}
+
+func TestCollectSuspenseNested(t *testing.T) {
+	outer := make(chan struct{})
+	inner := make(chan struct{})
+
+	var innerPending []<-chan struct{}
+	outerPending := CollectSuspense(func() {
+		Suspend(outer)
+		innerPending = CollectSuspense(func() {
+			Suspend(inner)
+		})
+	})
+
+	if len(outerPending) != 1 || outerPending[0] != (<-chan struct{})(outer) {
+		t.Errorf("outer boundary should only see its own work, got %v", outerPending)
+	}
+	if len(innerPending) != 1 || innerPending[0] != (<-chan struct{})(inner) {
+		t.Errorf("inner boundary should only see its own work, got %v", innerPending)
+	}
+}
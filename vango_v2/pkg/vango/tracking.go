@@ -25,6 +25,10 @@ type TrackingContext struct {
 	// pendingUpdates accumulates listeners to notify when batch completes.
 	// Deduplicated by ID before notification.
 	pendingUpdates []Listener
+
+	// suspended collects work reported via Suspend inside CollectSuspense.
+	// nil means no suspense boundary is collecting.
+	suspended *[]<-chan struct{}
 }
 
 // trackingContexts stores per-goroutine tracking contexts.
@@ -163,6 +167,12 @@ func WithOwner(owner *Owner, fn func()) {
 	fn()
 }
 
+// CurrentOwner returns the Owner of the current scope, or nil if none is set.
+// Capture it when work started during render must later run in the same scope.
+func CurrentOwner() *Owner {
+	return getCurrentOwner()
+}
+
 // WithListener runs a function with the specified listener for tracking.
 // This is used internally to set up dependency tracking during rendering.
 func WithListener(l Listener, fn func()) {
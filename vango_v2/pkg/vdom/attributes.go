@@ -241,6 +241,15 @@ func FormAttr(id string) Attr { return attr("form", id) }
 // Src sets the src attribute.
 func Src(url string) Attr { return attr("src", url) }
 
+// BlobSrc sets the src of the element to a blob sent by the server on the
+// live connection, once the client has received it. ref is the Ref of a
+// server.BlobTransfer.
+func BlobSrc(ref string) Attr { return attr("data-blob-src", ref) }
+
+// BlobHref sets the href of a link to a blob sent by the server, e.g. for a
+// download link with Download().
+func BlobHref(ref string) Attr { return attr("data-blob-href", ref) }
+
 // Alt sets the alt attribute.
 func Alt(text string) Attr { return attr("alt", text) }
 
@@ -118,6 +118,8 @@ func TestMediaAttributes(t *testing.T) {
 		value any
 	}{
 		{"Src", Src("/image.png"), "src", "/image.png"},
+		{"BlobSrc", BlobSrc("4"), "data-blob-src", "4"},
+		{"BlobHref", BlobHref("6"), "data-blob-href", "6"},
 		{"Alt", Alt("An image"), "alt", "An image"},
 		{"Width", Width(100), "width", 100},
 		{"Height", Height(200), "height", 200},
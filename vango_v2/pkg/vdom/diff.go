@@ -86,8 +86,9 @@ func diffText(prev, next *VNode, parentHID string, patches *[]Patch) {
 
 // diffElement compares element nodes.
 func diffElement(prev, next *VNode, patches *[]Patch) {
-	// Different tag - replace entire node
-	if prev.Tag != next.Tag {
+	// Different tag, or a Suspense boundary switching between fallback and
+	// content - replace entire node
+	if prev.Tag != next.Tag || suspenseChanged(prev, next) {
 		*patches = append(*patches, Patch{
 			Op:   PatchReplaceNode,
 			HID:  prev.HID,
@@ -166,6 +167,9 @@ func diffProps(prev, next *VNode, patches *[]Patch) {
 		if key == "key" {
 			continue // Key is not a real attribute
 		}
+		if strings.HasPrefix(key, "_") {
+			continue // Internal props are never rendered
+		}
 
 		nextVal, exists := next.Props[key]
 		if !exists {
@@ -194,6 +198,9 @@ func diffProps(prev, next *VNode, patches *[]Patch) {
 		if key == "key" {
 			continue
 		}
+		if strings.HasPrefix(key, "_") {
+			continue
+		}
 
 		if _, exists := prev.Props[key]; !exists {
 			// Attribute added
@@ -260,75 +267,83 @@ func diffUnkeyedChildren(parent *VNode, prev, next []*VNode, parentHID string, p
 }
 
 // diffKeyedChildren handles children with keys for efficient reordering.
+//
+// Nodes whose key is gone are removed first, so the indices of the inserts
+// and moves that follow are positions among the remaining nodes. A kept
+// node is only moved when it is not already next in place: shifting a
+// window over a list inserts the entering nodes and removes the leaving
+// ones without moving the others.
 func diffKeyedChildren(parent *VNode, prev, next []*VNode, parentHID string, patches *[]Patch) {
 	// Build key maps: key -> index
 	prevKeyMap := make(map[string]int)
-	nextKeyMap := make(map[string]int)
+	nextKeys := make(map[string]bool)
 
 	for i, child := range prev {
 		if key := getKey(child); key != "" {
 			prevKeyMap[key] = i
 		}
 	}
-	for i, child := range next {
+	for _, child := range next {
 		if key := getKey(child); key != "" {
-			nextKeyMap[key] = i
+			nextKeys[key] = true
 		}
 	}
 
-	// Track which prev nodes have been matched
-	matched := make(map[int]bool)
+	// Remove prev nodes without a match, keeping the others in order
+	remaining := make([]int, 0, len(prev))
+	for i, prevChild := range prev {
+		key := getKey(prevChild)
+		if key != "" && nextKeys[key] && prevKeyMap[key] == i {
+			remaining = append(remaining, i)
+			continue
+		}
+		*patches = append(*patches, Patch{
+			Op:  PatchRemoveNode,
+			HID: prevChild.HID,
+		})
+	}
+
+	// Track which prev nodes have been placed, and the first remaining
+	// node not placed yet: the one currently at the next index
+	placed := make(map[int]bool)
+	cursor := 0
 
 	// Process next children in order
 	for nextIdx, nextChild := range next {
-		key := getKey(nextChild)
+		for cursor < len(remaining) && placed[remaining[cursor]] {
+			cursor++
+		}
 
-		if key != "" {
-			if prevIdx, exists := prevKeyMap[key]; exists {
-				// Found matching key
-				matched[prevIdx] = true
-				prevChild := prev[prevIdx]
-
-				// Check if position changed
-				if prevIdx != nextIdx {
-					*patches = append(*patches, Patch{
-						Op:       PatchMoveNode,
-						HID:      prevChild.HID,
-						ParentID: parent.HID,
-						Index:    nextIdx,
-					})
-				}
-
-				// Diff the node itself - pass parent HID for text nodes
-				diff(prevChild, nextChild, parentHID, patches)
-			} else {
-				// New node with key
-				*patches = append(*patches, Patch{
-					Op:       PatchInsertNode,
-					ParentID: parent.HID,
-					Index:    nextIdx,
-					Node:     nextChild,
-				})
-			}
-		} else {
-			// Unkeyed node in keyed list - treat as insert
+		key := getKey(nextChild)
+		prevIdx, exists := prevKeyMap[key]
+		if key == "" || !exists || placed[prevIdx] {
+			// New node, unkeyed node or duplicate key - insert
 			*patches = append(*patches, Patch{
 				Op:       PatchInsertNode,
 				ParentID: parent.HID,
 				Index:    nextIdx,
 				Node:     nextChild,
 			})
+			continue
 		}
-	}
 
-	// Remove unmatched prev nodes
-	for i, prevChild := range prev {
-		if !matched[i] {
+		// Found matching key
+		placed[prevIdx] = true
+		prevChild := prev[prevIdx]
+
+		if cursor < len(remaining) && remaining[cursor] == prevIdx {
+			cursor++
+		} else {
 			*patches = append(*patches, Patch{
-				Op:  PatchRemoveNode,
-				HID: prevChild.HID,
+				Op:       PatchMoveNode,
+				HID:      prevChild.HID,
+				ParentID: parent.HID,
+				Index:    nextIdx,
 			})
 		}
+
+		// Diff the node itself - pass parent HID for text nodes
+		diff(prevChild, nextChild, parentHID, patches)
 	}
 }
 
@@ -366,6 +366,35 @@ func TestDiffKeyedRemoval(t *testing.T) {
 	}
 }
 
+func TestDiffKeyedWindow(t *testing.T) {
+	prev := Ul(
+		Li(Key("1"), Text("1")),
+		Li(Key("2"), Text("2")),
+		Li(Key("3"), Text("3")),
+		Li(Key("4"), Text("4")),
+	)
+	assignTestHIDs(prev)
+
+	// Scrolled by one row: 1 leaves, 5 enters, 2-4 keep their order
+	next := Ul(
+		Li(Key("2"), Text("2")),
+		Li(Key("3"), Text("3")),
+		Li(Key("4"), Text("4")),
+		Li(Key("5"), Text("5")),
+	)
+
+	patches := Diff(prev, next)
+	if len(patches) != 2 {
+		t.Fatalf("Expected 2 patches, got %d: %v", len(patches), patches)
+	}
+	if patches[0].Op != PatchRemoveNode || patches[0].HID != prev.Children[0].HID {
+		t.Errorf("patches[0] = %v, want RemoveNode of key 1", patches[0])
+	}
+	if patches[1].Op != PatchInsertNode || patches[1].Index != 3 {
+		t.Errorf("patches[1] = %v, want InsertNode at 3", patches[1])
+	}
+}
+
 func TestDiffFragmentChildren(t *testing.T) {
 	prev := Fragment(Div(), Span())
 	assignTestHIDs(prev)
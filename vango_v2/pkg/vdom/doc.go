@@ -30,4 +30,17 @@
 //
 // AssignHIDs walks the tree and assigns hydration IDs to interactive elements
 // (those with event handlers). These IDs link server VNodes to client DOM.
+//
+// # Transitions
+//
+// Transition declares CSS classes the client applies when a patch inserts,
+// removes or moves an element. Removed elements stay in the DOM until their
+// leave transition ends, but the server tree and HIDs are updated at once.
+//
+// # Portals
+//
+// Portal renders its children into a container declared with PortalTarget,
+// e.g. for modals escaping overflow and stacking contexts. The children stay
+// under the portal in the tree, with HIDs scoped to it (h5-p1, h5-p2), and
+// the client applies patches to them in the target.
 package vdom
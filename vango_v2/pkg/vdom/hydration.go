@@ -8,6 +8,7 @@ import (
 // HIDGenerator generates unique hydration IDs for interactive elements.
 type HIDGenerator struct {
 	counter uint32
+	scopes  map[string]uint32
 	mu      sync.Mutex
 }
 
@@ -24,11 +25,24 @@ func (g *HIDGenerator) Next() string {
 	return fmt.Sprintf("h%d", g.counter)
 }
 
+// NextIn returns the next hydration ID within scope (e.g., "h5-1", "h5-2").
+// Each scope numbers its elements independently; see SuspenseScope.
+func (g *HIDGenerator) NextIn(scope string) string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.scopes == nil {
+		g.scopes = make(map[string]uint32)
+	}
+	g.scopes[scope]++
+	return fmt.Sprintf("%s%d", scope, g.scopes[scope])
+}
+
 // Reset resets the counter to 0.
 func (g *HIDGenerator) Reset() {
 	g.mu.Lock()
 	defer g.mu.Unlock()
 	g.counter = 0
+	g.scopes = nil
 }
 
 // Current returns the current counter value without incrementing.
@@ -42,6 +56,11 @@ func (g *HIDGenerator) Current() uint32 {
 // An element is interactive if it has event handlers (props starting with "on").
 // Elements containing text children also get HIDs so text updates can target them.
 func AssignHIDs(node *VNode, gen *HIDGenerator) {
+	assignHIDs(node, gen, "")
+}
+
+// assignHIDs assigns HIDs within scope ("" for the document scope).
+func assignHIDs(node *VNode, gen *HIDGenerator, scope string) {
 	if node == nil {
 		return
 	}
@@ -49,12 +68,26 @@ func AssignHIDs(node *VNode, gen *HIDGenerator) {
 	// Assign HIDs to all elements to ensure they are addressable in the client.
 	// This supports dynamic updates (InsertNode, RemoveNode, etc.) anywhere in the tree.
 	if node.Kind == KindElement && node.HID == "" {
-		node.HID = gen.Next()
+		if scope == "" {
+			node.HID = gen.Next()
+		} else {
+			node.HID = gen.NextIn(scope)
+		}
+	}
+
+	// Suspense boundaries number their children in their own scope
+	if b := SuspenseOf(node); b != nil {
+		scope = SuspenseScope(node.HID, b.IsPending())
+	}
+
+	// So do portals, whose children SSR renders elsewhere
+	if _, ok := PortalOf(node); ok {
+		scope = PortalScope(node.HID)
 	}
 
 	// Recurse into children
 	for _, child := range node.Children {
-		AssignHIDs(child, gen)
+		assignHIDs(child, gen, scope)
 	}
 
 	// For component nodes, we might need to assign HIDs to the rendered output
@@ -171,6 +204,18 @@ func CopyHIDs(src, dst *VNode) bool {
 	// Copy HID
 	dst.HID = src.HID
 
+	// A boundary that switched between fallback and content is replaced as
+	// a whole; its new children are numbered fresh in their own scope
+	if suspenseChanged(src, dst) {
+		return true
+	}
+
+	// Keyed children are matched by key, so nodes that moved keep their
+	// HIDs and nodes new to the list get fresh ones
+	if hasKeys(src.Children) || hasKeys(dst.Children) {
+		return copyKeyedHIDs(src.Children, dst.Children)
+	}
+
 	// For same-structure trees, copy children HIDs
 	if len(src.Children) != len(dst.Children) {
 		return false
@@ -184,3 +229,28 @@ func CopyHIDs(src, dst *VNode) bool {
 
 	return true
 }
+
+// copyKeyedHIDs copies HIDs between keyed children with the same key.
+// Returns true if every child was matched.
+func copyKeyedHIDs(src, dst []*VNode) bool {
+	byKey := make(map[string]*VNode, len(src))
+	for _, child := range src {
+		if key := getKey(child); key != "" {
+			byKey[key] = child
+		}
+	}
+
+	ok := len(src) == len(dst)
+	for _, child := range dst {
+		prev, found := byKey[getKey(child)]
+		if !found {
+			ok = false
+			continue
+		}
+		delete(byKey, getKey(child))
+		if !CopyHIDs(prev, child) {
+			ok = false
+		}
+	}
+	return ok
+}
@@ -1,6 +1,9 @@
 package vdom
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+)
 
 func TestHIDGenerator(t *testing.T) {
 	gen := NewHIDGenerator()
@@ -292,6 +295,25 @@ func TestCopyHIDs(t *testing.T) {
 			t.Error("CopyHIDs with one nil should return false")
 		}
 	})
+
+	t.Run("keyed", func(t *testing.T) {
+		src := Ul(Li(Key("a")), Li(Key("b")), Li(Key("c")))
+		AssignAllHIDs(src, NewHIDGenerator())
+
+		// Window shifted by one: same length, different keys per position
+		dst := Ul(Li(Key("b")), Li(Key("c")), Li(Key("d")))
+		if CopyHIDs(src, dst) {
+			t.Error("CopyHIDs should return false when keys differ")
+		}
+
+		var got []string
+		for _, child := range dst.Children {
+			got = append(got, child.HID)
+		}
+		if want := []string{"h3", "h4", ""}; !reflect.DeepEqual(got, want) {
+			t.Errorf("keyed HIDs = %v, want %v", got, want)
+		}
+	})
 }
 
 func TestHIDGeneratorConcurrency(t *testing.T) {
@@ -0,0 +1,86 @@
+package vdom
+
+// PortalTag is the tag of the placeholder element rendered for a Portal.
+// It is styled display:contents and stays where the portal is declared.
+const PortalTag = "vango-portal"
+
+// PortalContentTag is the tag of the element holding the children of a
+// portal inside its target. It is styled display:contents and its
+// data-portal-for attribute names the HID of the portal.
+const PortalContentTag = "vango-portal-content"
+
+// Attributes naming the target of a portal and a target container.
+const (
+	portalAttr       = "data-portal"
+	portalTargetAttr = "data-portal-target"
+)
+
+// Portal renders children into the target container named target, declared
+// with PortalTarget, instead of where the portal is declared. Without such
+// a container, the children are appended to the document body.
+//
+// Only the DOM is moved: the children stay under the portal in the
+// component's tree, so they are diffed, their handlers are collected and
+// they are disposed of with the component that renders them. Patches to
+// them are applied in the target.
+//
+// Example:
+//
+//	Div(Class("card"),
+//	    Button(OnClick(open.Toggle), Text("Details")),
+//	    If(open.Get(), Portal("modals",
+//	        Div(Class("modal"), Text("..."))),
+//	    ),
+//	)
+func Portal(target string, children ...any) *VNode {
+	args := make([]any, 0, len(children)+2)
+	args = append(args,
+		Attr{Key: "style", Value: "display:contents"},
+		Attr{Key: portalAttr, Value: target},
+	)
+	args = append(args, children...)
+	return createElement(PortalTag, args)
+}
+
+// PortalTarget renders a container receiving the children of the portals
+// targeting name. Declare it once per page, outside the parts that
+// re-render, e.g. at the end of the layout.
+//
+// Example:
+//
+//	Body(
+//	    Main(children...),
+//	    PortalTarget("modals", Class("modal-root")),
+//	)
+func PortalTarget(name string, args ...any) *VNode {
+	return Div(append([]any{Attr{Key: portalTargetAttr, Value: name}}, args...)...)
+}
+
+// PortalOf returns the target of a Portal node. ok is false if node is
+// not a portal.
+func PortalOf(node *VNode) (target string, ok bool) {
+	if node == nil || node.Kind != KindElement || node.Tag != PortalTag {
+		return "", false
+	}
+	target, _ = node.Props[portalAttr].(string)
+	return target, true
+}
+
+// PortalTargetOf returns the name of a PortalTarget node, or "" if node is
+// not a portal target.
+func PortalTargetOf(node *VNode) string {
+	if node == nil || node.Kind != KindElement {
+		return ""
+	}
+	name, _ := node.Props[portalTargetAttr].(string)
+	return name
+}
+
+// PortalScope returns the HID scope for the children of a portal.
+//
+// Elements inside a portal are numbered within the portal, so they get the
+// same HIDs whether SSR renders them in their target, ahead of or after the
+// rest of the page, or a live session renders them under the portal.
+func PortalScope(portalHID string) string {
+	return portalHID + "-p"
+}
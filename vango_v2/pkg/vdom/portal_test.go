@@ -0,0 +1,41 @@
+package vdom
+
+import "testing"
+
+func TestPortal(t *testing.T) {
+	node := Portal("modals", Div(Text("dialog")))
+
+	if target, ok := PortalOf(node); !ok || target != "modals" {
+		t.Errorf("PortalOf() = %q, %v, want modals, true", target, ok)
+	}
+	if _, ok := PortalOf(Div()); ok {
+		t.Error("PortalOf(Div()) should not be a portal")
+	}
+	if len(node.Children) != 1 || node.Children[0].Tag != "div" {
+		t.Errorf("children = %+v, want the div", node.Children)
+	}
+
+	target := PortalTarget("modals", Class("root"))
+	if name := PortalTargetOf(target); name != "modals" {
+		t.Errorf("PortalTargetOf() = %q, want modals", name)
+	}
+	if target.Props["class"] != "root" {
+		t.Errorf("target should keep its attributes, got %v", target.Props)
+	}
+}
+
+func TestPortalScopedHIDs(t *testing.T) {
+	tree := Div(
+		Portal("modals", Div(Button())),
+		Button(),
+	)
+	AssignHIDs(tree, NewHIDGenerator())
+
+	content := tree.Children[0].Children[0]
+	if content.HID != "h2-p1" || content.Children[0].HID != "h2-p2" {
+		t.Errorf("portal children HIDs = %q, %q, want h2-p1, h2-p2", content.HID, content.Children[0].HID)
+	}
+	if tree.Children[1].HID != "h3" {
+		t.Errorf("sibling HID = %q, want h3", tree.Children[1].HID)
+	}
+}
@@ -0,0 +1,144 @@
+package vdom
+
+import (
+	"github.com/vango-dev/vango/v2/pkg/vango"
+)
+
+// SuspenseTag is the tag of the wrapper element rendered for a Suspense boundary.
+// It is styled display:contents so it does not affect layout.
+const SuspenseTag = "vango-suspense"
+
+// suspenseProp is the internal prop holding the boundary state.
+const suspenseProp = "_suspense"
+
+// SuspenseBoundary is the state of a Suspense node.
+type SuspenseBoundary struct {
+	// Fallback is shown while the content is pending.
+	Fallback *VNode
+
+	// Pending holds the channels the content is waiting on.
+	// Empty once the content has rendered without suspending.
+	Pending []<-chan struct{}
+
+	content func() *VNode
+	owner   *vango.Owner
+}
+
+// IsPending reports whether the boundary is showing its fallback.
+func (b *SuspenseBoundary) IsPending() bool {
+	return len(b.Pending) > 0
+}
+
+// Resolve renders the content again in the scope it was created in.
+// It returns the content and the channels it is still waiting on.
+func (b *SuspenseBoundary) Resolve() (*VNode, []<-chan struct{}) {
+	var content *VNode
+	pending := vango.CollectSuspense(func() {
+		vango.WithOwner(b.owner, func() {
+			content = b.content()
+		})
+	})
+	return content, pending
+}
+
+// Suspense creates a boundary that shows fallback until its children stop
+// waiting on asynchronous work, such as a loading Resource.
+//
+// Children are rendered inside the boundary so reads of pending data can be
+// detected. Pass content that reads async data as a func() *VNode or a
+// Component; plain nodes are included as they are.
+//
+// During streaming SSR the fallback is sent immediately and the resolved
+// content is streamed later in the same response. In a live session the
+// component re-renders when the data arrives and the boundary is swapped
+// to its content. A session started from a resource.Handover renders the
+// boundaries that streamed as resolved from the start.
+//
+// Example:
+//
+//	Suspense(
+//	    Div(Class("skeleton")),
+//	    func() *VNode {
+//	        return user.Match(
+//	            resource.OnReady(func(u *User) *VNode { return Profile(u) }),
+//	            resource.OnError(func(err error) *VNode { return ErrorBox(err) }),
+//	        )
+//	    },
+//	)
+func Suspense(fallback *VNode, children ...any) *VNode {
+	b := &SuspenseBoundary{
+		Fallback: fallback,
+		owner:    vango.CurrentOwner(),
+	}
+	b.content = func() *VNode {
+		return suspenseContent(children)
+	}
+
+	content, pending := b.Resolve()
+	b.Pending = pending
+	return suspenseNode(b, content)
+}
+
+// suspenseContent renders the boundary children into a fragment.
+func suspenseContent(children []any) *VNode {
+	nodes := make([]any, 0, len(children))
+	for _, child := range children {
+		switch v := child.(type) {
+		case func() *VNode:
+			nodes = append(nodes, v())
+		case Component:
+			nodes = append(nodes, v.Render())
+		default:
+			nodes = append(nodes, v)
+		}
+	}
+	return Fragment(nodes...)
+}
+
+// suspenseNode builds the wrapper element for b, showing content if b is
+// resolved and the fallback otherwise.
+func suspenseNode(b *SuspenseBoundary, content *VNode) *VNode {
+	state := "ready"
+	child := content
+	if b.IsPending() {
+		state = "pending"
+		child = b.Fallback
+	}
+
+	return createElement(SuspenseTag, []any{
+		Attr{Key: "style", Value: "display:contents"},
+		Attr{Key: "data-suspense", Value: state},
+		Attr{Key: suspenseProp, Value: b},
+		child,
+	})
+}
+
+// SuspenseOf returns the boundary state of a Suspense node, or nil if node
+// is not a Suspense boundary.
+func SuspenseOf(node *VNode) *SuspenseBoundary {
+	if node == nil || node.Kind != KindElement || node.Tag != SuspenseTag {
+		return nil
+	}
+	b, _ := node.Props[suspenseProp].(*SuspenseBoundary)
+	return b
+}
+
+// SuspenseScope returns the HID scope for the children of a boundary.
+//
+// Elements inside a boundary are numbered within the boundary rather than
+// the whole document, so the content gets the same HIDs whether it is
+// rendered inline, streamed after the shell, or rendered by a live session.
+// Fallback and content use separate scopes and never share HIDs.
+func SuspenseScope(boundaryHID string, pending bool) string {
+	if pending {
+		return boundaryHID + "-f"
+	}
+	return boundaryHID + "-"
+}
+
+// suspenseChanged reports whether prev and next are the same boundary in
+// different states (fallback vs content).
+func suspenseChanged(prev, next *VNode) bool {
+	pb, nb := SuspenseOf(prev), SuspenseOf(next)
+	return pb != nil && nb != nil && pb.IsPending() != nb.IsPending()
+}
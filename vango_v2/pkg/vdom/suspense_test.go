@@ -0,0 +1,121 @@
+package vdom
+
+import (
+	"testing"
+
+	"github.com/vango-dev/vango/v2/pkg/vango"
+)
+
+// gatedContent renders text once ready is closed and suspends until then.
+func gatedContent(ready chan struct{}, text string) func() *VNode {
+	return func() *VNode {
+		select {
+		case <-ready:
+			return P(Text(text))
+		default:
+			vango.Suspend(ready)
+			return nil
+		}
+	}
+}
+
+func TestSuspensePendingShowsFallback(t *testing.T) {
+	ready := make(chan struct{})
+	node := Suspense(Span(Text("loading")), gatedContent(ready, "done"))
+
+	b := SuspenseOf(node)
+	if b == nil {
+		t.Fatal("Suspense should produce a boundary node")
+	}
+	if !b.IsPending() {
+		t.Fatal("boundary should be pending")
+	}
+	if node.Props["data-suspense"] != "pending" {
+		t.Errorf("data-suspense = %v, want pending", node.Props["data-suspense"])
+	}
+	if len(node.Children) != 1 || node.Children[0].Tag != "span" {
+		t.Errorf("pending boundary should render the fallback, got %+v", node.Children)
+	}
+
+	close(ready)
+	content, pending := b.Resolve()
+	if len(pending) != 0 {
+		t.Errorf("resolved content should not be pending")
+	}
+	if content == nil || len(content.Children) != 1 || content.Children[0].Tag != "p" {
+		t.Errorf("Resolve() should render the content, got %+v", content)
+	}
+}
+
+func TestSuspenseReadyRendersContent(t *testing.T) {
+	ready := make(chan struct{})
+	close(ready)
+	node := Suspense(Span(Text("loading")), gatedContent(ready, "done"), "plain")
+
+	if SuspenseOf(node).IsPending() {
+		t.Fatal("boundary should not be pending")
+	}
+	if node.Props["data-suspense"] != "ready" {
+		t.Errorf("data-suspense = %v, want ready", node.Props["data-suspense"])
+	}
+	frag := node.Children[0]
+	if frag.Kind != KindFragment || len(frag.Children) != 2 {
+		t.Errorf("content should include all children, got %+v", frag)
+	}
+}
+
+func TestSuspenseScopedHIDs(t *testing.T) {
+	pendingCh := make(chan struct{})
+	readyCh := make(chan struct{})
+	close(readyCh)
+
+	tree := Div(
+		Suspense(Span(), gatedContent(pendingCh, "a")),
+		Suspense(Span(), gatedContent(readyCh, "b")),
+		Button(),
+	)
+	gen := NewHIDGenerator()
+	AssignHIDs(tree, gen)
+
+	fallback := tree.Children[0].Children[0]
+	content := tree.Children[1].Children[0].Children[0]
+	if fallback.HID != "h2-f1" {
+		t.Errorf("fallback HID = %q, want h2-f1", fallback.HID)
+	}
+	if content.HID != "h3-1" {
+		t.Errorf("content HID = %q, want h3-1", content.HID)
+	}
+	if tree.Children[2].HID != "h4" {
+		t.Errorf("sibling HID = %q, want h4", tree.Children[2].HID)
+	}
+
+	// New nodes in a scope continue its numbering
+	if hid := gen.NextIn(SuspenseScope("h3", false)); hid != "h3-2" {
+		t.Errorf("NextIn() = %q, want h3-2", hid)
+	}
+}
+
+func TestDiffSuspenseResolvedReplacesBoundary(t *testing.T) {
+	ready := make(chan struct{})
+	gen := NewHIDGenerator()
+
+	prev := Div(Suspense(Span(Text("loading")), gatedContent(ready, "done")))
+	AssignHIDs(prev, gen)
+
+	close(ready)
+	next := Div(Suspense(Span(Text("loading")), gatedContent(ready, "done")))
+	CopyHIDs(prev, next)
+	AssignHIDs(next, gen)
+
+	patches := Diff(prev, next)
+	if len(patches) != 1 {
+		t.Fatalf("expected 1 patch, got %d: %+v", len(patches), patches)
+	}
+	p := patches[0]
+	if p.Op != PatchReplaceNode || p.HID != "h2" {
+		t.Errorf("expected ReplaceNode on boundary h2, got %v on %s", p.Op, p.HID)
+	}
+	if got := p.Node.Children[0].Children[0].HID; got != "h2-1" {
+		t.Errorf("resolved content HID = %q, want h2-1", got)
+	}
+}
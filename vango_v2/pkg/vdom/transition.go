@@ -0,0 +1,79 @@
+package vdom
+
+import (
+	"strconv"
+	"time"
+)
+
+// TransitionConfig declares the CSS classes animating an element when a
+// patch inserts, removes or moves it.
+//
+// With Name, the classes default to Name-enter-from, Name-enter-active,
+// Name-enter-to, Name-leave-from, Name-leave-active, Name-leave-to and
+// Name-move; the other fields override them.
+type TransitionConfig struct {
+	Name string
+
+	// Enter classes: Enter is applied for the whole enter transition,
+	// EnterFrom for its first frame and EnterTo from its second frame on.
+	Enter     string
+	EnterFrom string
+	EnterTo   string
+
+	// Leave classes, applied like the enter classes before the element is
+	// removed.
+	Leave     string
+	LeaveFrom string
+	LeaveTo   string
+
+	// Move is applied while the element slides to a new position after a
+	// keyed move, or after siblings were inserted or removed.
+	Move string
+
+	// Duration is the length of the transitions. If zero, the client reads
+	// it from the transition and animation durations of the classes.
+	Duration time.Duration
+}
+
+// Transition animates an element when it is inserted, removed or moved by
+// a patch. Initial page loads are not animated.
+//
+// Only the client is affected: the server removes nodes from its tree and
+// releases their HIDs immediately, while the client keeps a removed
+// element in the DOM until its leave transition ends, out of the way of
+// later patches.
+//
+// Example:
+//
+//	Div(Key(t.ID), Class("toast"),
+//	    Transition(TransitionConfig{Name: "toast", Duration: 200 * time.Millisecond}),
+//	    Text(t.Message),
+//	)
+//
+// with CSS such as:
+//
+//	.toast-enter-active, .toast-leave-active { transition: opacity 200ms, transform 200ms; }
+//	.toast-enter-from, .toast-leave-to { opacity: 0; transform: translateY(8px); }
+//	.toast-move { transition: transform 200ms; }
+func Transition(config TransitionConfig) []Attr {
+	var attrs []Attr
+	if config.Name != "" {
+		attrs = append(attrs, attr("data-transition", config.Name))
+	}
+	add := func(key, value string) {
+		if value != "" {
+			attrs = append(attrs, attr("data-transition-"+key, value))
+		}
+	}
+	add("enter", config.Enter)
+	add("enter-from", config.EnterFrom)
+	add("enter-to", config.EnterTo)
+	add("leave", config.Leave)
+	add("leave-from", config.LeaveFrom)
+	add("leave-to", config.LeaveTo)
+	add("move", config.Move)
+	if config.Duration > 0 {
+		add("duration", strconv.FormatInt(config.Duration.Milliseconds(), 10))
+	}
+	return attrs
+}
@@ -0,0 +1,28 @@
+package vdom
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTransition(t *testing.T) {
+	node := Div(Transition(TransitionConfig{
+		Name:     "toast",
+		LeaveTo:  "gone",
+		Duration: 250 * time.Millisecond,
+	}))
+
+	want := Props{
+		"data-transition":          "toast",
+		"data-transition-leave-to": "gone",
+		"data-transition-duration": "250",
+	}
+	if !reflect.DeepEqual(node.Props, want) {
+		t.Errorf("Props = %v, want %v", node.Props, want)
+	}
+
+	if attrs := Transition(TransitionConfig{}); len(attrs) != 0 {
+		t.Errorf("empty config = %v, want no attributes", attrs)
+	}
+}
@@ -37,6 +37,10 @@
 //	vtest.ExpectContains(t, comp, "Welcome Admin")
 //	vtest.ExpectNotContains(t, comp, "Login")
 //
+// Assert a tree has no accessibility violations (see package a11y):
+//
+//	vtest.ExpectAccessible(t, comp)
+//
 // # Integration with Auth Package
 //
 // The vtest package integrates with the auth package for authenticated tests:
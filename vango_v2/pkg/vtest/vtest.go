@@ -4,6 +4,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/vango-dev/vango/v2/pkg/a11y"
 	"github.com/vango-dev/vango/v2/pkg/auth"
 	"github.com/vango-dev/vango/v2/pkg/render"
 	"github.com/vango-dev/vango/v2/pkg/server"
@@ -150,6 +151,19 @@ func ExpectAttribute(t *testing.T, node *vdom.VNode, attr, value string) {
 	}
 }
 
+// ExpectAccessible asserts that a tree has no accessibility violations,
+// reporting each one with its component path.
+//
+// Example:
+//
+//	vtest.ExpectAccessible(t, comp.Render())
+func ExpectAccessible(t *testing.T, node *vdom.VNode) {
+	t.Helper()
+	for _, v := range a11y.Check(node) {
+		t.Errorf("accessibility: %s", v)
+	}
+}
+
 // truncate truncates a string to max length with ellipsis.
 func truncate(s string, max int) string {
 	if len(s) <= max {
@@ -124,6 +124,21 @@ func TestExpectNotContains_Pass(t *testing.T) {
 	}
 }
 
+func TestExpectAccessible_Pass(t *testing.T) {
+	node := vdom.Div(
+		vdom.Label(vdom.For("name"), vdom.Text("Name")),
+		vdom.Input(vdom.ID("name")),
+		vdom.Img(vdom.Src("/logo.png"), vdom.Alt("Logo")),
+	)
+
+	mockT := &testing.T{}
+	vtest.ExpectAccessible(mockT, node)
+
+	if mockT.Failed() {
+		t.Error("ExpectAccessible should have passed")
+	}
+}
+
 func TestChainedBuilder(t *testing.T) {
 	user := &TestUser{ID: "chain"}
 	ctx := vtest.NewCtx().